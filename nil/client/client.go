@@ -3,13 +3,16 @@ package client
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
@@ -45,12 +48,21 @@ type Client interface {
 	SendRawTransaction(ctx context.Context, data []byte) (common.Hash, error)
 	GetInTransactionByHash(ctx context.Context, hash common.Hash) (*jsonrpc.RPCInTransaction, error)
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error)
+	GetReceiptInclusionProof(ctx context.Context, hash common.Hash, mainBlockId any) (*jsonrpc.RPCReceiptInclusionProof, error)
+	GetTransactionTree(ctx context.Context, hash common.Hash) (*jsonrpc.RPCTransactionTreeNode, error)
 	GetTransactionCount(ctx context.Context, address types.Address, blockId any) (types.Seqno, error)
 	GetBlockTransactionCount(ctx context.Context, shardId types.ShardId, blockId any) (uint64, error)
 	GetBalance(ctx context.Context, address types.Address, blockId any) (types.Value, error)
+	GetProof(
+		ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockId any,
+	) (*jsonrpc.RPCAccountProof, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
+	FeeHistory(
+		ctx context.Context, shardId types.ShardId, blockCount uint64, newestBlockId any, rewardPercentiles []float64,
+	) (*jsonrpc.FeeHistory, error)
+	GetLogs(ctx context.Context, shardId types.ShardId, query *filters.FilterQuery) ([]*jsonrpc.RPCLog, error)
 	ChainId(ctx context.Context) (types.ChainId, error)
 
 	DeployContract(
@@ -77,6 +89,12 @@ type Client interface {
 
 	// GetDebugContract retrieves smart contract with its data, such as code, storage and proof
 	GetDebugContract(ctx context.Context, contractAddr types.Address, blockId any) (*jsonrpc.DebugRPCContract, error)
+
+	// TraceTransaction re-executes the transaction with the tracer specified by cfg and returns its output
+	TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error)
+
+	// TraceCall executes the call with the tracer specified by cfg and returns its output
+	TraceCall(ctx context.Context, args *jsonrpc.CallArgs, blockId any, stateOverride *jsonrpc.StateOverrides, cfg *tracers.Config) (json.RawMessage, error)
 }
 
 func EstimateFeeExternal(ctx context.Context, c Client, txn *types.ExternalTransaction, blockId any) (*jsonrpc.EstimateFeeRes, error) {
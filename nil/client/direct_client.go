@@ -4,13 +4,16 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -38,7 +41,7 @@ func NewEthClient(ctx context.Context, db db.ReadOnlyDB, nShards types.ShardId,
 	}
 	localApi := rawapi.NewNodeApiOverShardApis(localShardApis)
 
-	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, true, false)
+	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, true, false, jsonrpc.EthAPIConfig{})
 	debugApi := jsonrpc.NewDebugAPI(localApi, logger)
 	dbApi := jsonrpc.NewDbAPI(db, logger)
 
@@ -126,6 +129,20 @@ func (c *DirectClient) GetInTransactionReceipt(ctx context.Context, hash common.
 	return c.ethApi.GetInTransactionReceipt(ctx, hash)
 }
 
+func (c *DirectClient) GetReceiptInclusionProof(
+	ctx context.Context, hash common.Hash, mainBlockId any,
+) (*jsonrpc.RPCReceiptInclusionProof, error) {
+	blockNrOrHash, err := transport.AsBlockReference(mainBlockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ethApi.GetReceiptInclusionProof(ctx, hash, transport.BlockNumberOrHash(blockNrOrHash))
+}
+
+func (c *DirectClient) GetTransactionTree(ctx context.Context, hash common.Hash) (*jsonrpc.RPCTransactionTreeNode, error) {
+	return c.debugApi.GetTransactionTree(ctx, hash)
+}
+
 func (c *DirectClient) GetTransactionCount(ctx context.Context, address types.Address, blockId any) (types.Seqno, error) {
 	blockNrOrHash, err := transport.AsBlockReference(blockId)
 	if err != nil {
@@ -189,6 +206,35 @@ func (c *DirectClient) GasPrice(ctx context.Context, shardId types.ShardId) (typ
 	return c.ethApi.GasPrice(ctx, shardId)
 }
 
+func (c *DirectClient) FeeHistory(
+	ctx context.Context, shardId types.ShardId, blockCount uint64, newestBlockId any, rewardPercentiles []float64,
+) (*jsonrpc.FeeHistory, error) {
+	blockNrOrHash, err := transport.AsBlockReference(newestBlockId)
+	if err != nil {
+		return nil, err
+	}
+	if blockNrOrHash.BlockNumber == nil {
+		return nil, errors.New("fee history requires a block number")
+	}
+	return c.ethApi.FeeHistory(ctx, shardId, hexutil.Uint64(blockCount), *blockNrOrHash.BlockNumber, rewardPercentiles)
+}
+
+func (c *DirectClient) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockId any,
+) (*jsonrpc.RPCAccountProof, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ethApi.GetProof(ctx, address, storageKeys, transport.BlockNumberOrHash(blockNrOrHash), &tokens)
+}
+
+func (c *DirectClient) GetLogs(
+	ctx context.Context, shardId types.ShardId, query *filters.FilterQuery,
+) ([]*jsonrpc.RPCLog, error) {
+	return c.ethApi.GetLogs(ctx, shardId, *query)
+}
+
 func (c *DirectClient) ChainId(ctx context.Context) (types.ChainId, error) {
 	res, err := c.ethApi.ChainId(ctx)
 	if err != nil {
@@ -317,3 +363,17 @@ func (c *DirectClient) PlainTextCall(_ context.Context, requestBody []byte) (jso
 func (c *DirectClient) GetDebugContract(ctx context.Context, contractAddr types.Address, blockId any) (*jsonrpc.DebugRPCContract, error) {
 	panic("Not supported")
 }
+
+func (c *DirectClient) TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error) {
+	return c.debugApi.TraceTransaction(ctx, hash, cfg)
+}
+
+func (c *DirectClient) TraceCall(
+	ctx context.Context, args *jsonrpc.CallArgs, blockId any, stateOverride *jsonrpc.StateOverrides, cfg *tracers.Config,
+) (json.RawMessage, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.debugApi.TraceCall(ctx, *args, transport.BlockNumberOrHash(blockNrOrHash), stateOverride, cfg)
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+var ErrInvalidProof = errors.New("invalid proof")
+
+// VerifyProof checks the proofs returned by GetProof against the block header: the account against the contract trie
+// of the block, the storage slots and the tokens against the tries of the account. The header itself must come
+// from a trusted source, e.g. be checked against the signatures of the validators.
+func VerifyProof(proof *jsonrpc.RPCAccountProof, block *types.Block) error {
+	if hash := block.Hash(proof.Address.ShardId()); hash != proof.BlockHash {
+		return fmt.Errorf("%w: proof is for block %s, header hash is %s", ErrInvalidProof, proof.BlockHash, hash)
+	}
+	if block.Id != proof.BlockNumber {
+		return fmt.Errorf("%w: proof is for block %d, header number is %d", ErrInvalidProof, proof.BlockNumber, block.Id)
+	}
+
+	if err := verifyRead(proof.AccountProof, proof.Address.Hash().Bytes(), proof.Contract, block.SmartContractsRoot); err != nil {
+		return fmt.Errorf("account %s: %w", proof.Address, err)
+	}
+
+	if len(proof.Contract) == 0 {
+		// The account doesn't exist, so it has no storage and tokens.
+		if !proof.Balance.IsZero() {
+			return fmt.Errorf("%w: non-zero balance of missing account %s", ErrInvalidProof, proof.Address)
+		}
+		for _, p := range proof.StorageProof {
+			if !p.Value.IsZero() {
+				return fmt.Errorf("%w: non-zero storage slot %s of missing account %s", ErrInvalidProof, p.Key, proof.Address)
+			}
+		}
+		for _, p := range proof.TokenProof {
+			if !p.Value.IsZero() {
+				return fmt.Errorf("%w: non-zero token %s of missing account %s", ErrInvalidProof, p.Token, proof.Address)
+			}
+		}
+		return nil
+	}
+
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(proof.Contract); err != nil {
+		return fmt.Errorf("%w: failed to decode account %s: %w", ErrInvalidProof, proof.Address, err)
+	}
+	if !contract.Balance.Eq(proof.Balance) ||
+		contract.CodeHash != proof.CodeHash ||
+		uint64(contract.Seqno) != uint64(proof.Seqno) ||
+		uint64(contract.ExtSeqno) != uint64(proof.ExtSeqno) ||
+		contract.StorageRoot != proof.StorageHash ||
+		contract.TokenRoot != proof.TokenHash {
+		return fmt.Errorf("%w: account fields of %s don't match the proven account", ErrInvalidProof, proof.Address)
+	}
+
+	for _, p := range proof.StorageProof {
+		value, err := p.Value.MarshalSSZ()
+		if err != nil {
+			return err
+		}
+		if err := verifyReadValue(p.Proof, p.Key.Bytes(), value, p.Value.IsZero(), contract.StorageRoot); err != nil {
+			return fmt.Errorf("storage slot %s: %w", p.Key, err)
+		}
+	}
+
+	for _, p := range proof.TokenProof {
+		value, err := p.Value.MarshalSSZ()
+		if err != nil {
+			return err
+		}
+		if err := verifyReadValue(p.Proof, p.Token[:], value, p.Value.IsZero(), contract.TokenRoot); err != nil {
+			return fmt.Errorf("token %s: %w", p.Token, err)
+		}
+	}
+	return nil
+}
+
+// VerifyReceiptInclusionProof checks the proof returned by GetReceiptInclusionProof against the main block header
+// and returns the proven receipt. The header itself must come from a trusted source, e.g. be checked against
+// the signatures of the validators.
+func VerifyReceiptInclusionProof(proof *jsonrpc.RPCReceiptInclusionProof, mainBlock *types.Block) (*types.Receipt, error) {
+	if hash := mainBlock.Hash(types.MainShardId); hash != proof.MainBlockHash {
+		return nil, fmt.Errorf("%w: proof is for main block %s, header hash is %s", ErrInvalidProof, proof.MainBlockHash, hash)
+	}
+	if mainBlock.Id != proof.MainBlockNumber {
+		return nil, fmt.Errorf("%w: proof is for main block %d, header number is %d", ErrInvalidProof, proof.MainBlockNumber, mainBlock.Id)
+	}
+	if shardId := types.ShardIdFromHash(proof.TransactionHash); shardId != proof.ShardId {
+		return nil, fmt.Errorf("%w: transaction %s is not in the shard %d", ErrInvalidProof, proof.TransactionHash, proof.ShardId)
+	}
+	if len(proof.BlockHeaders) == 0 || len(proof.Receipt) == 0 {
+		return nil, fmt.Errorf("%w: no receipt or block headers", ErrInvalidProof)
+	}
+
+	// Each header must be the parent of the next one.
+	var first *types.Block
+	var lastHash common.Hash
+	for i, raw := range proof.BlockHeaders {
+		header := new(types.Block)
+		if err := header.UnmarshalSSZ(raw); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode block header %d: %w", ErrInvalidProof, i, err)
+		}
+		if first == nil {
+			first = header
+		} else if header.PrevBlock != lastHash || header.Id != first.Id+types.BlockNumber(i) {
+			return nil, fmt.Errorf("%w: block header %d is not the child of the previous one", ErrInvalidProof, i)
+		}
+		lastHash = header.Hash(proof.ShardId)
+	}
+
+	index := types.TransactionIndex(proof.ReceiptIndex)
+	if err := verifyRead(proof.ReceiptProof, index.Bytes(), proof.Receipt, first.ReceiptsRoot); err != nil {
+		return nil, fmt.Errorf("receipt %d of block %d: %w", index, first.Id, err)
+	}
+
+	if proof.ShardId.IsMainShard() {
+		if lastHash != proof.MainBlockHash {
+			return nil, fmt.Errorf("%w: the last block header is not the main block", ErrInvalidProof)
+		}
+	} else if err := verifyRead(proof.ShardBlockProof, proof.ShardId.Bytes(), lastHash.Bytes(), mainBlock.ChildBlocksRootHash); err != nil {
+		return nil, fmt.Errorf("block %s of shard %d: %w", lastHash, proof.ShardId, err)
+	}
+
+	receipt := new(types.Receipt)
+	if err := receipt.UnmarshalSSZ(proof.Receipt); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode receipt: %w", ErrInvalidProof, err)
+	}
+	if receipt.TxnHash != proof.TransactionHash {
+		return nil, fmt.Errorf("%w: receipt is for transaction %s", ErrInvalidProof, receipt.TxnHash)
+	}
+	return receipt, nil
+}
+
+// verifyReadValue checks the proof of the value. The zero value may be stored explicitly or be missing from the trie.
+func verifyReadValue(encodedProof, key, value []byte, isZero bool, root common.Hash) error {
+	if isZero {
+		if err := verifyRead(encodedProof, key, nil, root); err == nil {
+			return nil
+		}
+	}
+	return verifyRead(encodedProof, key, value, root)
+}
+
+// verifyRead checks the proof of the value, an empty value means that the key is missing.
+func verifyRead(encodedProof, key, value []byte, root common.Hash) error {
+	proof, err := mpt.DecodeProof(encodedProof)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	ok, err := proof.VerifyRead(key, value, root)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	if !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
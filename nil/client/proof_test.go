@@ -0,0 +1,245 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyProof(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	shardId := types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	addr := types.GenerateRandomAddress(shardId)
+	token := types.TokenId(types.GenerateRandomAddress(shardId))
+	slot := common.HexToHash("0x01")
+	value := common.HexToHash("0x1234")
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetCode(addr, []byte("some code")))
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(1234)))
+	require.NoError(t, es.SetState(addr, slot, value))
+	require.NoError(t, es.AddToken(addr, token, types.NewValueFromUint64(100)))
+
+	blockRes, err := es.Commit(0, nil)
+	require.NoError(t, err)
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes))
+	require.NoError(t, tx.Commit())
+
+	c, err := NewEthClient(ctx, database, 2, nil, logging.NewLogger("test"))
+	require.NoError(t, err)
+
+	missingSlot := common.HexToHash("0x02")
+	missingToken := types.TokenId(types.GenerateRandomAddress(shardId))
+	getProof := func(t *testing.T, addr types.Address) *jsonrpc.RPCAccountProof {
+		t.Helper()
+
+		proof, err := c.GetProof(ctx, addr, []common.Hash{slot, missingSlot}, []types.TokenId{token, missingToken},
+			transport.LatestBlockNumber)
+		require.NoError(t, err)
+		return proof
+	}
+
+	t.Run("Existing", func(t *testing.T) {
+		proof := getProof(t, addr)
+		require.NoError(t, VerifyProof(proof, blockRes.Block))
+
+		assert.Equal(t, types.NewValueFromUint64(1234), proof.Balance)
+		require.Len(t, proof.StorageProof, 2)
+		assert.Equal(t, types.Uint256(*value.Uint256()), proof.StorageProof[0].Value)
+		assert.True(t, proof.StorageProof[1].Value.IsZero())
+		require.Len(t, proof.TokenProof, 2)
+		assert.Equal(t, types.NewValueFromUint64(100), proof.TokenProof[0].Value)
+		assert.True(t, proof.TokenProof[1].Value.IsZero())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		proof := getProof(t, types.GenerateRandomAddress(shardId))
+		assert.Empty(t, proof.Contract)
+		require.NoError(t, VerifyProof(proof, blockRes.Block))
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tamper := func(f func(p *jsonrpc.RPCAccountProof)) error {
+			p := getProof(t, addr)
+			f(p)
+			return VerifyProof(p, blockRes.Block)
+		}
+
+		require.ErrorIs(t, tamper(func(p *jsonrpc.RPCAccountProof) {
+			p.Balance = types.NewValueFromUint64(1235)
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(func(p *jsonrpc.RPCAccountProof) {
+			p.StorageProof[0].Value = types.Uint256(*common.HexToHash("0x1235").Uint256())
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(func(p *jsonrpc.RPCAccountProof) {
+			p.StorageProof[1].Value = types.Uint256(*value.Uint256())
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(func(p *jsonrpc.RPCAccountProof) {
+			p.TokenProof[0].Value = types.NewValueFromUint64(1000)
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(func(p *jsonrpc.RPCAccountProof) {
+			p.AccountProof = p.AccountProof[:len(p.AccountProof)/2]
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(func(p *jsonrpc.RPCAccountProof) {
+			p.BlockNumber++
+		}), ErrInvalidProof)
+	})
+}
+
+func TestVerifyReceiptInclusionProof(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	// writeBlock writes the block with the receipts of the transactions on top of the previous block.
+	writeBlock := func(shardId types.ShardId, prev *execution.BlockGenerationResult, txnHashes ...common.Hash) *execution.BlockGenerationResult {
+		t.Helper()
+
+		receipts := execution.NewDbReceiptTrie(tx, shardId)
+		res := &execution.BlockGenerationResult{Block: &types.Block{}, InTxnHashes: txnHashes}
+		for i, hash := range txnHashes {
+			receipt := &types.Receipt{Success: true, TxnHash: hash, GasUsed: types.Gas(i + 1)}
+			require.NoError(t, receipts.Update(types.TransactionIndex(i), receipt))
+			res.Receipts = append(res.Receipts, receipt)
+		}
+		res.Block.ReceiptsRoot = receipts.RootHash()
+		if prev != nil {
+			res.Block.Id = prev.Block.Id + 1
+			res.Block.PrevBlock = prev.BlockHash
+		}
+		return res
+	}
+	commit := func(shardId types.ShardId, res *execution.BlockGenerationResult) {
+		t.Helper()
+
+		res.BlockHash = res.Block.Hash(shardId)
+		require.NoError(t, db.WriteBlock(tx, shardId, res.BlockHash, res.Block))
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, res))
+	}
+	referenceShardBlock := func(main, shardBlock *execution.BlockGenerationResult) {
+		t.Helper()
+
+		trie := execution.NewDbShardBlocksTrie(tx, types.MainShardId, main.Block.Id)
+		require.NoError(t, trie.Update(types.BaseShardId, &shardBlock.BlockHash))
+		main.Block.ChildBlocksRootHash = trie.RootHash()
+	}
+
+	shardTxn := types.ToShardedHash(common.HexToHash("0x1234"), types.BaseShardId)
+	mainTxn := types.ToShardedHash(common.HexToHash("0x5678"), types.MainShardId)
+
+	shard0 := writeBlock(types.BaseShardId, nil)
+	commit(types.BaseShardId, shard0)
+	shard1 := writeBlock(types.BaseShardId, shard0, common.HexToHash("0x01"), shardTxn)
+	commit(types.BaseShardId, shard1)
+	shard2 := writeBlock(types.BaseShardId, shard1)
+	commit(types.BaseShardId, shard2)
+
+	main0 := writeBlock(types.MainShardId, nil, mainTxn)
+	referenceShardBlock(main0, shard0)
+	commit(types.MainShardId, main0)
+	main1 := writeBlock(types.MainShardId, main0)
+	referenceShardBlock(main1, shard2)
+	commit(types.MainShardId, main1)
+
+	require.NoError(t, tx.Commit())
+
+	c, err := NewEthClient(ctx, database, 2, nil, logging.NewLogger("test"))
+	require.NoError(t, err)
+
+	getProof := func(t *testing.T, hash common.Hash) *jsonrpc.RPCReceiptInclusionProof {
+		t.Helper()
+
+		proof, err := c.GetReceiptInclusionProof(ctx, hash, main1.BlockHash)
+		require.NoError(t, err)
+		return proof
+	}
+
+	t.Run("Shard", func(t *testing.T) {
+		proof := getProof(t, shardTxn)
+		assert.Len(t, proof.BlockHeaders, 2)
+		assert.NotEmpty(t, proof.ShardBlockProof)
+
+		receipt, err := VerifyReceiptInclusionProof(proof, main1.Block)
+		require.NoError(t, err)
+		assert.Equal(t, shardTxn, receipt.TxnHash)
+		assert.Equal(t, types.Gas(2), receipt.GasUsed)
+	})
+
+	t.Run("MainShard", func(t *testing.T) {
+		proof := getProof(t, mainTxn)
+		assert.Len(t, proof.BlockHeaders, 2)
+		assert.Empty(t, proof.ShardBlockProof)
+
+		receipt, err := VerifyReceiptInclusionProof(proof, main1.Block)
+		require.NoError(t, err)
+		assert.Equal(t, mainTxn, receipt.TxnHash)
+	})
+
+	t.Run("NotIncluded", func(t *testing.T) {
+		_, err := c.GetReceiptInclusionProof(ctx, shardTxn, main0.BlockHash)
+		require.ErrorContains(t, err, "not included in the main block")
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tamper := func(hash common.Hash, f func(p *jsonrpc.RPCReceiptInclusionProof)) error {
+			p := getProof(t, hash)
+			f(p)
+			_, err := VerifyReceiptInclusionProof(p, main1.Block)
+			return err
+		}
+
+		_, err := VerifyReceiptInclusionProof(getProof(t, shardTxn), main0.Block)
+		require.ErrorIs(t, err, ErrInvalidProof)
+
+		require.ErrorIs(t, tamper(shardTxn, func(p *jsonrpc.RPCReceiptInclusionProof) {
+			p.Receipt[len(p.Receipt)-1] ^= 1
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(shardTxn, func(p *jsonrpc.RPCReceiptInclusionProof) {
+			p.BlockHeaders = p.BlockHeaders[1:]
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(shardTxn, func(p *jsonrpc.RPCReceiptInclusionProof) {
+			p.BlockHeaders = p.BlockHeaders[:1]
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(shardTxn, func(p *jsonrpc.RPCReceiptInclusionProof) {
+			p.ReceiptIndex = 0
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(shardTxn, func(p *jsonrpc.RPCReceiptInclusionProof) {
+			p.ShardBlockProof = nil
+		}), ErrInvalidProof)
+		require.ErrorIs(t, tamper(mainTxn, func(p *jsonrpc.RPCReceiptInclusionProof) {
+			p.BlockHeaders = p.BlockHeaders[:1]
+		}), ErrInvalidProof)
+	})
+}
@@ -21,7 +21,9 @@ import (
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/rs/zerolog"
@@ -67,18 +69,25 @@ const (
 	Eth_sendRawTransaction               = "eth_sendRawTransaction"
 	Eth_getInTransactionByHash           = "eth_getInTransactionByHash"
 	Eth_getInTransactionReceipt          = "eth_getInTransactionReceipt"
+	Eth_getReceiptInclusionProof         = "eth_getReceiptInclusionProof"
 	Eth_getTransactionCount              = "eth_getTransactionCount"
 	Eth_getBlockTransactionCountByNumber = "eth_getBlockTransactionCountByNumber"
 	Eth_getBlockTransactionCountByHash   = "eth_getBlockTransactionCountByHash"
 	Eth_getBalance                       = "eth_getBalance"
+	Eth_getProof                         = "eth_getProof"
 	Eth_getTokens                        = "eth_getTokens" //nolint:gosec
 	Eth_getShardIdList                   = "eth_getShardIdList"
 	Eth_getNumShards                     = "eth_getNumShards"
 	Eth_gasPrice                         = "eth_gasPrice"
+	Eth_feeHistory                       = "eth_feeHistory"
+	Eth_getLogs                          = "eth_getLogs"
 	Eth_chainId                          = "eth_chainId"
 	Debug_getBlockByHash                 = "debug_getBlockByHash"
 	Debug_getBlockByNumber               = "debug_getBlockByNumber"
 	Debug_getContract                    = "debug_getContract"
+	Debug_getTransactionTree             = "debug_getTransactionTree"
+	Debug_traceTransaction               = "debug_traceTransaction"
+	Debug_traceCall                      = "debug_traceCall"
 )
 
 const (
@@ -533,6 +542,39 @@ func (c *Client) GetInTransactionReceipt(ctx context.Context, hash common.Hash)
 	return receipt, nil
 }
 
+func (c *Client) GetReceiptInclusionProof(
+	ctx context.Context, hash common.Hash, mainBlockId any,
+) (*jsonrpc.RPCReceiptInclusionProof, error) {
+	blockNrOrHash, err := transport.AsBlockReference(mainBlockId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.call(ctx, Eth_getReceiptInclusionProof, hash, transport.BlockNumberOrHash(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+
+	var proof *jsonrpc.RPCReceiptInclusionProof
+	if err := json.Unmarshal(res, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+func (c *Client) GetTransactionTree(ctx context.Context, hash common.Hash) (*jsonrpc.RPCTransactionTreeNode, error) {
+	res, err := c.call(ctx, Debug_getTransactionTree, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree *jsonrpc.RPCTransactionTreeNode
+	if err := json.Unmarshal(res, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
 func (c *Client) GetTransactionCount(ctx context.Context, address types.Address, blockId any) (types.Seqno, error) {
 	blockNrOrHash, err := transport.AsBlockReference(blockId)
 	if err != nil {
@@ -612,6 +654,29 @@ func (c *Client) GetBalance(ctx context.Context, address types.Address, blockId
 	return types.NewValueFromBigMust(bigVal.ToInt()), nil
 }
 
+func (c *Client) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockId any,
+) (*jsonrpc.RPCAccountProof, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+
+	if storageKeys == nil {
+		storageKeys = []common.Hash{}
+	}
+	res, err := c.call(ctx, Eth_getProof, address.String(), storageKeys, transport.BlockNumberOrHash(blockNrOrHash), tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	var proof *jsonrpc.RPCAccountProof
+	if err := json.Unmarshal(res, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
 func (c *Client) GetTokens(ctx context.Context, address types.Address, blockId any) (types.TokensMap, error) {
 	blockNrOrHash, err := transport.AsBlockReference(blockId)
 	if err != nil {
@@ -642,6 +707,44 @@ func (c *Client) GasPrice(ctx context.Context, shardId types.ShardId) (types.Val
 	return val, nil
 }
 
+func (c *Client) FeeHistory(
+	ctx context.Context, shardId types.ShardId, blockCount uint64, newestBlockId any, rewardPercentiles []float64,
+) (*jsonrpc.FeeHistory, error) {
+	blockNrOrHash, err := transport.AsBlockReference(newestBlockId)
+	if err != nil {
+		return nil, err
+	}
+	if blockNrOrHash.BlockNumber == nil {
+		return nil, errors.New("fee history requires a block number")
+	}
+
+	res, err := c.call(ctx, Eth_feeHistory, shardId, hexutil.Uint64(blockCount), *blockNrOrHash.BlockNumber, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var history *jsonrpc.FeeHistory
+	if err := json.Unmarshal(res, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *Client) GetLogs(
+	ctx context.Context, shardId types.ShardId, query *filters.FilterQuery,
+) ([]*jsonrpc.RPCLog, error) {
+	res, err := c.call(ctx, Eth_getLogs, shardId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []*jsonrpc.RPCLog
+	if err := json.Unmarshal(res, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
 func (c *Client) ChainId(ctx context.Context) (types.ChainId, error) {
 	res, err := c.call(ctx, Eth_chainId)
 	if err != nil {
@@ -940,3 +1043,17 @@ func (c *Client) GetDebugContract(ctx context.Context, contractAddr types.Addres
 
 	return DebugRPCContract, err
 }
+
+func (c *Client) TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error) {
+	return c.call(ctx, Debug_traceTransaction, hash, cfg)
+}
+
+func (c *Client) TraceCall(
+	ctx context.Context, args *jsonrpc.CallArgs, blockId any, stateOverride *jsonrpc.StateOverrides, cfg *tracers.Config,
+) (json.RawMessage, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.call(ctx, Debug_traceCall, args, blockNrOrHash, stateOverride, cfg)
+}
@@ -0,0 +1,378 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+const (
+	Eth_subscribe   = "eth_subscribe"
+	Eth_unsubscribe = "eth_unsubscribe"
+)
+
+const subscriptionBufferSize = 100
+
+var (
+	ErrConnectionClosed          = newCallErr("connection closed")
+	ErrSubscriptionQueueOverflow = newCallErr("subscription queue overflow")
+)
+
+// WsClient is a JSON-RPC client working over a WebSocket connection.
+// Unlike Client, it can receive notifications from the server.
+type WsClient struct {
+	conn    *websocket.Conn
+	seqno   atomic.Uint64
+	logger  zerolog.Logger
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingCall
+	subs    map[transport.SubscriptionID]subscriptionSink
+	err     error
+	closed  chan struct{}
+}
+
+type pendingCall struct {
+	ch       chan wsResponse
+	onResult func(json.RawMessage) error
+}
+
+type wsResponse struct {
+	result json.RawMessage
+	err    error
+}
+
+type wsMessage struct {
+	Id     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+type wsNotification struct {
+	Subscription transport.SubscriptionID `json:"subscription"`
+	Result       json.RawMessage          `json:"result"`
+}
+
+// subscriptionSink is the type-erased receiving side of Subscription.
+type subscriptionSink interface {
+	deliver(data json.RawMessage) bool
+	fail(err error)
+}
+
+// Subscription is a stream of notifications of type T.
+// Notifications must be consumed in time, otherwise the subscription is terminated
+// with ErrSubscriptionQueueOverflow.
+type Subscription[T any] struct {
+	ID     transport.SubscriptionID
+	client *WsClient
+	ch     chan T
+	errCh  chan error
+
+	// mu guards the channels: notifications are delivered by the read loop
+	// while the subscription may be terminated concurrently by Unsubscribe.
+	mu   sync.Mutex
+	done bool
+}
+
+// Notifications returns the channel of received notifications.
+// It is closed when the subscription is terminated.
+func (s *Subscription[T]) Notifications() <-chan T {
+	return s.ch
+}
+
+// Err returns the channel that receives the error terminating the subscription.
+// It is closed without a value after Unsubscribe.
+func (s *Subscription[T]) Err() <-chan error {
+	return s.errCh
+}
+
+// Unsubscribe cancels the subscription on the server and closes its channels.
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	if !s.client.removeSubscription(s.ID) {
+		return nil
+	}
+	s.fail(nil)
+
+	_, err := s.client.call(ctx, Eth_unsubscribe, s.ID)
+	if errors.Is(err, ErrConnectionClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Subscription[T]) deliver(data json.RawMessage) bool {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		s.fail(fmt.Errorf("%w: %w", ErrFailedToUnmarshalResponse, err))
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return false
+	}
+	select {
+	case s.ch <- value:
+		return true
+	default:
+		s.failLocked(ErrSubscriptionQueueOverflow)
+		return false
+	}
+}
+
+func (s *Subscription[T]) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failLocked(err)
+}
+
+func (s *Subscription[T]) failLocked(err error) {
+	if s.done {
+		return
+	}
+	s.done = true
+	if err != nil {
+		s.errCh <- err
+	}
+	close(s.errCh)
+	close(s.ch)
+}
+
+// DialWebsocket connects to the WebSocket endpoint of a node.
+// The endpoint may use ws://, wss://, http://, https:// or tcp:// scheme.
+func DialWebsocket(ctx context.Context, endpoint string, logger zerolog.Logger) (*WsClient, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, toWebsocketEndpoint(endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToSendRequest, err)
+	}
+
+	c := &WsClient{
+		conn:    conn,
+		logger:  logger,
+		pending: make(map[uint64]*pendingCall),
+		subs:    make(map[transport.SubscriptionID]subscriptionSink),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+func toWebsocketEndpoint(endpoint string) string {
+	switch {
+	case strings.HasPrefix(endpoint, "http://"):
+		return "ws://" + strings.TrimPrefix(endpoint, "http://")
+	case strings.HasPrefix(endpoint, "https://"):
+		return "wss://" + strings.TrimPrefix(endpoint, "https://")
+	case strings.HasPrefix(endpoint, "tcp://"):
+		return "ws://" + strings.TrimPrefix(endpoint, "tcp://")
+	}
+	return endpoint
+}
+
+// Close closes the connection and terminates all subscriptions.
+func (c *WsClient) Close() error {
+	err := c.conn.Close()
+	<-c.closed
+	return err
+}
+
+// SubscribeNewHeads subscribes to headers of new blocks of the shard.
+func (c *WsClient) SubscribeNewHeads(
+	ctx context.Context, shardId types.ShardId,
+) (*Subscription[*jsonrpc.RPCBlock], error) {
+	return subscribe[*jsonrpc.RPCBlock](ctx, c, shardId, jsonrpc.NewHeadsSubscription, nil)
+}
+
+// SubscribeLogs subscribes to logs of new blocks of the shard matching the query.
+func (c *WsClient) SubscribeLogs(
+	ctx context.Context, shardId types.ShardId, query filters.FilterQuery,
+) (*Subscription[*jsonrpc.RPCLog], error) {
+	return subscribe[*jsonrpc.RPCLog](ctx, c, shardId, jsonrpc.LogsSubscription, &query)
+}
+
+// SubscribePendingTransactions subscribes to hashes of transactions added to the pool of the shard.
+func (c *WsClient) SubscribePendingTransactions(
+	ctx context.Context, shardId types.ShardId,
+) (*Subscription[common.Hash], error) {
+	return subscribe[common.Hash](ctx, c, shardId, jsonrpc.NewPendingTransactionsSubscription, nil)
+}
+
+func subscribe[T any](
+	ctx context.Context, c *WsClient, shardId types.ShardId, kind string, query *filters.FilterQuery,
+) (*Subscription[T], error) {
+	params := []any{shardId, kind}
+	if query != nil {
+		params = append(params, query)
+	}
+
+	// The subscription has to be registered before the response is processed,
+	// because the server may send notifications right after it.
+	sub := &Subscription[T]{
+		client: c,
+		ch:     make(chan T, subscriptionBufferSize),
+		errCh:  make(chan error, 1),
+	}
+	res, err := c.callWith(ctx, Eth_subscribe, func(result json.RawMessage) error {
+		if err := json.Unmarshal(result, &sub.ID); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToUnmarshalResponse, err)
+		}
+		c.subs[sub.ID] = sub
+		return nil
+	}, params...)
+	if err != nil {
+		return nil, err
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	return sub, nil
+}
+
+func (c *WsClient) call(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+	res, err := c.callWith(ctx, method, nil, params...)
+	if err != nil {
+		return nil, err
+	}
+	return res.result, res.err
+}
+
+// callWith sends the request and waits for the response. If onResult is set, it is called
+// for a successful response under the client lock, before any subsequent message is processed.
+func (c *WsClient) callWith(
+	ctx context.Context, method string, onResult func(json.RawMessage) error, params ...any,
+) (wsResponse, error) {
+	request := NewRequest(c.seqno.Add(1), method, params)
+	call := &pendingCall{ch: make(chan wsResponse, 1), onResult: onResult}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return wsResponse{}, c.err
+	}
+	c.pending[request.Id] = call
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, request.Id)
+		c.mu.Unlock()
+	}()
+
+	c.logger.Trace().Any("request", request).Send()
+	c.writeMu.Lock()
+	err := c.conn.WriteJSON(request)
+	c.writeMu.Unlock()
+	if err != nil {
+		return wsResponse{}, fmt.Errorf("%w: %w", ErrFailedToSendRequest, err)
+	}
+
+	select {
+	case res := <-call.ch:
+		return res, nil
+	case <-ctx.Done():
+		return wsResponse{}, ctx.Err()
+	}
+}
+
+func (c *WsClient) removeSubscription(id transport.SubscriptionID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.subs[id]
+	delete(c.subs, id)
+	return ok
+}
+
+// readLoop dispatches responses to pending calls and notifications to subscriptions.
+// It never blocks on slow consumers.
+func (c *WsClient) readLoop() {
+	defer close(c.closed)
+
+	for {
+		var msg wsMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			c.terminate(err)
+			return
+		}
+
+		switch {
+		case msg.Id != nil:
+			c.handleResponse(*msg.Id, &msg)
+		case strings.HasSuffix(msg.Method, "_subscription"):
+			c.handleNotification(msg.Params)
+		default:
+			c.logger.Debug().Str("method", msg.Method).Msg("Ignoring unexpected message")
+		}
+	}
+}
+
+func (c *WsClient) handleResponse(id uint64, msg *wsMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call, ok := c.pending[id]
+	if !ok {
+		return
+	}
+	var res wsResponse
+	if msg.Error != nil {
+		res.err = fmt.Errorf("%w: %s", ErrRPCError, msg.Error)
+	} else {
+		res.result = msg.Result
+		if call.onResult != nil {
+			res.err = call.onResult(msg.Result)
+		}
+	}
+	call.ch <- res
+}
+
+func (c *WsClient) handleNotification(params json.RawMessage) {
+	var n wsNotification
+	if err := json.Unmarshal(params, &n); err != nil {
+		c.logger.Debug().Err(err).Msg("Failed to unmarshal notification")
+		return
+	}
+
+	c.mu.Lock()
+	sub, ok := c.subs[n.Subscription]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	if !sub.deliver(n.Result) {
+		// The subscription is already terminated locally, so there's nobody to report the error to.
+		if c.removeSubscription(n.Subscription) {
+			go func() {
+				_, _ = c.call(context.Background(), Eth_unsubscribe, n.Subscription)
+			}()
+		}
+	}
+}
+
+func (c *WsClient) terminate(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = fmt.Errorf("%w: %w", ErrConnectionClosed, err)
+	for _, call := range c.pending {
+		call.ch <- wsResponse{err: c.err}
+	}
+	for id, sub := range c.subs {
+		sub.fail(c.err)
+		delete(c.subs, id)
+	}
+}
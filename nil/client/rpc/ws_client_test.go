@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wsWaitTimeout = 5 * time.Second
+
+// testEthSubscriptionService sends the given number of hashes to each new subscription,
+// or keeps sending them until the subscription is cancelled if the number is negative.
+type testEthSubscriptionService struct {
+	notifications int
+}
+
+func (s *testEthSubscriptionService) Subscribe(
+	ctx context.Context, shardId types.ShardId, kind string, query *filters.FilterQuery,
+) (transport.SubscriptionID, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return "", transport.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := 0; s.notifications < 0 || i < s.notifications; i++ {
+			if err := notifier.Notify(sub, common.IntToHash(i)); err != nil {
+				return
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (s *testEthSubscriptionService) Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return false, transport.ErrNotificationsUnsupported
+	}
+	if err := notifier.Unsubscribe(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func startWsServer(t *testing.T, notifications int) (*transport.Server, *httptest.Server) {
+	t.Helper()
+
+	server := transport.NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	t.Cleanup(server.Stop)
+	require.NoError(t, server.RegisterName("eth", &testEthSubscriptionService{notifications: notifications}))
+
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(httpServer.Close)
+	return server, httpServer
+}
+
+func dialWsServer(t *testing.T, httpServer *httptest.Server) *WsClient {
+	t.Helper()
+
+	client, err := DialWebsocket(context.Background(), httpServer.URL, logging.NewLogger("Test client"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func requireClosed[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+
+	timeout := time.After(wsWaitTimeout)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			require.Fail(t, "channel is not closed")
+		}
+	}
+}
+
+func TestWsClientSubscription(t *testing.T) {
+	t.Parallel()
+
+	_, httpServer := startWsServer(t, 3)
+	client := dialWsServer(t, httpServer)
+	ctx := context.Background()
+
+	sub, err := client.SubscribePendingTransactions(ctx, types.MainShardId)
+	require.NoError(t, err)
+	require.NotEmpty(t, sub.ID)
+
+	for i := range 3 {
+		select {
+		case hash := <-sub.Notifications():
+			assert.Equal(t, common.IntToHash(i), hash)
+		case <-time.After(wsWaitTimeout):
+			require.Fail(t, "notification is not received")
+		}
+	}
+
+	require.NoError(t, sub.Unsubscribe(ctx))
+	requireClosed(t, sub.Notifications())
+	_, ok := <-sub.Err()
+	require.False(t, ok, "error must not be reported after Unsubscribe")
+
+	// The second call is a no-op.
+	require.NoError(t, sub.Unsubscribe(ctx))
+}
+
+func TestWsClientQueueOverflow(t *testing.T) {
+	t.Parallel()
+
+	_, httpServer := startWsServer(t, 2*subscriptionBufferSize)
+	client := dialWsServer(t, httpServer)
+
+	sub, err := client.SubscribePendingTransactions(context.Background(), types.MainShardId)
+	require.NoError(t, err)
+
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, ErrSubscriptionQueueOverflow)
+	case <-time.After(wsWaitTimeout):
+		require.Fail(t, "subscription is not terminated")
+	}
+	requireClosed(t, sub.Notifications())
+}
+
+func TestWsClientConnectionClosed(t *testing.T) {
+	t.Parallel()
+
+	server, httpServer := startWsServer(t, 0)
+	client := dialWsServer(t, httpServer)
+
+	sub, err := client.SubscribePendingTransactions(context.Background(), types.MainShardId)
+	require.NoError(t, err)
+
+	// Stopping the server closes its WebSocket connections.
+	server.Stop()
+
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, ErrConnectionClosed)
+	case <-time.After(wsWaitTimeout):
+		require.Fail(t, "subscription is not terminated")
+	}
+	requireClosed(t, sub.Notifications())
+
+	_, err = client.SubscribePendingTransactions(context.Background(), types.MainShardId)
+	require.ErrorIs(t, err, ErrConnectionClosed)
+}
+
+func TestWsClientUnsubscribeWhileDelivering(t *testing.T) {
+	t.Parallel()
+
+	_, httpServer := startWsServer(t, -1)
+	client := dialWsServer(t, httpServer)
+	ctx := context.Background()
+
+	subs := make([]*Subscription[common.Hash], 10)
+	for i := range subs {
+		var err error
+		subs[i], err = client.SubscribePendingTransactions(ctx, types.MainShardId)
+		require.NoError(t, err)
+	}
+
+	// Notifications keep arriving while the subscriptions are cancelled,
+	// the client must neither panic nor block.
+	var wg sync.WaitGroup
+	for _, sub := range subs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-sub.Notifications()
+			assert.NoError(t, sub.Unsubscribe(ctx))
+		}()
+	}
+	wg.Wait()
+
+	for _, sub := range subs {
+		requireClosed(t, sub.Notifications())
+	}
+}
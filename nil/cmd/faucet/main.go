@@ -10,6 +10,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/services/faucet"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 type Command uint
@@ -19,9 +20,11 @@ const (
 )
 
 type config struct {
-	command  Command
-	port     int
-	endpoint string
+	command    Command
+	port       int
+	endpoint   string
+	configPath string
+	dbPath     string
 }
 
 func main() {
@@ -44,11 +47,36 @@ func processRun(cfg *config) error {
 	addr := fmt.Sprintf("tcp://127.0.0.1:%d", cfg.port)
 	client := rpc_client.NewClient(cfg.endpoint, logging.NewLogger("faucet"))
 
-	serviceFaucet, err := faucet.NewService(client)
+	faucetCfg, err := loadFaucetConfig(cfg.configPath)
 	if err != nil {
 		return err
 	}
-	return serviceFaucet.Run(context.Background(), addr)
+	if cfg.dbPath != "" {
+		faucetCfg.DbPath = cfg.dbPath
+	}
+
+	ctx := context.Background()
+	serviceFaucet, err := faucet.NewService(ctx, faucetCfg, client)
+	if err != nil {
+		return err
+	}
+	return serviceFaucet.Run(ctx, addr)
+}
+
+func loadFaucetConfig(name string) (*faucet.Config, error) {
+	cfg := faucet.NewDefaultConfig()
+	if name == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("can't read config %s: %w", name, err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("can't parse config %s: %w", name, err)
+	}
+	return cfg, nil
 }
 
 func parseArgs() *config {
@@ -61,6 +89,8 @@ func parseArgs() *config {
 	}
 	rootCmd.PersistentFlags().StringVar(&cfg.endpoint, "node-endpoint", "http://127.0.0.1:8529", "nil node endpoint")
 	rootCmd.PersistentFlags().IntVar(&cfg.port, "port", 8527, "http service port")
+	rootCmd.PersistentFlags().StringVar(&cfg.configPath, "config", "", "path to the yaml config with the limits of the faucets")
+	rootCmd.PersistentFlags().StringVar(&cfg.dbPath, "db-path", "", "path to the db with the record of the grants (overrides the config)")
 
 	runCmd := &cobra.Command{
 		Use:   "run",
@@ -18,6 +18,7 @@ func GetCommand() *cobra.Command {
 	}
 	cmd.AddCommand(GetInfoCommand())
 	cmd.AddCommand(GetRegisterCommand())
+	cmd.AddCommand(GetVerifyCommand())
 
 	return cmd
 }
@@ -42,6 +43,28 @@ func GetRegisterCommand() *cobra.Command {
 	return cmd
 }
 
+func GetVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [options]",
+		Short: "Verify that the compiled contract matches the deployed bytecode and register its metadata",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVerifyCommand(cmd)
+		},
+	}
+
+	cmd.Flags().Var(&params.address, "address", "The contract address")
+	cmd.Flags().StringVar(&params.inputJsonFile, "compile-input", "", "The JSON file with the compilation input")
+	for _, flag := range []string{"address", "compile-input"} {
+		if err := cmd.MarkFlagRequired(flag); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	return cmd
+}
+
 func GetInfoCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "info",
@@ -85,6 +108,29 @@ func runRegisterCommand(_ *cobra.Command) error {
 	return nil
 }
 
+func runVerifyCommand(_ *cobra.Command) error {
+	cometaClient := common.GetCometaRpcClient()
+
+	inputJsonData, err := os.ReadFile(params.inputJsonFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the input JSON file: %w", err)
+	}
+
+	inputJson, err := normalizeCompileInput(string(inputJsonData), params.inputJsonFile)
+	if err != nil {
+		return fmt.Errorf("failed to normalize the input JSON file: %w", err)
+	}
+
+	status, err := cometaClient.VerifyContract(inputJson, params.address)
+	if err != nil {
+		return fmt.Errorf("failed to verify the contract: %w", err)
+	}
+
+	fmt.Printf("Contract at address %s has been verified, match: %s\n", params.address, status)
+
+	return nil
+}
+
 func normalizeCompileInput(inputJson, inputJsonFile string) (string, error) {
 	var input cometa.CompilerTask
 	if err := json.Unmarshal([]byte(inputJson), &input); err != nil {
@@ -120,6 +166,7 @@ func runInfoCommand(_ *cobra.Command) error {
 	} else {
 		fmt.Printf("Contract metadata for address %s\n", params.address)
 		fmt.Printf("  Name: %s\n", contract.Name)
+		fmt.Printf("  Verification: %s\n", contract.Verification)
 		if len(contract.Description) > 0 {
 			fmt.Printf("  Description:\n%s\n", contract.Description)
 		}
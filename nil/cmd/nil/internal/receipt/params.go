@@ -0,0 +1,15 @@
+package receipt
+
+const (
+	treeFlag    = "tree"
+	jsonFlag    = "json"
+	noColorFlag = "no-color"
+)
+
+var params = &receiptParams{}
+
+type receiptParams struct {
+	tree       bool
+	jsonOutput bool
+	noColor    bool
+}
@@ -22,9 +22,18 @@ func GetCommand(cfg *common.Config) *cobra.Command {
 		SilenceUsage: true,
 	}
 
+	setFlags(serverCmd)
+
 	return serverCmd
 }
 
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&params.tree, treeFlag, false,
+		"Show the tree of transactions caused by the transaction across all shards, including pending ones")
+	cmd.Flags().BoolVar(&params.jsonOutput, jsonFlag, false, "Enable JSON output of the tree")
+	cmd.Flags().BoolVar(&params.noColor, noColorFlag, false, "Do not colorize the tree")
+}
+
 func runCommand(cmd *cobra.Command, args []string) error {
 	service := cliservice.NewService(cmd.Context(), common.GetRpcClient(), nil, nil)
 
@@ -33,6 +42,16 @@ func runCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if hash != libcommon.EmptyHash && params.tree {
+		tree, err := service.FetchTransactionTreeText(hash, params.jsonOutput, params.noColor)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to fetch the transaction tree")
+			return err
+		}
+		fmt.Println(string(tree))
+		return nil
+	}
+
 	if hash != libcommon.EmptyHash {
 		receipt, err := service.FetchReceiptByHashJson(hash)
 		if err != nil {
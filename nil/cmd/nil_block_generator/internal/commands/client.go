@@ -23,9 +23,9 @@ import (
 )
 
 func backgroundNilNode(cfg *nildconfig.Config) {
-	database, err := db.NewBadgerDb(cfg.DB.Path)
+	database, err := db.NewDb(cfg.DB.Engine, cfg.DB.Path)
 	if err != nil {
-		fmt.Printf("failed to create new BadgerDB\n")
+		fmt.Printf("failed to create new DB\n")
 		return
 	}
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
@@ -63,7 +63,7 @@ func waitStartNil(rpcEndpoint string) error {
 func RunNilNode(rpcEndpoint string) error {
 	cfg := &nildconfig.Config{
 		Config: nilservice.NewDefaultConfig(),
-		DB:     db.NewDefaultBadgerDBOptions(),
+		DB:     db.NewDefaultDBOptions(),
 		ReadThrough: &nildconfig.ReadThroughOptions{
 			ForkMainAtBlock: transport.LatestBlockNumber,
 		},
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/NilFoundation/nil/nil/cmd/nild/nildconfig"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func DbCommand(cfg *nildconfig.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and repair the database of a stopped node",
+	}
+
+	var (
+		shardId     = types.BaseShardId
+		blockNumber types.BlockNumber
+		address     string
+	)
+	// run opens the DB, calls f and exits, so that the DB is closed before the deferred calls of the node.
+	run := func(f func(ctx context.Context, database db.DB, out io.Writer) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			database, err := openExistingDb(cfg)
+			if err != nil {
+				return err
+			}
+			err = f(cmd.Context(), database, cmd.OutOrStdout())
+			database.Close()
+			if err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		}
+	}
+	// blockFlag returns the block selected by the flag or the last block of the shard.
+	blockFlag := func(cmd *cobra.Command) *types.BlockNumber {
+		if cmd.Flags().Changed("block") {
+			return &blockNumber
+		}
+		return nil
+	}
+	addBlockFlags := func(cmd *cobra.Command) {
+		cmd.Flags().Var(&shardId, "shard-id", "shard id")
+		cmd.Flags().Var(&blockNumber, "block", "block number (the latest one by default)")
+	}
+
+	tablesCmd := &cobra.Command{
+		Use:          "tables",
+		Short:        "List the tables with the numbers of the keys and the sizes of the keys and the values",
+		SilenceUsage: true,
+		RunE:         run(printTableStats),
+	}
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print the decoded data of a shard as JSON",
+	}
+	dumpBlockCmd := &cobra.Command{
+		Use:          "block",
+		Short:        "Print the block with its transactions",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, database db.DB, out io.Writer) error {
+				return dumpBlock(ctx, database, shardId, blockFlag(cmd), out)
+			})(cmd, args)
+		},
+	}
+	addBlockFlags(dumpBlockCmd)
+	dumpReceiptsCmd := &cobra.Command{
+		Use:          "receipts",
+		Short:        "Print the receipts of the block",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, database db.DB, out io.Writer) error {
+				return dumpReceipts(ctx, database, shardId, blockFlag(cmd), out)
+			})(cmd, args)
+		},
+	}
+	addBlockFlags(dumpReceiptsCmd)
+	dumpAccountsCmd := &cobra.Command{
+		Use:          "accounts",
+		Short:        "Print the accounts of the shard at the block",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, database db.DB, out io.Writer) error {
+				return dumpAccounts(ctx, database, shardId, blockFlag(cmd), address, out)
+			})(cmd, args)
+		},
+	}
+	addBlockFlags(dumpAccountsCmd)
+	dumpAccountsCmd.Flags().StringVar(&address, "address", "", "print only the account with the address")
+	dumpCmd.AddCommand(dumpBlockCmd, dumpReceiptsCmd, dumpAccountsCmd)
+
+	checkCmd := &cobra.Command{
+		Use:          "check",
+		Short:        "Check that the tries and the codes of the last blocks of all the shards are complete",
+		SilenceUsage: true,
+		RunE:         run(checkLastBlocks),
+	}
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Make the block the last one of the shard and remove the newer blocks",
+		Long: "Make the block the last one of the shard and remove the newer blocks. " +
+			"The blocks referenced by the last blocks of the other shards can't be removed, " +
+			"so the referencing shards must be rolled back first. " +
+			"The collator state is not rolled back, so the shard must be synchronized from the peers afterwards.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, database db.DB, out io.Writer) error {
+				return rollbackShard(ctx, database, shardId, blockNumber)
+			})(cmd, args)
+		},
+	}
+	rollbackCmd.Flags().Var(&shardId, "shard-id", "shard id to roll back")
+	rollbackCmd.Flags().Var(&blockNumber, "block", "block to make the last one")
+	check.PanicIfErr(rollbackCmd.MarkFlagRequired("block"))
+
+	cmd.AddCommand(tablesCmd, dumpCmd, checkCmd, rollbackCmd)
+	return cmd
+}
+
+// openExistingDb opens the DB without creating an empty one at a wrong path.
+func openExistingDb(cfg *nildconfig.Config) (db.DB, error) {
+	if _, err := os.Stat(cfg.DB.Path); err != nil {
+		return nil, fmt.Errorf("failed to open the database: %w", err)
+	}
+	return openDb(cfg.DB, false, logging.NewLogger("db"))
+}
+
+func printJson(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printTableStats(ctx context.Context, database db.DB, out io.Writer) error {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stats, err := db.CollectTableStats(tx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TABLE\tKEYS\tSIZE\t")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t\n", s.Name, s.Keys, s.Size)
+	}
+	return w.Flush()
+}
+
+// readDumpBlock reads the block with the number or the last block of the shard.
+func readDumpBlock(
+	tx db.RoTx, shardId types.ShardId, blockNumber *types.BlockNumber,
+) (*types.Block, common.Hash, error) {
+	if blockNumber == nil {
+		block, hash, err := db.ReadLastBlock(tx, shardId)
+		if err != nil {
+			return nil, common.EmptyHash, fmt.Errorf("failed to read the last block of shard %d: %w", shardId, err)
+		}
+		return block, hash, nil
+	}
+
+	hash, err := db.ReadBlockHashByNumber(tx, shardId, *blockNumber)
+	if err != nil {
+		return nil, common.EmptyHash, fmt.Errorf("failed to read block %d of shard %d: %w", *blockNumber, shardId, err)
+	}
+	block, err := db.ReadBlock(tx, shardId, hash)
+	if err != nil {
+		return nil, common.EmptyHash, fmt.Errorf("failed to read block %s of shard %d: %w", hash, shardId, err)
+	}
+	return block, hash, nil
+}
+
+func dumpBlock(
+	ctx context.Context, database db.DB, shardId types.ShardId, blockNumber *types.BlockNumber, out io.Writer,
+) error {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	block, hash, err := readDumpBlock(tx, shardId, blockNumber)
+	if err != nil {
+		return err
+	}
+
+	readTxns := func(root common.Hash) ([]*types.Transaction, error) {
+		reader := execution.NewDbTransactionTrieReader(tx, shardId)
+		reader.SetRootHash(root)
+		entries, err := reader.Entries()
+		if err != nil {
+			return nil, err
+		}
+		return orderByIndex(entries)
+	}
+	inTxns, err := readTxns(block.InTransactionsRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read inbound transactions: %w", err)
+	}
+	outTxns, err := readTxns(block.OutTransactionsRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read outbound transactions: %w", err)
+	}
+
+	var childBlocks []common.Hash
+	if shardId.IsMainShard() {
+		reader := execution.NewDbShardBlocksTrieReader(tx, shardId, block.Id)
+		reader.SetRootHash(block.ChildBlocksRootHash)
+		entries, err := reader.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read child blocks: %w", err)
+		}
+		for _, entry := range entries {
+			childBlocks = append(childBlocks, *entry.Val)
+		}
+	}
+
+	return printJson(out, struct {
+		Hash            common.Hash          `json:"hash"`
+		Block           *types.Block         `json:"block"`
+		InTransactions  []*types.Transaction `json:"inTransactions"`
+		OutTransactions []*types.Transaction `json:"outTransactions"`
+		ChildBlocks     []common.Hash        `json:"childBlocks,omitempty"`
+	}{hash, block, inTxns, outTxns, childBlocks})
+}
+
+func dumpReceipts(
+	ctx context.Context, database db.DB, shardId types.ShardId, blockNumber *types.BlockNumber, out io.Writer,
+) error {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	block, _, err := readDumpBlock(tx, shardId, blockNumber)
+	if err != nil {
+		return err
+	}
+
+	reader := execution.NewDbReceiptTrieReader(tx, shardId)
+	reader.SetRootHash(block.ReceiptsRoot)
+	entries, err := reader.Entries()
+	if err != nil {
+		return fmt.Errorf("failed to read receipts: %w", err)
+	}
+	receipts, err := orderByIndex(entries)
+	if err != nil {
+		return err
+	}
+	return printJson(out, receipts)
+}
+
+// orderByIndex returns the values of the trie in the order of their indexes.
+// The tries are iterated in the order of the encoded keys, which differs from the order of the indexes.
+func orderByIndex[V any](entries []execution.Entry[types.TransactionIndex, *V]) ([]*V, error) {
+	res := make([]*V, len(entries))
+	for _, entry := range entries {
+		if uint64(entry.Key) >= uint64(len(res)) {
+			return nil, fmt.Errorf("invalid index %d", entry.Key)
+		}
+		res[entry.Key] = entry.Val
+	}
+	return res, nil
+}
+
+func dumpAccounts(
+	ctx context.Context,
+	database db.DB,
+	shardId types.ShardId,
+	blockNumber *types.BlockNumber,
+	address string,
+	out io.Writer,
+) error {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	block, _, err := readDumpBlock(tx, shardId, blockNumber)
+	if err != nil {
+		return err
+	}
+	if err := execution.NewStateAccessor().Access(tx, shardId).CheckStateAvailable(block.Id); err != nil {
+		return err
+	}
+
+	reader := execution.NewDbContractTrieReader(tx, shardId)
+	reader.SetRootHash(block.SmartContractsRoot)
+	if address != "" {
+		addr := types.HexToAddress(address)
+		contract, err := reader.Fetch(addr.Hash())
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return fmt.Errorf("account %s is not found at block %d", addr, block.Id)
+		}
+		if err != nil {
+			return err
+		}
+		return printJson(out, []*types.SmartContract{contract})
+	}
+
+	contracts, err := reader.Values()
+	if err != nil {
+		return fmt.Errorf("failed to read accounts: %w", err)
+	}
+	return printJson(out, contracts)
+}
+
+func checkLastBlocks(ctx context.Context, database db.DB, out io.Writer) error {
+	// The results of the checked shards are printed even if the check fails.
+	results, err := execution.CheckLastBlocksState(ctx, database)
+	if err := printJson(out, results); err != nil {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("state check failed: %w", err)
+	}
+	return nil
+}
+
+func rollbackShard(ctx context.Context, database db.DB, shardId types.ShardId, blockNumber types.BlockNumber) error {
+	res, err := execution.RollbackShard(ctx, database, shardId, blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to roll back shard %d: %w", shardId, err)
+	}
+
+	logger := logging.NewLogger("db")
+	logger.Info().
+		Stringer(logging.FieldShardId, shardId).
+		Stringer(logging.FieldBlockNumber, res.To).
+		Uint64("removedBlocks", uint64(res.From-res.To)).
+		Msg("Shard is rolled back")
+	return nil
+}
@@ -330,7 +330,7 @@ func (devnet *devnet) writeServerConfig(instanceId int, srv server, only string)
 	cfg.BootstrapPeers = getPeers(devnet.validators, inst.BootstrapPeersIdx)
 	cfg.AdminSocketPath = srv.workDir + "/admin_socket"
 	cfg.LogClientRpcEvents = srv.logClientEvents
-	cfg.DB = db.NewDefaultBadgerDBOptions()
+	cfg.DB = db.NewDefaultDBOptions()
 	cfg.DB.Path = srv.workDir + "/database"
 	cfg.DB.AllowDrop = spec.NilWipeOnUpdate
 	cfg.Network.DHTEnabled = true
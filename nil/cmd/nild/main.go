@@ -11,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/version"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/profiling"
 	"github.com/NilFoundation/nil/nil/internal/readthroughdb"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -27,7 +28,10 @@ const (
 	appTitle = "=;Nil"
 )
 
-var logFilter string
+var (
+	logFilter   string
+	genesisFile string
+)
 
 func main() {
 	logger := logging.NewLogger("nild")
@@ -38,7 +42,7 @@ func main() {
 
 	profiling.Start(cfg.PprofPort)
 
-	database, err := openDb(cfg.DB.Path, cfg.DB.AllowDrop, logger)
+	database, err := openDb(cfg.DB, cfg.DB.AllowDrop, logger)
 	check.PanicIfErr(err)
 
 	if len(cfg.ReadThrough.SourceAddr) != 0 {
@@ -58,7 +62,7 @@ func main() {
 func loadConfig() (*nildconfig.Config, error) {
 	cfg := &nildconfig.Config{
 		Config: nilservice.NewDefaultConfig(),
-		DB:     db.NewDefaultBadgerDBOptions(),
+		DB:     db.NewDefaultDBOptions(),
 		ReadThrough: &nildconfig.ReadThroughOptions{
 			ForkMainAtBlock: transport.LatestBlockNumber,
 		},
@@ -89,7 +93,7 @@ func loadConfig() (*nildconfig.Config, error) {
 	}
 
 	if cfg.DB == nil {
-		cfg.DB = db.NewDefaultBadgerDBOptions()
+		cfg.DB = db.NewDefaultDBOptions()
 	}
 
 	return cfg, nil
@@ -100,6 +104,9 @@ func addNetworkFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	fset.IntVar(&cfg.Network.QuicPort, "quic-port", cfg.Network.QuicPort, "udp port for network")
 	fset.BoolVar(&cfg.Network.DHTEnabled, "with-discovery", cfg.Network.DHTEnabled, "enable discovery (with Kademlia DHT)")
 	fset.Var(&cfg.Network.DHTBootstrapPeers, "discovery-bootstrap-peers", "bootstrap peers for discovery")
+	fset.IntVar(&cfg.Network.MaxInboundPeers, "max-inbound-peers", cfg.Network.MaxInboundPeers, "maximum number of inbound peers (0 for no limit)")
+	fset.IntVar(&cfg.Network.MaxOutboundPeers, "max-outbound-peers", cfg.Network.MaxOutboundPeers, "maximum number of outbound peers (0 for no limit)")
+	fset.BoolVar(&cfg.TxnRelay, "txn-relay", cfg.TxnRelay, "relay the transactions to the shards not served by the node via pub-sub")
 	fset.StringVar(&cfg.NetworkKeysPath, "keys-path", cfg.NetworkKeysPath, "path to write libp2p keys")
 	check.PanicIfErr(fset.SetAnnotation("discovery-bootstrap-peers", cobra.BashCompOneRequiredFlag, []string{"with-discovery"}))
 }
@@ -120,6 +127,7 @@ func addBasicFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 	fset.UintSliceVar(&cfg.MyShards, "my-shards", cfg.MyShards, "run only specified shard(s)")
 	addAllowDbClearFlag(fset, cfg)
 	fset.Uint32Var(&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
+	fset.Uint64Var((*uint64)(&cfg.MaxGasInBlock), "max-gas-in-block", uint64(cfg.MaxGasInBlock), "gas limit of the blocks produced by the collators")
 }
 
 func parseArgs() *nildconfig.Config {
@@ -137,9 +145,12 @@ func parseArgs() *nildconfig.Config {
 	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (none by default)")
 
 	rootCmd.PersistentFlags().StringVar(&cfg.DB.Path, "db-path", cfg.DB.Path, "path to database")
+	rootCmd.PersistentFlags().Var(&cfg.DB.Engine, "db-engine", "database engine: badger|pebble")
 	rootCmd.PersistentFlags().Float64Var(&cfg.DB.DiscardRatio, "db-discard-ratio", cfg.DB.DiscardRatio, "discard ratio for badger GC")
 	rootCmd.PersistentFlags().DurationVar(&cfg.DB.GcFrequency, "db-gc-interval", cfg.DB.GcFrequency, "frequency for badger GC")
 	rootCmd.PersistentFlags().IntVar(&cfg.RPCPort, "http-port", cfg.RPCPort, "http port for rpc server")
+	rootCmd.PersistentFlags().Uint64Var(&cfg.MaxLogsBlockRange, "max-logs-block-range", cfg.MaxLogsBlockRange, "maximum number of blocks in the range of eth_getLogs")
+	rootCmd.PersistentFlags().Uint64Var(&cfg.MaxLogsPerQuery, "max-logs-per-query", cfg.MaxLogsPerQuery, "maximum number of logs returned by eth_getLogs")
 	rootCmd.PersistentFlags().Var(&cfg.BootstrapPeers, "bootstrap-peers", "peers for snapshot fetching or transaction sending, must go in the order of shards")
 	rootCmd.PersistentFlags().StringVar(&cfg.AdminSocketPath, "admin-socket-path", cfg.AdminSocketPath, "unix socket path to start admin server on (disabled if empty)}")
 	rootCmd.PersistentFlags().StringVar(&cfg.ReadThrough.SourceAddr, "read-through-db-addr", cfg.ReadThrough.SourceAddr, "address of the read-through database server. If provided, the local node will be run in read-through mode.")
@@ -162,6 +173,9 @@ func parseArgs() *nildconfig.Config {
 	runCmd.Flags().BoolVar(&cfg.SplitShards, "split-shards", cfg.SplitShards, "run each shard in separate process")
 	runCmd.Flags().StringVar(&cfg.CometaConfig, "cometa-config", "", "path to Cometa config")
 	runCmd.Flags().StringVar(&cfg.ValidatorKeysPath, "validator-keys-path", cfg.ValidatorKeysPath, "path to write validator keys")
+	runCmd.Flags().Uint64Var(&cfg.StatePruning.KeepBlocks, "prune-state", cfg.StatePruning.KeepBlocks, "number of the latest blocks of each shard to keep the state of (0 keeps the full history)")
+	runCmd.Flags().Uint64Var(&cfg.StatePruning.Interval, "prune-state-interval", cfg.StatePruning.Interval, "number of blocks between state pruning runs")
+	runCmd.Flags().StringVar(&genesisFile, "genesis", "", "path to genesis JSON file with the alloc of the accounts to add to the zero state")
 
 	addBasicFlags(runCmd.Flags(), cfg)
 	addNetworkFlags(runCmd.Flags(), cfg)
@@ -213,8 +227,10 @@ func parseArgs() *nildconfig.Config {
 	}
 
 	devnetCmd := DevnetCommand()
+	snapshotCmd := SnapshotCommand(cfg)
+	dbCmd := DbCommand(cfg)
 
-	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, versionCmd)
+	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, snapshotCmd, dbCmd, versionCmd)
 
 	f := rootCmd.HelpFunc()
 	rootCmd.SetHelpFunc(func(c *cobra.Command, s []string) {
@@ -230,6 +246,16 @@ func parseArgs() *nildconfig.Config {
 		cfg.Replay.BlockIdLast = cfg.Replay.BlockIdFirst
 	}
 
+	if genesisFile != "" {
+		alloc, err := execution.LoadGenesisAlloc(genesisFile)
+		check.PanicIfErr(err)
+		if cfg.ZeroState == nil {
+			cfg.ZeroState, err = execution.CreateDefaultZeroStateConfig(nil)
+			check.PanicIfErr(err)
+		}
+		cfg.ZeroState.AddGenesisAlloc(alloc)
+	}
+
 	if cfg.CometaConfig != "" {
 		cfg.Cometa = &cometa.Config{}
 		cfg.Cometa.ResetToDefault()
@@ -244,9 +270,9 @@ func parseArgs() *nildconfig.Config {
 	return cfg
 }
 
-func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error) {
+func openDb(opts *db.DBOptions, allowDrop bool, logger zerolog.Logger) (db.DB, error) {
 	dbExists := true
-	if _, err := os.Open(dbPath); err != nil {
+	if _, err := os.Open(opts.Path); err != nil {
 		if !os.IsNotExist(err) {
 			logger.Error().Err(err).Msg("Error opening db path")
 			return nil, err
@@ -255,12 +281,12 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 	}
 
 	// each shard will interact with DB via this client
-	badger, err := db.NewBadgerDb(dbPath)
+	database, err := db.NewDb(opts.Engine, opts.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := badger.CreateRwTx(context.Background())
+	tx, err := database.CreateRwTx(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -278,7 +304,7 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 		}
 
 		logger.Info().Msg("Clearing database from old data...")
-		if err := badger.DropAll(); err != nil {
+		if err := database.DropAll(); err != nil {
 			return nil, err
 		}
 	}
@@ -292,5 +318,5 @@ func openDb(dbPath string, allowDrop bool, logger zerolog.Logger) (db.DB, error)
 		}
 	}
 
-	return badger, nil
+	return database, nil
 }
@@ -14,7 +14,7 @@ type ReadThroughOptions struct {
 type Config struct {
 	*nilservice.Config `yaml:",inline"`
 
-	DB           *db.BadgerDBOptions `yaml:"db"`
+	DB           *db.DBOptions       `yaml:"db"`
 	ReadThrough  *ReadThroughOptions `yaml:"readThrough,omitempty"`
 	CometaConfig string              `yaml:"cometaConfig,omitempty"`
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/cmd/nild/nildconfig"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func SnapshotCommand(cfg *nildconfig.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export or import the state of a shard",
+	}
+
+	var (
+		shardId     = types.BaseShardId
+		blockNumber types.BlockNumber
+		headers     uint64
+		output      string
+		input       string
+	)
+
+	exportCmd := &cobra.Command{
+		Use:          "export",
+		Short:        "Export the state of the shard at the block to the snapshot file",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var block *types.BlockNumber
+			if cmd.Flags().Changed("block") {
+				block = &blockNumber
+			}
+			if err := exportSnapshot(cmd.Context(), cfg, shardId, block, headers, output); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+	exportCmd.Flags().Var(&shardId, "shard-id", "shard id to export the state of")
+	exportCmd.Flags().Var(&blockNumber, "block", "block to export the state at (the latest one by default)")
+	exportCmd.Flags().Uint64Var(&headers, "headers", 0, "number of the block headers to export (all by default)")
+	exportCmd.Flags().StringVarP(&output, "output", "o", "", "path to the snapshot file")
+	check.PanicIfErr(exportCmd.MarkFlagRequired("output"))
+
+	importCmd := &cobra.Command{
+		Use:          "import",
+		Short:        "Import the snapshot file into the empty shard",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := importSnapshot(cmd.Context(), cfg, input); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+	importCmd.Flags().StringVarP(&input, "input", "i", "", "path to the snapshot file")
+	check.PanicIfErr(importCmd.MarkFlagRequired("input"))
+
+	cmd.AddCommand(exportCmd, importCmd)
+	return cmd
+}
+
+func exportSnapshot(
+	ctx context.Context,
+	cfg *nildconfig.Config,
+	shardId types.ShardId,
+	blockNumber *types.BlockNumber,
+	headers uint64,
+	output string,
+) error {
+	logger := logging.NewLogger("snapshot")
+
+	database, err := openDb(cfg.DB, false, logger)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if blockNumber == nil {
+		tx, err := database.CreateRoTx(ctx)
+		if err != nil {
+			return err
+		}
+		block, _, err := db.ReadLastBlock(tx, shardId)
+		tx.Rollback()
+		if err != nil {
+			return fmt.Errorf("failed to read the last block of shard %d: %w", shardId, err)
+		}
+		blockNumber = &block.Id
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	header, err := execution.ExportSnapshot(ctx, database, shardId, *blockNumber, headers, w)
+	if err != nil {
+		return fmt.Errorf("failed to export snapshot: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Stringer(logging.FieldShardId, header.ShardId).
+		Stringer(logging.FieldBlockNumber, header.BlockNumber).
+		Stringer(logging.FieldBlockHash, header.BlockHash).
+		Uint64("headers", header.Headers).
+		Msgf("Snapshot is written to %s", output)
+	return nil
+}
+
+func importSnapshot(ctx context.Context, cfg *nildconfig.Config, input string) error {
+	logger := logging.NewLogger("snapshot")
+
+	database, err := openDb(cfg.DB, false, logger)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header, err := execution.ImportSnapshot(ctx, database, f)
+	if err != nil {
+		return fmt.Errorf("failed to import snapshot: %w", err)
+	}
+
+	logger.Info().
+		Stringer(logging.FieldShardId, header.ShardId).
+		Stringer(logging.FieldBlockNumber, header.BlockNumber).
+		Stringer(logging.FieldBlockHash, header.BlockHash).
+		Uint64("headers", header.Headers).
+		Msgf("Snapshot is imported from %s", input)
+	return nil
+}
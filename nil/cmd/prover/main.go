@@ -52,6 +52,12 @@ func execute() error {
 	}
 	addCommonFlags(runCmd, commonCfg)
 	runCmd.Flags().StringVar(&runConfig.DbPath, "db-path", "prover.db", "path to database")
+	runCmd.Flags().StringSliceVar(
+		&commonCfg.TaskTypes, "task-types", nil,
+		"types of tasks accepted by the prover, e.g. PartialProve,AggregatedFRI (all types if empty)")
+	runCmd.Flags().StringSliceVar(
+		&commonCfg.ResourceClasses, "resource-classes", nil,
+		"resource classes of tasks accepted by the prover: Standard, HighMemory (all classes if empty)")
 
 	rootCmd.AddCommand(runCmd)
 
@@ -124,6 +130,8 @@ func run(cfg *RunConfig) error {
 	serviceConfig := prover.Config{
 		NilRpcEndpoint:           cfg.NilRpcEndpoint,
 		ProofProviderRpcEndpoint: cfg.ProofProviderRpcEndpoint,
+		TaskTypes:                cfg.TaskTypes,
+		ResourceClasses:          cfg.ResourceClasses,
 	}
 
 	database, err := db.NewBadgerDb(cfg.DbPath)
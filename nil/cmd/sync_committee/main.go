@@ -58,6 +58,10 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 	cmd.Flags().StringVar(&cfg.ProposerParams.PrivateKey, "l1-private-key", cfg.ProposerParams.PrivateKey, "L1 account private key")
 	cmd.Flags().StringVar(&cfg.ProposerParams.ContractAddress, "l1-contract-address", cfg.ProposerParams.ContractAddress, "L1 update state contract address")
 	cmd.Flags().DurationVar(&cfg.ProposerParams.EthClientTimeout, "l1-client-timeout", cfg.ProposerParams.EthClientTimeout, "L1 client timeout")
+	cmd.Flags().Uint16Var(&cfg.BatchEncodingVersion, "batch-encoding-version", cfg.BatchEncodingVersion, "format version of batches committed to L1")
+	cmd.Flags().StringVar(&cfg.TaskEventWebhookParams.Endpoint, "task-event-webhook", cfg.TaskEventWebhookParams.Endpoint, "URL task lifecycle events are posted to, delivery is disabled if empty")
+	cmd.Flags().DurationVar(&cfg.TaskEventWebhookParams.RequestTimeout, "task-event-webhook-timeout", cfg.TaskEventWebhookParams.RequestTimeout, "task event webhook request timeout")
+	cmd.Flags().Uint32Var(&cfg.TaskEventWebhookParams.MaxRetries, "task-event-webhook-retries", cfg.TaskEventWebhookParams.MaxRetries, "number of task event delivery attempts before the next check")
 	logLevel := cmd.Flags().String("log-level", "info", "log level: trace|debug|info|warn|error|fatal|panic")
 
 	// Telemetry flags
@@ -5,10 +5,8 @@ import (
 	"errors"
 	"io"
 	"os"
-	"sync"
 
-	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
-	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/versions"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	"github.com/rs/zerolog"
 )
@@ -21,29 +19,9 @@ type DecodeBatchParams struct {
 	OutputFile string
 }
 
-type batchIntermediateDecoder interface {
-	DecodeIntermediate(from io.Reader, to io.Writer) error
-}
-
-var (
-	knownDecoders []batchIntermediateDecoder
-	decoderLoader sync.Once
-)
-
-func initDecoders(logger zerolog.Logger) {
-	decoderLoader.Do(func() {
-		knownDecoders = append(knownDecoders,
-			v1.NewDecoder(logger),
-			// each new implemented decoder needs to be added here
-		)
-	})
-}
-
 // TODO embed this call into commands.Executor?
 func DecodeBatch(_ context.Context, params *DecodeBatchParams, logger zerolog.Logger) error {
-	initDecoders(logger)
-
-	var batchSource io.ReadSeeker
+	var batchSource io.Reader
 
 	var emptyBatchId public.BatchId
 	if params.BatchId != emptyBatchId {
@@ -67,21 +45,8 @@ func DecodeBatch(_ context.Context, params *DecodeBatchParams, logger zerolog.Lo
 	if err != nil {
 		return err
 	}
+	defer outFile.Close()
 
-	for _, decoder := range knownDecoders {
-		err := decoder.DecodeIntermediate(batchSource, outFile)
-		if err == nil {
-			break
-		}
-		if !errors.Is(err, encode.ErrInvalidVersion) {
-			return err
-		}
-
-		// in case of version mismatch reset the input stream offset and try next available decoder
-		_, err = batchSource.Seek(0, io.SeekStart)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	// the decoder is picked by the version from the batch header
+	return versions.NewRegistry(logger).DecodeIntermediate(batchSource, outFile)
 }
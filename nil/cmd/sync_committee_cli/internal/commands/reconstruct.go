@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/reconstruct"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rs/zerolog"
+)
+
+type ReconstructParams struct {
+	// L1 endpoint is required to fetch blobs from L1 and to verify replayed state roots
+	L1Endpoint      string
+	ContractAddress string
+	BeaconEndpoint  string
+	RequestTimeout  time.Duration
+
+	// one of
+	BlobDir     string
+	L1FromBlock uint64
+
+	// batches to process in the order of their commitment,
+	// if empty, all batches committed within the L1 block range are processed
+	BatchIds  []string
+	L1ToBlock uint64
+
+	DbPath string
+}
+
+func DefaultReconstructParams() *ReconstructParams {
+	return &ReconstructParams{
+		RequestTimeout: 30 * time.Second,
+	}
+}
+
+func Reconstruct(ctx context.Context, params *ReconstructParams, out io.Writer, logger zerolog.Logger) error {
+	if params.DbPath == "" {
+		return errors.New("path to the local database is not specified")
+	}
+
+	var (
+		blobs    reconstruct.BlobSource
+		roots    reconstruct.StateRootSource
+		l1Source *reconstruct.L1Source
+	)
+
+	if params.L1Endpoint != "" {
+		dialCtx, cancel := context.WithTimeout(ctx, params.RequestTimeout)
+		defer cancel()
+		client, err := ethclient.DialContext(dialCtx, params.L1Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to connect to L1 endpoint: %w", err)
+		}
+		defer client.Close()
+
+		l1Source, err = reconstruct.NewL1Source(client, reconstruct.L1SourceConfig{
+			ContractAddress: params.ContractAddress,
+			BeaconEndpoint:  params.BeaconEndpoint,
+			FromBlock:       params.L1FromBlock,
+			RequestTimeout:  params.RequestTimeout,
+		})
+		if err != nil {
+			return err
+		}
+		roots = l1Source
+	}
+
+	switch {
+	case params.BlobDir != "":
+		blobs = reconstruct.NewFileBlobSource(params.BlobDir)
+	case l1Source != nil && params.BeaconEndpoint != "":
+		blobs = l1Source
+	default:
+		return errors.New("blob source is not specified: either blob directory or L1 and beacon endpoints are required")
+	}
+
+	batchIds, err := resolveBatchIds(ctx, params, l1Source)
+	if err != nil {
+		return err
+	}
+	if len(batchIds) == 0 {
+		return errors.New("no batches to reconstruct")
+	}
+
+	// The engine is detected from the files of the database, badger is used for a new one.
+	database, err := db.NewDb("", params.DbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open local database: %w", err)
+	}
+	defer database.Close()
+
+	reconstructor := reconstruct.NewReconstructor(
+		blobs, roots, reconstruct.NewExecutionReplayer(database, logger), logger)
+
+	return reconstructor.Run(ctx, batchIds, func(report *reconstruct.BatchReport) {
+		fmt.Fprintf(out, "%s\t%s\tblobs=%d blocks=%d txs=%d replayed=%s contract=%s\n",
+			report.BatchId, report.Status, report.BlobCount, report.BlockCount, report.TxCount,
+			report.ReplayedRoot, report.ContractRoot)
+	})
+}
+
+func resolveBatchIds(
+	ctx context.Context,
+	params *ReconstructParams,
+	l1Source *reconstruct.L1Source,
+) ([]public.BatchId, error) {
+	if len(params.BatchIds) > 0 {
+		ids := make([]public.BatchId, len(params.BatchIds))
+		for i, id := range params.BatchIds {
+			if err := ids[i].Set(id); err != nil {
+				return nil, fmt.Errorf("invalid batch id %q: %w", id, err)
+			}
+		}
+		return ids, nil
+	}
+
+	if l1Source == nil {
+		return nil, errors.New("batch ids are required when L1 endpoint is not specified")
+	}
+
+	var toBlock *uint64
+	if params.L1ToBlock != 0 {
+		toBlock = &params.L1ToBlock
+	}
+	committed, err := l1Source.CommittedBatches(ctx, params.L1FromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]public.BatchId, len(committed))
+	for i, batch := range committed {
+		ids[i] = batch.BatchId
+	}
+	return ids, nil
+}
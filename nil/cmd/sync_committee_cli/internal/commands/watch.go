@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/rs/zerolog"
+)
+
+type WatchParams struct {
+	RpcEndpoint  string
+	PollInterval time.Duration
+
+	// AfterSeq is the sequence number of the last already seen event, the log is tailed from its beginning if zero
+	AfterSeq uint64
+
+	// Types filters events by their type, all events are printed if empty
+	Types []string
+	Json  bool
+}
+
+func DefaultWatchParams() *WatchParams {
+	return &WatchParams{
+		RpcEndpoint:  core.DefaultTaskRpcEndpoint,
+		PollInterval: time.Second,
+	}
+}
+
+func (p *WatchParams) Validate() error {
+	if p.PollInterval < MinRefreshInterval {
+		return fmt.Errorf("poll interval cannot be less than %s, actual is %s", MinRefreshInterval, p.PollInterval)
+	}
+	for _, eventType := range p.Types {
+		if _, ok := public.TaskEventTypes[eventType]; !ok {
+			return fmt.Errorf("unknown task event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Watch tails the task event log and prints received events until the context is canceled.
+func Watch(
+	ctx context.Context,
+	params *WatchParams,
+	api public.TaskEventsApi,
+	out io.Writer,
+	logger zerolog.Logger,
+) error {
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid command params: %w", err)
+	}
+
+	cursor := params.AfterSeq
+	for {
+		events, err := api.GetTaskEvents(ctx, public.NewTaskEventsRequest(cursor, public.TaskEventsMaxLimit))
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil
+		case err != nil:
+			// the node may be restarting, keep watching
+			logger.Error().Err(err).Msg("failed to get task events")
+		default:
+			if missed := public.MissedTaskEvents(cursor, events); missed > 0 {
+				logger.Warn().
+					Uint64("afterSeq", cursor).
+					Uint64("missedCount", missed).
+					Msg("task events were removed from the log before they were received")
+			}
+			for _, event := range events {
+				if err := printEvent(out, event, params); err != nil {
+					return err
+				}
+				cursor = event.Seq
+			}
+			if len(events) == public.TaskEventsMaxLimit {
+				// more events are available
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(params.PollInterval):
+		}
+	}
+}
+
+func printEvent(out io.Writer, event *public.TaskEvent, params *WatchParams) error {
+	if len(params.Types) > 0 && !slices.Contains(params.Types, string(event.Type)) {
+		return nil
+	}
+
+	if params.Json {
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal task event %d: %w", event.Seq, err)
+		}
+		_, err = fmt.Fprintln(out, string(encoded))
+		return err
+	}
+
+	_, err := io.WriteString(out, buildEventOutput(event))
+	return err
+}
+
+func buildEventOutput(event *public.TaskEvent) CmdOutput {
+	var builder outputBuilder
+
+	var typeStr string
+	switch event.Type {
+	case public.TaskEventFailed:
+		typeStr = RedStr("%s", event.Type)
+	case public.TaskEventRescheduled:
+		typeStr = YellowStr("%s", event.Type)
+	default:
+		typeStr = GreenStr("%s", event.Type)
+	}
+
+	builder.WriteString(fmt.Sprintf("%s #%d %s", event.Time.Format(time.RFC3339), event.Seq, typeStr))
+
+	if event.TaskId != nil {
+		builder.WriteString(fmt.Sprintf(" task=%s type=%s", event.TaskId, event.TaskType))
+	}
+	if event.BatchId != nil {
+		builder.WriteString(" batch=" + event.BatchId.String())
+	}
+	if event.ExecutorId != public.DefaultDebugTaskOwner {
+		builder.WriteString(" executor=" + CyanStr("%s", event.ExecutorId))
+	}
+	if event.RetryCount > 0 {
+		builder.WriteString(fmt.Sprintf(" retries=%d", event.RetryCount))
+	}
+	if event.NewStateRoot != nil {
+		builder.WriteString(fmt.Sprintf(" root=%s->%s", event.OldStateRoot, event.NewStateRoot))
+	}
+	if event.L1TxHash != nil {
+		builder.WriteString(" l1Tx=" + event.L1TxHash.Hex())
+	}
+	if event.Error != nil {
+		builder.WriteString(" error=" + RedStr("%s", event.Error))
+	}
+
+	builder.WriteLine()
+	return builder.String()
+}
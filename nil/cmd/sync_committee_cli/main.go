@@ -3,12 +3,18 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 
 	"github.com/NilFoundation/nil/nil/cmd/sync_committee_cli/internal/commands"
 	"github.com/NilFoundation/nil/nil/cmd/sync_committee_cli/internal/flags"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/debug"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -41,6 +47,12 @@ func execute() error {
 	decodeBatchCmd := buildDecodeBatchCmd(executorParams, logger)
 	rootCmd.AddCommand(decodeBatchCmd)
 
+	reconstructCmd := buildReconstructCmd(logger)
+	rootCmd.AddCommand(reconstructCmd)
+
+	watchCmd := buildWatchCmd(logger)
+	rootCmd.AddCommand(watchCmd)
+
 	return rootCmd.Execute()
 }
 
@@ -131,6 +143,59 @@ func buildDecodeBatchCmd(_ *commands.ExecutorParams, logger zerolog.Logger) *cob
 	return cmd
 }
 
+func buildReconstructCmd(logger zerolog.Logger) *cobra.Command {
+	params := commands.DefaultReconstructParams()
+
+	cmd := &cobra.Command{
+		Use:   "reconstruct",
+		Short: "Replay batches committed to L1 against the local database and verify resulting state roots",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.Reconstruct(cmd.Context(), params, os.Stdout, logger)
+		},
+	}
+
+	cmd.Flags().StringVar(&params.L1Endpoint, "l1-endpoint", "", "L1 endpoint to fetch committed batches and state roots from")
+	cmd.Flags().StringVar(&params.ContractAddress, "contract-address", "", "L1 rollup contract address")
+	cmd.Flags().StringVar(&params.BeaconEndpoint, "beacon-endpoint", "", "L1 beacon node endpoint to fetch blobs from")
+	cmd.Flags().DurationVar(&params.RequestTimeout, "request-timeout", params.RequestTimeout, "L1 request timeout")
+	cmd.Flags().StringVar(&params.BlobDir, "blob-dir", "", "directory with files of concatenated blobs named after batch ids")
+	cmd.Flags().StringSliceVar(&params.BatchIds, "batch-ids", nil, "batches to reconstruct in the order of their commitment")
+	cmd.Flags().Uint64Var(&params.L1FromBlock, "from-l1-block", 0, "first L1 block to search for committed batches")
+	cmd.Flags().Uint64Var(&params.L1ToBlock, "to-l1-block", 0, "last L1 block to search for committed batches (latest if 0)")
+	cmd.Flags().StringVar(&params.DbPath, "db-path", "", "local database with the L2 state the first batch is based on")
+
+	return cmd
+}
+
+func buildWatchCmd(logger zerolog.Logger) *cobra.Command {
+	params := commands.DefaultWatchParams()
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Tail task lifecycle events of the node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, syscall.SIGINT)
+			defer stop()
+			client := debug.NewEventsClient(params.RpcEndpoint, logger)
+			return commands.Watch(ctx, params, client, os.Stdout, logger)
+		},
+	}
+
+	cmd.Flags().StringVar(&params.RpcEndpoint, "endpoint", params.RpcEndpoint, "task rpc endpoint")
+	cmd.Flags().DurationVar(&params.PollInterval, "poll-interval", params.PollInterval, "interval between polls for new events")
+	cmd.Flags().Uint64Var(&params.AfterSeq, "after-seq", params.AfterSeq, "print events after the given sequence number only")
+	cmd.Flags().StringSliceVar(
+		&params.Types,
+		"types",
+		nil,
+		"comma separated list of event types to print, possible values: "+
+			strings.Join(slices.Sorted(maps.Keys(public.TaskEventTypes)), ", "),
+	)
+	cmd.Flags().BoolVar(&params.Json, "json", params.Json, "print events as JSON lines")
+
+	return cmd
+}
+
 func addCommonFlags(cmd *cobra.Command, params *commands.ExecutorParams) {
 	cmd.Flags().StringVar(&params.DebugRpcEndpoint, "endpoint", params.DebugRpcEndpoint, "debug rpc endpoint")
 	cmd.Flags().BoolVar(&params.AutoRefresh, "refresh", params.AutoRefresh, "should the received data be refreshed")
@@ -21,6 +21,7 @@ contract ConfigTest is NilBase {
                 bytes32 b = keccak256(abi.encodePacked(real.PublicKey));
                 require(a == b, "Public keys are not equal");
                 require(input.WithdrawalAddress == real.WithdrawalAddress, "Withdraw addresses are not equal");
+                require(input.Weight == real.Weight, "Weights are not equal");
             }
         }
     }
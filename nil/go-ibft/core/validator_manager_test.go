@@ -184,6 +184,36 @@ func Test_CalculateQuorum(t *testing.T) {
 			},
 			hasQuorum: false,
 		},
+		{
+			// case total voting power of 13
+			validatorsVotingPower: map[string]*big.Int{
+				"A": big.NewInt(10),
+				"B": big.NewInt(1),
+				"C": big.NewInt(1),
+				"D": big.NewInt(1),
+			},
+			// only the heavy validator signed with voting power of 10 (quorum should be 9)
+			signers: map[string]struct{}{
+				"A": {},
+			},
+			hasQuorum: true,
+		},
+		{
+			// case total voting power of 13
+			validatorsVotingPower: map[string]*big.Int{
+				"A": big.NewInt(10),
+				"B": big.NewInt(1),
+				"C": big.NewInt(1),
+				"D": big.NewInt(1),
+			},
+			// 3 of 4 signed with voting power of 3 (quorum should be 9)
+			signers: map[string]struct{}{
+				"B": {},
+				"C": {},
+				"D": {},
+			},
+			hasQuorum: false,
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,237 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	// headerBatchSize is the number of headers requested at once, the bodies of these blocks are downloaded in parallel.
+	headerBatchSize = maxBlockBatchSize
+	// bodyBatchSize is the number of blocks with transactions requested from a peer at once.
+	bodyBatchSize = 64
+)
+
+var (
+	errInvalidHeaders = errors.New("headers are not chained to the last block")
+	errBodyMismatch   = errors.New("block doesn't match its header")
+	errNoPeersLeft    = errors.New("all peers failed to serve the blocks")
+)
+
+// blockChunk is a range of blocks whose bodies are downloaded from a single peer.
+type blockChunk struct {
+	headers []common.Hash
+	from    types.BlockNumber
+
+	// blocks are set before done is closed.
+	blocks []*types.BlockWithExtractedData
+	done   chan struct{}
+}
+
+// fetchBlockBatches downloads the blocks following the last one with the batch protocol.
+// The headers of a range are requested from a single peer and checked to be chained to the last block,
+// so that the bodies downloaded from all the peers in parallel can be checked against them.
+// A chunk failed by a peer (because of a timeout or invalid data) is passed to the other peers,
+// and an error is returned only when no peer is able to serve the blocks.
+func (s *Syncer) fetchBlockBatches(ctx context.Context, peers []network.PeerID) error {
+	for {
+		lastBlock, lastHash, err := s.validator.GetLastBlock(ctx)
+		if err != nil {
+			return err
+		}
+
+		from := lastBlock.Id + 1
+		headers, headersPeer, err := s.fetchHeaders(ctx, peers, from, lastHash)
+		if err != nil {
+			return err
+		}
+		if len(headers) == 0 {
+			s.logger.Trace().Msg("No new blocks to fetch")
+			return nil
+		}
+
+		s.logger.Debug().
+			Stringer(logging.FieldPeerId, headersPeer).
+			Msgf("Fetching blocks %d-%d from %d peers", from, from+types.BlockNumber(len(headers))-1, len(peers))
+
+		if err := s.fetchBodies(ctx, peers, from, headers, headersPeer); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchHeaders returns the hashes of the headers following the last block from the first peer that serves them.
+// It returns no headers if the peers that responded have no new blocks.
+func (s *Syncer) fetchHeaders(
+	ctx context.Context, peers []network.PeerID, from types.BlockNumber, lastHash common.Hash,
+) ([]common.Hash, network.PeerID, error) {
+	responded := false
+	for _, p := range peers {
+		headers, err := requestBlockHeaders(ctx, s.networkManager, p, s.config.ShardId, from, headerBatchSize)
+		if err == nil {
+			var hashes []common.Hash
+			if hashes, err = s.checkHeaders(headers, from, lastHash); err == nil {
+				if len(hashes) > 0 {
+					return hashes, p, nil
+				}
+				responded = true
+				continue
+			}
+		}
+
+		s.reportFailure(p, err)
+		s.logger.Warn().Err(err).Msgf("Failed to fetch block headers from peer %s", p)
+	}
+	if responded {
+		return nil, "", nil
+	}
+	return nil, "", errNoPeersLeft
+}
+
+// checkHeaders checks that the headers are consecutive and chained to the last block and returns their hashes.
+// The signatures are checked when the blocks are replayed.
+func (s *Syncer) checkHeaders(headers []*types.Block, from types.BlockNumber, lastHash common.Hash) ([]common.Hash, error) {
+	hashes := make([]common.Hash, len(headers))
+	prevHash := lastHash
+	for i, header := range headers {
+		if header.Id != from+types.BlockNumber(i) || header.PrevBlock != prevHash {
+			return nil, fmt.Errorf("%w: unexpected block %d", errInvalidHeaders, header.Id)
+		}
+		hashes[i] = header.Hash(s.config.ShardId)
+		prevHash = hashes[i]
+	}
+	return hashes, nil
+}
+
+// fetchBodies downloads the blocks with the given headers in chunks from all the peers at once
+// and replays them in order.
+func (s *Syncer) fetchBodies(
+	ctx context.Context, peers []network.PeerID, from types.BlockNumber, headers []common.Hash,
+	headersPeer network.PeerID,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := make([]*blockChunk, 0, (len(headers)+bodyBatchSize-1)/bodyBatchSize)
+	for i := 0; i < len(headers); i += bodyBatchSize {
+		chunks = append(chunks, &blockChunk{
+			headers: headers[i:min(i+bodyBatchSize, len(headers))],
+			from:    from + types.BlockNumber(i),
+			done:    make(chan struct{}),
+		})
+	}
+
+	// The queue fits all the chunks, so returning a failed chunk never blocks.
+	queue := make(chan *blockChunk, len(chunks))
+	for _, c := range chunks {
+		queue <- c
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range peers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.downloadChunks(ctx, p, queue)
+		}()
+	}
+	allFailed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(allFailed)
+	}()
+
+	for _, c := range chunks {
+		select {
+		case <-c.done:
+		case <-allFailed:
+			// The last peer may have completed the chunk before leaving.
+			select {
+			case <-c.done:
+			default:
+				return errNoPeersLeft
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		for _, block := range c.blocks {
+			if err := s.saveBlock(ctx, block); err != nil {
+				if errors.Is(err, errOldBlock) {
+					continue
+				}
+				if errors.Is(err, errInvalidSignature) {
+					// The block matches the header, so it's the chain of the headers that is invalid.
+					s.networkManager.ReportPeer(headersPeer, network.MisbehaviorInvalidBlock)
+				}
+				s.logger.Error().
+					Err(err).
+					Stringer(logging.FieldBlockNumber, block.Id).
+					Msg("Failed to save block")
+				return err
+			}
+		}
+		// Release the memory of the replayed blocks.
+		c.blocks = nil
+	}
+	return nil
+}
+
+// downloadChunks downloads the chunks from the queue until the peer fails one of them.
+func (s *Syncer) downloadChunks(ctx context.Context, p network.PeerID, queue chan *blockChunk) {
+	for {
+		var c *blockChunk
+		select {
+		case c = <-queue:
+		case <-ctx.Done():
+			return
+		}
+
+		blocks, err := requestBlockBodies(ctx, s.networkManager, p, s.config.ShardId, c.from, len(c.headers))
+		if err == nil {
+			err = s.checkBodies(blocks, c.headers)
+		}
+		if err != nil {
+			queue <- c
+			if ctx.Err() == nil {
+				s.reportFailure(p, err)
+				s.logger.Warn().Err(err).Msgf("Failed to fetch blocks %d-%d from peer %s, dropping the peer",
+					c.from, c.from+types.BlockNumber(len(c.headers))-1, p)
+			}
+			return
+		}
+
+		c.blocks = blocks
+		close(c.done)
+	}
+}
+
+func (s *Syncer) checkBodies(blocks []*types.BlockWithExtractedData, headers []common.Hash) error {
+	if len(blocks) != len(headers) {
+		// The peer may be behind the one that served the headers.
+		return fmt.Errorf("requested %d blocks, got %d", len(headers), len(blocks))
+	}
+	for i, block := range blocks {
+		if hash := block.Block.Hash(s.config.ShardId); hash != headers[i] {
+			return fmt.Errorf("%w: block %d has hash %x, expected %x", errBodyMismatch, block.Id, hash, headers[i])
+		}
+	}
+	return nil
+}
+
+// reportFailure decreases the reputation of the peer if the error is caused by the data it sent.
+func (s *Syncer) reportFailure(p network.PeerID, err error) {
+	switch {
+	case errors.Is(err, errMalformedBlock):
+		s.networkManager.ReportPeer(p, network.MisbehaviorMalformedMessage)
+	case errors.Is(err, errInvalidHeaders), errors.Is(err, errBodyMismatch):
+		s.networkManager.ReportPeer(p, network.MisbehaviorInvalidBlock)
+	}
+}
@@ -0,0 +1,296 @@
+package collate
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestSyncParams() *Params {
+	params := &Params{
+		BlockGeneratorParams: execution.NewBlockGeneratorParams(types.MainShardId, 1),
+		Topology:             new(TrivialShardTopology),
+	}
+	params.DisableConsensus = true
+	return params
+}
+
+func newTestSyncer(
+	t *testing.T, params *Params, zeroState *execution.ZeroStateConfig, database db.DB, nm *network.Manager,
+) *Syncer {
+	t.Helper()
+
+	syncer, err := NewSyncer(&SyncerConfig{
+		BlockGeneratorParams: params.BlockGeneratorParams,
+		Name:                 "syncer",
+		ShardId:              params.ShardId,
+		Timeout:              time.Minute,
+		ZeroStateConfig:      zeroState,
+	}, NewValidator(params, database, &MockTxnPool{}, nm), database, nm)
+	require.NoError(t, err)
+	require.NoError(t, syncer.GenerateZerostate(t.Context()))
+	return syncer
+}
+
+// generateTestChain generates n empty blocks following the zero state and returns the hash of the last one.
+func generateTestChain(
+	t *testing.T, params *Params, zeroState *execution.ZeroStateConfig, database db.DB, n int,
+) common.Hash {
+	t.Helper()
+
+	validator := newTestSyncer(t, params, zeroState, database, nil).validator
+	for range n {
+		proposal, err := validator.BuildProposal(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, validator.InsertProposal(t.Context(), proposal, &types.ConsensusParams{}))
+	}
+
+	_, hash, err := validator.GetLastBlock(t.Context())
+	require.NoError(t, err)
+	return hash
+}
+
+func newTestZeroState(balance uint64) *execution.ZeroStateConfig {
+	zeroState := &execution.ZeroStateConfig{}
+	zeroState.AddGenesisAlloc(execution.GenesisAlloc{
+		types.ShardAndHexToAddress(types.MainShardId, "11"): {Balance: types.NewValueFromUint64(balance)},
+	})
+	return zeroState
+}
+
+func TestSyncerFetchBlockBatches(t *testing.T) {
+	t.Parallel()
+
+	const nBlocks = 2500
+
+	ctx := t.Context()
+	params := newTestSyncParams()
+	zeroState := newTestZeroState(1000)
+	logger := logging.NewLogger("test")
+
+	honestDb, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer honestDb.Close()
+	lastHash := generateTestChain(t, params, zeroState, honestDb, nBlocks)
+
+	// The lying peer serves a different chain of the same length.
+	lyingDb, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer lyingDb.Close()
+	generateTestChain(t, params, newTestZeroState(2000), lyingDb, nBlocks)
+
+	managers := network.NewTestManagers(t, ctx, 9200, 4)
+	defer func() {
+		for _, m := range managers {
+			m.Close()
+		}
+	}()
+	node, peers := managers[0], managers[1:]
+	SetRequestHandler(ctx, peers[0], params.ShardId, honestDb, logger)
+	SetRequestHandler(ctx, peers[1], params.ShardId, honestDb, logger)
+	SetRequestHandler(ctx, peers[2], params.ShardId, lyingDb, logger)
+	var liar network.PeerID
+	for _, p := range peers {
+		_, liar = network.ConnectManagers(t, node, p)
+	}
+	var batchPeers []network.PeerID
+	require.Eventually(t, func() bool {
+		batchPeers = node.GetPeersForProtocol(protocolShardBlockBatch(params.ShardId))
+		return len(batchPeers) == len(peers)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	nodeDb, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer nodeDb.Close()
+	syncer := newTestSyncer(t, params, zeroState, nodeDb, node)
+
+	require.NoError(t, syncer.fetchBlockBatches(ctx, batchPeers))
+
+	block, hash, err := syncer.validator.GetLastBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, types.BlockNumber(nBlocks), block.Id)
+	assert.Equal(t, lastHash, hash)
+
+	// The lying peer is reported either for the headers or for the bodies.
+	nodePeers := node.Peers()
+	idx := slices.IndexFunc(nodePeers, func(info network.PeerInfo) bool {
+		return info.Id == liar
+	})
+	require.GreaterOrEqual(t, idx, 0)
+	assert.True(t, nodePeers[idx].Reputation < 0 || nodePeers[idx].BannedUntil != nil)
+}
+
+// setFaultyBlockBatchHandler serves the blocks of the database with the batch protocol.
+// The headers are served as is, while the responses with the bodies are passed through fault,
+// which alters them or returns false to leave the request unanswered.
+func setFaultyBlockBatchHandler(
+	t *testing.T, ctx context.Context, nm *network.Manager, shardId types.ShardId, database db.DB,
+	fault func(blocks *pb.RawFullBlocks) bool,
+) {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	accessor := execution.NewStateAccessor()
+
+	nm.SetStreamHandler(ctx, protocolShardBlockBatch(shardId), func(s network.Stream) {
+		data, err := io.ReadAll(s)
+		if err != nil {
+			return
+		}
+		var req pb.BlocksBatchRequest
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		tx, err := database.CreateRoTx(ctx)
+		if err != nil {
+			return
+		}
+		defer tx.Rollback()
+
+		acc := accessor.RawAccess(tx, shardId).GetBlock()
+		if !req.HeadersOnly {
+			acc = acc.WithOutTransactions().WithInTransactions().WithChildBlocks().WithConfig()
+		}
+		blocks := &pb.RawFullBlocks{}
+		for id := req.Id; id < req.Id+int64(req.Count); id++ {
+			resp, err := acc.ByNumber(types.BlockNumber(id))
+			if err != nil {
+				break
+			}
+			blocks.Blocks = append(blocks.Blocks, newRawFullBlock(resp, req.HeadersOnly))
+		}
+
+		if !req.HeadersOnly && !fault(blocks) {
+			select {
+			case <-time.After(2 * requestTimeout):
+			case <-ctx.Done():
+			}
+			return
+		}
+		_ = writeBlockBatchToStream(s, encoder, blocks)
+	})
+}
+
+func TestSyncerFetchBlockBatchesFaultyBodies(t *testing.T) {
+	t.Parallel()
+
+	const nBlocks = 300
+
+	params := newTestSyncParams()
+	zeroState := newTestZeroState(1000)
+	logger := logging.NewLogger("test")
+
+	honestDb, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer honestDb.Close()
+	lastHash := generateTestChain(t, params, zeroState, honestDb, nBlocks)
+
+	for i, test := range []struct {
+		name  string
+		fault func(blocks *pb.RawFullBlocks) bool
+		// reported tells whether the faulty peer is expected to be reported.
+		reported bool
+	}{
+		{
+			// The peer serves the valid headers, but the bodies don't match them.
+			name: "TamperedBodies",
+			fault: func(blocks *pb.RawFullBlocks) bool {
+				for _, b := range blocks.Blocks {
+					block := &types.Block{}
+					require.NoError(t, block.UnmarshalSSZ(b.BlockSSZ))
+					block.Timestamp++
+					var err error
+					b.BlockSSZ, err = block.MarshalSSZ()
+					require.NoError(t, err)
+				}
+				return true
+			},
+			reported: true,
+		},
+		{
+			// The peer doesn't respond to the requests of the bodies until they time out.
+			name: "StalledPeer",
+			fault: func(*pb.RawFullBlocks) bool {
+				return false
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := t.Context()
+			managers := network.NewTestManagers(t, ctx, 9210+10*i, 3)
+			defer func() {
+				for _, m := range managers {
+					m.Close()
+				}
+			}()
+			node, honest, faulty := managers[0], managers[1], managers[2]
+			SetRequestHandler(ctx, honest, params.ShardId, honestDb, logger)
+			setFaultyBlockBatchHandler(t, ctx, faulty, params.ShardId, honestDb, test.fault)
+			network.ConnectManagers(t, node, honest)
+			_, faultyId := network.ConnectManagers(t, node, faulty)
+
+			var batchPeers []network.PeerID
+			require.Eventually(t, func() bool {
+				batchPeers = node.GetPeersForProtocol(protocolShardBlockBatch(params.ShardId))
+				return len(batchPeers) == 2
+			}, 5*time.Second, 50*time.Millisecond)
+
+			nodeDb, err := db.NewBadgerDbInMemory()
+			require.NoError(t, err)
+			defer nodeDb.Close()
+			syncer := newTestSyncer(t, params, zeroState, nodeDb, node)
+
+			// The chunks failed by the faulty peer are downloaded from the honest one.
+			require.NoError(t, syncer.fetchBlockBatches(ctx, batchPeers))
+
+			block, hash, err := syncer.validator.GetLastBlock(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, types.BlockNumber(nBlocks), block.Id)
+			assert.Equal(t, lastHash, hash)
+
+			nodePeers := node.Peers()
+			idx := slices.IndexFunc(nodePeers, func(info network.PeerInfo) bool {
+				return info.Id == faultyId
+			})
+			require.GreaterOrEqual(t, idx, 0)
+			assert.Equal(t, test.reported, nodePeers[idx].Reputation < 0 || nodePeers[idx].BannedUntil != nil)
+		})
+	}
+}
+
+func TestSyncerCheckHeaders(t *testing.T) {
+	t.Parallel()
+
+	params := newTestSyncParams()
+	syncer := &Syncer{config: &SyncerConfig{ShardId: params.ShardId}}
+
+	prevHash := common.HexToHash("0x01")
+	b1 := &types.Block{BlockData: types.BlockData{Id: 1, PrevBlock: prevHash}}
+	b2 := &types.Block{BlockData: types.BlockData{Id: 2, PrevBlock: b1.Hash(params.ShardId)}}
+
+	hashes, err := syncer.checkHeaders([]*types.Block{b1, b2}, 1, prevHash)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{b1.Hash(params.ShardId), b2.Hash(params.ShardId)}, hashes)
+
+	_, err = syncer.checkHeaders([]*types.Block{b2}, 1, prevHash)
+	require.ErrorIs(t, err, errInvalidHeaders)
+
+	_, err = syncer.checkHeaders([]*types.Block{b1, b2}, 1, common.HexToHash("0x02"))
+	require.ErrorIs(t, err, errInvalidHeaders)
+}
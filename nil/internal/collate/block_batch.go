@@ -0,0 +1,261 @@
+package collate
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxBlockBatchSize is the maximum number of blocks in a response of the batch protocol.
+	maxBlockBatchSize = 1024
+	// maxBlockBatchBytes limits both the compressed and the decompressed size of a response.
+	maxBlockBatchBytes = 256 << 20
+)
+
+// protocolShardBlockBatch is the second version of the block protocol.
+// Unlike the first one, it serves a limited range of blocks in a single compressed response
+// and can serve the headers without the transactions.
+func protocolShardBlockBatch(shardId types.ShardId) network.ProtocolID {
+	return network.ProtocolID(fmt.Sprintf("/shard/%s/block/2", shardId))
+}
+
+// rawBlockSource is the part of the raw block accessor result that is sent over the network.
+type rawBlockSource interface {
+	Block() []byte
+	InTransactions() [][]byte
+	OutTransactions() [][]byte
+	ChildBlocks() []common.Hash
+	Config() map[string][]byte
+}
+
+func newRawFullBlock(resp rawBlockSource, headersOnly bool) *pb.RawFullBlock {
+	if headersOnly {
+		return &pb.RawFullBlock{BlockSSZ: resp.Block()}
+	}
+	return &pb.RawFullBlock{
+		BlockSSZ:           resp.Block(),
+		OutTransactionsSSZ: resp.OutTransactions(),
+		InTransactionsSSZ:  resp.InTransactions(),
+		ChildBlocks:        pb.PackHashes(resp.ChildBlocks()),
+		Config:             resp.Config(),
+	}
+}
+
+// The response of the batch protocol is a single message:
+// 1. The size of the compressed data as 8 bytes (big-endian).
+// 2. pb.RawFullBlocks in protobuf format compressed with zstd.
+func writeBlockBatchToStream(s network.Stream, encoder *zstd.Encoder, blocks *pb.RawFullBlocks) error {
+	data, err := proto.Marshal(blocks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal blocks to Protobuf: %w", err)
+	}
+	compressed := encoder.EncodeAll(data, nil)
+
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint64(header, uint64(len(compressed)))
+
+	if _, err := s.Write(header); err != nil {
+		return fmt.Errorf("failed to write batch size to stream: %w", err)
+	}
+	if _, err := s.Write(compressed); err != nil {
+		return fmt.Errorf("failed to write batch to stream: %w", err)
+	}
+	return nil
+}
+
+func readBlockBatchFromStream(s network.Stream) ([]*pb.RawFullBlock, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(s, header); err != nil {
+		return nil, fmt.Errorf("failed to read batch size: %w", err)
+	}
+
+	length := binary.BigEndian.Uint64(header)
+	if length > maxBlockBatchBytes {
+		return nil, fmt.Errorf("%w: batch size %d exceeds the limit", errMalformedBlock, length)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(s, buf); err != nil {
+		return nil, fmt.Errorf("failed to read batch: %w", err)
+	}
+
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxBlockBatchBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+
+	data, err := decoder.DecodeAll(buf, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decompress batch: %w", errMalformedBlock, err)
+	}
+
+	var blocks pb.RawFullBlocks
+	if err := proto.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal batch: %w", errMalformedBlock, err)
+	}
+	if len(blocks.Blocks) > maxBlockBatchSize {
+		return nil, fmt.Errorf("%w: batch of %d blocks exceeds the limit", errMalformedBlock, len(blocks.Blocks))
+	}
+	return blocks.Blocks, nil
+}
+
+// requestBlockBatch requests up to count blocks starting from the given one.
+// The peer may return fewer blocks if it doesn't have them.
+func requestBlockBatch(
+	ctx context.Context, networkManager *network.Manager, peerID network.PeerID, shardId types.ShardId,
+	from types.BlockNumber, count int, headersOnly bool,
+) ([]*pb.RawFullBlock, error) {
+	req, err := proto.Marshal(&pb.BlocksBatchRequest{
+		Id:          int64(from),
+		Count:       uint32(count),
+		HeadersOnly: headersOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blocks request: %w", err)
+	}
+
+	stream, err := networkManager.NewStream(ctx, peerID, protocolShardBlockBatch(shardId))
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	if err := stream.SetDeadline(time.Now().Add(requestTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := stream.Write(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseWrite(); err != nil {
+		return nil, err
+	}
+
+	blocks, err := readBlockBatchFromStream(stream)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) > count {
+		return nil, fmt.Errorf("%w: requested %d blocks, got %d", errMalformedBlock, count, len(blocks))
+	}
+	return blocks, nil
+}
+
+// requestBlockHeaders requests up to count block headers starting from the given one.
+func requestBlockHeaders(
+	ctx context.Context, networkManager *network.Manager, peerID network.PeerID, shardId types.ShardId,
+	from types.BlockNumber, count int,
+) ([]*types.Block, error) {
+	raw, err := requestBlockBatch(ctx, networkManager, peerID, shardId, from, count, true)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]*types.Block, len(raw))
+	for i, b := range raw {
+		headers[i] = &types.Block{}
+		if err := headers[i].UnmarshalSSZ(b.BlockSSZ); err != nil {
+			return nil, fmt.Errorf("%w: failed to unmarshal header: %w", errMalformedBlock, err)
+		}
+	}
+	return headers, nil
+}
+
+// requestBlockBodies requests up to count blocks with their transactions starting from the given one.
+func requestBlockBodies(
+	ctx context.Context, networkManager *network.Manager, peerID network.PeerID, shardId types.ShardId,
+	from types.BlockNumber, count int,
+) ([]*types.BlockWithExtractedData, error) {
+	raw, err := requestBlockBatch(ctx, networkManager, peerID, shardId, from, count, false)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*types.BlockWithExtractedData, len(raw))
+	for i, b := range raw {
+		if blocks[i], err = unmarshalBlockSSZ(b); err != nil {
+			return nil, fmt.Errorf("%w: %w", errMalformedBlock, err)
+		}
+	}
+	return blocks, nil
+}
+
+func setBlockBatchRequestHandler(
+	ctx context.Context, networkManager *network.Manager, shardId types.ShardId, database db.DB,
+	accessor *execution.StateAccessor, logger zerolog.Logger,
+) {
+	// The encoder is safe for concurrent use with EncodeAll.
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create zstd encoder, the block batch protocol is disabled")
+		return
+	}
+
+	handler := func(s network.Stream) {
+		if err := s.SetDeadline(time.Now().Add(requestTimeout)); err != nil {
+			return
+		}
+
+		req, err := io.ReadAll(s)
+		if err != nil {
+			logError(logger, err, "Failed to read request")
+			return
+		}
+		if err = s.CloseRead(); err != nil {
+			logError(logger, err, "Failed to close stream for reading")
+		}
+
+		var batchReq pb.BlocksBatchRequest
+		if err := proto.Unmarshal(req, &batchReq); err != nil {
+			logError(logger, err, "Failed to unmarshal block batch request")
+			return
+		}
+		count := int64(batchReq.Count)
+		if count == 0 || count > maxBlockBatchSize {
+			count = maxBlockBatchSize
+		}
+
+		tx, err := database.CreateRoTx(ctx)
+		if err != nil {
+			logError(logger, err, "Failed to create transaction")
+			return
+		}
+		defer tx.Rollback()
+
+		acc := accessor.RawAccess(tx, shardId).GetBlock()
+		if !batchReq.HeadersOnly {
+			acc = acc.WithOutTransactions().WithInTransactions().WithChildBlocks().WithConfig()
+		}
+
+		blocks := &pb.RawFullBlocks{}
+		for id := batchReq.Id; id < batchReq.Id+count; id++ {
+			resp, err := acc.ByNumber(types.BlockNumber(id))
+			if err != nil {
+				if !errors.Is(err, db.ErrKeyNotFound) {
+					logError(logger, err, "DB error")
+				}
+				break
+			}
+			blocks.Blocks = append(blocks.Blocks, newRawFullBlock(resp, batchReq.HeadersOnly))
+		}
+
+		if err := writeBlockBatchToStream(s, encoder, blocks); err != nil {
+			logError(logger, err, "Failed to handle output block batch")
+		}
+	}
+
+	networkManager.SetStreamHandler(ctx, protocolShardBlockBatch(shardId), handler)
+}
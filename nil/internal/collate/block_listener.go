@@ -19,6 +19,9 @@ import (
 
 const requestTimeout = 10 * time.Second
 
+// errMalformedBlock is returned for the blocks that are received in full but can't be decoded.
+var errMalformedBlock = errors.New("malformed block")
+
 func topicShardBlocks(shardId types.ShardId) string {
 	return fmt.Sprintf("/shard/%s/blocks", shardId)
 }
@@ -82,10 +85,14 @@ func readBlockFromStream(s network.Stream) (*types.BlockWithExtractedData, error
 
 	var pbBlock pb.RawFullBlock
 	if err := proto.Unmarshal(buf, &pbBlock); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal block: %w", err)
+		return nil, fmt.Errorf("%w: failed to unmarshal block: %w", errMalformedBlock, err)
 	}
 
-	return unmarshalBlockSSZ(&pbBlock)
+	block, err := unmarshalBlockSSZ(&pbBlock)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errMalformedBlock, err)
+	}
+	return block, nil
 }
 
 func writeBlockToStream(s network.Stream, block *pb.RawFullBlock) error {
@@ -142,6 +149,9 @@ func RequestBlocks(ctx context.Context, networkManager *network.Manager, peerID
 		for {
 			block, err := readBlockFromStream(stream)
 			if err != nil {
+				if errors.Is(err, errMalformedBlock) {
+					networkManager.ReportPeer(peerID, network.MisbehaviorMalformedMessage)
+				}
 				logError(logger, err, "Failed to handle input block")
 				break
 			}
@@ -228,15 +238,7 @@ func SetRequestHandler(
 				break
 			}
 
-			b := &pb.RawFullBlock{
-				BlockSSZ:           resp.Block(),
-				OutTransactionsSSZ: resp.OutTransactions(),
-				InTransactionsSSZ:  resp.InTransactions(),
-				ChildBlocks:        pb.PackHashes(resp.ChildBlocks()),
-				Config:             resp.Config(),
-			}
-
-			if err := writeBlockToStream(s, b); err != nil {
+			if err := writeBlockToStream(s, newRawFullBlock(resp, false)); err != nil {
 				logError(logger, err, "Failed to handle output block")
 				return
 			}
@@ -244,4 +246,5 @@ func SetRequestHandler(
 	}
 
 	networkManager.SetStreamHandler(ctx, protocolShardBlock(shardId), handler)
+	setBlockBatchRequestHandler(ctx, networkManager, shardId, database, accessor, logger)
 }
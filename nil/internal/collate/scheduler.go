@@ -38,6 +38,8 @@ type Params struct {
 	Topology ShardTopology
 
 	L1Fetcher rollup.L1BlockFetcher
+
+	StatePruning *execution.StatePruningConfig
 }
 
 type Scheduler struct {
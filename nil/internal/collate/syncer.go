@@ -158,14 +158,18 @@ func (s *Syncer) Run(ctx context.Context) error {
 	}
 	defer sub.Close()
 
-	ch := sub.Start(ctx, true)
+	ch := sub.StartMessages(ctx, true)
 	for {
 		select {
 		case <-ctx.Done():
 			s.logger.Debug().Msg("Syncer is terminated")
 			return nil
-		case data := <-ch:
-			saved, err := s.processTopicTransaction(ctx, data)
+		case msg, ok := <-ch:
+			if !ok {
+				s.logger.Debug().Msg("Syncer subscription is closed")
+				return nil
+			}
+			saved, err := s.processTopicTransaction(ctx, msg)
 			if err != nil {
 				s.logger.Error().Err(err).Msg("Failed to process topic transaction")
 			}
@@ -180,13 +184,15 @@ func (s *Syncer) Run(ctx context.Context) error {
 	}
 }
 
-func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool, error) {
+func (s *Syncer) processTopicTransaction(ctx context.Context, msg *network.Message) (bool, error) {
 	var pbBlock pb.RawFullBlock
-	if err := proto.Unmarshal(data, &pbBlock); err != nil {
+	if err := proto.Unmarshal(msg.Data, &pbBlock); err != nil {
+		s.networkManager.ReportPeer(msg.From, network.MisbehaviorMalformedMessage)
 		return false, err
 	}
 	b, err := unmarshalBlockSSZ(&pbBlock)
 	if err != nil {
+		s.networkManager.ReportPeer(msg.From, network.MisbehaviorMalformedMessage)
 		return false, err
 	}
 
@@ -202,6 +208,9 @@ func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool
 			return false, nil
 		case errors.Is(err, errOldBlock):
 			return false, nil
+		case errors.Is(err, errInvalidSignature):
+			s.networkManager.ReportPeer(msg.From, network.MisbehaviorInvalidBlock)
+			return false, err
 		default:
 			return false, err
 		}
@@ -218,11 +227,22 @@ func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool
 }
 
 func (s *Syncer) fetchBlocks(ctx context.Context) {
+	if peers := s.networkManager.GetPeersForProtocol(protocolShardBlockBatch(s.config.ShardId)); len(peers) > 0 {
+		err := s.fetchBlockBatches(ctx, peers)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		s.logger.Warn().Err(err).Msg("Failed to fetch block batches, falling back to the sequential protocol")
+	}
+	s.fetchBlocksSequentially(ctx)
+}
+
+func (s *Syncer) fetchBlocksSequentially(ctx context.Context) {
 	// todo: fetch blocks until the queue (see todo above) is empty
 	for {
 		s.logger.Trace().Msg("Fetching next blocks")
 
-		blocksCh := s.fetchBlocksRange(ctx)
+		peer, blocksCh := s.fetchBlocksRange(ctx)
 		if blocksCh == nil {
 			return
 		}
@@ -233,6 +253,9 @@ func (s *Syncer) fetchBlocks(ctx context.Context) {
 				if errors.Is(err, errOldBlock) {
 					continue
 				}
+				if errors.Is(err, errInvalidSignature) {
+					s.networkManager.ReportPeer(peer, network.MisbehaviorInvalidBlock)
+				}
 				s.logger.Error().
 					Err(err).
 					Stringer(logging.FieldBlockNumber, block.Id).
@@ -247,19 +270,20 @@ func (s *Syncer) fetchBlocks(ctx context.Context) {
 	}
 }
 
-func (s *Syncer) fetchBlocksRange(ctx context.Context) <-chan *types.BlockWithExtractedData {
+// fetchBlocksRange requests the blocks following the last one from the first peer that serves them.
+func (s *Syncer) fetchBlocksRange(ctx context.Context) (network.PeerID, <-chan *types.BlockWithExtractedData) {
 	peers := ListPeers(s.networkManager, s.config.ShardId)
 
 	if len(peers) == 0 {
 		s.logger.Warn().Msg("No peers to fetch block from")
-		return nil
+		return "", nil
 	}
 
 	s.logger.Trace().Msgf("Found %d peers to fetch block from:\n%v", len(peers), peers)
 
 	lastBlock, _, err := s.validator.GetLastBlock(ctx)
 	if err != nil {
-		return nil
+		return "", nil
 	}
 
 	for _, p := range peers {
@@ -267,7 +291,7 @@ func (s *Syncer) fetchBlocksRange(ctx context.Context) <-chan *types.BlockWithEx
 
 		blocksCh, err := RequestBlocks(ctx, s.networkManager, p, s.config.ShardId, lastBlock.Id+1, s.logger)
 		if err == nil {
-			return blocksCh
+			return p, blocksCh
 		}
 
 		if errors.As(err, &multistream.ErrNotSupported[network.ProtocolID]{}) {
@@ -277,7 +301,7 @@ func (s *Syncer) fetchBlocksRange(ctx context.Context) <-chan *types.BlockWithEx
 		}
 	}
 
-	return nil
+	return "", nil
 }
 
 func (s *Syncer) saveBlock(ctx context.Context, block *types.BlockWithExtractedData) error {
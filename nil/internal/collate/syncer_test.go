@@ -0,0 +1,77 @@
+package collate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncerGenerateZerostate(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardId := types.MainShardId
+	addr := types.GenerateRandomAddress(shardId)
+	token := types.TokenId(types.GenerateRandomAddress(shardId))
+	slot := common.HexToHash("0x01")
+	value := common.HexToHash("0x1234")
+
+	zeroState := &execution.ZeroStateConfig{}
+	zeroState.AddGenesisAlloc(execution.GenesisAlloc{
+		addr: {
+			Balance: types.NewValueFromUint64(1000),
+			Code:    []byte{0x60, 0x00},
+			Storage: map[common.Hash]common.Hash{slot: value},
+			Tokens:  map[types.TokenId]types.Value{token: types.NewValueFromUint64(10)},
+		},
+	})
+
+	params := &Params{BlockGeneratorParams: execution.NewBlockGeneratorParams(shardId, 2)}
+	syncer, err := NewSyncer(&SyncerConfig{
+		BlockGeneratorParams: params.BlockGeneratorParams,
+		Name:                 "syncer",
+		ShardId:              shardId,
+		Timeout:              time.Second,
+		ZeroStateConfig:      zeroState,
+	}, NewValidator(params, database, nil, nil), database, nil)
+	require.NoError(t, err)
+	require.NoError(t, syncer.GenerateZerostate(ctx))
+
+	tx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	hash, err := db.ReadLastBlockHash(tx, shardId)
+	require.NoError(t, err)
+	block, err := db.ReadBlock(tx, shardId, hash)
+	require.NoError(t, err)
+	configAccessor, err := config.NewConfigAccessorTx(tx, &hash)
+	require.NoError(t, err)
+	state, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          block,
+		ConfigAccessor: configAccessor,
+	})
+	require.NoError(t, err)
+
+	balance, err := state.GetBalance(addr)
+	require.NoError(t, err)
+	require.Equal(t, types.NewValueFromUint64(1000), balance)
+	actual, err := state.GetState(addr, slot)
+	require.NoError(t, err)
+	require.Equal(t, value, actual)
+	require.Equal(t, map[types.TokenId]types.Value{token: types.NewValueFromUint64(10)}, state.GetTokens(addr))
+
+	// The shard isn't empty anymore, so the zero state is not generated again.
+	require.NoError(t, syncer.GenerateZerostate(ctx))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -39,6 +40,9 @@ type Validator struct {
 	lastBlock     *types.Block
 	lastBlockHash common.Hash
 
+	// pruning is set while the state is pruned in the background.
+	pruning atomic.Bool
+
 	logger zerolog.Logger
 }
 
@@ -201,6 +205,28 @@ func (s *Validator) onBlockCommitUnlocked(
 				Msgf("Failed to remove %d committed transactions from pool", len(proposal.ExternalTxns))
 		}
 	}
+
+	if s.params.StatePruning.ShouldPrune(res.Block.Id) && s.pruning.CompareAndSwap(false, true) {
+		go func() {
+			defer s.pruning.Store(false)
+			s.pruneState(ctx)
+		}()
+	}
+}
+
+// pruneState removes the state of the old blocks.
+// It runs in the background without the lock, the blocks committed meanwhile are kept by PruneState.
+// The next run is skipped if the previous one is not finished yet.
+func (s *Validator) pruneState(ctx context.Context) {
+	res, err := execution.PruneState(ctx, s.txFabric, s.params.ShardId, s.params.StatePruning.KeepBlocks)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to prune state")
+		return
+	}
+	s.logger.Debug().
+		Stringer(logging.FieldBlockNumber, res.OldestBlock).
+		Int("deletedNodes", res.DeletedNodes).
+		Msg("State pruned")
 }
 
 func (s *Validator) logBlockDiffError(expected, got *types.Block, expHash, gotHash common.Hash) error {
@@ -54,6 +54,33 @@ type IConfigParam interface {
 	Accessor() *ParamAccessor
 }
 
+// paramValidator is implemented by the params whose values are restricted beyond their encoding.
+// The values are validated on every write, including the writes from the contracts.
+type paramValidator interface {
+	Validate() error
+}
+
+// versionedParam is implemented by the params whose SSZ layout has changed.
+// The data of the current layout is marked with a version, the data written before is migrated on reading.
+type versionedParam interface {
+	marshalVersioned() ([]byte, error)
+	unmarshalVersioned(data []byte) error
+}
+
+func marshalParam(p ssz.Marshaler) ([]byte, error) {
+	if versioned, ok := p.(versionedParam); ok {
+		return versioned.marshalVersioned()
+	}
+	return p.MarshalSSZ()
+}
+
+func unmarshalParam(p ssz.Unmarshaler, data []byte) error {
+	if versioned, ok := p.(versionedParam); ok {
+		return versioned.unmarshalVersioned(data)
+	}
+	return p.UnmarshalSSZ(data)
+}
+
 // IConfigParamPointer is an interface that allows to avoid error like:
 // `... does not satisfy IConfigParam (method ... has pointer receiver)`
 type IConfigParamPointer[T any] interface {
@@ -83,7 +110,7 @@ type ConfigAccessorStub struct{}
 var _ ConfigAccessor = (*ConfigAccessorStub)(nil)
 
 func (c *ConfigAccessorStub) GetParamData(name string) ([]byte, error) {
-	return nil, errors.New("stub config accessor should not be called")
+	return nil, fmt.Errorf("%w: stub config accessor has no params", ErrParamNotFound)
 }
 
 func (c *ConfigAccessorStub) GetParams() (map[string][]byte, error) {
@@ -243,7 +270,7 @@ func getParamImpl[T any, paramPtr IConfigParamPointer[T]](c ConfigAccessor) (*T,
 	if err != nil {
 		return nil, err
 	}
-	if err := res.UnmarshalSSZ(data); err != nil {
+	if err := unmarshalParam(res, data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config param: %w", err)
 	}
 	return res, nil
@@ -253,8 +280,13 @@ func getParamImpl[T any, paramPtr IConfigParamPointer[T]](c ConfigAccessor) (*T,
 func setParamImpl[T any](c ConfigAccessor, obj *T) error {
 	if configParam, ok := any(obj).(IConfigParam); ok {
 		name := configParam.Name()
+		if validator, ok := any(obj).(paramValidator); ok {
+			if err := validator.Validate(); err != nil {
+				return fmt.Errorf("invalid config param %s: %w", name, err)
+			}
+		}
 		if marshaler, ok := any(obj).(ssz.Marshaler); ok {
-			data, err := marshaler.MarshalSSZ()
+			data, err := marshalParam(marshaler)
 			if err != nil {
 				return fmt.Errorf("failed to marshal config param %s: %w", name, err)
 			}
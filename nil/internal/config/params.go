@@ -1,8 +1,10 @@
 package config
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -20,12 +22,14 @@ const (
 	NameValidators = "curr_validators"
 	NameGasPrice   = "gas_price"
 	NameL1Block    = "l1block"
+	NameFees       = "fees"
 )
 
 var ParamsList = []IConfigParam{
 	new(ParamValidators),
 	new(ParamGasPrice),
 	new(ParamL1BlockInfo),
+	new(ParamFees),
 }
 
 type Pubkey [ValidatorPubkeySize]byte
@@ -40,7 +44,7 @@ func (k *Pubkey) UnmarshalText(input []byte) error {
 
 func InitParams(accessor ConfigAccessor) {
 	for _, p := range ParamsList {
-		data, err := p.MarshalSSZ()
+		data, err := marshalParam(p)
 		check.PanicIfErr(err)
 		err = accessor.SetParamData(p.Name(), data)
 		check.PanicIfErr(err)
@@ -67,6 +71,33 @@ type ParamValidators struct {
 type ValidatorInfo struct {
 	PublicKey         Pubkey        `json:"pubKey" yaml:"pubKey" ssz-size:"128"`
 	WithdrawalAddress types.Address `json:"withdrawalAddress" yaml:"withdrawalAddress"`
+	// Weight is the stake of the validator that determines its voting power in the consensus.
+	// Zero weight is treated as 1, so the configs without weights keep equal voting powers.
+	Weight uint64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// ValidatorInfoV1 is the layout of ValidatorInfo before the weights were added.
+// The validator lists written with it are read with zero weights, so the validators keep equal voting powers.
+type ValidatorInfoV1 struct {
+	PublicKey         Pubkey        `json:"pubKey" yaml:"pubKey" ssz-size:"128"`
+	WithdrawalAddress types.Address `json:"withdrawalAddress" yaml:"withdrawalAddress"`
+}
+
+type ListValidatorsV1 struct {
+	List []ValidatorInfoV1 `json:"list" ssz-max:"4096" yaml:"list"`
+}
+
+// ParamValidatorsV1 is the layout of ParamValidators stored in the config before the weights were added.
+type ParamValidatorsV1 struct {
+	Validators []ListValidatorsV1 `json:"validators" ssz-max:"4096" yaml:"validators"`
+}
+
+// VotingPower returns the voting power of the validator in the consensus.
+func (v *ValidatorInfo) VotingPower() uint64 {
+	if v.Weight == 0 {
+		return 1
+	}
+	return v.Weight
 }
 
 var _ IConfigParam = new(ParamValidators)
@@ -79,6 +110,34 @@ func (p *ParamValidators) Accessor() *ParamAccessor {
 	return CreateAccessor[ParamValidators]()
 }
 
+// paramValidatorsVersion prefixes the encoding of ParamValidators since the weights were added.
+// The encoding of ParamValidatorsV1 starts with the offset of the list, which is always 4,
+// so the data without the prefix is decoded with the old layout.
+var paramValidatorsVersion = []byte{0xff, 2}
+
+func (p *ParamValidators) marshalVersioned() ([]byte, error) {
+	return p.MarshalSSZTo(bytes.Clone(paramValidatorsVersion))
+}
+
+func (p *ParamValidators) unmarshalVersioned(data []byte) error {
+	if rest, ok := bytes.CutPrefix(data, paramValidatorsVersion); ok {
+		return p.UnmarshalSSZ(rest)
+	}
+
+	var v1 ParamValidatorsV1
+	if err := v1.UnmarshalSSZ(data); err != nil {
+		return err
+	}
+	p.Validators = make([]ListValidators, len(v1.Validators))
+	for i, list := range v1.Validators {
+		p.Validators[i].List = make([]ValidatorInfo, len(list.List))
+		for j, v := range list.List {
+			p.Validators[i].List[j] = ValidatorInfo{PublicKey: v.PublicKey, WithdrawalAddress: v.WithdrawalAddress}
+		}
+	}
+	return nil
+}
+
 type ParamGasPrice struct {
 	Shards []types.Uint256 `json:"shards" ssz-max:"4096" yaml:"shards"`
 }
@@ -111,6 +170,81 @@ func (p *ParamL1BlockInfo) Accessor() *ParamAccessor {
 	return CreateAccessor[ParamL1BlockInfo]()
 }
 
+// FeeParams are the parameters of the base fee curve of a shard.
+// The shares of the block gas limit and the changes of the base fee are given in basis points (1/10000).
+type FeeParams struct {
+	// TargetUtilization is the gas usage of the block at which the base fee stays the same.
+	// Zero means that the params are not set, and the built-in curve is used.
+	TargetUtilization uint64 `json:"targetUtilization" yaml:"targetUtilization"`
+	// MaxChange is the limit of the base fee change per block.
+	MaxChange uint64 `json:"maxChange" yaml:"maxChange"`
+	// Smoothing is the width of the transition between the flat and the saturated parts of the curve.
+	// It can't exceed the block gas limit.
+	Smoothing uint64 `json:"smoothing" yaml:"smoothing"`
+	// MinBaseFee is the lower bound of the base fee. Zero means types.DefaultGasPrice.
+	MinBaseFee types.Uint256 `json:"minBaseFee" yaml:"minBaseFee"`
+}
+
+// IsSet reports whether the params are set.
+func (p *FeeParams) IsSet() bool {
+	return p.TargetUtilization != 0
+}
+
+// Validate checks that the params describe a valid curve.
+func (p *FeeParams) Validate() error {
+	if !p.IsSet() {
+		return nil
+	}
+	if p.TargetUtilization >= BasisPoints {
+		return fmt.Errorf("target utilization %d must be less than %d", p.TargetUtilization, BasisPoints)
+	}
+	if p.MaxChange == 0 || p.MaxChange >= BasisPoints {
+		return fmt.Errorf("max change %d must be in (0, %d)", p.MaxChange, BasisPoints)
+	}
+	if p.Smoothing == 0 || p.Smoothing > BasisPoints {
+		return fmt.Errorf("smoothing %d must be in (0, %d]", p.Smoothing, BasisPoints)
+	}
+	return nil
+}
+
+// BasisPoints is the denominator of the shares in FeeParams.
+const BasisPoints = 10000
+
+// ParamFees holds the base fee curves of the shards, indexed by the shard id.
+type ParamFees struct {
+	Shards []FeeParams `json:"shards" ssz-max:"4096" yaml:"shards"`
+}
+
+var (
+	_ IConfigParam   = new(ParamFees)
+	_ paramValidator = new(ParamFees)
+)
+
+func (p *ParamFees) Name() string {
+	return NameFees
+}
+
+func (p *ParamFees) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamFees]()
+}
+
+func (p *ParamFees) Validate() error {
+	for i := range p.Shards {
+		if err := p.Shards[i].Validate(); err != nil {
+			return fmt.Errorf("invalid fee params of shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ForShard returns the params of the shard, or nil if they are not set.
+func (p *ParamFees) ForShard(shardId types.ShardId) *FeeParams {
+	if int(shardId) >= len(p.Shards) || !p.Shards[shardId].IsSet() {
+		return nil
+	}
+	return &p.Shards[shardId]
+}
+
 func CreateAccessor[T any, paramPtr IConfigParamPointer[T]]() *ParamAccessor {
 	return &ParamAccessor{
 		func(c ConfigAccessor) (any, error) {
@@ -289,6 +423,26 @@ func SetParamL1Block(c ConfigAccessor, params *ParamL1BlockInfo) error {
 	return setParamImpl(c, params)
 }
 
+func GetParamFees(c ConfigAccessor) (*ParamFees, error) {
+	return getParamImpl[ParamFees](c)
+}
+
+func SetParamFees(c ConfigAccessor, params *ParamFees) error {
+	return setParamImpl(c, params)
+}
+
+// GetShardFeeParams returns the fee params of the shard, or nil if they are not set.
+func GetShardFeeParams(c ConfigAccessor, shardId types.ShardId) (*FeeParams, error) {
+	param, err := getParamImpl[ParamFees](c)
+	if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return param.ForShard(shardId), nil
+}
+
 func GetParamNShards(c ConfigAccessor) (uint32, error) {
 	param, err := getParamImpl[ParamGasPrice](c)
 	if err != nil {
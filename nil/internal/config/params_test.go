@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	ssz "github.com/NilFoundation/fastssz"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamValidatorsOldLayout(t *testing.T) {
+	t.Parallel()
+
+	key1, key2 := Pubkey{0x01}, Pubkey{0x02}
+	address := types.HexToAddress("0x1234")
+
+	// the list written before the weights were added
+	old := &ParamValidatorsV1{Validators: []ListValidatorsV1{
+		{List: []ValidatorInfoV1{{PublicKey: key1, WithdrawalAddress: address}, {PublicKey: key2}}},
+		{List: []ValidatorInfoV1{{PublicKey: key2, WithdrawalAddress: address}}},
+	}}
+	data, err := old.MarshalSSZ()
+	require.NoError(t, err)
+
+	accessor := NewConfigAccessorFromMap(map[string][]byte{NameValidators: data})
+	param, err := GetParamValidators(accessor)
+	require.NoError(t, err)
+	require.Equal(t, []ListValidators{
+		{List: []ValidatorInfo{{PublicKey: key1, WithdrawalAddress: address}, {PublicKey: key2}}},
+		{List: []ValidatorInfo{{PublicKey: key2, WithdrawalAddress: address}}},
+	}, param.Validators)
+	require.Equal(t, uint64(1), param.Validators[0].List[0].VotingPower())
+
+	// the list is written with the current layout and keeps the weights
+	param.Validators[0].List[0].Weight = 5
+	require.NoError(t, SetParamValidators(accessor, param))
+	updated, err := GetParamValidators(accessor)
+	require.NoError(t, err)
+	require.Equal(t, param, updated)
+	require.Equal(t, uint64(5), updated.Validators[0].List[0].VotingPower())
+}
+
+func TestParamValidatorsEmpty(t *testing.T) {
+	t.Parallel()
+
+	for _, param := range []ssz.Marshaler{
+		&ParamValidatorsV1{}, &ParamValidators{},
+	} {
+		data, err := marshalParam(param)
+		require.NoError(t, err)
+
+		decoded := &ParamValidators{}
+		require.NoError(t, unmarshalParam(decoded, data))
+		require.Empty(t, decoded.Validators)
+	}
+}
@@ -213,6 +213,7 @@ func (i *backendIBFT) Init(ctx context.Context) error {
 	return i.setupTransport(ctx)
 }
 
+// GetVotingPowers returns the weights of the validators from the config that is used at the given height.
 func (i *backendIBFT) GetVotingPowers(height uint64) (map[string]*big.Int, error) {
 	validators, err := i.validatorsCache.getValidators(i.ctx, height)
 	if err != nil {
@@ -225,7 +226,7 @@ func (i *backendIBFT) GetVotingPowers(height uint64) (map[string]*big.Int, error
 
 	result := make(map[string]*big.Int, len(validators))
 	for _, v := range validators {
-		result[string(v.PublicKey[:])] = big.NewInt(1)
+		result[string(v.PublicKey[:])] = new(big.Int).SetUint64(v.VotingPower())
 	}
 	return result, nil
 }
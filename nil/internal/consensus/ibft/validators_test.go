@@ -0,0 +1,152 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/go-ibft/core"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(b byte, weight uint64) config.ValidatorInfo {
+	var pubkey config.Pubkey
+	pubkey[0] = b
+	return config.ValidatorInfo{PublicKey: pubkey, Weight: weight}
+}
+
+// writeTestBlock writes the block of the shard and indexes it by number.
+func writeTestBlock(t *testing.T, tx db.RwTx, shardId types.ShardId, block *types.Block) common.Hash {
+	t.Helper()
+
+	hash := block.Hash(shardId)
+	require.NoError(t, db.WriteBlock(tx, shardId, hash, block))
+	require.NoError(t, tx.PutToShard(shardId, db.BlockHashByNumberIndex, block.Id.Bytes(), hash.Bytes()))
+	return hash
+}
+
+// writeTestConfig commits the validators of the shard to the config trie and returns the new root.
+func writeTestConfig(
+	t *testing.T, tx db.RwTx, root common.Hash, validators ...config.ValidatorInfo,
+) common.Hash {
+	t.Helper()
+
+	accessor := config.NewConfigAccessorFromMap(nil)
+	param := &config.ParamValidators{
+		Validators: []config.ListValidators{{List: validators}},
+	}
+	require.NoError(t, config.SetParam(accessor, config.NameValidators, param))
+	root, err := accessor.Commit(tx, root)
+	require.NoError(t, err)
+	return root
+}
+
+func votingPowers(t *testing.T, backend *backendIBFT, height uint64) map[string]int64 {
+	t.Helper()
+
+	powers, err := backend.GetVotingPowers(height)
+	require.NoError(t, err)
+
+	res := make(map[string]int64, len(powers))
+	for k, v := range powers {
+		res[k] = v.Int64()
+	}
+	return res
+}
+
+func TestGetVotingPowers(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	heavy := newTestValidator(1, 10)
+	light1 := newTestValidator(2, 1)
+	light2 := newTestValidator(3, 0)
+	light3 := newTestValidator(4, 1)
+	key := func(v config.ValidatorInfo) string {
+		return string(v.PublicKey[:])
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	// The weight of the heavy validator is changed in the first main shard block.
+	root0 := writeTestConfig(t, tx, common.EmptyHash, heavy, light1, light2, light3)
+	mainHash0 := writeTestBlock(t, tx, types.MainShardId, &types.Block{
+		BlockData: types.BlockData{Id: 0, ConfigRoot: root0},
+	})
+	heavy.Weight = 1
+	root1 := writeTestConfig(t, tx, root0, heavy, light1, light2, light3)
+	mainHash1 := writeTestBlock(t, tx, types.MainShardId, &types.Block{
+		BlockData: types.BlockData{Id: 1, PrevBlock: mainHash0, ConfigRoot: root1},
+	})
+
+	// The first shard block observes the old config, the second one observes the new config.
+	shardHash0 := writeTestBlock(t, tx, types.BaseShardId, &types.Block{
+		BlockData: types.BlockData{Id: 0, MainChainHash: mainHash0},
+	})
+	writeTestBlock(t, tx, types.BaseShardId, &types.Block{
+		BlockData: types.BlockData{Id: 1, PrevBlock: shardHash0, MainChainHash: mainHash1},
+	})
+	require.NoError(t, tx.Commit())
+
+	backend := &backendIBFT{
+		ctx:             ctx,
+		shardId:         types.BaseShardId,
+		logger:          logging.NewLogger("Test"),
+		validatorsCache: newValidatorsMap(database, types.BaseShardId),
+	}
+
+	t.Run("WeightedPowers", func(t *testing.T) {
+		require.Equal(t, map[string]int64{
+			key(heavy):  10,
+			key(light1): 1,
+			key(light2): 1,
+			key(light3): 1,
+		}, votingPowers(t, backend, 1))
+	})
+
+	t.Run("HeavyMinorityReachesQuorum", func(t *testing.T) {
+		vm := core.NewValidatorManager(backend, nil)
+		require.NoError(t, vm.Init(1))
+
+		// The heavy validator alone has 10 of 13 votes, the quorum is 9.
+		require.True(t, vm.HasQuorum(map[string]struct{}{key(heavy): {}}))
+		require.False(t, vm.HasQuorum(map[string]struct{}{
+			key(light1): {},
+			key(light2): {},
+			key(light3): {},
+		}))
+	})
+
+	t.Run("WeightChangeAppliesFromNextHeight", func(t *testing.T) {
+		require.Equal(t, map[string]int64{
+			key(heavy):  1,
+			key(light1): 1,
+			key(light2): 1,
+			key(light3): 1,
+		}, votingPowers(t, backend, 2))
+
+		vm := core.NewValidatorManager(backend, nil)
+		require.NoError(t, vm.Init(2))
+		require.False(t, vm.HasQuorum(map[string]struct{}{key(heavy): {}}))
+		require.True(t, vm.HasQuorum(map[string]struct{}{
+			key(heavy):  {},
+			key(light1): {},
+			key(light2): {},
+		}))
+	})
+
+	t.Run("UnknownHeight", func(t *testing.T) {
+		_, err := backend.GetVotingPowers(3)
+		require.Error(t, err)
+	})
+}
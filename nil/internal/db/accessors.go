@@ -133,6 +133,41 @@ func WriteLastBlockHash(tx RwTx, shardId types.ShardId, hash common.Hash) error
 	return tx.Put(LastBlockTable, shardId.Bytes(), hash.Bytes())
 }
 
+// ReadLastBlockHashes returns the hashes of the last blocks of all the shards that have blocks.
+func ReadLastBlockHashes(tx RoTx) (map[types.ShardId]common.Hash, error) {
+	iter, err := tx.Range(LastBlockTable, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	res := make(map[types.ShardId]common.Hash)
+	for iter.HasNext() {
+		key, value, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		res[types.BytesToShardId(key)] = common.BytesToHash(value)
+	}
+	return res, nil
+}
+
+// ReadOldestStateBlock returns the number of the oldest block of the shard whose state was not pruned.
+// ErrKeyNotFound means that the state of the shard was never pruned.
+func ReadOldestStateBlock(tx RoTx, shardId types.ShardId) (types.BlockNumber, error) {
+	value, err := tx.Get(oldestStateBlockTable, shardId.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return types.BlockNumber(binary.LittleEndian.Uint64(value)), nil
+}
+
+func WriteOldestStateBlock(tx RwTx, shardId types.ShardId, blockNumber types.BlockNumber) error {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, uint64(blockNumber))
+	return tx.Put(oldestStateBlockTable, shardId.Bytes(), value)
+}
+
 func WriteBlockTimestamp(tx RwTx, shardId types.ShardId, blockHash common.Hash, timestamp uint64) error {
 	value := make([]byte, 8)
 	binary.LittleEndian.PutUint64(value, timestamp)
@@ -151,6 +186,14 @@ func WriteBlock(tx RwTx, shardId types.ShardId, hash common.Hash, block *types.B
 	return writeEncodable(tx, blockTable, shardId, hash, block)
 }
 
+// DeleteBlock removes the header and the timestamp of the block.
+func DeleteBlock(tx RwTx, shardId types.ShardId, hash common.Hash) error {
+	if err := tx.DeleteFromShard(shardId, blockTable, hash.Bytes()); err != nil {
+		return err
+	}
+	return tx.DeleteFromShard(shardId, blockTimestampTable, hash.Bytes())
+}
+
 func WriteError(tx RwTx, txnHash common.Hash, errMsg string) error {
 	return tx.Put(errorByTransactionHashTable, txnHash.Bytes(), []byte(errMsg))
 }
@@ -163,6 +206,10 @@ func ReadError(tx RoTx, txnHash common.Hash) (string, error) {
 	return string(res), nil
 }
 
+func DeleteError(tx RwTx, txnHash common.Hash) error {
+	return tx.Delete(errorByTransactionHashTable, txnHash.Bytes())
+}
+
 func WriteCode(tx RwTx, shardId types.ShardId, hash common.Hash, code types.Code) error {
 	return tx.PutToShard(shardId, codeTable, hash.Bytes(), code[:])
 }
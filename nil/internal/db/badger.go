@@ -21,21 +21,6 @@ type badgerDB struct {
 	lock     sync.Mutex
 }
 
-type BadgerDBOptions struct {
-	Path         string        `yaml:"path"`
-	DiscardRatio float64       `yaml:"gcDiscardRatio,omitempty"`
-	GcFrequency  time.Duration `yaml:"gcFrequency,omitempty"`
-	AllowDrop    bool          `yaml:"allowDrop,omitempty"`
-}
-
-func NewDefaultBadgerDBOptions() *BadgerDBOptions {
-	return &BadgerDBOptions{
-		Path:         "test.db",
-		DiscardRatio: 0.5,
-		GcFrequency:  time.Hour,
-	}
-}
-
 type BadgerRoTx struct {
 	tx       *badger.Txn
 	onFinish assert.TxFinishCb
@@ -177,15 +162,22 @@ func (db *badgerDB) LogGC(ctx context.Context, discardRation float64, gcFrequenc
 	}
 }
 
+func convertBadgerError(err error) error {
+	if errors.Is(err, badger.ErrConflict) {
+		return ErrConflict
+	}
+	return err
+}
+
 func (tx *BadgerRwTx) Commit() error {
 	tx.onFinish()
-	return tx.tx.Commit()
+	return convertBadgerError(tx.tx.Commit())
 }
 
 func (tx *BadgerRwTx) CommitWithTs() (Timestamp, error) {
 	tx.onFinish()
 	ts, err := tx.tx.CommitWithTs()
-	return Timestamp(ts), err
+	return Timestamp(ts), convertBadgerError(err)
 }
 
 func (tx *BadgerRoTx) Rollback() {
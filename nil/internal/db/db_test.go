@@ -8,27 +8,52 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
-type SuiteBadgerDb struct {
+// testEngine opens the databases of an engine for the conformance suite.
+type testEngine struct {
+	name         string
+	open         func(path string) (DB, error)
+	openInMemory func() (DB, error)
+}
+
+var testEngines = []testEngine{
+	{
+		name:         "badger",
+		open:         func(path string) (DB, error) { return NewDb(EngineBadger, path) },
+		openInMemory: func() (DB, error) { return NewBadgerDbInMemory() },
+	},
+	{
+		name:         "pebble",
+		open:         func(path string) (DB, error) { return NewDb(EnginePebble, path) },
+		openInMemory: func() (DB, error) { return NewPebbleDbInMemory() },
+	},
+}
+
+// SuiteDb checks that an engine implements the contract of DB.
+type SuiteDb struct {
 	suite.Suite
 
-	ctx context.Context
-	db  DB
+	engine testEngine
+	ctx    context.Context
+	path   string
+	db     DB
 }
 
-func (s *SuiteBadgerDb) SetupSuite() {
+func (s *SuiteDb) SetupSuite() {
 	s.ctx = context.Background()
 }
 
-func (s *SuiteBadgerDb) SetupTest() {
+func (s *SuiteDb) SetupTest() {
 	var err error
-	s.db, err = NewBadgerDb(s.Suite.T().TempDir())
+	s.path = s.Suite.T().TempDir()
+	s.db, err = s.engine.open(s.path)
 	s.Require().NoError(err)
 }
 
-func (s *SuiteBadgerDb) TearDownTest() {
+func (s *SuiteDb) TearDownTest() {
 	s.db.Close()
 }
 
@@ -216,7 +241,7 @@ func ValidateDbOperations(s *suite.Suite, d DB) {
 	})
 }
 
-func (s *SuiteBadgerDb) TestTwoParallelTransaction() {
+func (s *SuiteDb) TestTwoParallelTransaction() {
 	ctx := context.Background()
 
 	tx, err := s.db.CreateRwTx(ctx)
@@ -242,27 +267,27 @@ func (s *SuiteBadgerDb) TestTwoParallelTransaction() {
 	s.Suite.Require().NoError(tx2.Commit())
 }
 
-func (s *SuiteBadgerDb) TestValidateTables() {
+func (s *SuiteDb) TestValidateTables() {
 	ValidateTables(&s.Suite, s.db)
 }
 
-func (s *SuiteBadgerDb) TestValidateTablesName() {
+func (s *SuiteDb) TestValidateTablesName() {
 	ValidateTablesName(&s.Suite, s.db)
 }
 
-func (s *SuiteBadgerDb) TestValidateTransaction() {
+func (s *SuiteDb) TestValidateTransaction() {
 	ValidateTransaction(&s.Suite, s.db)
 }
 
-func (s *SuiteBadgerDb) TestValidateBlock() {
+func (s *SuiteDb) TestValidateBlock() {
 	ValidateBlock(&s.Suite, s.db)
 }
 
-func (s *SuiteBadgerDb) TestValidateDbOperations() {
+func (s *SuiteDb) TestValidateDbOperations() {
 	ValidateDbOperations(&s.Suite, s.db)
 }
 
-func (s *SuiteBadgerDb) fillData(tbl string) {
+func (s *SuiteDb) fillData(tbl string) {
 	s.T().Helper()
 
 	tx, err := s.db.CreateRwTx(s.ctx)
@@ -284,7 +309,7 @@ func (s *SuiteBadgerDb) fillData(tbl string) {
 	s.Require().NoError(tx.Commit())
 }
 
-func (s *SuiteBadgerDb) TestRange() {
+func (s *SuiteDb) TestRange() {
 	db := s.db
 	ctx := context.Background()
 
@@ -438,7 +463,7 @@ func (s *SuiteBadgerDb) TestRange() {
 	})
 }
 
-func (s *SuiteBadgerDb) TestTimestamps() {
+func (s *SuiteDb) TestTimestamps() {
 	db := s.db
 	ctx := context.Background()
 	var ts Timestamp
@@ -481,9 +506,160 @@ func (s *SuiteBadgerDb) TestTimestamps() {
 		s.Require().NoError(tx.Put("tbl", []byte("foo"), []byte("bar2")))
 		s.Require().NoError(tx.Commit())
 	})
+
+	s.Run("read-history", func() {
+		tx, err := db.CreateRoTxAt(ctx, ts)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+
+		val, err := tx.Get("tbl", []byte("foo"))
+		s.Require().NoError(err)
+		s.Require().Equal([]byte("bar"), val)
+	})
 }
 
-func (s *SuiteBadgerDb) TestStreamLoad() {
+func (s *SuiteDb) TestConflict() {
+	ctx := context.Background()
+
+	tx1, err := s.db.CreateRwTx(ctx)
+	s.Require().NoError(err)
+	defer tx1.Rollback()
+
+	tx2, err := s.db.CreateRwTx(ctx)
+	s.Require().NoError(err)
+	defer tx2.Rollback()
+
+	_, err = tx1.Get("tbl", []byte("foo"))
+	s.Require().ErrorIs(err, ErrKeyNotFound)
+	s.Require().NoError(tx1.Put("tbl", []byte("bar"), []byte("1")))
+
+	s.Require().NoError(tx2.Put("tbl", []byte("foo"), []byte("2")))
+	s.Require().NoError(tx2.Commit())
+
+	s.Require().ErrorIs(tx1.Commit(), ErrConflict)
+
+	// Blind writes don't conflict.
+	tx3, err := s.db.CreateRwTx(ctx)
+	s.Require().NoError(err)
+	defer tx3.Rollback()
+
+	tx4, err := s.db.CreateRwTx(ctx)
+	s.Require().NoError(err)
+	defer tx4.Rollback()
+
+	s.Require().NoError(tx3.Put("tbl", []byte("foo"), []byte("3")))
+	s.Require().NoError(tx4.Put("tbl", []byte("foo"), []byte("4")))
+	s.Require().NoError(tx4.Commit())
+	s.Require().NoError(tx3.Commit())
+}
+
+func (s *SuiteDb) TestRangeWithPendingWrites() {
+	s.fillData("pending")
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	s.Require().NoError(tx.Put("pending", []byte("key2"), []byte("value2.2")))
+	s.Require().NoError(tx.Put("pending", []byte("key3"), []byte("value3.2")))
+	s.Require().NoError(tx.Delete("pending", []byte("key4")))
+	s.Require().NoError(tx.Put("pending", []byte("key5"), []byte("value5.2")))
+
+	it, err := tx.Range("pending", []byte("key1"), []byte("key4"))
+	s.Require().NoError(err)
+	defer it.Close()
+
+	var keys, values []string
+	for it.HasNext() {
+		k, v, err := it.Next()
+		s.Require().NoError(err)
+		keys = append(keys, string(k))
+		values = append(values, string(v))
+	}
+	s.Equal([]string{"key1", "key2", "key3"}, keys)
+	s.Equal([]string{"value1.1", "value2.2", "value3.2"}, values)
+}
+
+func (s *SuiteDb) TestBinaryKeys() {
+	keys := [][]byte{{0}, {0, 0}, {0, 1}, {1, 0}, {1, 0, 0xFF}, {0xFF}}
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	for i, key := range keys {
+		s.Require().NoError(tx.Put("bin", key, []byte{byte(i)}))
+	}
+	s.Require().NoError(tx.Commit())
+
+	roTx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer roTx.Rollback()
+
+	it, err := roTx.Range("bin", nil, nil)
+	s.Require().NoError(err)
+	defer it.Close()
+
+	var actual [][]byte
+	for it.HasNext() {
+		k, v, err := it.Next()
+		s.Require().NoError(err)
+		s.Equal([]byte{byte(len(actual))}, v)
+		actual = append(actual, k)
+	}
+	s.Equal(keys, actual)
+}
+
+func (s *SuiteDb) TestCollectTableStats() {
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	s.Require().NoError(WriteLastBlockHash(tx, types.MainShardId, common.EmptyHash))
+	s.Require().NoError(tx.PutToShard(types.MainShardId, ContractTrieTable, []byte("a"), []byte("1")))
+	s.Require().NoError(tx.PutToShard(types.BaseShardId, ContractTrieTable, []byte("b"), []byte("22")))
+	s.Require().NoError(tx.PutToShard(types.BaseShardId, ContractTrieTable, []byte("c"), []byte("333")))
+	s.Require().NoError(tx.PutToShard(types.BaseShardId, ContractTable, []byte("d"), nil))
+	s.Require().NoError(tx.Commit())
+
+	roTx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer roTx.Rollback()
+
+	stats, err := CollectTableStats(roTx)
+	s.Require().NoError(err)
+	s.Equal([]*TableStats{
+		{Name: LastBlockTable, Keys: 1, Size: 2 + 32},
+		{Name: "ContractTrie:0", Keys: 1, Size: 2},
+		{Name: "ContractTrie:1", Keys: 2, Size: 7},
+		{Name: "Contract:1", Keys: 1, Size: 1},
+	}, stats)
+}
+
+func (s *SuiteDb) TestReopen() {
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	s.Require().NoError(tx.Put("tbl", []byte("foo"), []byte("bar")))
+	ts, err := tx.CommitWithTs()
+	s.Require().NoError(err)
+
+	s.db.Close()
+	s.db, err = s.engine.open(s.path)
+	s.Require().NoError(err)
+
+	roTx, err := s.db.CreateRoTx(s.ctx)
+	s.Require().NoError(err)
+	defer roTx.Rollback()
+
+	s.Equal(ts, roTx.ReadTimestamp())
+	val, err := roTx.Get("tbl", []byte("foo"))
+	s.Require().NoError(err)
+	s.Equal([]byte("bar"), val)
+}
+
+func (s *SuiteDb) TestStreamLoad() {
 	s.fillData("t")
 
 	s.Run("dump and load all data", func() {
@@ -491,7 +667,7 @@ func (s *SuiteBadgerDb) TestStreamLoad() {
 		err := s.db.Stream(s.ctx, func([]byte) bool { return true }, &buf)
 		s.Require().NoError(err)
 
-		newDb, err := NewBadgerDbInMemory()
+		newDb, err := s.engine.openInMemory()
 		s.Require().NoError(err)
 		defer newDb.Close()
 
@@ -511,6 +687,39 @@ func (s *SuiteBadgerDb) TestStreamLoad() {
 		s.EqualValues("value0.3", v)
 	})
 
+	s.Run("dump and load into every engine", func() {
+		for _, engine := range testEngines {
+			var buf bytes.Buffer
+			s.Require().NoError(s.db.Stream(s.ctx, func([]byte) bool { return true }, &buf))
+
+			newDb, err := engine.openInMemory()
+			s.Require().NoError(err)
+			defer newDb.Close()
+
+			s.Require().NoError(newDb.Fetch(s.ctx, &buf), engine.name)
+
+			tx, err := newDb.CreateRwTx(s.ctx)
+			s.Require().NoError(err)
+			defer tx.Rollback()
+
+			v, err := tx.Get("t", []byte("key4"))
+			s.Require().NoError(err, engine.name)
+			s.EqualValues("value4.1", v)
+
+			// The timestamps of the loaded data don't go back.
+			s.Require().NoError(tx.Put("t", []byte("key4"), []byte("value4.2")))
+			s.Require().NoError(tx.Commit())
+
+			roTx, err := newDb.CreateRoTx(s.ctx)
+			s.Require().NoError(err)
+			defer roTx.Rollback()
+
+			v, err = roTx.Get("t", []byte("key4"))
+			s.Require().NoError(err)
+			s.EqualValues("value4.2", v, engine.name)
+		}
+	})
+
 	s.Run("dump and load with filter", func() {
 		var buf bytes.Buffer
 		err := s.db.Stream(s.ctx, func(b []byte) bool {
@@ -518,7 +727,7 @@ func (s *SuiteBadgerDb) TestStreamLoad() {
 		}, &buf)
 		s.Require().NoError(err)
 
-		newDb, err := NewBadgerDbInMemory()
+		newDb, err := s.engine.openInMemory()
 		s.Require().NoError(err)
 		defer newDb.Close()
 
@@ -555,7 +764,7 @@ func (s *SuiteBadgerDb) TestStreamLoad() {
 		}, &buf2)
 		s.Require().NoError(err)
 
-		newDb, err := NewBadgerDbInMemory()
+		newDb, err := s.engine.openInMemory()
 		s.Require().NoError(err)
 		defer newDb.Close()
 
@@ -589,8 +798,52 @@ func (s *SuiteBadgerDb) TestStreamLoad() {
 	})
 }
 
-func TestSuiteBadgerDb(t *testing.T) {
+func TestSuiteDb(t *testing.T) {
 	t.Parallel()
 
-	suite.Run(t, new(SuiteBadgerDb))
+	for _, engine := range testEngines {
+		t.Run(engine.name, func(t *testing.T) {
+			t.Parallel()
+
+			suite.Run(t, &SuiteDb{engine: engine})
+		})
+	}
+}
+
+func TestNewDbEngineMismatch(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range []Engine{EngineBadger, EnginePebble} {
+		t.Run(engine.String(), func(t *testing.T) {
+			t.Parallel()
+
+			path := t.TempDir()
+			detected, err := DetectEngine(path)
+			require.NoError(t, err)
+			require.Empty(t, detected)
+
+			db, err := NewDb(engine, path)
+			require.NoError(t, err)
+			db.Close()
+
+			detected, err = DetectEngine(path)
+			require.NoError(t, err)
+			require.Equal(t, engine, detected)
+
+			other := EnginePebble
+			if engine == EnginePebble {
+				other = EngineBadger
+			}
+			_, err = NewDb(other, path)
+			require.ErrorIs(t, err, ErrEngineMismatch)
+
+			db, err = NewDb("", path)
+			require.NoError(t, err)
+			db.Close()
+
+			db, err = NewDb(engine, path)
+			require.NoError(t, err)
+			db.Close()
+		})
+	}
 }
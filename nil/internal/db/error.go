@@ -3,3 +3,8 @@ package db
 import "errors"
 
 var ErrKeyNotFound = errors.New("key not found in db")
+
+var ErrConflict = errors.New("transaction conflict")
+
+// ErrHistoryPruned is returned when the versions to read at the timestamp may be removed already.
+var ErrHistoryPruned = errors.New("history at the timestamp is pruned")
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// Tables are the tables that are not split by shards.
+var Tables = []TableName{
+	collatorStateTable,
+	errorByTransactionHashTable,
+	schemeVersionTable,
+	LastBlockTable,
+	oldestStateBlockTable,
+	LogIndexStartTable,
+}
+
+// ShardedTables are the tables stored separately for each shard.
+// The shard blocks tries of the main shard are stored in a table per main block (see ShardBlocksTrieTableName).
+var ShardedTables = []ShardedTableName{
+	blockTable,
+	blockTimestampTable,
+	codeTable,
+	ContractTrieTable,
+	StorageTrieTable,
+	TransactionTrieTable,
+	ReceiptTrieTable,
+	TokenTrieTable,
+	ConfigTrieTable,
+	ContractTable,
+	BlockHashByNumberIndex,
+	BlockHashAndInTransactionIndexByTransactionHash,
+	BlockHashAndOutTransactionIndexByTransactionHash,
+	AsyncCallContextTable,
+	LogIndexByAddressTable,
+	LogIndexByTopicTable,
+}
+
+// TableStats is the number of the keys of a table and the total size of its keys and values.
+// The size doesn't include the overhead of the storage engine.
+type TableStats struct {
+	Name TableName `json:"name"`
+	Keys uint64    `json:"keys"`
+	Size uint64    `json:"size"`
+}
+
+func (s *TableStats) add(key, value []byte) error {
+	s.Keys++
+	s.Size += uint64(len(key) + len(value))
+	return nil
+}
+
+// CollectTableStats counts the keys of all the tables.
+// The sharded tables are reported per shard, e.g. "ContractTrie:1", the empty tables are omitted.
+func CollectTableStats(tx RoTx) ([]*TableStats, error) {
+	var res []*TableStats
+	for _, table := range Tables {
+		stats := &TableStats{Name: table}
+		if err := rangeTable(tx, table, stats.add); err != nil {
+			return nil, err
+		}
+		if stats.Keys > 0 {
+			res = append(res, stats)
+		}
+	}
+
+	for _, table := range ShardedTables {
+		stats, err := collectShardedTableStats(tx, table, table, nil)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, stats...)
+	}
+
+	// The shard blocks tries of all the main blocks are reported as a single table.
+	lastMain, _, err := ReadLastBlock(tx, types.MainShardId)
+	if errors.Is(err, ErrKeyNotFound) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var shardBlocks []*TableStats
+	for blockId := range lastMain.Id + 1 {
+		table := ShardBlocksTrieTableName(blockId)
+		shardBlocks, err = collectShardedTableStats(tx, table, shardBlocksTrieTable, shardBlocks)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(res, shardBlocks...), nil
+}
+
+// collectShardedTableStats adds the keys of the table to the stats of the shards named after the "as" table
+// (the stats of the new shards are appended).
+// The keys of the sharded tables are "<shard>:<key>" within the table without the shard suffix.
+func collectShardedTableStats(
+	tx RoTx, table, as ShardedTableName, res []*TableStats,
+) ([]*TableStats, error) {
+	byName := make(map[TableName]*TableStats, len(res))
+	for _, stats := range res {
+		byName[stats.Name] = stats
+	}
+
+	err := rangeTable(tx, TableName(table), func(key, value []byte) error {
+		shard, rest, ok := bytes.Cut(key, []byte(":"))
+		if !ok {
+			return fmt.Errorf("invalid key %x of table %s", key, table)
+		}
+		shardId, err := types.ParseShardIdFromString(string(shard))
+		if err != nil {
+			return fmt.Errorf("invalid key %x of table %s: %w", key, table, err)
+		}
+		name := ShardTableName(as, shardId)
+		stats, ok := byName[name]
+		if !ok {
+			stats = &TableStats{Name: name}
+			byName[name] = stats
+			res = append(res, stats)
+		}
+		return stats.add(rest, value)
+	})
+	return res, err
+}
+
+func rangeTable(tx RoTx, table TableName, f func(key, value []byte) error) error {
+	iter, err := tx.Range(table, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.HasNext() {
+		key, value, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if err := f(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// The log index maps the emitting addresses and the topics of the logs to the numbers of the blocks that contain them.
+// The keys are the indexed value followed by the big-endian block number, so that the blocks of a value
+// are stored in order and a range of blocks is read with a single iteration. The values are empty.
+//
+// LogIndexByAddressTable: address || block number
+// LogIndexByTopicTable:   topic position || topic || block number
+
+func logAddressPrefix(address types.Address) []byte {
+	return address.Bytes()
+}
+
+func logTopicPrefix(position int, topic common.Hash) []byte {
+	return append([]byte{byte(position)}, topic.Bytes()...)
+}
+
+func logIndexKey(prefix []byte, blockId types.BlockNumber) []byte {
+	return binary.BigEndian.AppendUint64(slices.Clone(prefix), uint64(blockId))
+}
+
+// WriteLogIndex adds the logs of the receipts of the block to the log index of the shard.
+// The first indexed block of the shard is recorded, the log index can't be used for the older blocks.
+func WriteLogIndex(tx RwTx, shardId types.ShardId, blockId types.BlockNumber, receipts []*types.Receipt) error {
+	if _, err := ReadLogIndexStart(tx, shardId); errors.Is(err, ErrKeyNotFound) {
+		value := make([]byte, 8)
+		binary.LittleEndian.PutUint64(value, uint64(blockId))
+		if err := tx.Put(LogIndexStartTable, shardId.Bytes(), value); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			key := logIndexKey(logAddressPrefix(log.Address), blockId)
+			if err := tx.PutToShard(shardId, LogIndexByAddressTable, key, nil); err != nil {
+				return err
+			}
+			for i, topic := range log.Topics {
+				key := logIndexKey(logTopicPrefix(i, topic), blockId)
+				if err := tx.PutToShard(shardId, LogIndexByTopicTable, key, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteLogIndex removes the logs of the receipts of the block from the log index of the shard.
+// If the block is the first indexed one, the index of the shard starts anew from the next written block.
+func DeleteLogIndex(tx RwTx, shardId types.ShardId, blockId types.BlockNumber, receipts []*types.Receipt) error {
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			key := logIndexKey(logAddressPrefix(log.Address), blockId)
+			if err := tx.DeleteFromShard(shardId, LogIndexByAddressTable, key); err != nil {
+				return err
+			}
+			for i, topic := range log.Topics {
+				key := logIndexKey(logTopicPrefix(i, topic), blockId)
+				if err := tx.DeleteFromShard(shardId, LogIndexByTopicTable, key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	start, err := ReadLogIndexStart(tx, shardId)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if start >= blockId {
+		return tx.Delete(LogIndexStartTable, shardId.Bytes())
+	}
+	return nil
+}
+
+// ReadLogIndexStart returns the number of the first block of the shard added to the log index.
+// ErrKeyNotFound means that no block of the shard was indexed yet.
+func ReadLogIndexStart(tx RoTx, shardId types.ShardId) (types.BlockNumber, error) {
+	value, err := tx.Get(LogIndexStartTable, shardId.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return types.BlockNumber(binary.LittleEndian.Uint64(value)), nil
+}
+
+// ReadLogBlocksByAddress returns the ascending numbers of the blocks in [from, to]
+// that contain logs emitted by the address.
+func ReadLogBlocksByAddress(
+	tx RoTx, shardId types.ShardId, address types.Address, from, to types.BlockNumber,
+) ([]types.BlockNumber, error) {
+	return readLogBlocks(tx, shardId, LogIndexByAddressTable, logAddressPrefix(address), from, to)
+}
+
+// ReadLogBlocksByTopic returns the ascending numbers of the blocks in [from, to]
+// that contain logs with the topic at the given position.
+func ReadLogBlocksByTopic(
+	tx RoTx, shardId types.ShardId, position int, topic common.Hash, from, to types.BlockNumber,
+) ([]types.BlockNumber, error) {
+	if position < 0 || position > math.MaxUint8 {
+		return nil, nil
+	}
+	return readLogBlocks(tx, shardId, LogIndexByTopicTable, logTopicPrefix(position, topic), from, to)
+}
+
+func readLogBlocks(
+	tx RoTx, shardId types.ShardId, table ShardedTableName, prefix []byte, from, to types.BlockNumber,
+) ([]types.BlockNumber, error) {
+	iter, err := tx.RangeByShard(shardId, table, logIndexKey(prefix, from), logIndexKey(prefix, to))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var res []types.BlockNumber
+	for iter.HasNext() {
+		key, _, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if len(key) != len(prefix)+8 {
+			return nil, errors.New("invalid log index key")
+		}
+		res = append(res, types.BlockNumber(binary.BigEndian.Uint64(key[len(prefix):])))
+	}
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Engine is the storage engine of the database.
+type Engine string
+
+const (
+	EngineBadger Engine = "badger"
+	EnginePebble Engine = "pebble"
+)
+
+func (e Engine) String() string {
+	return string(e)
+}
+
+func (e *Engine) Set(s string) error {
+	switch engine := Engine(s); engine {
+	case EngineBadger, EnginePebble:
+		*e = engine
+		return nil
+	default:
+		return fmt.Errorf("unknown db engine %q", s)
+	}
+}
+
+func (Engine) Type() string {
+	return "Engine"
+}
+
+type DBOptions struct {
+	Engine       Engine        `yaml:"engine,omitempty"`
+	Path         string        `yaml:"path"`
+	DiscardRatio float64       `yaml:"gcDiscardRatio,omitempty"`
+	GcFrequency  time.Duration `yaml:"gcFrequency,omitempty"`
+	AllowDrop    bool          `yaml:"allowDrop,omitempty"`
+}
+
+func NewDefaultDBOptions() *DBOptions {
+	return &DBOptions{
+		Engine:       EngineBadger,
+		Path:         "test.db",
+		DiscardRatio: 0.5,
+		GcFrequency:  time.Hour,
+	}
+}
+
+// ErrEngineMismatch is returned when the database is opened with an engine other than the one that created it.
+var ErrEngineMismatch = errors.New("db engine mismatch")
+
+// engineFiles are the files each engine creates in the database directory.
+var engineFiles = []struct {
+	engine Engine
+	file   string
+}{
+	{EngineBadger, "KEYREGISTRY"},
+	{EnginePebble, "CURRENT"},
+}
+
+// DetectEngine returns the engine of the database at the path or an empty engine if there is no database.
+func DetectEngine(pathToDb string) (Engine, error) {
+	for _, f := range engineFiles {
+		_, err := os.Stat(filepath.Join(pathToDb, f.file))
+		if err == nil {
+			return f.engine, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// NewDb opens the database of the engine at the path. An existing database must be opened
+// with the engine that created it. If the engine is not set, the detected one or badger is used.
+func NewDb(engine Engine, pathToDb string) (DB, error) {
+	detected, err := DetectEngine(pathToDb)
+	if err != nil {
+		return nil, err
+	}
+	if engine == "" {
+		engine = detected
+	}
+	if detected != "" && detected != engine {
+		return nil, fmt.Errorf("%w: database at %s is created by %s, not %s", ErrEngineMismatch, pathToDb, detected, engine)
+	}
+
+	switch engine {
+	case EngineBadger, "":
+		db, err := NewBadgerDb(pathToDb)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case EnginePebble:
+		db, err := NewPebbleDb(pathToDb)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unknown db engine %q", engine)
+	}
+}
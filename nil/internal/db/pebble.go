@@ -0,0 +1,922 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/assert"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/dgraph-io/badger/v4/pb"
+	"github.com/rs/zerolog/log"
+)
+
+// Pebble has no transactions with timestamps, so the versions of the keys are kept explicitly.
+// A key is stored as escape(key) | 0x00 0x01 | ^ts, where the zero bytes of the key are escaped as 0x00 0xFF.
+// This way the order of the keys is preserved and the versions of a key go one after another
+// from the newest to the oldest. Service keys start with 0x00 0x00 and never clash with the data.
+//
+// The versions that are not visible to the open transactions and are older than pebbleHistory commits
+// are removed by the following commits. The keys that got such versions are queued in pebbleStaleKey entries.
+
+const (
+	pebbleTsSize     = 8
+	pebbleSuffixSize = 2 + pebbleTsSize
+	pebbleMaxTs      = Timestamp(math.MaxUint64)
+
+	pebbleValue   byte = 0
+	pebbleDeleted byte = 1
+
+	// pebbleHistory is the number of the latest commits that can be read with CreateRoTxAt.
+	pebbleHistory = 1024
+	// pebbleMaxGCKeys limits the number of the stale keys cleaned up within a commit.
+	pebbleMaxGCKeys = 16 * 1024
+
+	pebbleStreamBatchSize = 4 << 20
+	pebbleFetchBatchSize  = 64 << 20
+
+	// badgerBitDelete marks the deleted entries in the backup format of badger that Stream and Fetch use.
+	badgerBitDelete byte = 1 << 0
+)
+
+var (
+	pebbleLastTsKey      = []byte("\x00\x00lastTs")
+	pebbleStaleKeyPrefix = []byte("\x00\x00stale")
+	pebbleDataLowerBound = []byte{0x00, 0x01}
+)
+
+type pebbleDB struct {
+	db       *pebble.DB
+	txLedger assert.TxLedger
+
+	// commitLock serializes the commits, so they are applied in the order of their timestamps.
+	commitLock sync.Mutex
+
+	// lock guards the fields below.
+	lock   sync.Mutex
+	lastTs Timestamp
+	// readers counts the open transactions by their read timestamps.
+	readers map[Timestamp]int
+	// commits are the recent commits to check the writing transactions for conflicts.
+	commits []pebbleCommit
+}
+
+type pebbleCommit struct {
+	ts   Timestamp
+	keys map[string]struct{}
+}
+
+type pebbleWrite struct {
+	key     string
+	value   []byte
+	deleted bool
+}
+
+type PebbleRoTx struct {
+	db       *pebbleDB
+	readTs   Timestamp
+	onFinish assert.TxFinishCb
+	finished bool
+
+	// lock guards the iterator reused for the point reads and the read set.
+	lock sync.Mutex
+	iter *pebble.Iterator
+
+	// writes and reads are only tracked by the writing transactions.
+	writes map[string]*pebbleWrite
+	reads  map[string]struct{}
+}
+
+type PebbleRwTx struct {
+	*PebbleRoTx
+}
+
+type PebbleIter struct {
+	iter        *pebble.Iterator
+	tx          *PebbleRoTx
+	tablePrefix int
+
+	// writes are the pending writes of the transaction within the range.
+	writes []*pebbleWrite
+
+	dbValid   bool
+	dbKey     []byte
+	dbValue   []byte
+	dbVersion Timestamp
+	dbDeleted bool
+
+	valid   bool
+	key     []byte
+	value   []byte
+	version Timestamp
+	err     error
+}
+
+// interfaces
+var (
+	_ RoTx = new(PebbleRoTx)
+	_ RwTx = new(PebbleRwTx)
+	_ DB   = new(pebbleDB)
+	_ Iter = new(PebbleIter)
+)
+
+func appendEscapedKey(dst, key []byte) []byte {
+	for _, b := range key {
+		if b == 0 {
+			dst = append(dst, 0x00, 0xFF)
+		} else {
+			dst = append(dst, b)
+		}
+	}
+	return dst
+}
+
+// pebbleKeyPrefix returns the common prefix of all the versions of the key.
+func pebbleKeyPrefix(key []byte) []byte {
+	return append(appendEscapedKey(make([]byte, 0, len(key)+pebbleSuffixSize), key), 0x00, 0x01)
+}
+
+func appendPebbleTs(prefix []byte, ts Timestamp) []byte {
+	return binary.BigEndian.AppendUint64(prefix, ^uint64(ts))
+}
+
+func pebbleVersionTs(versionKey []byte) Timestamp {
+	return Timestamp(^binary.BigEndian.Uint64(versionKey[len(versionKey)-pebbleTsSize:]))
+}
+
+// pebbleSplit returns the length of the key prefix shared by all the versions of the key.
+func pebbleSplit(key []byte) int {
+	n := len(key) - pebbleSuffixSize
+	if n >= 0 && key[n] == 0x00 && key[n+1] == 0x01 {
+		return n + 2
+	}
+	return len(key)
+}
+
+func decodePebbleKey(versionKey []byte) ([]byte, error) {
+	n := pebbleSplit(versionKey)
+	if n == len(versionKey) {
+		return nil, fmt.Errorf("malformed pebble key %x", versionKey)
+	}
+	encoded := versionKey[:n-2]
+	key := make([]byte, 0, len(encoded))
+	for i := 0; i < len(encoded); i++ {
+		if encoded[i] == 0x00 {
+			if i+1 == len(encoded) || encoded[i+1] != 0xFF {
+				return nil, fmt.Errorf("malformed pebble key %x", versionKey)
+			}
+			i++
+			key = append(key, 0x00)
+		} else {
+			key = append(key, encoded[i])
+		}
+	}
+	return key, nil
+}
+
+// prefixSuccessor returns the smallest key that is greater than all the keys with the prefix.
+func prefixSuccessor(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] != 0xFF {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func encodePebbleValue(w *pebbleWrite) []byte {
+	if w.deleted {
+		return []byte{pebbleDeleted}
+	}
+	return append([]byte{pebbleValue}, w.value...)
+}
+
+func pebbleStaleKey(ts Timestamp, key []byte) []byte {
+	return append(binary.BigEndian.AppendUint64(bytes.Clone(pebbleStaleKeyPrefix), uint64(ts)), key...)
+}
+
+var pebbleComparer = func() *pebble.Comparer {
+	c := *pebble.DefaultComparer
+	c.Split = pebbleSplit
+	c.Name = "nil.pebble.versioned"
+	return &c
+}()
+
+type pebbleLogger struct{}
+
+func (pebbleLogger) Infof(format string, args ...any) {
+	log.Debug().Msgf(format, args...)
+}
+
+func (pebbleLogger) Fatalf(format string, args ...any) {
+	log.Fatal().Msgf(format, args...)
+}
+
+func newPebbleOptions() *pebble.Options {
+	opts := &pebble.Options{
+		Comparer: pebbleComparer,
+		Logger:   pebbleLogger{},
+		Levels:   make([]pebble.LevelOptions, 7),
+	}
+	for i := range opts.Levels {
+		opts.Levels[i].FilterPolicy = bloom.FilterPolicy(10)
+		opts.Levels[i].FilterType = pebble.TableFilter
+	}
+	return opts
+}
+
+func NewPebbleDb(pathToDb string) (*pebbleDB, error) {
+	return newPebbleDb(pathToDb, newPebbleOptions())
+}
+
+func NewPebbleDbInMemory() (*pebbleDB, error) {
+	opts := newPebbleOptions()
+	opts.FS = vfs.NewMem()
+	return newPebbleDb("", opts)
+}
+
+func newPebbleDb(pathToDb string, opts *pebble.Options) (*pebbleDB, error) {
+	cache := pebble.NewCache(256 << 20)
+	defer cache.Unref()
+	opts.Cache = cache
+
+	pebbleInstance, err := pebble.Open(pathToDb, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &pebbleDB{db: pebbleInstance, txLedger: assert.NewTxLedger(), readers: make(map[Timestamp]int)}
+
+	value, closer, err := pebbleInstance.Get(pebbleLastTsKey)
+	switch {
+	case errors.Is(err, pebble.ErrNotFound):
+	case err != nil:
+		pebbleInstance.Close()
+		return nil, err
+	default:
+		db.lastTs = Timestamp(binary.BigEndian.Uint64(value))
+		closer.Close()
+	}
+	return db, nil
+}
+
+func (db *pebbleDB) Close() {
+	if err := db.db.Close(); err != nil {
+		log.Error().Err(err).Msg("Error closing pebble")
+	}
+	db.txLedger.CheckLeakyTransactions()
+}
+
+func (db *pebbleDB) DropAll() error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	iter, err := db.db.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	var end []byte
+	if iter.Last() {
+		end = append(bytes.Clone(iter.Key()), 0)
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	if end == nil {
+		return nil
+	}
+	return db.db.DeleteRange([]byte{}, end, pebble.Sync)
+}
+
+func (db *pebbleDB) startRead() Timestamp {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.readers[db.lastTs]++
+	return db.lastTs
+}
+
+func (db *pebbleDB) startReadAt(ts Timestamp) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	// The versions below the watermark may be removed by the garbage collection already.
+	if watermark := db.watermarkLocked(); ts < watermark {
+		return fmt.Errorf("%w: timestamp %d, oldest readable %d", ErrHistoryPruned, ts, watermark)
+	}
+	db.readers[ts]++
+	return nil
+}
+
+func (db *pebbleDB) finishRead(ts Timestamp) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.readers[ts]--; db.readers[ts] == 0 {
+		delete(db.readers, ts)
+	}
+
+	// Only the commits after the oldest read may conflict with the open transactions.
+	oldest, ok := db.oldestReadLocked()
+	if !ok {
+		db.commits = nil
+		return
+	}
+	i := 0
+	for i < len(db.commits) && db.commits[i].ts <= oldest {
+		i++
+	}
+	db.commits = db.commits[i:]
+}
+
+func (db *pebbleDB) oldestReadLocked() (Timestamp, bool) {
+	if len(db.readers) == 0 {
+		return 0, false
+	}
+	oldest := pebbleMaxTs
+	for ts := range db.readers {
+		oldest = min(oldest, ts)
+	}
+	return oldest, true
+}
+
+// watermarkLocked returns the timestamp such that the versions older than the newest one
+// at the timestamp are not needed anymore.
+func (db *pebbleDB) watermarkLocked() Timestamp {
+	var watermark Timestamp
+	if db.lastTs > pebbleHistory {
+		watermark = db.lastTs - pebbleHistory
+	}
+	if oldest, ok := db.oldestReadLocked(); ok {
+		watermark = min(watermark, oldest)
+	}
+	return watermark
+}
+
+func (db *pebbleDB) createRoTx(_ context.Context, readTs Timestamp) *PebbleRoTx {
+	tx := &PebbleRoTx{db: db, readTs: readTs, onFinish: func() {}}
+	if assert.Enable {
+		stack := captureStacktrace()
+		tx.onFinish = db.txLedger.TxOnStart(stack)
+	}
+	return tx
+}
+
+// CreateRoTxAt returns ErrHistoryPruned if the versions at the timestamp are not retained anymore.
+func (db *pebbleDB) CreateRoTxAt(ctx context.Context, ts Timestamp) (RoTx, error) {
+	if err := db.startReadAt(ts); err != nil {
+		return nil, err
+	}
+	return db.createRoTx(ctx, ts), nil
+}
+
+func (db *pebbleDB) CreateRoTx(ctx context.Context) (RoTx, error) {
+	return db.createRoTx(ctx, db.startRead()), nil
+}
+
+func (db *pebbleDB) CreateRwTx(ctx context.Context) (RwTx, error) {
+	tx := db.createRoTx(ctx, db.startRead())
+	tx.writes = make(map[string]*pebbleWrite)
+	tx.reads = make(map[string]struct{})
+	return &PebbleRwTx{tx}, nil
+}
+
+func (db *pebbleDB) commit(tx *PebbleRoTx) (Timestamp, error) {
+	if len(tx.writes) == 0 {
+		return tx.readTs, nil
+	}
+
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	db.lock.Lock()
+	for _, c := range db.commits {
+		if c.ts <= tx.readTs {
+			continue
+		}
+		for key := range tx.reads {
+			if _, ok := c.keys[key]; ok {
+				db.lock.Unlock()
+				return 0, ErrConflict
+			}
+		}
+	}
+	ts := db.lastTs + 1
+	watermark := db.watermarkLocked()
+	db.lock.Unlock()
+
+	batch := db.db.NewBatch()
+	defer batch.Close()
+
+	if err := db.collectGarbage(batch, watermark, pebbleMaxGCKeys); err != nil {
+		return 0, err
+	}
+
+	iter, err := db.db.NewIter(nil)
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+
+	keys := make(map[string]struct{}, len(tx.writes))
+	for key, w := range tx.writes {
+		keys[key] = struct{}{}
+
+		prefix := pebbleKeyPrefix([]byte(key))
+		exists := iter.SeekPrefixGE(appendPebbleTs(prefix, pebbleMaxTs))
+		if err := iter.Error(); err != nil {
+			return 0, err
+		}
+		if exists {
+			// The older versions are removed once they are not visible anymore.
+			if err := batch.Set(pebbleStaleKey(ts, []byte(key)), nil, nil); err != nil {
+				return 0, err
+			}
+		} else if w.deleted {
+			continue
+		}
+		if err := batch.Set(appendPebbleTs(prefix, ts), encodePebbleValue(w), nil); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Set(pebbleLastTsKey, binary.BigEndian.AppendUint64(nil, uint64(ts)), nil); err != nil {
+		return 0, err
+	}
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return 0, err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.lastTs = ts
+	db.commits = append(db.commits, pebbleCommit{ts: ts, keys: keys})
+	return ts, nil
+}
+
+// collectGarbage removes the versions of the stale keys that are not visible at the watermark or later:
+// the versions older than the newest one at the watermark, and the newest one as well if it is a deletion.
+func (db *pebbleDB) collectGarbage(batch *pebble.Batch, watermark Timestamp, limit int) error {
+	queue, err := db.db.NewIter(&pebble.IterOptions{
+		LowerBound: pebbleStaleKeyPrefix,
+		UpperBound: pebbleStaleKey(watermark+1, nil),
+	})
+	if err != nil {
+		return err
+	}
+	defer queue.Close()
+
+	iter, err := db.db.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for valid := queue.First(); valid && limit > 0; valid = queue.Next() {
+		limit--
+
+		key := queue.Key()[len(pebbleStaleKeyPrefix)+pebbleTsSize:]
+		first := true
+		for valid := iter.SeekPrefixGE(appendPebbleTs(pebbleKeyPrefix(key), watermark)); valid; valid = iter.Next() {
+			if first {
+				first = false
+				value, err := iter.ValueAndErr()
+				if err != nil {
+					return err
+				}
+				if value[0] != pebbleDeleted {
+					continue
+				}
+			}
+			if err := batch.Delete(bytes.Clone(iter.Key()), nil); err != nil {
+				return err
+			}
+		}
+		if err := iter.Error(); err != nil {
+			return err
+		}
+		if err := batch.Delete(bytes.Clone(queue.Key()), nil); err != nil {
+			return err
+		}
+	}
+	return queue.Error()
+}
+
+func (db *pebbleDB) Stream(ctx context.Context, keyFilter func([]byte) bool, writer io.Writer) error {
+	tx := db.createRoTx(ctx, db.startRead())
+	defer tx.Rollback()
+
+	iter, err := tx.newIter(pebbleDataLowerBound, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	list := &pb.KVList{}
+	size := 0
+	for iter.HasNext() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		version := iter.version
+		key, value, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if !keyFilter(key) {
+			continue
+		}
+		list.Kv = append(list.Kv, &pb.KV{Key: key, Value: value, Version: uint64(version)})
+		if size += len(key) + len(value); size >= pebbleStreamBatchSize {
+			if err := writeKVList(list, writer); err != nil {
+				return err
+			}
+			list.Kv, size = nil, 0
+		}
+	}
+	if len(list.Kv) == 0 {
+		return nil
+	}
+	return writeKVList(list, writer)
+}
+
+func writeKVList(list *pb.KVList, w io.Writer) error {
+	data, err := list.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint64(len(data))); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (db *pebbleDB) Fetch(_ context.Context, reader io.Reader) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	db.lock.Lock()
+	lastTs := db.lastTs
+	db.lock.Unlock()
+
+	batch := db.db.NewBatch()
+	defer func() {
+		batch.Close()
+	}()
+
+	var data []byte
+	for {
+		var size uint64
+		if err := binary.Read(reader, binary.LittleEndian, &size); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		if uint64(cap(data)) < size {
+			data = make([]byte, size)
+		}
+		if _, err := io.ReadFull(reader, data[:size]); err != nil {
+			return err
+		}
+
+		list := &pb.KVList{}
+		if err := list.Unmarshal(data[:size]); err != nil {
+			return err
+		}
+		for _, kv := range list.Kv {
+			if kv.StreamDone {
+				continue
+			}
+			w := &pebbleWrite{value: kv.Value, deleted: len(kv.Meta) > 0 && kv.Meta[0]&badgerBitDelete != 0}
+			ts := Timestamp(kv.Version)
+			if err := batch.Set(appendPebbleTs(pebbleKeyPrefix(kv.Key), ts), encodePebbleValue(w), nil); err != nil {
+				return err
+			}
+			lastTs = max(lastTs, ts)
+		}
+
+		if batch.Len() >= pebbleFetchBatchSize {
+			if err := batch.Commit(pebble.NoSync); err != nil {
+				return err
+			}
+			batch.Close()
+			batch = db.db.NewBatch()
+		}
+	}
+
+	if err := batch.Set(pebbleLastTsKey, binary.BigEndian.AppendUint64(nil, uint64(lastTs)), nil); err != nil {
+		return err
+	}
+	if err := batch.Commit(pebble.Sync); err != nil {
+		return err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.lastTs = lastTs
+	return nil
+}
+
+// LogGC removes the stale versions of the keys left after the commits. Pebble has no value log,
+// so the discard ratio is not used.
+func (db *pebbleDB) LogGC(ctx context.Context, _ float64, gcFrequency time.Duration) error {
+	log.Info().Msg("Starting pebble garbage collection...")
+	ticker := time.NewTicker(gcFrequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			log.Debug().Msg("Execute pebble GC")
+			if err := db.runGC(); err != nil {
+				log.Error().Err(err).Msg("Error during pebble GC")
+				return err
+			}
+		case <-ctx.Done():
+			log.Info().Msg("Stopping pebble garbage collection...")
+			return nil
+		}
+	}
+}
+
+func (db *pebbleDB) runGC() error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	db.lock.Lock()
+	watermark := db.watermarkLocked()
+	db.lock.Unlock()
+
+	batch := db.db.NewBatch()
+	defer batch.Close()
+
+	if err := db.collectGarbage(batch, watermark, math.MaxInt); err != nil {
+		return err
+	}
+	return batch.Commit(pebble.NoSync)
+}
+
+func (tx *PebbleRwTx) Commit() error {
+	_, err := tx.CommitWithTs()
+	return err
+}
+
+func (tx *PebbleRwTx) CommitWithTs() (Timestamp, error) {
+	tx.onFinish()
+	defer tx.finish()
+	return tx.db.commit(tx.PebbleRoTx)
+}
+
+func (tx *PebbleRoTx) finish() {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.finished {
+		return
+	}
+	tx.finished = true
+	if tx.iter != nil {
+		if err := tx.iter.Close(); err != nil {
+			log.Error().Err(err).Msg("Error closing pebble iterator")
+		}
+		tx.iter = nil
+	}
+	tx.db.finishRead(tx.readTs)
+}
+
+func (tx *PebbleRoTx) Rollback() {
+	tx.onFinish()
+	tx.finish()
+}
+
+func (tx *PebbleRoTx) ReadTimestamp() Timestamp {
+	return tx.readTs
+}
+
+func (tx *PebbleRoTx) addRead(key []byte) {
+	if tx.reads == nil {
+		return
+	}
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.reads[string(key)] = struct{}{}
+}
+
+func (tx *PebbleRoTx) get(key []byte) ([]byte, bool, error) {
+	if tx.writes != nil {
+		if w, ok := tx.writes[string(key)]; ok {
+			return w.value, !w.deleted, nil
+		}
+	}
+	tx.addRead(key)
+
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+
+	if tx.iter == nil {
+		iter, err := tx.db.db.NewIter(nil)
+		if err != nil {
+			return nil, false, err
+		}
+		tx.iter = iter
+	}
+	if !tx.iter.SeekPrefixGE(appendPebbleTs(pebbleKeyPrefix(key), tx.readTs)) {
+		return nil, false, tx.iter.Error()
+	}
+	value, err := tx.iter.ValueAndErr()
+	if err != nil {
+		return nil, false, err
+	}
+	if value[0] == pebbleDeleted {
+		return nil, false, nil
+	}
+	return bytes.Clone(value[1:]), true, nil
+}
+
+func (tx *PebbleRoTx) Get(tableName TableName, key []byte) ([]byte, error) {
+	value, ok, err := tx.get(MakeKey(tableName, key))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (tx *PebbleRoTx) Exists(tableName TableName, key []byte) (bool, error) {
+	_, ok, err := tx.get(MakeKey(tableName, key))
+	return ok, err
+}
+
+func (tx *PebbleRwTx) put(key []byte, w *pebbleWrite) error {
+	w.key = string(key)
+	tx.writes[w.key] = w
+	return nil
+}
+
+func (tx *PebbleRwTx) Put(tableName TableName, key, value []byte) error {
+	return tx.put(MakeKey(tableName, key), &pebbleWrite{value: bytes.Clone(value)})
+}
+
+func (tx *PebbleRwTx) Delete(tableName TableName, key []byte) error {
+	return tx.put(MakeKey(tableName, key), &pebbleWrite{deleted: true})
+}
+
+func (tx *PebbleRoTx) Range(tableName TableName, from []byte, to []byte) (Iter, error) {
+	tablePrefix := []byte(tableName + ":")
+	lower := appendEscapedKey(nil, MakeKey(tableName, from))
+	upper := prefixSuccessor(appendEscapedKey(nil, tablePrefix))
+	if to != nil {
+		upper = prefixSuccessor(pebbleKeyPrefix(MakeKey(tableName, to)))
+	}
+	return tx.newIter(lower, upper, len(tablePrefix))
+}
+
+func (tx *PebbleRoTx) newIter(lower, upper []byte, tablePrefix int) (*PebbleIter, error) {
+	iter, err := tx.db.db.NewIter(&pebble.IterOptions{LowerBound: lower, UpperBound: upper})
+	if err != nil {
+		return nil, err
+	}
+	it := &PebbleIter{iter: iter, tx: tx, tablePrefix: tablePrefix}
+
+	for _, w := range tx.writes {
+		encoded := appendEscapedKey(nil, []byte(w.key))
+		if bytes.Compare(encoded, lower) >= 0 && (upper == nil || bytes.Compare(encoded, upper) < 0) {
+			it.writes = append(it.writes, w)
+		}
+	}
+	slices.SortFunc(it.writes, func(a, b *pebbleWrite) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	iter.First()
+	it.nextDb()
+	it.advance()
+	return it, nil
+}
+
+func (tx *PebbleRoTx) ExistsInShard(shardId types.ShardId, tableName ShardedTableName, key []byte) (bool, error) {
+	return tx.Exists(ShardTableName(tableName, shardId), key)
+}
+
+func (tx *PebbleRoTx) GetFromShard(shardId types.ShardId, tableName ShardedTableName, key []byte) ([]byte, error) {
+	return tx.Get(ShardTableName(tableName, shardId), key)
+}
+
+func (tx *PebbleRwTx) PutToShard(shardId types.ShardId, tableName ShardedTableName, key, value []byte) error {
+	return tx.Put(ShardTableName(tableName, shardId), key, value)
+}
+
+func (tx *PebbleRwTx) DeleteFromShard(shardId types.ShardId, tableName ShardedTableName, key []byte) error {
+	return tx.Delete(ShardTableName(tableName, shardId), key)
+}
+
+func (tx *PebbleRoTx) RangeByShard(shardId types.ShardId, tableName ShardedTableName, from []byte, to []byte) (Iter, error) {
+	return tx.Range(ShardTableName(tableName, shardId), from, to)
+}
+
+// nextDb moves to the next key visible at the read timestamp of the transaction.
+func (it *PebbleIter) nextDb() {
+	it.dbValid = false
+	for it.iter.Valid() {
+		versionKey := it.iter.Key()
+		if ts := pebbleVersionTs(versionKey); ts > it.tx.readTs {
+			it.iter.Next()
+			continue
+		}
+
+		key, err := decodePebbleKey(versionKey)
+		if err != nil {
+			it.err = err
+			return
+		}
+		value, err := it.iter.ValueAndErr()
+		if err != nil {
+			it.err = err
+			return
+		}
+		it.dbKey = key
+		it.dbValue = bytes.Clone(value[1:])
+		it.dbDeleted = value[0] == pebbleDeleted
+		it.dbVersion = pebbleVersionTs(versionKey)
+		it.dbValid = true
+
+		// Skip the older versions of the key.
+		prefix := bytes.Clone(versionKey[:pebbleSplit(versionKey)])
+		for it.iter.Next() && bytes.Equal(it.iter.Key()[:pebbleSplit(it.iter.Key())], prefix) {
+		}
+		return
+	}
+	it.err = it.iter.Error()
+}
+
+// advance merges the keys of the database with the pending writes of the transaction.
+func (it *PebbleIter) advance() {
+	for it.err == nil {
+		var w *pebbleWrite
+		if len(it.writes) > 0 {
+			w = it.writes[0]
+		}
+
+		switch {
+		case w == nil && !it.dbValid:
+			it.valid = false
+			return
+		case w != nil && (!it.dbValid || strings.Compare(w.key, string(it.dbKey)) <= 0):
+			if it.dbValid && w.key == string(it.dbKey) {
+				it.nextDb()
+			}
+			it.writes = it.writes[1:]
+			if w.deleted {
+				continue
+			}
+			it.key, it.value, it.version = []byte(w.key), w.value, 0
+		default:
+			it.key, it.value, it.version = it.dbKey, it.dbValue, it.dbVersion
+			deleted := it.dbDeleted
+			it.nextDb()
+			if deleted {
+				continue
+			}
+		}
+		it.valid = true
+		return
+	}
+	it.valid = true
+}
+
+func (it *PebbleIter) HasNext() bool {
+	return it.valid
+}
+
+func (it *PebbleIter) Next() ([]byte, []byte, error) {
+	if it.err != nil {
+		err := it.err
+		it.err = nil
+		it.valid = false
+		return nil, nil, err
+	}
+	key, value := it.key, it.value
+	it.tx.addRead(key)
+	it.advance()
+	return key[it.tablePrefix:], value, nil
+}
+
+func (it *PebbleIter) Close() {
+	if err := it.iter.Close(); err != nil {
+		log.Error().Err(err).Msg("Error closing pebble iterator")
+	}
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPebbleKeyEncoding(t *testing.T) {
+	t.Parallel()
+
+	keys := [][]byte{{}, {0}, {0, 0}, {0, 1}, {1}, {1, 0}, {1, 0, 0xFF}, {1, 0xFF}, {0xFF}}
+	for i, key := range keys {
+		versionKey := appendPebbleTs(pebbleKeyPrefix(key), 5)
+		decoded, err := decodePebbleKey(versionKey)
+		require.NoError(t, err)
+		assert.Equal(t, key, decoded)
+		assert.Equal(t, Timestamp(5), pebbleVersionTs(versionKey))
+
+		if i > 0 {
+			// The order of the keys is preserved regardless of the versions.
+			prev := appendPebbleTs(pebbleKeyPrefix(keys[i-1]), 1)
+			assert.Negative(t, pebbleComparer.Compare(prev, versionKey), "%x < %x", keys[i-1], key)
+		}
+	}
+	assert.Negative(t, pebbleComparer.Compare(
+		appendPebbleTs(pebbleKeyPrefix(keys[1]), 2),
+		appendPebbleTs(pebbleKeyPrefix(keys[1]), 1)))
+}
+
+func TestPebbleGC(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := NewPebbleDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	countVersions := func(key string) int {
+		t.Helper()
+
+		prefix := pebbleKeyPrefix(MakeKey("tbl", []byte(key)))
+		iter, err := database.db.NewIter(&pebble.IterOptions{LowerBound: prefix, UpperBound: prefixSuccessor(prefix)})
+		require.NoError(t, err)
+		defer iter.Close()
+
+		n := 0
+		for valid := iter.First(); valid; valid = iter.Next() {
+			n++
+		}
+		return n
+	}
+	put := func(key string, value []byte) {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		if value == nil {
+			require.NoError(t, tx.Delete("tbl", []byte(key)))
+		} else {
+			require.NoError(t, tx.Put("tbl", []byte(key), value))
+		}
+		require.NoError(t, tx.Commit())
+	}
+
+	put("deleted", []byte{1})
+	// A reader holds the versions it can see.
+	reader, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer reader.Rollback()
+	put("deleted", nil)
+
+	for i := range pebbleHistory + 2 {
+		put("updated", []byte{byte(i)})
+	}
+	assert.Equal(t, 2, countVersions("deleted"))
+	value, err := reader.Get("tbl", []byte("deleted"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, value)
+	reader.Rollback()
+
+	for range pebbleHistory + 2 {
+		put("updated", []byte{0})
+	}
+	assert.Equal(t, 0, countVersions("deleted"))
+	assert.LessOrEqual(t, countVersions("updated"), pebbleHistory+2)
+
+	require.NoError(t, database.runGC())
+	assert.Equal(t, pebbleHistory+1, countVersions("updated"))
+
+	// Deletion of a missing key leaves nothing.
+	put("missing", nil)
+	assert.Equal(t, 0, countVersions("missing"))
+}
+
+func TestPebbleHistoryPruned(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := NewPebbleDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	put := func(value byte) Timestamp {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		require.NoError(t, tx.Put("tbl", []byte("key"), []byte{value}))
+		ts, err := tx.CommitWithTs()
+		require.NoError(t, err)
+		return ts
+	}
+	readAt := func(ts Timestamp) ([]byte, error) {
+		t.Helper()
+
+		tx, err := database.CreateRoTxAt(ctx, ts)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		return tx.Get("tbl", []byte("key"))
+	}
+
+	first := put(0)
+	// An open reader keeps the history it can see.
+	reader, err := database.CreateRoTxAt(ctx, first)
+	require.NoError(t, err)
+	defer reader.Rollback()
+
+	last := first
+	for i := range pebbleHistory + 1 {
+		last = put(byte(i + 1))
+	}
+	value, err := readAt(first)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0}, value)
+	reader.Rollback()
+
+	_, err = readAt(first)
+	require.ErrorIs(t, err, ErrHistoryPruned)
+
+	value, err = readAt(last - pebbleHistory)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, value)
+}
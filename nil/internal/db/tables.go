@@ -28,11 +28,15 @@ const (
 	BlockHashAndInTransactionIndexByTransactionHash  = ShardedTableName("BlockHashAndInTransactionIndexByTransactionHash")
 	BlockHashAndOutTransactionIndexByTransactionHash = ShardedTableName("BlockHashAndOutTransactionIndexByTransactionHash")
 	AsyncCallContextTable                            = ShardedTableName("AsyncCallContext")
+	LogIndexByAddressTable                           = ShardedTableName("LogIndexByAddress")
+	LogIndexByTopicTable                             = ShardedTableName("LogIndexByTopic")
 
 	collatorStateTable          = TableName("CollatorState")
 	errorByTransactionHashTable = TableName("ErrorByTransactionHash")
 	schemeVersionTable          = TableName("SchemeVersion")
 	LastBlockTable              = TableName("LastBlock")
+	oldestStateBlockTable       = TableName("OldestStateBlock")
+	LogIndexStartTable          = TableName("LogIndexStart")
 )
 
 func ShardTableName(tableName ShardedTableName, shardId types.ShardId) TableName {
@@ -51,6 +51,7 @@ type BlockGenerationResult struct {
 	InTxnHashes  []common.Hash
 	OutTxns      []*types.Transaction
 	OutTxnHashes []common.Hash
+	Receipts     []*types.Receipt
 	ConfigParams map[string][]byte
 }
 
@@ -256,12 +257,10 @@ func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
 
 	txnHash := g.executionState.AddInTransaction(txn)
 
-	var res *ExecutionResult
+	res := handleInTransaction(g.ctx, g.executionState, txn, g.logger)
 	if txn.IsInternal() {
-		res = g.handleInternalInTransaction(txn)
 		g.counters.InternalTransactions++
 	} else {
-		res = g.handleExternalTransaction(txn)
 		g.counters.ExternalTransactions++
 	}
 
@@ -310,27 +309,35 @@ func ValidateInternalTransaction(transaction *types.Transaction) error {
 	return nil
 }
 
-func (g *BlockGenerator) handleInternalInTransaction(txn *types.Transaction) *ExecutionResult {
+// handleInTransaction validates and executes the incoming transaction that has already been added to the state.
+func handleInTransaction(ctx context.Context, es *ExecutionState, txn *types.Transaction, logger zerolog.Logger) *ExecutionResult {
+	if txn.IsInternal() {
+		return handleInternalInTransaction(ctx, es, txn, logger)
+	}
+	return handleExternalTransaction(ctx, es, txn, logger)
+}
+
+func handleInternalInTransaction(ctx context.Context, es *ExecutionState, txn *types.Transaction, logger zerolog.Logger) *ExecutionResult {
 	if err := ValidateInternalTransaction(txn); err != nil {
-		g.logger.Warn().Err(err).Msg("Invalid internal transaction")
+		logger.Warn().Err(err).Msg("Invalid internal transaction")
 		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
 	}
 
-	return g.executionState.HandleTransaction(g.ctx, txn, NewTransactionPayer(txn, g.executionState))
+	return es.HandleTransaction(ctx, txn, NewTransactionPayer(txn, es))
 }
 
-func (g *BlockGenerator) handleExternalTransaction(txn *types.Transaction) *ExecutionResult {
-	verifyResult := ValidateExternalTransaction(g.executionState, txn)
+func handleExternalTransaction(ctx context.Context, es *ExecutionState, txn *types.Transaction, logger zerolog.Logger) *ExecutionResult {
+	verifyResult := ValidateExternalTransaction(es, txn)
 	if verifyResult.Failed() {
-		g.logger.Error().Err(verifyResult.Error).Msg("External transaction validation failed.")
+		logger.Error().Err(verifyResult.Error).Msg("External transaction validation failed.")
 		return verifyResult
 	}
 
-	acc, err := g.executionState.GetAccount(txn.To)
+	acc, err := es.GetAccount(txn.To)
 	// Validation cached the account.
 	check.PanicIfErr(err)
 
-	res := g.executionState.HandleTransaction(g.ctx, txn, NewAccountPayer(acc, txn))
+	res := es.HandleTransaction(ctx, txn, NewAccountPayer(acc, txn))
 	res.AddUsed(verifyResult.GasUsed)
 	return res
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -688,3 +690,55 @@ func BenchmarkBlockGeneration(b *testing.B) {
 		tx.Rollback()
 	}
 }
+
+// BenchmarkBlockImport compares the storage engines on writing the blocks that update the state of many accounts.
+func BenchmarkBlockImport(b *testing.B) {
+	const accountsPerBlock = 100
+
+	for _, engine := range []db.Engine{db.EngineBadger, db.EnginePebble} {
+		b.Run(engine.String(), func(b *testing.B) {
+			ctx := b.Context()
+			shardId := types.BaseShardId
+			path := b.TempDir()
+
+			database, err := db.NewDb(engine, path)
+			require.NoError(b, err)
+
+			var prevBlock *types.Block
+			b.ResetTimer()
+			for n := range b.N {
+				tx, err := database.CreateRwTx(ctx)
+				require.NoError(b, err)
+
+				es, err := NewExecutionState(tx, shardId, StateParams{
+					Block:          prevBlock,
+					ConfigAccessor: config.GetStubAccessor(),
+				})
+				require.NoError(b, err)
+				for i := range accountsPerBlock {
+					addr := types.ShardAndHexToAddress(shardId, fmt.Sprintf("%08x", n*accountsPerBlock+i+1))
+					require.NoError(b, es.CreateAccount(addr))
+					require.NoError(b, es.SetBalance(addr, types.NewValueFromUint64(uint64(n+1))))
+					require.NoError(b, es.SetState(addr, common.IntToHash(i), common.IntToHash(n)))
+				}
+
+				res, err := es.Commit(types.BlockNumber(n), nil)
+				require.NoError(b, err)
+				require.NoError(b, PostprocessBlock(tx, shardId, res))
+				require.NoError(b, tx.Commit())
+				prevBlock = res.Block
+			}
+			b.StopTimer()
+
+			database.Close()
+			var size int64
+			require.NoError(b, filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					size += info.Size()
+				}
+				return err
+			}))
+			b.ReportMetric(float64(size)/float64(b.N), "disk-B/block")
+		})
+	}
+}
@@ -1,26 +1,29 @@
 package execution
 
 import (
-	"sync"
-
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/shopspring/decimal"
 )
 
-var lock sync.Mutex
-
 type FeeCalculator interface {
-	CalculateBaseFee(prevBlock *types.Block) types.Value
+	// CalculateBaseFee returns the base fee of the block that follows prevBlock.
+	// params are the fee params of the shard active at that height, nil if they are not set in the config.
+	CalculateBaseFee(prevBlock *types.Block, params *config.FeeParams) types.Value
 }
 
 type ConstFeeCalculator struct {
 	Value types.Value
 }
 
-func (c *ConstFeeCalculator) CalculateBaseFee(prevBlock *types.Block) types.Value {
+func (c *ConstFeeCalculator) CalculateBaseFee(prevBlock *types.Block, params *config.FeeParams) types.Value {
 	return c.Value
 }
 
+// MainFeeCalculator changes the base fee according to the difference of two sigmoids of the gas used by the previous
+// block: the fee stays almost the same around the target utilization and changes by up to the max change when the
+// block is empty or full. The curve is taken from the config params of the shard, the built-in one is used without them.
 type MainFeeCalculator struct{}
 
 func GetEffectivePriorityFee(baseFee types.Value, txn *types.Transaction) (types.Value, bool) {
@@ -34,6 +37,18 @@ func GetEffectivePriorityFee(baseFee types.Value, txn *types.Transaction) (types
 	return effectivePriorityFee, true
 }
 
+// NextBaseFee returns the base fee of the block that follows the given one.
+// The main shard always charges the default gas price (see BlockGenerator.updateGasPrices).
+func NextBaseFee(shardId types.ShardId, calculator FeeCalculator, block *types.Block, params *config.FeeParams) types.Value {
+	if shardId.IsMainShard() {
+		return types.DefaultGasPrice
+	}
+	if calculator == nil {
+		calculator = &MainFeeCalculator{}
+	}
+	return calculator.CalculateBaseFee(block, params)
+}
+
 func GasTarget(gasLimit types.Gas) types.Gas {
 	return gasLimit / 2
 }
@@ -50,43 +65,112 @@ var (
 	// Center of second sigmoid (75% of gas limit)
 	centerSigmoid2 = decimal.New(7500, -4).Mul(gasLimitPercentage)
 	blockGasLimit  = decimal.NewFromInt(int64(types.DefaultMaxGasInBlock.Uint64()))
-	maxSigmaDiff   decimal.Decimal
 	eNumber        = decimal.New(27182818284, -10)
+
+	// defaultFeeCurve is used by the shards without the fee params in the config.
+	defaultFeeCurve *feeCurve
 )
 
+// factorialsPrecision is the precision of the exponent computed to fill the factorials cache of the decimal package.
+// It takes about 170 factorials, while the sigmoids need less than 60 of them.
+const factorialsPrecision = 300
+
 func init() {
-	a := sigmoid2(gasLimitPercentage, centerSigmoid2, smoothingFactor).
-		Sub(sigmoid1(gasLimitPercentage, centerSigmoid1, smoothingFactor))
-	b := sigmoid2(decimal.New(0, -4), centerSigmoid2, smoothingFactor).
-		Sub(sigmoid1(decimal.New(0, -4), centerSigmoid1, smoothingFactor))
-	if a.Cmp(b) >= 0 {
-		maxSigmaDiff = a
-	} else {
-		maxSigmaDiff = b
+	// Decimal.Pow extends the global factorials cache without synchronization
+	// (https://github.com/shopspring/decimal/issues/368). Fill it in advance,
+	// so the base fee calculations only read it and can run concurrently.
+	_, err := decimal.New(1, 0).ExpTaylor(factorialsPrecision)
+	check.PanicIfErr(err)
+
+	defaultFeeCurve = newFeeCurve(adjustmentFactor, centerSigmoid1, centerSigmoid2, smoothingFactor, types.DefaultGasPrice)
+	// The built-in curve normalizes both directions by the larger of the signed differences for the full
+	// and the empty block. Keep it to produce the same fees for the existing blocks.
+	legacySigmaDiff := decimal.Max(defaultFeeCurve.maxSigmaDiff, defaultFeeCurve.minSigmaDiff.Neg())
+	defaultFeeCurve.maxSigmaDiff = legacySigmaDiff
+	defaultFeeCurve.minSigmaDiff = legacySigmaDiff
+}
+
+// feeCurve describes the change of the base fee depending on the gas used by the previous block.
+// The gas usage, the centers of the sigmoids and the smoothing are given in percents of the block gas limit.
+type feeCurve struct {
+	maxChange      decimal.Decimal
+	centerSigmoid1 decimal.Decimal
+	centerSigmoid2 decimal.Decimal
+	smoothing      decimal.Decimal
+	// maxSigmaDiff and minSigmaDiff are the absolute values of the difference of the sigmoids
+	// for the full and the empty block, they map the curve to [-maxChange, maxChange].
+	maxSigmaDiff decimal.Decimal
+	minSigmaDiff decimal.Decimal
+	minBaseFee   types.Value
+}
+
+func newFeeCurve(maxChange, centerSigmoid1, centerSigmoid2, smoothing decimal.Decimal, minBaseFee types.Value) *feeCurve {
+	c := &feeCurve{
+		maxChange:      maxChange,
+		centerSigmoid1: centerSigmoid1,
+		centerSigmoid2: centerSigmoid2,
+		smoothing:      smoothing,
+		minBaseFee:     minBaseFee,
 	}
+	c.maxSigmaDiff = c.sigmaDiff(gasLimitPercentage)
+	c.minSigmaDiff = c.sigmaDiff(decimal.New(0, -4)).Neg()
+	return c
 }
 
-func (m *MainFeeCalculator) CalculateBaseFee(prevBlock *types.Block) types.Value {
-	gasUsedPrevious := prevBlock.GasUsed
-	baseFeePrevious := prevBlock.BaseFee
+// newFeeCurveFromParams places the centers of the sigmoids symmetrically around the target utilization,
+// so the base fee doesn't change at the target.
+func newFeeCurveFromParams(params *config.FeeParams) *feeCurve {
+	toPercents := func(bp uint64) decimal.Decimal {
+		return decimal.NewFromInt(int64(bp)).Mul(gasLimitPercentage).Div(decimal.NewFromInt(config.BasisPoints))
+	}
+	target := toPercents(params.TargetUtilization)
+	halfWidth := decimal.Min(target, gasLimitPercentage.Sub(target)).Div(decimal.NewFromInt(2))
+
+	minBaseFee := types.DefaultGasPrice
+	if !params.MinBaseFee.IsZero() {
+		minBaseFee = types.NewValue(params.MinBaseFee.Int().Clone())
+	}
+	return newFeeCurve(
+		decimal.NewFromInt(int64(params.MaxChange)).Div(decimal.NewFromInt(config.BasisPoints)),
+		target.Sub(halfWidth),
+		target.Add(halfWidth),
+		toPercents(params.Smoothing),
+		minBaseFee)
+}
+
+func (c *feeCurve) sigmaDiff(gasUsedPercentage decimal.Decimal) decimal.Decimal {
+	return sigmoid2(gasUsedPercentage, c.centerSigmoid2, c.smoothing).
+		Sub(sigmoid1(gasUsedPercentage, c.centerSigmoid1, c.smoothing))
+}
 
+func (c *feeCurve) nextBaseFee(gasUsedPrevious types.Gas, baseFeePrevious types.Value) types.Value {
 	// Convert to percentage
 	gasUsedPercentage := toPercentage(decimal.NewFromInt(int64(gasUsedPrevious.Uint64())), blockGasLimit)
 
-	// Calculate the difference between the two sigmoids
-	sigmaDiff := sigmoid2(gasUsedPercentage, centerSigmoid2, smoothingFactor).Sub(
-		sigmoid1(gasUsedPercentage, centerSigmoid1, smoothingFactor))
-
-	// Normalize the difference
-	normalized := sigmaDiff.Div(maxSigmaDiff)
+	// Calculate the difference between the two sigmoids and normalize it
+	diff := c.sigmaDiff(gasUsedPercentage)
+	var normalized decimal.Decimal
+	if diff.Sign() >= 0 {
+		normalized = diff.Div(c.maxSigmaDiff)
+	} else {
+		normalized = diff.Div(c.minSigmaDiff)
+	}
 
 	baseFeePreviousFixed := decimal.NewFromBigInt(baseFeePrevious.Int().ToBig(), 0)
-	newFee := baseFeePreviousFixed.Mul(adjustmentFactor.Mul(normalized).Add(decimal.New(1, 0)))
+	newFee := baseFeePreviousFixed.Mul(c.maxChange.Mul(normalized).Add(decimal.New(1, 0)))
+
+	if newFee.Cmp(decimal.NewFromBigInt(c.minBaseFee.ToBig(), 0)) > 0 {
+		return types.NewValueFromBigMust(newFee.BigInt())
+	}
+	return c.minBaseFee
+}
 
-	resultBaseFee := types.DefaultGasPrice
-	if newFee.Cmp(decimal.NewFromBigInt(types.DefaultGasPrice.ToBig(), 0)) > 0 {
-		resultBaseFee = types.NewValueFromBigMust(newFee.BigInt())
+func (m *MainFeeCalculator) CalculateBaseFee(prevBlock *types.Block, params *config.FeeParams) types.Value {
+	curve := defaultFeeCurve
+	if params != nil {
+		curve = newFeeCurveFromParams(params)
 	}
+	resultBaseFee := curve.nextBaseFee(prevBlock.GasUsed, prevBlock.BaseFee)
 
 	if resultBaseFee.Cmp(prevBlock.BaseFee) != 0 {
 		logger.Debug().
@@ -104,22 +188,10 @@ func toPercentage(gasUsedPrevious, gasLimitAbsolute decimal.Decimal) decimal.Dec
 
 func sigmoid1(gasUsedPercentage, centerSigmoid, smoothingFactor decimal.Decimal) decimal.Decimal {
 	n := gasUsedPercentage.Sub(centerSigmoid).Div(smoothingFactor)
-	// Decimal's Pow function is not thread-safe, so we need to lock it
-	// https://github.com/shopspring/decimal/issues/368
-	// TODO: This is a workaround, we should find a better solution
-	lock.Lock()
-	exp := eNumber.Pow(n)
-	lock.Unlock()
-	return decimal.NewFromInt(1).Div(decimal.NewFromInt(1).Add(exp))
+	return decimal.NewFromInt(1).Div(decimal.NewFromInt(1).Add(eNumber.Pow(n)))
 }
 
 func sigmoid2(gasUsedPercentage, centerSigmoid, smoothingFactor decimal.Decimal) decimal.Decimal {
 	n := gasUsedPercentage.Sub(centerSigmoid).Neg().Div(smoothingFactor)
-	// Decimal's Pow function is not thread-safe, so we need to lock it
-	// https://github.com/shopspring/decimal/issues/368
-	// TODO: This is a workaround, we should find a better solution
-	lock.Lock()
-	exp := eNumber.Pow(n)
-	lock.Unlock()
-	return decimal.NewFromInt(1).Div(decimal.NewFromInt(1).Add(exp))
+	return decimal.NewFromInt(1).Div(decimal.NewFromInt(1).Add(eNumber.Pow(n)))
 }
@@ -1,9 +1,12 @@
 package execution
 
 import (
+	"sync"
 	"testing"
 
+	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,16 +20,124 @@ func TestPriceCalculation(t *testing.T) {
 	gasTarget := GasTarget(types.DefaultMaxGasInBlock)
 	prevBlock.BaseFee = types.DefaultGasPrice
 	prevBlock.GasUsed = gasTarget
-	f := feeCalc.CalculateBaseFee(prevBlock)
+	f := feeCalc.CalculateBaseFee(prevBlock, nil)
 	require.Equal(t, prevBlock.BaseFee.Uint64(), f.Uint64())
 
 	prevBlock.BaseFee = f
 	prevBlock.GasUsed = gasTarget * 2
-	f = feeCalc.CalculateBaseFee(prevBlock)
+	f = feeCalc.CalculateBaseFee(prevBlock, nil)
 	require.Greater(t, f.Uint64(), prevBlock.BaseFee.Uint64())
 
 	prevBlock.BaseFee = f
 	prevBlock.GasUsed = gasTarget - 1_000_000
-	f = feeCalc.CalculateBaseFee(prevBlock)
+	f = feeCalc.CalculateBaseFee(prevBlock, nil)
 	require.Less(t, f.Uint64(), prevBlock.BaseFee.Uint64())
 }
+
+func TestPriceCalculationWithParams(t *testing.T) {
+	t.Parallel()
+
+	feeCalc := MainFeeCalculator{}
+	baseFee := types.DefaultGasPrice.Mul(types.NewValueFromUint64(10))
+	gasLimit := types.DefaultMaxGasInBlock.Uint64()
+	calculate := func(params *config.FeeParams, gasUsed uint64) types.Value {
+		prevBlock := &types.Block{}
+		prevBlock.BaseFee = baseFee
+		prevBlock.GasUsed = types.Gas(gasUsed)
+		return feeCalc.CalculateBaseFee(prevBlock, params)
+	}
+
+	params := &config.FeeParams{TargetUtilization: 8000, MaxChange: 1250, Smoothing: 500}
+	// The fee doesn't change at the target.
+	assert.Equal(t, baseFee, calculate(params, gasLimit*8/10))
+	// The change is limited by the max change.
+	full := calculate(params, gasLimit)
+	assert.Positive(t, full.Cmp(baseFee))
+	assert.LessOrEqual(t, full.Cmp(baseFee.Mul(types.NewValueFromUint64(11250)).Div(types.NewValueFromUint64(10000))), 0)
+	empty := calculate(params, 0)
+	assert.Negative(t, empty.Cmp(baseFee))
+	assert.GreaterOrEqual(t, empty.Cmp(baseFee.Mul(types.NewValueFromUint64(8750)).Div(types.NewValueFromUint64(10000))), 0)
+	// The half-full block is below the target, so the fee goes down, unlike with the built-in curve.
+	assert.Negative(t, calculate(params, gasLimit/2).Cmp(baseFee))
+
+	// The fee doesn't go below the min base fee.
+	params.MinBaseFee = *baseFee.Uint256
+	assert.Equal(t, baseFee, calculate(params, 0))
+}
+
+func TestPriceCalculationConcurrent(t *testing.T) {
+	t.Parallel()
+
+	feeCalc := MainFeeCalculator{}
+	gasLimit := types.DefaultMaxGasInBlock.Uint64()
+	params := []*config.FeeParams{
+		nil,
+		{TargetUtilization: 5000, MaxChange: 1250, Smoothing: 1},
+		{TargetUtilization: 9000, MaxChange: 1250, Smoothing: config.BasisPoints},
+	}
+	calculate := func(params *config.FeeParams, gasUsed uint64) types.Value {
+		prevBlock := &types.Block{}
+		prevBlock.BaseFee = types.DefaultGasPrice
+		prevBlock.GasUsed = types.Gas(gasUsed)
+		return feeCalc.CalculateBaseFee(prevBlock, params)
+	}
+
+	gasUsed := []uint64{0, gasLimit / 3, gasLimit}
+
+	// The calculations don't share mutable state, so they give the same results when run concurrently.
+	results := make([][]types.Value, 8)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, p := range params {
+				for _, g := range gasUsed {
+					results[i] = append(results[i], calculate(p, g))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var expected []types.Value
+	for _, p := range params {
+		for _, g := range gasUsed {
+			expected = append(expected, calculate(p, g))
+		}
+	}
+	for _, res := range results {
+		assert.Equal(t, expected, res)
+	}
+}
+
+func TestFeeParamsValidation(t *testing.T) {
+	t.Parallel()
+
+	accessor := config.NewConfigAccessorFromMap(nil)
+
+	params, err := config.GetShardFeeParams(accessor, types.BaseShardId)
+	require.NoError(t, err)
+	assert.Nil(t, params)
+
+	valid := config.FeeParams{TargetUtilization: 5000, MaxChange: 1250, Smoothing: 500}
+	require.NoError(t, config.SetParamFees(accessor, &config.ParamFees{
+		Shards: []config.FeeParams{{}, valid},
+	}))
+	params, err = config.GetShardFeeParams(accessor, types.BaseShardId)
+	require.NoError(t, err)
+	assert.Equal(t, &valid, params)
+	params, err = config.GetShardFeeParams(accessor, types.MainShardId)
+	require.NoError(t, err)
+	assert.Nil(t, params)
+
+	for _, invalid := range []config.FeeParams{
+		{TargetUtilization: config.BasisPoints, MaxChange: 1250, Smoothing: 500},
+		{TargetUtilization: 5000, Smoothing: 500},
+		{TargetUtilization: 5000, MaxChange: config.BasisPoints, Smoothing: 500},
+		{TargetUtilization: 5000, MaxChange: 1250},
+		{TargetUtilization: 5000, MaxChange: 1250, Smoothing: config.BasisPoints + 1},
+	} {
+		require.Error(t, config.SetParamFees(accessor, &config.ParamFees{Shards: []config.FeeParams{invalid}}))
+	}
+}
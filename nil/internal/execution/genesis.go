@@ -0,0 +1,73 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// GenesisAccount is an account of the genesis alloc. The format follows the alloc of geth's genesis.json,
+// the token balances are an extension of =nil;.
+type GenesisAccount struct {
+	Code    hexutil.Bytes                 `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash   `json:"storage,omitempty"`
+	Balance types.Value                   `json:"balance"`
+	Nonce   hexutil.Uint64                `json:"nonce,omitempty"`
+	Tokens  map[types.TokenId]types.Value `json:"tokens,omitempty"`
+}
+
+// GenesisAlloc is the set of accounts of the zero state.
+type GenesisAlloc map[types.Address]GenesisAccount
+
+// Genesis is the content of a genesis file. Only the alloc is used, the chain config comes from ZeroStateConfig.
+type Genesis struct {
+	Alloc GenesisAlloc `json:"alloc"`
+}
+
+// LoadGenesisAlloc reads the alloc of the genesis file.
+func LoadGenesisAlloc(fname string) (GenesisAlloc, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	var genesis Genesis
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis file %s: %w", fname, err)
+	}
+	return genesis.Alloc, nil
+}
+
+// AddGenesisAlloc adds the accounts of the alloc to the zero state in the order of their addresses.
+// The nonce of an account becomes its external seqno.
+func (c *ZeroStateConfig) AddGenesisAlloc(alloc GenesisAlloc) {
+	addrs := make([]types.Address, 0, len(alloc))
+	for addr := range alloc {
+		addrs = append(addrs, addr)
+	}
+	slices.SortFunc(addrs, func(a, b types.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	for _, addr := range addrs {
+		account := alloc[addr]
+		value := account.Balance
+		if value.Uint256 == nil {
+			value = types.Value0
+		}
+		c.Contracts = append(c.Contracts, &ContractDescr{
+			Name:     addr.Hex(),
+			Address:  addr,
+			Value:    value,
+			Code:     account.Code,
+			Storage:  account.Storage,
+			Tokens:   account.Tokens,
+			ExtSeqno: types.Seqno(account.Nonce),
+		})
+	}
+}
@@ -27,6 +27,7 @@ func (pp *blockPostprocessor) Postprocess() error {
 		pp.fillLastBlockTable,
 		pp.fillBlockHashByNumberIndex,
 		pp.fillBlockHashAndTransactionIndexByTransactionHash,
+		pp.fillLogIndex,
 	} {
 		if err := postpocessor(); err != nil {
 			return err
@@ -44,26 +45,38 @@ func (pp *blockPostprocessor) fillBlockHashByNumberIndex() error {
 }
 
 func (pp *blockPostprocessor) fillBlockHashAndTransactionIndexByTransactionHash() error {
-	fill := func(txnHashes []common.Hash, table db.ShardedTableName) error {
-		for i, hash := range txnHashes {
-			blockHashAndTransactionIndex := db.BlockHashAndTransactionIndex{
-				BlockHash:        pp.blockResult.BlockHash,
-				TransactionIndex: types.TransactionIndex(i),
-			}
-			value, err := blockHashAndTransactionIndex.MarshalSSZ()
-			if err != nil {
-				return err
-			}
+	if err := writeTransactionIndex(
+		pp.tx, pp.shardId, pp.blockResult.BlockHash, pp.blockResult.InTxnHashes,
+		db.BlockHashAndInTransactionIndexByTransactionHash,
+	); err != nil {
+		return err
+	}
+	return writeTransactionIndex(
+		pp.tx, pp.shardId, pp.blockResult.BlockHash, pp.blockResult.OutTxnHashes,
+		db.BlockHashAndOutTransactionIndexByTransactionHash)
+}
 
-			if err := pp.tx.PutToShard(pp.shardId, table, hash.Bytes(), value); err != nil {
-				return err
-			}
+// writeTransactionIndex maps the hashes of the transactions of the block to their positions in the block.
+func writeTransactionIndex(
+	tx db.RwTx, shardId types.ShardId, blockHash common.Hash, txnHashes []common.Hash, table db.ShardedTableName,
+) error {
+	for i, hash := range txnHashes {
+		blockHashAndTransactionIndex := db.BlockHashAndTransactionIndex{
+			BlockHash:        blockHash,
+			TransactionIndex: types.TransactionIndex(i),
+		}
+		value, err := blockHashAndTransactionIndex.MarshalSSZ()
+		if err != nil {
+			return err
 		}
-		return nil
-	}
 
-	if err := fill(pp.blockResult.InTxnHashes, db.BlockHashAndInTransactionIndexByTransactionHash); err != nil {
-		return err
+		if err := tx.PutToShard(shardId, table, hash.Bytes(), value); err != nil {
+			return err
+		}
 	}
-	return fill(pp.blockResult.OutTxnHashes, db.BlockHashAndOutTransactionIndexByTransactionHash)
+	return nil
+}
+
+func (pp *blockPostprocessor) fillLogIndex() error {
+	return db.WriteLogIndex(pp.tx, pp.shardId, pp.blockResult.Block.Id, pp.blockResult.Receipts)
 }
@@ -0,0 +1,106 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// ReplayTransaction re-executes the incoming transaction with the given index of the block
+// on top of the state of the parent block. The preceding transactions of the block are executed
+// first without tracing, hooks receive the events of the target transaction only.
+// The resulting state is never committed.
+func ReplayTransaction(
+	ctx context.Context,
+	tx db.RoTx,
+	accessor *StateAccessor,
+	shardId types.ShardId,
+	blockHash common.Hash,
+	index types.TransactionIndex,
+	hooks *tracing.Hooks,
+) (*ExecutionResult, error) {
+	data, err := accessor.Access(tx, shardId).GetBlock().WithInTransactions().ByHash(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %s: %w", blockHash, err)
+	}
+	block := data.Block()
+	txns := data.InTransactions()
+	if int(index) >= len(txns) {
+		return nil, fmt.Errorf("block %s has only %d incoming transactions", blockHash, len(txns))
+	}
+
+	prevData, err := accessor.Access(tx, shardId).GetBlock().ByHash(block.PrevBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parent block %s: %w", block.PrevBlock, err)
+	}
+	if err := accessor.Access(tx, shardId).CheckStateAvailable(prevData.Block().Id); err != nil {
+		return nil, err
+	}
+
+	es, err := newReplayExecutionState(tx, shardId, prevData.Block(), block)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Nop()
+	for _, txn := range txns[:index] {
+		es.AddInTransaction(txn)
+		res := handleInTransaction(ctx, es, txn, logger)
+		if res.FatalError != nil {
+			return nil, fmt.Errorf("failed to replay transaction %s: %w", txn.Hash(), res.FatalError)
+		}
+		es.AddReceipt(res)
+	}
+
+	txn := txns[index]
+	es.SetEvmTracer(hooks)
+	es.AddInTransaction(txn)
+	res := handleInTransaction(ctx, es, txn, logger)
+	if res.FatalError != nil {
+		return nil, fmt.Errorf("failed to replay transaction %s: %w", txn.Hash(), res.FatalError)
+	}
+	return res, nil
+}
+
+// newReplayExecutionState prepares the state in which the transactions of the block were executed.
+// The fee values are taken from the block itself rather than recomputed, so the result doesn't depend
+// on the fee calculator of the node replaying the block.
+func newReplayExecutionState(tx db.RoTx, shardId types.ShardId, prevBlock, block *types.Block) (*ExecutionState, error) {
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+
+	es, err := NewExecutionState(tx, shardId, StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	es.MainChainHash = block.MainChainHash
+	es.BaseFee = block.BaseFee
+
+	if shardId.IsMainShard() {
+		// The main shard updates gas prices of all shards before executing the transactions
+		// (see BlockGenerator.updateGasPrices). The result is stored in the config of the block itself.
+		blockConfig, err := config.NewConfigAccessorFromBlockWithTx(tx, block, shardId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create config accessor: %w", err)
+		}
+		gasPrice, err := config.GetParamGasPrice(blockConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gas prices: %w", err)
+		}
+		if err := config.SetParamGasPrice(es.GetConfigAccessor(), gasPrice); err != nil {
+			return nil, fmt.Errorf("failed to set gas prices: %w", err)
+		}
+	}
+	return es, nil
+}
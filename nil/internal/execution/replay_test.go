@@ -0,0 +1,71 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayUsesBlockBaseFee(t *testing.T) {
+	t.Parallel()
+
+	const nShards = 2
+	shardId := types.ShardId(1)
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	for id := range types.ShardId(nShards) {
+		gen, err := NewBlockGenerator(t.Context(), NewBlockGeneratorParams(id, nShards), database, nil)
+		require.NoError(t, err)
+		_, err = gen.GenerateZeroState(&ZeroStateConfig{})
+		gen.Rollback()
+		require.NoError(t, err)
+	}
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	prevBlock, prevHash, err := db.ReadLastBlock(tx, shardId)
+	require.NoError(t, err)
+	mainHash, err := db.ReadLastBlockHash(tx, types.MainShardId)
+	require.NoError(t, err)
+	tx.Rollback()
+
+	// the block is collated with a calculator that differs from the default one
+	baseFee := types.NewValueFromUint64(123_456_789)
+	params := NewBlockGeneratorParams(shardId, nShards)
+	params.FeeCalculator = &ConstFeeCalculator{Value: baseFee}
+	gen, err := NewBlockGenerator(t.Context(), params, database, prevBlock)
+	require.NoError(t, err)
+	defer gen.Rollback()
+
+	txn := types.NewEmptyTransaction()
+	txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+	txn.To = types.ShardAndHexToAddress(shardId, "0x1234")
+	txn.FeeCredit = types.NewValueFromUint64(1_000_000)
+	txn.MaxFeePerGas = baseFee
+	res, err := gen.GenerateBlock(&Proposal{
+		PrevBlockId:   prevBlock.Id,
+		PrevBlockHash: prevHash,
+		MainChainHash: mainHash,
+		InternalTxns:  []*types.Transaction{txn},
+	}, &types.ConsensusParams{})
+	require.NoError(t, err)
+	require.Equal(t, baseFee, res.Block.BaseFee)
+	require.Len(t, res.InTxns, 1)
+
+	tx, err = database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := newReplayExecutionState(tx, shardId, prevBlock, res.Block)
+	require.NoError(t, err)
+	require.Equal(t, baseFee, es.BaseFee)
+
+	replayed, err := ReplayTransaction(t.Context(), tx, NewStateAccessor(), shardId, res.BlockHash, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, res.Receipts[0].GasUsed, replayed.GasUsed)
+}
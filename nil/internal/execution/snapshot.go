@@ -0,0 +1,583 @@
+package execution
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// A snapshot file holds the state of a shard at a block and the headers of the block and its ancestors:
+//
+//	magic | version (uint32, big-endian) | header record | block records | node and code records | end record
+//
+// Each record is kind (1 byte) | payload length (uvarint) | payload.
+// Block records go from the snapshot block back to its ancestors.
+// The payload of the end record is the SHA-256 of everything before it.
+const (
+	snapshotMagic = "nilsnap\x00"
+
+	// SnapshotVersion is the version of the snapshot files written by ExportSnapshot.
+	SnapshotVersion uint32 = 1
+
+	// maxSnapshotRecordSize limits the size of a record (a trie node may hold up to 100 MB of data).
+	maxSnapshotRecordSize = 128 << 20
+
+	// snapshotImportBatchSize limits the number of records written in a single DB transaction.
+	snapshotImportBatchSize = 10_000
+)
+
+type snapshotRecordKind byte
+
+const (
+	// snapshotRecordHeader holds SnapshotHeader encoded as JSON.
+	snapshotRecordHeader snapshotRecordKind = iota + 1
+	// snapshotRecordBlock holds a block header encoded as SSZ.
+	snapshotRecordBlock
+	// snapshotRecordNode holds table name length (uvarint) | table name | node key (32 bytes) | node.
+	snapshotRecordNode
+	// snapshotRecordCode holds code hash (32 bytes) | code.
+	snapshotRecordCode
+	// snapshotRecordEnd holds the checksum of the file.
+	snapshotRecordEnd
+)
+
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// SnapshotHeader describes the content of a snapshot file.
+type SnapshotHeader struct {
+	ShardId     types.ShardId     `json:"shardId"`
+	BlockNumber types.BlockNumber `json:"blockNumber"`
+	BlockHash   common.Hash       `json:"blockHash"`
+	// Headers is the number of the block headers in the snapshot, including the snapshot block.
+	Headers uint64 `json:"headers"`
+}
+
+// snapshotTrieRoots returns the roots of the tries stored in the snapshot of the block, except the state tries.
+// The transactions and the receipts of the snapshot block are included, so the block can be served in full.
+func snapshotTrieRoots(shardId types.ShardId, block *types.Block) map[db.ShardedTableName][]common.Hash {
+	roots := map[db.ShardedTableName][]common.Hash{
+		db.TransactionTrieTable: {block.InTransactionsRoot, block.OutTransactionsRoot},
+		db.ReceiptTrieTable:     {block.ReceiptsRoot},
+	}
+	if shardId.IsMainShard() {
+		roots[db.ConfigTrieTable] = []common.Hash{block.ConfigRoot}
+		roots[db.ShardBlocksTrieTableName(block.Id)] = []common.Hash{block.ChildBlocksRootHash}
+	}
+	return roots
+}
+
+// markSnapshot collects the nodes and the code hashes of the snapshot of the block.
+func markSnapshot(
+	tx db.RoTx, shardId types.ShardId, block *types.Block,
+) (map[db.ShardedTableName]mpt.NodeSet, map[common.Hash]struct{}, error) {
+	sets := make(map[db.ShardedTableName]mpt.NodeSet)
+	for _, table := range stateTrieTables {
+		sets[table] = make(mpt.NodeSet)
+	}
+	codes := make(map[common.Hash]struct{})
+	onContract := func(contract *types.SmartContract) error {
+		codes[contract.CodeHash] = struct{}{}
+		return nil
+	}
+	if err := markBlockState(tx, shardId, block, sets, onContract); err != nil {
+		return nil, nil, fmt.Errorf("failed to mark state: %w", err)
+	}
+
+	for table, roots := range snapshotTrieRoots(shardId, block) {
+		sets[table] = make(mpt.NodeSet)
+		for _, root := range roots {
+			reader := mpt.NewDbReader(tx, shardId, table)
+			reader.SetRootHash(root)
+			if err := reader.Mark(sets[table], nil); err != nil {
+				return nil, nil, fmt.Errorf("failed to mark %s: %w", table, err)
+			}
+		}
+	}
+	return sets, codes, nil
+}
+
+type snapshotWriter struct {
+	w        *bufio.Writer
+	checksum hash.Hash
+	out      io.Writer
+}
+
+func newSnapshotWriter(w io.Writer) *snapshotWriter {
+	sw := &snapshotWriter{w: bufio.NewWriter(w), checksum: sha256.New()}
+	sw.out = io.MultiWriter(sw.w, sw.checksum)
+	return sw
+}
+
+func (sw *snapshotWriter) writeRecord(kind snapshotRecordKind, payload ...[]byte) error {
+	size := 0
+	for _, p := range payload {
+		size += len(p)
+	}
+	record := binary.AppendUvarint([]byte{byte(kind)}, uint64(size))
+	if _, err := sw.out.Write(record); err != nil {
+		return err
+	}
+	for _, p := range payload {
+		if _, err := sw.out.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish writes the end record and flushes the data.
+func (sw *snapshotWriter) finish() error {
+	checksum := sw.checksum.Sum(nil)
+	if err := sw.writeRecord(snapshotRecordEnd, checksum); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
+
+// ExportSnapshot writes the state of the shard at the block and the given number of the headers ending
+// with the block (zero means all the headers down to the zero state).
+// The snapshot can be imported into an empty database with ImportSnapshot.
+func ExportSnapshot(
+	ctx context.Context,
+	txFabric db.ReadOnlyDB,
+	shardId types.ShardId,
+	blockNumber types.BlockNumber,
+	headers uint64,
+	w io.Writer,
+) (*SnapshotHeader, error) {
+	tx, err := txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	oldest, err := db.ReadOldestStateBlock(tx, shardId)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, err
+	}
+	if blockNumber < oldest {
+		return nil, fmt.Errorf("%w: block %d of shard %d is older than the oldest available state %d",
+			ErrStatePruned, blockNumber, shardId, oldest)
+	}
+
+	blockHash, err := db.ReadBlockHashByNumber(tx, shardId, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %d: %w", blockNumber, err)
+	}
+	block, err := db.ReadBlock(tx, shardId, blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %s: %w", blockHash, err)
+	}
+	if headers == 0 || headers > uint64(block.Id)+1 {
+		headers = uint64(block.Id) + 1
+	}
+
+	header := &SnapshotHeader{
+		ShardId:     shardId,
+		BlockNumber: block.Id,
+		BlockHash:   blockHash,
+		Headers:     headers,
+	}
+	headerData, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	sw := newSnapshotWriter(w)
+	if _, err := sw.out.Write(binary.BigEndian.AppendUint32([]byte(snapshotMagic), SnapshotVersion)); err != nil {
+		return nil, err
+	}
+	if err := sw.writeRecord(snapshotRecordHeader, headerData); err != nil {
+		return nil, err
+	}
+
+	cur := block
+	for i := range headers {
+		if i > 0 {
+			if cur, err = db.ReadBlock(tx, shardId, cur.PrevBlock); err != nil {
+				return nil, fmt.Errorf("failed to read block %d: %w", block.Id-types.BlockNumber(i), err)
+			}
+		}
+		data, err := cur.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		if err := sw.writeRecord(snapshotRecordBlock, data); err != nil {
+			return nil, err
+		}
+	}
+
+	sets, codes, err := markSnapshot(tx, shardId, block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The records are sorted to make the snapshots of the same state identical.
+	tables := make([]db.ShardedTableName, 0, len(sets))
+	for table := range sets {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+	for _, table := range tables {
+		keys := make([]string, 0, len(sets[table]))
+		for key := range sets[table] {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		tableName := binary.AppendUvarint(nil, uint64(len(table)))
+		tableName = append(tableName, table...)
+		for _, key := range keys {
+			node, err := tx.GetFromShard(shardId, table, []byte(key))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read node %x of %s: %w", key, table, err)
+			}
+			if err := sw.writeRecord(snapshotRecordNode, tableName, []byte(key), node); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	codeHashes := make([]common.Hash, 0, len(codes))
+	for codeHash := range codes {
+		codeHashes = append(codeHashes, codeHash)
+	}
+	slices.SortFunc(codeHashes, func(a, b common.Hash) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	for _, codeHash := range codeHashes {
+		code, err := db.ReadCode(tx, shardId, codeHash)
+		if errors.Is(err, db.ErrKeyNotFound) && codeHash == common.EmptyHash {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read code %s: %w", codeHash, err)
+		}
+		if err := sw.writeRecord(snapshotRecordCode, codeHash.Bytes(), code); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sw.finish(); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+type snapshotReader struct {
+	r        *bufio.Reader
+	checksum hash.Hash
+	in       io.Reader
+}
+
+func newSnapshotReader(r io.Reader) (*snapshotReader, error) {
+	sr := &snapshotReader{r: bufio.NewReader(r), checksum: sha256.New()}
+	sr.in = io.TeeReader(sr.r, sr.checksum)
+
+	preamble := make([]byte, len(snapshotMagic)+4)
+	if _, err := io.ReadFull(sr.in, preamble); err != nil {
+		return nil, fmt.Errorf("%w: failed to read preamble: %w", ErrInvalidSnapshot, err)
+	}
+	if string(preamble[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("%w: not a snapshot file", ErrInvalidSnapshot)
+	}
+	if version := binary.BigEndian.Uint32(preamble[len(snapshotMagic):]); version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, version)
+	}
+	return sr, nil
+}
+
+// next reads the next record. The end record is checked against the checksum of the preceding data.
+func (sr *snapshotReader) next() (snapshotRecordKind, []byte, error) {
+	checksum := sr.checksum.Sum(nil)
+
+	kind, err := sr.r.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: failed to read record: %w", ErrInvalidSnapshot, err)
+	}
+	sr.checksum.Write([]byte{kind})
+	size, err := binary.ReadUvarint(byteReader{sr.in})
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: failed to read record size: %w", ErrInvalidSnapshot, err)
+	}
+	if size > maxSnapshotRecordSize {
+		return 0, nil, fmt.Errorf("%w: record of %d bytes is too big", ErrInvalidSnapshot, size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(sr.in, payload); err != nil {
+		return 0, nil, fmt.Errorf("%w: failed to read record: %w", ErrInvalidSnapshot, err)
+	}
+
+	if snapshotRecordKind(kind) == snapshotRecordEnd {
+		if !bytes.Equal(payload, checksum) {
+			return 0, nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidSnapshot)
+		}
+		if _, err := sr.r.ReadByte(); !errors.Is(err, io.EOF) {
+			return 0, nil, fmt.Errorf("%w: data after the end record", ErrInvalidSnapshot)
+		}
+	}
+	return snapshotRecordKind(kind), payload, nil
+}
+
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(r, b[:])
+	return b[0], err
+}
+
+// ImportSnapshot reads the snapshot written by ExportSnapshot into the database, which must have no blocks
+// of the shard. The input is read twice: the checksum of the whole file is verified before anything is written.
+// The trie nodes are checked against their keys, and the state of the snapshot block is checked to be complete
+// before the blocks are written. Until then, the shard stays empty, so a failed import leaves only unreachable
+// nodes behind.
+// The state and the transactions of the blocks before the snapshot block are not available after the import.
+func ImportSnapshot(ctx context.Context, txFabric db.DB, r io.ReadSeeker) (*SnapshotHeader, error) {
+	if err := verifySnapshotChecksum(r); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	sr, err := newSnapshotReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, payload, err := sr.next()
+	if err != nil {
+		return nil, err
+	}
+	if kind != snapshotRecordHeader {
+		return nil, fmt.Errorf("%w: the first record is not the header", ErrInvalidSnapshot)
+	}
+	header := new(SnapshotHeader)
+	if err := json.Unmarshal(payload, header); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode header: %w", ErrInvalidSnapshot, err)
+	}
+	if header.Headers == 0 || header.Headers > uint64(header.BlockNumber)+1 {
+		return nil, fmt.Errorf("%w: invalid number of headers %d", ErrInvalidSnapshot, header.Headers)
+	}
+	shardId := header.ShardId
+
+	if err := checkShardIsEmpty(ctx, txFabric, shardId); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*types.Block, 0, header.Headers)
+	hashes := make([]common.Hash, 0, header.Headers)
+	for range header.Headers {
+		kind, payload, err := sr.next()
+		if err != nil {
+			return nil, err
+		}
+		if kind != snapshotRecordBlock {
+			return nil, fmt.Errorf("%w: expected %d block headers, got %d", ErrInvalidSnapshot, header.Headers, len(blocks))
+		}
+		block := new(types.Block)
+		if err := block.UnmarshalSSZ(payload); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode block: %w", ErrInvalidSnapshot, err)
+		}
+
+		expected := header.BlockHash
+		expectedId := header.BlockNumber
+		if n := len(blocks); n > 0 {
+			expected = blocks[n-1].PrevBlock
+			expectedId = blocks[n-1].Id - 1
+		}
+		blockHash := block.Hash(shardId)
+		if blockHash != expected || block.Id != expectedId {
+			return nil, fmt.Errorf("%w: block %d doesn't match the chain of the snapshot block", ErrInvalidSnapshot, block.Id)
+		}
+		blocks = append(blocks, block)
+		hashes = append(hashes, blockHash)
+	}
+	block := blocks[0]
+
+	allowedTables := make(map[db.ShardedTableName]bool)
+	for _, table := range stateTrieTables {
+		allowedTables[table] = true
+	}
+	for table := range snapshotTrieRoots(shardId, block) {
+		allowedTables[table] = true
+	}
+
+	var tx db.RwTx
+	defer func() {
+		if tx != nil {
+			tx.Rollback()
+		}
+	}()
+	for records := 0; ; records++ {
+		kind, payload, err := sr.next()
+		if err != nil {
+			return nil, err
+		}
+		if kind == snapshotRecordEnd {
+			break
+		}
+
+		if records%snapshotImportBatchSize == 0 {
+			if tx != nil {
+				if err := tx.Commit(); err != nil {
+					return nil, err
+				}
+			}
+			if tx, err = txFabric.CreateRwTx(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		switch kind {
+		case snapshotRecordNode:
+			tableLen, n := binary.Uvarint(payload)
+			if n <= 0 || uint64(len(payload)-n) < tableLen+common.HashSize {
+				return nil, fmt.Errorf("%w: malformed node record", ErrInvalidSnapshot)
+			}
+			payload = payload[n:]
+			table := db.ShardedTableName(payload[:tableLen])
+			key, node := payload[tableLen:tableLen+common.HashSize], payload[tableLen+common.HashSize:]
+			if !allowedTables[table] {
+				return nil, fmt.Errorf("%w: unexpected table %s", ErrInvalidSnapshot, table)
+			}
+			if !mpt.IsNodeKey(key, node) {
+				return nil, fmt.Errorf("%w: node %x of %s doesn't match its key", ErrInvalidSnapshot, key, table)
+			}
+			if err := tx.PutToShard(shardId, table, key, node); err != nil {
+				return nil, err
+			}
+		case snapshotRecordCode:
+			if len(payload) < common.HashSize {
+				return nil, fmt.Errorf("%w: malformed code record", ErrInvalidSnapshot)
+			}
+			codeHash, code := common.BytesToHash(payload[:common.HashSize]), types.Code(payload[common.HashSize:])
+			if code.Hash() != codeHash {
+				return nil, fmt.Errorf("%w: code %s doesn't match its hash", ErrInvalidSnapshot, codeHash)
+			}
+			if err := db.WriteCode(tx, shardId, codeHash, code); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected record kind %d", ErrInvalidSnapshot, kind)
+		}
+	}
+
+	if tx == nil {
+		if tx, err = txFabric.CreateRwTx(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := verifySnapshotState(tx, shardId, block); err != nil {
+		return nil, err
+	}
+
+	for i, b := range blocks {
+		if err := db.WriteBlock(tx, shardId, hashes[i], b); err != nil {
+			return nil, err
+		}
+		if err := tx.PutToShard(shardId, db.BlockHashByNumberIndex, b.Id.Bytes(), hashes[i].Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeSnapshotTransactionIndex(tx, shardId, header.BlockHash, block); err != nil {
+		return nil, err
+	}
+	if err := db.WriteOldestStateBlock(tx, shardId, block.Id); err != nil {
+		return nil, err
+	}
+	if err := db.WriteLastBlockHash(tx, shardId, header.BlockHash); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	tx = nil
+	return header, nil
+}
+
+// verifySnapshotChecksum reads the snapshot up to the end record and checks its checksum.
+func verifySnapshotChecksum(r io.Reader) error {
+	sr, err := newSnapshotReader(r)
+	if err != nil {
+		return err
+	}
+	for {
+		kind, _, err := sr.next()
+		if err != nil {
+			return err
+		}
+		if kind == snapshotRecordEnd {
+			return nil
+		}
+	}
+}
+
+// writeSnapshotTransactionIndex indexes the transactions of the snapshot block by their hashes.
+// The transactions of the older blocks are not included in the snapshot.
+func writeSnapshotTransactionIndex(tx db.RwTx, shardId types.ShardId, blockHash common.Hash, block *types.Block) error {
+	for table, root := range map[db.ShardedTableName]common.Hash{
+		db.BlockHashAndInTransactionIndexByTransactionHash:  block.InTransactionsRoot,
+		db.BlockHashAndOutTransactionIndexByTransactionHash: block.OutTransactionsRoot,
+	} {
+		reader := NewDbTransactionTrieReader(tx, shardId)
+		reader.SetRootHash(root)
+		entries, err := reader.Entries()
+		if err != nil {
+			return fmt.Errorf("%w: failed to read transactions: %w", ErrInvalidSnapshot, err)
+		}
+
+		txnHashes := make([]common.Hash, len(entries))
+		for _, entry := range entries {
+			if uint64(entry.Key) >= uint64(len(txnHashes)) {
+				return fmt.Errorf("%w: unexpected transaction index %d", ErrInvalidSnapshot, entry.Key)
+			}
+			txnHashes[entry.Key] = entry.Val.Hash()
+		}
+		if err := writeTransactionIndex(tx, shardId, blockHash, txnHashes, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySnapshotState checks that all the nodes and the codes of the snapshot block are present.
+func verifySnapshotState(tx db.RoTx, shardId types.ShardId, block *types.Block) error {
+	if _, err := CheckBlockState(tx, shardId, block); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	return nil
+}
+
+func checkShardIsEmpty(ctx context.Context, txFabric db.DB, shardId types.ShardId) error {
+	tx, err := txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = db.ReadLastBlockHash(tx, shardId)
+	if err == nil {
+		return fmt.Errorf("shard %d already has blocks", shardId)
+	}
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	return nil
+}
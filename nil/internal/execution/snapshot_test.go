@@ -0,0 +1,194 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rwTxCounter counts the read-write transactions opened in the database.
+type rwTxCounter struct {
+	db.DB
+	rwTxs int
+}
+
+func (d *rwTxCounter) CreateRwTx(ctx context.Context) (db.RwTx, error) {
+	d.rwTxs++
+	return d.DB.CreateRwTx(ctx)
+}
+
+func TestExportImportSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	shardId := types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	address := types.ShardAndHexToAddress(shardId, "11")
+	token := types.TokenId(types.ShardAndHexToAddress(shardId, "22"))
+	code := types.Code("some code")
+	slot := common.IntToHash(1)
+
+	var blocks []*types.Block
+	var txnHashes []common.Hash
+	for n := range uint64(4) {
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+
+		var prevBlock *types.Block
+		if len(blocks) > 0 {
+			prevBlock = blocks[len(blocks)-1]
+		}
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		if n == 0 {
+			require.NoError(t, es.CreateAccount(address))
+			require.NoError(t, es.SetCode(address, code))
+			require.NoError(t, es.AddToken(address, token, types.NewValueFromUint64(100)))
+		}
+		require.NoError(t, es.SetBalance(address, types.NewValueFromUint64(n+1)))
+		require.NoError(t, es.SetState(address, slot, common.IntToHash(int(n))))
+		txn := types.NewEmptyTransaction()
+		txn.To = address
+		txn.Seqno = types.Seqno(n)
+		txnHashes = append(txnHashes, es.AddInTransaction(txn))
+		es.AddReceipt(NewExecutionResult())
+
+		res, err := es.Commit(types.BlockNumber(n), nil)
+		require.NoError(t, err)
+		require.NoError(t, PostprocessBlock(tx, shardId, res))
+		require.NoError(t, tx.Commit())
+		blocks = append(blocks, res.Block)
+	}
+
+	export := func(t *testing.T, database db.DB, blockNumber types.BlockNumber, headers uint64) []byte {
+		t.Helper()
+
+		var buf bytes.Buffer
+		header, err := ExportSnapshot(ctx, database, shardId, blockNumber, headers, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[blockNumber].Hash(shardId), header.BlockHash)
+		return buf.Bytes()
+	}
+	newDb := func(t *testing.T) db.DB {
+		t.Helper()
+
+		database, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		t.Cleanup(database.Close)
+		return database
+	}
+
+	snapshot := export(t, database, 2, 0)
+
+	t.Run("Import", func(t *testing.T) {
+		imported := newDb(t)
+		header, err := ImportSnapshot(ctx, imported, bytes.NewReader(snapshot))
+		require.NoError(t, err)
+		assert.Equal(t, &SnapshotHeader{
+			ShardId:     shardId,
+			BlockNumber: 2,
+			BlockHash:   blocks[2].Hash(shardId),
+			Headers:     3,
+		}, header)
+
+		tx, err := imported.CreateRoTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		block, hash, err := db.ReadLastBlock(tx, shardId)
+		require.NoError(t, err)
+		assert.Equal(t, header.BlockHash, hash)
+		first, err := db.ReadBlockByNumber(tx, shardId, 0)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[0].Hash(shardId), first.Hash(shardId))
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          block,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		balance, err := es.GetBalance(address)
+		require.NoError(t, err)
+		assert.Equal(t, types.NewValueFromUint64(3), balance)
+		value, err := es.GetState(address, slot)
+		require.NoError(t, err)
+		assert.Equal(t, common.IntToHash(2), value)
+		actualCode, _, err := es.GetCode(address)
+		require.NoError(t, err)
+		assert.Equal(t, code, types.Code(actualCode))
+		assert.Equal(t, map[types.TokenId]types.Value{token: types.NewValueFromUint64(100)}, es.GetTokens(address))
+
+		accessor := NewStateAccessor().Access(tx, shardId)
+		require.NoError(t, accessor.CheckStateAvailable(2))
+		require.ErrorIs(t, accessor.CheckStateAvailable(1), ErrStatePruned)
+
+		// Only the transactions of the snapshot block are available.
+		txn, err := accessor.GetInTransaction().ByHash(txnHashes[2])
+		require.NoError(t, err)
+		assert.Equal(t, txnHashes[2], txn.Transaction().Hash())
+		assert.Equal(t, types.TransactionIndex(0), txn.Index())
+		_, err = accessor.GetInTransaction().ByHash(txnHashes[1])
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+		// The export of the same state is identical.
+		assert.Equal(t, snapshot, export(t, imported, 2, 0))
+	})
+
+	t.Run("Headers", func(t *testing.T) {
+		imported := newDb(t)
+		_, err := ImportSnapshot(ctx, imported, bytes.NewReader(export(t, database, 3, 1)))
+		require.NoError(t, err)
+
+		tx, err := imported.CreateRoTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		_, err = db.ReadBlockByNumber(tx, shardId, 3)
+		require.NoError(t, err)
+		_, err = db.ReadBlockByNumber(tx, shardId, 2)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		_, err := ImportSnapshot(ctx, database, bytes.NewReader(snapshot))
+		require.ErrorContains(t, err, "already has blocks")
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		importCorrupted := func(data []byte) error {
+			imported := &rwTxCounter{DB: newDb(t)}
+			_, err := ImportSnapshot(ctx, imported, bytes.NewReader(data))
+
+			// The checksum is verified before anything is written.
+			assert.Zero(t, imported.rwTxs)
+
+			tx, txErr := imported.CreateRoTx(ctx)
+			require.NoError(t, txErr)
+			defer tx.Rollback()
+			_, readErr := db.ReadLastBlockHash(tx, shardId)
+			require.ErrorIs(t, readErr, db.ErrKeyNotFound)
+			return err
+		}
+
+		flipped := bytes.Clone(snapshot)
+		flipped[len(flipped)/2] ^= 1
+		require.ErrorIs(t, importCorrupted(flipped), ErrInvalidSnapshot)
+		require.ErrorIs(t, importCorrupted(snapshot[:len(snapshot)-1]), ErrInvalidSnapshot)
+		require.ErrorIs(t, importCorrupted(append(bytes.Clone(snapshot), 0)), ErrInvalidSnapshot)
+		require.ErrorIs(t, importCorrupted([]byte("not a snapshot")), ErrInvalidSnapshot)
+	})
+}
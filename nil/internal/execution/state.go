@@ -91,6 +91,9 @@ type ExecutionState struct {
 	// If true, log every instruction execution.
 	TraceVm bool
 
+	// If set, the hooks are installed into every VM created by the state.
+	evmTracer *tracing.Hooks
+
 	shardAccessor *shardAccessor
 
 	// Pointer to currently executed VM
@@ -258,7 +261,15 @@ func NewExecutionState(tx any, shardId types.ShardId, params StateParams) (*Exec
 	var baseFeePerGas types.Value
 	var prevBlockHash common.Hash
 	if params.Block != nil {
-		baseFeePerGas = feeCalculator.CalculateBaseFee(params.Block)
+		// The config of the new block holds the fee params active at its height, so replays use the same curve.
+		var feeParams *config.FeeParams
+		if params.ConfigAccessor != nil {
+			var err error
+			if feeParams, err = config.GetShardFeeParams(params.ConfigAccessor, shardId); err != nil {
+				return nil, err
+			}
+		}
+		baseFeePerGas = feeCalculator.CalculateBaseFee(params.Block, feeParams)
 		prevBlockHash = params.Block.Hash(shardId)
 	}
 
@@ -1427,6 +1438,7 @@ func (es *ExecutionState) BuildBlock(blockId types.BlockNumber) (*BlockGeneratio
 		InTxnHashes:  es.InTransactionHashes,
 		OutTxns:      outTxnValues,
 		OutTxnHashes: outTxnHashes,
+		Receipts:     es.Receipts,
 		ConfigParams: configParams,
 	}, nil
 }
@@ -1737,9 +1749,16 @@ func (es *ExecutionState) newVm(internal bool, origin types.Address, state *vm.E
 	}
 	es.evm = vm.NewEVM(blockContext, es, origin, es.GasPrice, state)
 	es.evm.IsAsyncCall = internal
+	es.evm.Config.Tracer = es.evmTracer
 	return nil
 }
 
+// SetEvmTracer sets the hooks that receive events of all subsequent VM executions.
+// Pass nil to disable tracing.
+func (es *ExecutionState) SetEvmTracer(hooks *tracing.Hooks) {
+	es.evmTracer = hooks
+}
+
 func (es *ExecutionState) resetVm() {
 	es.evm = nil
 }
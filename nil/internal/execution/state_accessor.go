@@ -15,6 +15,9 @@ import (
 	lru "github.com/hashicorp/golang-lru/v2"
 )
 
+// ErrStatePruned is returned for the state queries of the blocks that are older than the state retention window.
+var ErrStatePruned = errors.New("state is pruned")
+
 type fieldAccessor[T any] func() T
 
 func notInitialized[T any](name string) fieldAccessor[T] {
@@ -207,6 +210,22 @@ type rawShardAccessor struct {
 	shardId  types.ShardId
 }
 
+// CheckStateAvailable returns ErrStatePruned if the state of the block was removed by the state pruning.
+func (s *rawShardAccessor) CheckStateAvailable(blockId types.BlockNumber) error {
+	oldest, err := db.ReadOldestStateBlock(s.tx, s.shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if blockId < oldest {
+		return fmt.Errorf("%w: state of block %d of shard %d is not available, the oldest block with state is %d",
+			ErrStatePruned, blockId, s.shardId, oldest)
+	}
+	return nil
+}
+
 func (s *rawShardAccessor) GetBlock() rawBlockAccessor {
 	return rawBlockAccessor{rawShardAccessor: s}
 }
@@ -0,0 +1,278 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+var (
+	ErrIncompleteState = errors.New("incomplete state")
+	// ErrReferencedBlock is returned when a rolled back block is referenced by another shard.
+	ErrReferencedBlock = errors.New("block is referenced by another shard")
+)
+
+type StateCheckResult struct {
+	ShardId     types.ShardId     `json:"shardId"`
+	BlockNumber types.BlockNumber `json:"blockNumber"`
+	BlockHash   common.Hash       `json:"blockHash"`
+	// Nodes is the number of the stored trie nodes of the block per table.
+	Nodes map[db.ShardedTableName]int `json:"nodes"`
+	// Codes is the number of the distinct contract codes.
+	Codes int `json:"codes"`
+}
+
+// CheckBlockState checks that the tries of the block (the state, the transactions, the receipts and,
+// for the main shard, the config and the shard blocks) and the codes of the contracts are stored in full.
+// The missing data is reported as ErrIncompleteState.
+func CheckBlockState(tx db.RoTx, shardId types.ShardId, block *types.Block) (*StateCheckResult, error) {
+	sets, codes, err := markSnapshot(tx, shardId, block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIncompleteState, err)
+	}
+
+	res := &StateCheckResult{
+		ShardId:     shardId,
+		BlockNumber: block.Id,
+		BlockHash:   block.Hash(shardId),
+		Nodes:       make(map[db.ShardedTableName]int, len(sets)),
+	}
+	for table, set := range sets {
+		res.Nodes[table] = len(set)
+	}
+	for codeHash := range codes {
+		if codeHash == common.EmptyHash {
+			continue
+		}
+		if _, err := db.ReadCode(tx, shardId, codeHash); err != nil {
+			return nil, fmt.Errorf("%w: code %s: %w", ErrIncompleteState, codeHash, err)
+		}
+		res.Codes++
+	}
+	return res, nil
+}
+
+// CheckLastBlocksState runs CheckBlockState for the last blocks of all the shards.
+// The results are ordered by the shards, the check stops at the first shard with an incomplete state.
+func CheckLastBlocksState(ctx context.Context, txFabric db.ReadOnlyDB) ([]*StateCheckResult, error) {
+	tx, err := txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	heads, err := db.ReadLastBlockHashes(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIds := slices.Sorted(maps.Keys(heads))
+	res := make([]*StateCheckResult, 0, len(heads))
+	for _, shardId := range shardIds {
+		block, err := db.ReadBlock(tx, shardId, heads[shardId])
+		if err != nil {
+			return res, fmt.Errorf("failed to read the last block %s of shard %d: %w", heads[shardId], shardId, err)
+		}
+		checked, err := CheckBlockState(tx, shardId, block)
+		if err != nil {
+			return res, fmt.Errorf("shard %d, block %d: %w", shardId, block.Id, err)
+		}
+		res = append(res, checked)
+	}
+	return res, nil
+}
+
+type RollbackResult struct {
+	// From is the last block of the shard before the rollback.
+	From types.BlockNumber
+	// To is the last block of the shard after the rollback.
+	To types.BlockNumber
+}
+
+// RollbackShard makes the block the last one of the shard and removes the newer blocks with their transaction
+// and log indexes. The state of the block must be complete. The blocks are removed one by one from the last one,
+// so an interrupted rollback leaves a consistent shard and may be repeated.
+// The trie nodes of the removed blocks stay in the DB until they are pruned.
+// The rollback is refused with ErrReferencedBlock if a removed block is referenced by the last block
+// of the main shard (for a child shard) or of a child shard (for the main shard): roll back the referencing
+// shard first. The collator state of the shard is not rolled back, so the shard must be synchronized from the peers
+// rather than collated after the rollback.
+func RollbackShard(
+	ctx context.Context, txFabric db.DB, shardId types.ShardId, blockId types.BlockNumber,
+) (*RollbackResult, error) {
+	res, err := checkRollbackTarget(ctx, txFabric, shardId, blockId)
+	if err != nil {
+		return nil, err
+	}
+	for last := res.From; last > blockId; last-- {
+		if err := removeLastBlock(ctx, txFabric, shardId, last); err != nil {
+			return nil, fmt.Errorf("failed to remove block %d: %w", last, err)
+		}
+	}
+	return res, nil
+}
+
+func checkRollbackTarget(
+	ctx context.Context, txFabric db.DB, shardId types.ShardId, blockId types.BlockNumber,
+) (*RollbackResult, error) {
+	tx, err := txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	last, _, err := db.ReadLastBlock(tx, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the last block of shard %d: %w", shardId, err)
+	}
+	if blockId > last.Id {
+		return nil, fmt.Errorf("block %d is newer than the last block %d of shard %d", blockId, last.Id, shardId)
+	}
+	block, err := db.ReadBlockByNumber(tx, shardId, blockId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %d: %w", blockId, err)
+	}
+	if _, err := CheckBlockState(tx, shardId, block); err != nil {
+		return nil, fmt.Errorf("can't roll back to block %d: %w", blockId, err)
+	}
+	if err := checkRollbackReferences(tx, shardId, blockId); err != nil {
+		return nil, fmt.Errorf("can't roll back to block %d: %w", blockId, err)
+	}
+	return &RollbackResult{From: last.Id, To: blockId}, nil
+}
+
+// checkRollbackReferences checks that the last blocks of the other shards don't reference the blocks
+// of the shard newer than blockId. The main shard references the child blocks in ChildBlocksRootHash,
+// the child shards reference the main shard blocks in MainChainHash.
+func checkRollbackReferences(tx db.RoTx, shardId types.ShardId, blockId types.BlockNumber) error {
+	heads, err := db.ReadLastBlockHashes(tx)
+	if err != nil {
+		return err
+	}
+
+	for otherId, head := range heads {
+		if otherId == shardId || (!shardId.IsMainShard() && !otherId.IsMainShard()) {
+			continue
+		}
+		other, err := db.ReadBlock(tx, otherId, head)
+		if err != nil {
+			return fmt.Errorf("failed to read the last block %s of shard %d: %w", head, otherId, err)
+		}
+
+		var ref common.Hash
+		if shardId.IsMainShard() {
+			ref = other.MainChainHash
+		} else {
+			treeShards := NewDbShardBlocksTrieReader(tx, otherId, other.Id)
+			treeShards.SetRootHash(other.ChildBlocksRootHash)
+			value, err := treeShards.Fetch(shardId)
+			if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+				return fmt.Errorf("failed to read the child blocks of main shard block %d: %w", other.Id, err)
+			}
+			if value != nil {
+				ref = *value
+			}
+		}
+		if ref.Empty() {
+			continue
+		}
+
+		refBlock, err := db.ReadBlock(tx, shardId, ref)
+		if err != nil {
+			return fmt.Errorf("failed to read block %s referenced by shard %d: %w", ref, otherId, err)
+		}
+		if refBlock.Id > blockId {
+			return fmt.Errorf("%w: block %d of shard %d references block %d",
+				ErrReferencedBlock, other.Id, otherId, refBlock.Id)
+		}
+	}
+	return nil
+}
+
+// removeLastBlock removes the last block of the shard and makes its parent the last one.
+func removeLastBlock(ctx context.Context, txFabric db.DB, shardId types.ShardId, blockId types.BlockNumber) error {
+	tx, err := txFabric.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	block, hash, err := db.ReadLastBlock(tx, shardId)
+	if err != nil {
+		return err
+	}
+	if block.Id != blockId {
+		return fmt.Errorf("the last block of shard %d changed to %d", shardId, block.Id)
+	}
+
+	receiptsReader := NewDbReceiptTrieReader(tx, shardId)
+	receiptsReader.SetRootHash(block.ReceiptsRoot)
+	receipts, err := receiptsReader.Values()
+	if err != nil {
+		return fmt.Errorf("failed to read receipts: %w", err)
+	}
+	outTxnsReader := NewDbTransactionTrieReader(tx, shardId)
+	outTxnsReader.SetRootHash(block.OutTransactionsRoot)
+	outTxns, err := outTxnsReader.Values()
+	if err != nil {
+		return fmt.Errorf("failed to read outbound transactions: %w", err)
+	}
+
+	// The receipts hold the hashes of the inbound transactions.
+	for _, receipt := range receipts {
+		if err := deleteTxnIndex(
+			tx, shardId, db.BlockHashAndInTransactionIndexByTransactionHash, receipt.TxnHash, hash); err != nil {
+			return err
+		}
+		if err := db.DeleteError(tx, receipt.TxnHash); err != nil {
+			return err
+		}
+	}
+	for _, txn := range outTxns {
+		if err := deleteTxnIndex(
+			tx, shardId, db.BlockHashAndOutTransactionIndexByTransactionHash, txn.Hash(), hash); err != nil {
+			return err
+		}
+	}
+	if err := db.DeleteLogIndex(tx, shardId, block.Id, receipts); err != nil {
+		return err
+	}
+
+	if err := tx.DeleteFromShard(shardId, db.BlockHashByNumberIndex, block.Id.Bytes()); err != nil {
+		return err
+	}
+	if err := db.DeleteBlock(tx, shardId, hash); err != nil {
+		return err
+	}
+	if err := db.WriteLastBlockHash(tx, shardId, block.PrevBlock); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteTxnIndex removes the index entry of the transaction if it points to the block.
+func deleteTxnIndex(
+	tx db.RwTx, shardId types.ShardId, table db.ShardedTableName, txnHash, blockHash common.Hash,
+) error {
+	value, err := tx.GetFromShard(shardId, table, txnHash.Bytes())
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var index db.BlockHashAndTransactionIndex
+	if err := index.UnmarshalSSZ(value); err != nil {
+		return err
+	}
+	if index.BlockHash != blockHash {
+		return nil
+	}
+	return tx.DeleteFromShard(shardId, table, txnHash.Bytes())
+}
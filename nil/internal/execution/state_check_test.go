@@ -0,0 +1,184 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAndRollbackShard(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	shardId := types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	address := types.ShardAndHexToAddress(shardId, "11")
+	code := types.Code("some code")
+
+	var (
+		blocks  []*types.Block
+		hashes  []common.Hash
+		txnHash []common.Hash
+	)
+	for n := range uint64(4) {
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+
+		var prevBlock *types.Block
+		if len(blocks) > 0 {
+			prevBlock = blocks[len(blocks)-1]
+		}
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		if n == 0 {
+			require.NoError(t, es.CreateAccount(address))
+			require.NoError(t, es.SetCode(address, code))
+		}
+		require.NoError(t, es.SetBalance(address, types.NewValueFromUint64(n+1)))
+
+		txn := types.NewEmptyTransaction()
+		txn.To = address
+		txn.Seqno = types.Seqno(n)
+		hash := es.AddInTransaction(txn)
+		es.Logs[hash] = []*types.Log{{Address: address, Topics: []common.Hash{common.IntToHash(int(n))}}}
+		es.AddReceipt(&ExecutionResult{})
+
+		res, err := es.Commit(types.BlockNumber(n), nil)
+		require.NoError(t, err)
+		require.NoError(t, PostprocessBlock(tx, shardId, res))
+		require.NoError(t, tx.Commit())
+		blocks = append(blocks, res.Block)
+		hashes = append(hashes, res.BlockHash)
+		txnHash = append(txnHash, hash)
+	}
+
+	results, err := CheckLastBlocksState(ctx, database)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, shardId, results[0].ShardId)
+	assert.Equal(t, types.BlockNumber(3), results[0].BlockNumber)
+	assert.Equal(t, hashes[3], results[0].BlockHash)
+	assert.Equal(t, 1, results[0].Codes)
+	assert.Positive(t, results[0].Nodes[db.ContractTrieTable])
+
+	t.Run("NoBlocks", func(t *testing.T) {
+		_, err := RollbackShard(ctx, database, types.MainShardId, 0)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	// The main shard blocks reference the child blocks 1 and 3,
+	// the blocks of another shard reference the main shard blocks.
+	otherShardId := shardId + 1
+	commitBlock := func(shardId types.ShardId, prevBlock *types.Block, setup func(es *ExecutionState)) *BlockGenerationResult {
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		setup(es)
+
+		var blockId types.BlockNumber
+		if prevBlock != nil {
+			blockId = prevBlock.Id + 1
+		}
+		res, err := es.Commit(blockId, nil)
+		require.NoError(t, err)
+		require.NoError(t, PostprocessBlock(tx, shardId, res))
+		require.NoError(t, tx.Commit())
+		return res
+	}
+	var mainBlock, otherBlock *types.Block
+	for _, childBlock := range []int{1, 3} {
+		mainRes := commitBlock(types.MainShardId, mainBlock, func(es *ExecutionState) {
+			es.ChildChainBlocks = map[types.ShardId]common.Hash{shardId: hashes[childBlock]}
+		})
+		mainBlock = mainRes.Block
+		otherBlock = commitBlock(otherShardId, otherBlock, func(es *ExecutionState) {
+			es.MainChainHash = mainRes.BlockHash
+		}).Block
+	}
+
+	t.Run("ReferencedByMainShard", func(t *testing.T) {
+		_, err := RollbackShard(ctx, database, shardId, 1)
+		require.ErrorIs(t, err, ErrReferencedBlock)
+	})
+
+	t.Run("ReferencedByChildShard", func(t *testing.T) {
+		_, err := RollbackShard(ctx, database, types.MainShardId, 0)
+		require.ErrorIs(t, err, ErrReferencedBlock)
+	})
+
+	res, err := RollbackShard(ctx, database, otherShardId, 0)
+	require.NoError(t, err)
+	assert.Equal(t, &RollbackResult{From: 1, To: 0}, res)
+
+	res, err = RollbackShard(ctx, database, types.MainShardId, 0)
+	require.NoError(t, err)
+	assert.Equal(t, &RollbackResult{From: 1, To: 0}, res)
+
+	res, err = RollbackShard(ctx, database, shardId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &RollbackResult{From: 3, To: 1}, res)
+
+	tx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, hash, err := db.ReadLastBlock(tx, shardId)
+	require.NoError(t, err)
+	assert.Equal(t, hashes[1], hash)
+
+	for n, blockHash := range hashes {
+		_, err := db.ReadBlockHashByNumber(tx, shardId, types.BlockNumber(n))
+		_, blockErr := db.ReadBlock(tx, shardId, blockHash)
+		_, indexErr := tx.GetFromShard(
+			shardId, db.BlockHashAndInTransactionIndexByTransactionHash, txnHash[n].Bytes())
+		if n <= 1 {
+			require.NoError(t, err)
+			require.NoError(t, blockErr)
+			require.NoError(t, indexErr)
+		} else {
+			require.ErrorIs(t, err, db.ErrKeyNotFound)
+			require.ErrorIs(t, blockErr, db.ErrKeyNotFound)
+			require.ErrorIs(t, indexErr, db.ErrKeyNotFound)
+		}
+	}
+
+	logBlocks, err := db.ReadLogBlocksByAddress(tx, shardId, address, 0, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []types.BlockNumber{0, 1}, logBlocks)
+
+	_, err = CheckBlockState(tx, shardId, blocks[1])
+	require.NoError(t, err)
+
+	t.Run("Incomplete", func(t *testing.T) {
+		rwTx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		defer rwTx.Rollback()
+
+		require.NoError(t, rwTx.DeleteFromShard(shardId, db.ContractTrieTable, blocks[0].SmartContractsRoot.Bytes()))
+		_, err = CheckBlockState(rwTx, shardId, blocks[0])
+		require.ErrorIs(t, err, ErrIncompleteState)
+	})
+
+	t.Run("TooNew", func(t *testing.T) {
+		_, err := RollbackShard(ctx, database, shardId, 2)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,230 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const DefaultStatePruningInterval = 100
+
+// StatePruningConfig configures the removal of the historical state of the shards.
+// Blocks, transactions and receipts are never pruned.
+type StatePruningConfig struct {
+	// KeepBlocks is the number of the latest blocks of each shard whose state is kept. Zero disables pruning.
+	KeepBlocks uint64 `yaml:"keepBlocks,omitempty"`
+	// Interval is the number of blocks between pruning runs.
+	Interval uint64 `yaml:"interval,omitempty"`
+}
+
+func NewDefaultStatePruningConfig() *StatePruningConfig {
+	return &StatePruningConfig{
+		Interval: DefaultStatePruningInterval,
+	}
+}
+
+func (c *StatePruningConfig) Enabled() bool {
+	return c != nil && c.KeepBlocks > 0
+}
+
+// ShouldPrune reports whether the state should be pruned after the block is committed.
+func (c *StatePruningConfig) ShouldPrune(blockId types.BlockNumber) bool {
+	if !c.Enabled() {
+		return false
+	}
+	interval := c.Interval
+	if interval == 0 {
+		interval = DefaultStatePruningInterval
+	}
+	return uint64(blockId)%interval == 0
+}
+
+// stateTrieTables are the tables whose nodes are referenced from the state of the blocks.
+var stateTrieTables = []db.ShardedTableName{
+	db.ContractTrieTable,
+	db.StorageTrieTable,
+	db.TokenTrieTable,
+	db.AsyncCallContextTable,
+}
+
+type PruneStateResult struct {
+	// OldestBlock is the oldest block of the shard whose state is available after pruning.
+	OldestBlock types.BlockNumber
+	// DeletedNodes is the number of the trie nodes removed from the DB.
+	DeletedNodes int
+}
+
+// PruneState removes the trie nodes that are not reachable from the state of the last keepBlocks blocks of the shard.
+// The oldest available block is recorded before the nodes are deleted, so the state queries for older blocks
+// fail with ErrStatePruned instead of reading a partially deleted trie.
+// The retention window is marked in a single read snapshot, and the blocks committed concurrently
+// are marked before every deletion, so the state may be pruned while new blocks are written.
+func PruneState(
+	ctx context.Context, txFabric db.DB, shardId types.ShardId, keepBlocks uint64,
+) (*PruneStateResult, error) {
+	if keepBlocks == 0 {
+		return nil, errors.New("at least one block must be kept")
+	}
+
+	marker, err := markState(ctx, txFabric, shardId, keepBlocks)
+	if err != nil {
+		return nil, err
+	}
+	res := &PruneStateResult{OldestBlock: marker.oldest}
+	if marker.oldest == 0 {
+		return res, nil
+	}
+
+	if err := writeOldestStateBlock(ctx, txFabric, shardId, marker.oldest); err != nil {
+		return nil, err
+	}
+
+	res.DeletedNodes, err = marker.sweep(ctx, txFabric)
+	return res, err
+}
+
+// stateMarker collects the nodes of the state tries reachable from the blocks [oldest, last].
+type stateMarker struct {
+	shardId types.ShardId
+	sets    map[db.ShardedTableName]mpt.NodeSet
+	oldest  types.BlockNumber
+	last    types.BlockNumber
+}
+
+// markState marks the state of the last keepBlocks blocks of the shard in a single read snapshot.
+func markState(
+	ctx context.Context, txFabric db.DB, shardId types.ShardId, keepBlocks uint64,
+) (*stateMarker, error) {
+	tx, err := txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lastBlock, _, err := db.ReadLastBlock(tx, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last block: %w", err)
+	}
+	var oldest types.BlockNumber
+	if uint64(lastBlock.Id) >= keepBlocks {
+		oldest = lastBlock.Id + 1 - types.BlockNumber(keepBlocks)
+	}
+
+	// The state of the older blocks may be missing already, e.g. after a snapshot import.
+	available, err := db.ReadOldestStateBlock(tx, shardId)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, err
+	}
+
+	m := &stateMarker{
+		shardId: shardId,
+		sets:    make(map[db.ShardedTableName]mpt.NodeSet, len(stateTrieTables)),
+		oldest:  max(oldest, available),
+	}
+	for _, table := range stateTrieTables {
+		m.sets[table] = make(mpt.NodeSet)
+	}
+	if m.oldest == 0 {
+		return m, nil
+	}
+	if err := m.markBlocks(tx, m.oldest, lastBlock.Id); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// markNewBlocks marks the state of the blocks committed after the last marked one.
+// Reading the last block within the deleting transaction makes it conflict with a concurrent block commit.
+func (m *stateMarker) markNewBlocks(tx db.RoTx) error {
+	lastBlock, _, err := db.ReadLastBlock(tx, m.shardId)
+	if err != nil {
+		return fmt.Errorf("failed to read last block: %w", err)
+	}
+	if lastBlock.Id <= m.last {
+		return nil
+	}
+	return m.markBlocks(tx, m.last+1, lastBlock.Id)
+}
+
+// sweep deletes the nodes of the state tries that are not marked and returns the number of deleted nodes.
+func (m *stateMarker) sweep(ctx context.Context, txFabric db.DB) (int, error) {
+	total := 0
+	for _, table := range stateTrieTables {
+		deleted, err := mpt.Sweep(ctx, txFabric, m.shardId, table, m.sets[table], m.markNewBlocks)
+		total += deleted
+		if err != nil {
+			return total, fmt.Errorf("failed to sweep %s: %w", table, err)
+		}
+	}
+	return total, nil
+}
+
+func (m *stateMarker) markBlocks(tx db.RoTx, from, to types.BlockNumber) error {
+	for blockId := from; blockId <= to; blockId++ {
+		block, err := db.ReadBlockByNumber(tx, m.shardId, blockId)
+		if err != nil {
+			return fmt.Errorf("failed to read block %d: %w", blockId, err)
+		}
+		if err := markBlockState(tx, m.shardId, block, m.sets, nil); err != nil {
+			return fmt.Errorf("failed to mark state of block %d: %w", blockId, err)
+		}
+		m.last = blockId
+	}
+	return nil
+}
+
+func writeOldestStateBlock(ctx context.Context, txFabric db.DB, shardId types.ShardId, oldest types.BlockNumber) error {
+	tx, err := txFabric.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := db.WriteOldestStateBlock(tx, shardId, oldest); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// markBlockState adds the nodes of the state tries of the block to the sets of stateTrieTables.
+// onContract (if any) is called for the accounts found in the newly visited nodes.
+func markBlockState(
+	tx db.RoTx,
+	shardId types.ShardId,
+	block *types.Block,
+	sets map[db.ShardedTableName]mpt.NodeSet,
+	onContract func(contract *types.SmartContract) error,
+) error {
+	markSubtrie := func(table db.ShardedTableName, root common.Hash) error {
+		reader := mpt.NewDbReader(tx, shardId, table)
+		reader.SetRootHash(root)
+		return reader.Mark(sets[table], nil)
+	}
+	markContract := func(value []byte) error {
+		var contract types.SmartContract
+		if err := contract.UnmarshalSSZ(value); err != nil {
+			return err
+		}
+		if onContract != nil {
+			if err := onContract(&contract); err != nil {
+				return err
+			}
+		}
+		if err := markSubtrie(db.StorageTrieTable, contract.StorageRoot); err != nil {
+			return err
+		}
+		if err := markSubtrie(db.TokenTrieTable, contract.TokenRoot); err != nil {
+			return err
+		}
+		return markSubtrie(db.AsyncCallContextTable, contract.AsyncContextRoot)
+	}
+
+	contracts := mpt.NewDbReader(tx, shardId, db.ContractTrieTable)
+	contracts.SetRootHash(block.SmartContractsRoot)
+	return contracts.Mark(sets[db.ContractTrieTable], markContract)
+}
@@ -0,0 +1,184 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneState(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	shardId := types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	address := types.ShardAndHexToAddress(shardId, "11")
+	slot := common.IntToHash(1)
+
+	var blocks []*types.Block
+	// values are the balances and the stored values of the blocks.
+	var values []uint64
+	generateBlockWithValue := func(value uint64) {
+		t.Helper()
+
+		tx, err := database.CreateRwTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		var prevBlock *types.Block
+		if len(blocks) > 0 {
+			prevBlock = blocks[len(blocks)-1]
+		}
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+
+		n := uint64(len(blocks))
+		if n == 0 {
+			require.NoError(t, es.CreateAccount(address))
+		}
+		require.NoError(t, es.SetBalance(address, types.NewValueFromUint64(value+1)))
+		require.NoError(t, es.SetState(address, slot, common.IntToHash(int(value))))
+
+		res, err := es.Commit(types.BlockNumber(n), nil)
+		require.NoError(t, err)
+		require.NoError(t, PostprocessBlock(tx, shardId, res))
+		require.NoError(t, tx.Commit())
+
+		blocks = append(blocks, res.Block)
+		values = append(values, value)
+	}
+	generateBlock := func() {
+		t.Helper()
+		generateBlockWithValue(uint64(len(blocks)))
+	}
+
+	// checkState checks that the state of the block is either fully readable or removed.
+	checkState := func(n int, available bool) {
+		t.Helper()
+
+		tx, err := database.CreateRoTx(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		block := blocks[n]
+		contracts := NewDbContractTrieReader(tx, shardId)
+		contracts.SetRootHash(block.SmartContractsRoot)
+		contract, err := contracts.Fetch(address.Hash())
+
+		checkErr := NewStateAccessor().Access(tx, shardId).CheckStateAvailable(block.Id)
+		if !available {
+			require.ErrorIs(t, err, db.ErrKeyNotFound, "block %d", n)
+			require.ErrorIs(t, checkErr, ErrStatePruned, "block %d", n)
+			return
+		}
+		require.NoError(t, checkErr)
+		require.NoError(t, err, "block %d", n)
+		assert.Equal(t, types.NewValueFromUint64(values[n]+1), contract.Balance)
+
+		storage := NewDbStorageTrieReader(tx, shardId)
+		storage.SetRootHash(contract.StorageRoot)
+		value, err := storage.Fetch(slot)
+		require.NoError(t, err, "block %d", n)
+		assert.Equal(t, types.NewUint256(values[n]), value)
+
+		// Blocks are never pruned.
+		_, err = db.ReadBlockByNumber(tx, shardId, block.Id)
+		require.NoError(t, err)
+	}
+
+	for range 10 {
+		generateBlock()
+	}
+
+	t.Run("NothingToPrune", func(t *testing.T) {
+		res, err := PruneState(ctx, database, shardId, 20)
+		require.NoError(t, err)
+		assert.Equal(t, &PruneStateResult{}, res)
+		for n := range blocks {
+			checkState(n, true)
+		}
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		res, err := PruneState(ctx, database, shardId, 3)
+		require.NoError(t, err)
+		assert.Equal(t, types.BlockNumber(7), res.OldestBlock)
+		assert.Positive(t, res.DeletedNodes)
+
+		for n := range blocks {
+			checkState(n, n >= 7)
+		}
+	})
+
+	t.Run("PruneAgain", func(t *testing.T) {
+		// New blocks are built on top of the pruned state.
+		for range 5 {
+			generateBlock()
+		}
+
+		res, err := PruneState(ctx, database, shardId, 3)
+		require.NoError(t, err)
+		assert.Equal(t, types.BlockNumber(12), res.OldestBlock)
+
+		for n := range blocks {
+			checkState(n, n >= 12)
+		}
+
+		// The second run with the same window has nothing to delete.
+		res, err = PruneState(ctx, database, shardId, 3)
+		require.NoError(t, err)
+		assert.Zero(t, res.DeletedNodes)
+	})
+
+	t.Run("BlockCommittedWhilePruning", func(t *testing.T) {
+		for range 3 {
+			generateBlock()
+		}
+
+		marker, err := markState(ctx, database, shardId, 3)
+		require.NoError(t, err)
+		require.Equal(t, types.BlockNumber(15), marker.oldest)
+		require.NoError(t, writeOldestStateBlock(ctx, database, shardId, marker.oldest))
+
+		// The new block restores the state of the block 13, which is not marked, so its nodes are garbage
+		// unless the new block is marked before they are deleted.
+		generateBlockWithValue(13)
+
+		deleted, err := marker.sweep(ctx, database)
+		require.NoError(t, err)
+		assert.Positive(t, deleted)
+
+		for n := range blocks {
+			// The nodes of the block 13 are reachable from the new block, but its state is reported as pruned.
+			if n != 13 {
+				checkState(n, n >= 15)
+			}
+		}
+	})
+}
+
+func TestStatePruningConfig(t *testing.T) {
+	t.Parallel()
+
+	var disabled *StatePruningConfig
+	assert.False(t, disabled.Enabled())
+	assert.False(t, disabled.ShouldPrune(100))
+	assert.False(t, NewDefaultStatePruningConfig().ShouldPrune(100))
+
+	cfg := &StatePruningConfig{KeepBlocks: 10, Interval: 5}
+	assert.True(t, cfg.Enabled())
+	assert.True(t, cfg.ShouldPrune(10))
+	assert.False(t, cfg.ShouldPrune(11))
+}
@@ -15,13 +15,22 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// ContractDescr describes an account of the zero state. The account is deployed from the compiled Contract,
+// gets the raw Code as is, or is a plain account without code if both are empty.
 type ContractDescr struct {
 	Name     string        `yaml:"name"`
 	Address  types.Address `yaml:"address,omitempty"`
 	Value    types.Value   `yaml:"value"`
 	Shard    types.ShardId `yaml:"shard,omitempty"`
-	Contract string        `yaml:"contract"`
+	Contract string        `yaml:"contract,omitempty"`
 	CtorArgs []any         `yaml:"ctorArgs,omitempty"`
+
+	// Code is the deployed bytecode of the account. The constructor is not run.
+	Code hexutil.Bytes `yaml:"code,omitempty"`
+	// Storage is written after the deployment, so it overrides the slots set by the constructor.
+	Storage  map[common.Hash]common.Hash   `yaml:"storage,omitempty"`
+	Tokens   map[types.TokenId]types.Value `yaml:"tokens,omitempty"`
+	ExtSeqno types.Seqno                   `yaml:"extSeqno,omitempty"`
 }
 
 type MainKeys struct {
@@ -32,6 +41,7 @@ type MainKeys struct {
 type ConfigParams struct {
 	Validators config.ParamValidators `yaml:"validators,omitempty"`
 	GasPrice   config.ParamGasPrice   `yaml:"gasPrice"`
+	Fees       config.ParamFees       `yaml:"fees,omitempty"`
 }
 
 type ZeroStateConfig struct {
@@ -138,6 +148,10 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 		if err != nil {
 			return err
 		}
+		err = config.SetParamFees(cfgAccessor, &stateConfig.ConfigParams.Fees)
+		if err != nil {
+			return err
+		}
 	}
 
 	if len(stateConfig.ConfigParams.GasPrice.Shards) != 0 {
@@ -148,69 +162,121 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 	}
 
 	for _, contract := range stateConfig.Contracts {
-		code, err := contracts.GetCode(contract.Contract)
+		addr, err := contract.address()
 		if err != nil {
 			return err
 		}
-		var addr types.Address
-		if contract.Address != types.EmptyAddress {
-			addr = contract.Address
-		} else {
-			addr = types.CreateAddress(contract.Shard, types.BuildDeployPayload(code, common.EmptyHash))
-		}
-
 		if addr.ShardId() != es.ShardId {
 			continue
 		}
 
-		abi, err := contracts.GetAbi(contract.Contract)
-		if err != nil {
+		if err := es.CreateAccount(addr); err != nil {
+			return err
+		}
+		if err := es.SetBalance(addr, contract.Value); err != nil {
 			return err
 		}
 
-		args := make([]any, 0)
-		for _, arg := range contract.CtorArgs {
-			switch arg := arg.(type) {
-			case string:
-				switch {
-				case arg[:2] == "0x":
-					args = append(args, hexutil.FromHex(arg))
-				default:
-					return fmt.Errorf("unknown constructor argument string pattern: %s", arg)
-				}
-			default:
-				args = append(args, arg)
+		switch {
+		case contract.Contract != "":
+			if err := es.deployZeroStateContract(addr, contract); err != nil {
+				return err
+			}
+		case len(contract.Code) != 0:
+			if err := es.CreateContract(addr); err != nil {
+				return err
+			}
+			if err := es.SetCode(addr, contract.Code); err != nil {
+				return err
 			}
-		}
-		argsPacked, err := abi.Pack("", args...)
-		if err != nil {
-			return fmt.Errorf("[ZeroState] ctorArgs pack failed: %w", err)
-		}
-		code = append(code, argsPacked...)
-
-		mainDeployTxn := &types.Transaction{
-			TransactionDigest: types.TransactionDigest{
-				Flags:        types.NewTransactionFlags(types.TransactionFlagInternal),
-				Seqno:        0,
-				Data:         code,
-				MaxFeePerGas: types.MaxFeePerGasDefault,
-			},
 		}
 
-		if err := es.CreateAccount(addr); err != nil {
-			return err
-		}
-		if err := es.CreateContract(addr); err != nil {
-			return err
+		for key, value := range contract.Storage {
+			if err := es.SetState(addr, key, value); err != nil {
+				return err
+			}
 		}
-		if err := es.SetBalance(addr, contract.Value); err != nil {
-			return err
+		for token, amount := range contract.Tokens {
+			if err := es.AddToken(addr, token, amount); err != nil {
+				return err
+			}
 		}
-		if err := es.SetInitState(addr, mainDeployTxn); err != nil {
-			return err
+		if contract.ExtSeqno != 0 {
+			if err := es.SetExtSeqno(addr, contract.ExtSeqno); err != nil {
+				return err
+			}
 		}
 
 		logger.Debug().Str("name", contract.Name).Stringer("address", addr).Msg("Created zero state contract")
 	}
 	return nil
 }
+
+// address returns the address of the account. Contracts without explicit address are placed at the address
+// of their deployment without salt in the given shard.
+func (c *ContractDescr) address() (types.Address, error) {
+	if c.Contract != "" && len(c.Code) != 0 {
+		return types.EmptyAddress, fmt.Errorf("zero state contract %s has both contract and code", c.Name)
+	}
+	if c.Address != types.EmptyAddress {
+		return c.Address, nil
+	}
+
+	code := []byte(c.Code)
+	switch {
+	case c.Contract != "":
+		var err error
+		if code, err = contracts.GetCode(c.Contract); err != nil {
+			return types.EmptyAddress, err
+		}
+	case len(code) == 0:
+		return types.EmptyAddress, fmt.Errorf("zero state account %s has neither contract, code nor address", c.Name)
+	}
+	return types.CreateAddress(c.Shard, types.BuildDeployPayload(code, common.EmptyHash)), nil
+}
+
+func (es *ExecutionState) deployZeroStateContract(addr types.Address, contract *ContractDescr) error {
+	code, err := contracts.GetCode(contract.Contract)
+	if err != nil {
+		return err
+	}
+
+	abi, err := contracts.GetAbi(contract.Contract)
+	if err != nil {
+		return err
+	}
+
+	args := make([]any, 0)
+	for _, arg := range contract.CtorArgs {
+		switch arg := arg.(type) {
+		case string:
+			switch {
+			case arg[:2] == "0x":
+				args = append(args, hexutil.FromHex(arg))
+			default:
+				return fmt.Errorf("unknown constructor argument string pattern: %s", arg)
+			}
+		default:
+			args = append(args, arg)
+		}
+	}
+	argsPacked, err := abi.Pack("", args...)
+	if err != nil {
+		return fmt.Errorf("[ZeroState] ctorArgs pack failed: %w", err)
+	}
+	code = append(code, argsPacked...)
+
+	mainDeployTxn := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags:        types.NewTransactionFlags(types.TransactionFlagInternal),
+			Seqno:        0,
+			Data:         code,
+			MaxFeePerGas: types.MaxFeePerGasDefault,
+		},
+	}
+
+	if err := es.CreateContract(addr); err != nil {
+		return err
+	}
+	return es.SetInitState(addr, mainDeployTxn)
+}
@@ -3,10 +3,13 @@ package execution
 import (
 	"context"
 	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
@@ -234,6 +237,128 @@ func TestZerostateFromConfig(t *testing.T) {
 	require.Nil(t, smartAccount)
 }
 
+func TestZerostateAccounts(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	shardId := types.BaseShardId
+	code := hexutil.FromHex("0x600160005260206000f3")
+	codeAddr := types.CreateAddress(shardId, types.BuildDeployPayload(code, common.EmptyHash))
+	accountAddr := types.GenerateRandomAddress(shardId)
+	token := types.TokenId(types.GenerateRandomAddress(shardId))
+	slot := common.HexToHash("0x01")
+	value := common.HexToHash("0x1234")
+
+	zeroState := &ZeroStateConfig{
+		Contracts: []*ContractDescr{
+			{
+				Name: "Raw", Code: code, Shard: shardId, Value: types.NewValueFromUint64(100),
+				Storage: map[common.Hash]common.Hash{slot: value},
+				Tokens:  map[types.TokenId]types.Value{token: types.NewValueFromUint64(5)},
+			},
+			{
+				Name: "Account", Address: accountAddr, Value: types.NewValueFromUint64(200), ExtSeqno: 3,
+				Tokens: map[types.TokenId]types.Value{token: types.NewValueFromUint64(7)},
+			},
+			{Name: "OtherShard", Address: types.GenerateRandomAddress(types.MainShardId), Value: types.NewValueFromUint64(1)},
+		},
+	}
+
+	t.Run("YamlSerialization", func(t *testing.T) {
+		data, err := yaml.Marshal(zeroState)
+		require.NoError(t, err)
+		deserialized := &ZeroStateConfig{}
+		require.NoError(t, yaml.Unmarshal(data, deserialized))
+		require.Equal(t, zeroState, deserialized)
+	})
+
+	state, err := NewExecutionState(tx, shardId, StateParams{ConfigAccessor: config.GetStubAccessor()})
+	require.NoError(t, err)
+	require.NoError(t, state.GenerateZeroState(zeroState))
+
+	actualCode, _, err := state.GetCode(codeAddr)
+	require.NoError(t, err)
+	require.Equal(t, code, []byte(actualCode))
+	balance, err := state.GetBalance(codeAddr)
+	require.NoError(t, err)
+	require.Equal(t, types.NewValueFromUint64(100), balance)
+	actualValue, err := state.GetState(codeAddr, slot)
+	require.NoError(t, err)
+	require.Equal(t, value, actualValue)
+	require.Equal(t, map[types.TokenId]types.Value{token: types.NewValueFromUint64(5)}, state.GetTokens(codeAddr))
+
+	actualCode, _, err = state.GetCode(accountAddr)
+	require.NoError(t, err)
+	require.Empty(t, actualCode)
+	balance, err = state.GetBalance(accountAddr)
+	require.NoError(t, err)
+	require.Equal(t, types.NewValueFromUint64(200), balance)
+	extSeqno, err := state.GetExtSeqno(accountAddr)
+	require.NoError(t, err)
+	require.Equal(t, types.Seqno(3), extSeqno)
+	require.Equal(t, map[types.TokenId]types.Value{token: types.NewValueFromUint64(7)}, state.GetTokens(accountAddr))
+
+	for _, contract := range []*ContractDescr{
+		{Name: "NoAddress"},
+		{Name: "ContractAndCode", Contract: "Faucet", Code: code},
+	} {
+		require.Error(t, state.GenerateZeroState(&ZeroStateConfig{Contracts: []*ContractDescr{contract}}))
+	}
+}
+
+func TestLoadGenesisAlloc(t *testing.T) {
+	t.Parallel()
+
+	addr1 := types.ShardAndHexToAddress(types.BaseShardId, "0x2222222222222222222222222222222222")
+	addr2 := types.ShardAndHexToAddress(types.BaseShardId, "0x1111111111111111111111111111111111")
+	token := types.TokenId(addr2)
+	fname := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(fname, []byte(`{
+		"config": {"chainId": 1},
+		"alloc": {
+			"`+addr1.Hex()+`": {
+				"balance": "0x64",
+				"code": "0x600160005260206000f3",
+				"storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000001234"},
+				"nonce": "0x2"
+			},
+			"`+addr2.Hex()+`": {
+				"balance": "1000",
+				"tokens": {"`+token.String()+`": "0x10"}
+			}
+		}
+	}`), 0o600))
+
+	alloc, err := LoadGenesisAlloc(fname)
+	require.NoError(t, err)
+
+	zeroState := &ZeroStateConfig{}
+	zeroState.AddGenesisAlloc(alloc)
+	require.Equal(t, []*ContractDescr{
+		{
+			Name: addr2.Hex(), Address: addr2, Value: types.NewValueFromUint64(1000),
+			Tokens: map[types.TokenId]types.Value{token: types.NewValueFromUint64(16)},
+		},
+		{
+			Name: addr1.Hex(), Address: addr1, Value: types.NewValueFromUint64(100),
+			Code: hexutil.FromHex("0x600160005260206000f3"),
+			Storage: map[common.Hash]common.Hash{
+				common.HexToHash("0x01"): common.HexToHash("0x1234"),
+			},
+			ExtSeqno: 2,
+		},
+	}, zeroState.Contracts)
+
+	_, err = LoadGenesisAlloc(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
 func TestSuiteZeroState(t *testing.T) {
 	t.Parallel()
 
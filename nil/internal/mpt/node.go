@@ -1,6 +1,8 @@
 package mpt
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 
 	ssz "github.com/NilFoundation/fastssz"
@@ -40,6 +42,11 @@ func calcNodeKey(data []byte) []byte {
 	return key
 }
 
+// IsNodeKey reports whether the key is the key of the stored node with the given encoding.
+func IsNodeKey(key, data []byte) bool {
+	return len(data) >= 32 && bytes.Equal(calcNodeKey(data), key)
+}
+
 type NodeBase struct {
 	NodePath Path
 }
@@ -131,6 +138,9 @@ func (n *BranchNode) Encode() ([]byte, error) {
 }
 
 func DecodeNode(data []byte) (Node, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty node")
+	}
 	nodeKind := ssz.UnmarshallUint8(data)
 	data = data[1:]
 
@@ -13,6 +13,8 @@ import (
 
 type MPTOperation uint32
 
+var errTruncatedProof = errors.New("truncated proof")
+
 const (
 	ReadMPTOperation MPTOperation = iota
 	SetMPTOperation
@@ -220,12 +222,19 @@ func (p *Proof) Encode() ([]byte, error) {
 func DecodeProof(data []byte) (Proof, error) {
 	// here we deserialize proof from the data piece by piece
 	// and each time advance the offset on correct amount of bytes
+	// the data may come from an untrusted source, so every length is checked
 
 	p := Proof{}
+	if len(data) < 5 {
+		return p, errTruncatedProof
+	}
 	p.operation = MPTOperation(ssz.UnmarshallUint32(data))
 	data = data[4:]
 
-	keyLen := ssz.UnmarshallUint8(data)
+	keyLen := int(ssz.UnmarshallUint8(data))
+	if len(data) < 2+keyLen {
+		return p, errTruncatedProof
+	}
 	p.key = data[1 : 1+keyLen]
 	data = data[1+keyLen:]
 
@@ -233,7 +242,13 @@ func DecodeProof(data []byte) (Proof, error) {
 	data = data[1:]
 
 	for range pathLen {
-		nodeLen := ssz.UnmarshallUint32(data)
+		if len(data) < 4 {
+			return p, errTruncatedProof
+		}
+		nodeLen := uint64(ssz.UnmarshallUint32(data))
+		if uint64(len(data)) < 4+nodeLen {
+			return p, errTruncatedProof
+		}
 
 		node, err := DecodeNode(data[4 : 4+nodeLen])
 		if err != nil {
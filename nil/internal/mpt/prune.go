@@ -0,0 +1,160 @@
+package mpt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// sweepBatchSize limits the number of nodes deleted in a single DB transaction.
+const sweepBatchSize = 10_000
+
+// NodeSet is a set of the keys of the trie nodes stored in the DB.
+type NodeSet map[string]struct{}
+
+// Mark adds the keys of the stored nodes reachable from the root to the set and calls onValue (if any)
+// for the values of the newly visited nodes. Subtrees whose roots are already in the set are skipped,
+// so the same set can be passed for all the roots of a table to visit every node only once.
+func (m *Reader) Mark(set NodeSet, onValue func(value []byte) error) error {
+	if !m.root.IsValid() || m.RootHash() == common.EmptyHash {
+		return nil
+	}
+	return m.mark(m.root, set, onValue)
+}
+
+func (m *Reader) mark(ref Reference, set NodeSet, onValue func(value []byte) error) error {
+	// Short nodes are embedded into their parents and are not stored separately.
+	if len(ref) >= 32 {
+		if _, ok := set[string(ref)]; ok {
+			return nil
+		}
+		set[string(ref)] = struct{}{}
+	}
+
+	node, err := m.getNode(ref)
+	if err != nil {
+		return fmt.Errorf("failed to read node %x: %w", []byte(ref), err)
+	}
+
+	if data := node.Data(); len(data) > 0 && onValue != nil {
+		if err := onValue(data); err != nil {
+			return err
+		}
+	}
+
+	switch node := node.(type) {
+	case *BranchNode:
+		for _, br := range node.Branches {
+			if br.IsValid() {
+				if err := m.mark(br, set, onValue); err != nil {
+					return err
+				}
+			}
+		}
+	case *ExtensionNode:
+		return m.mark(node.NextRef, set, onValue)
+	}
+	return nil
+}
+
+// Sweep deletes the nodes of the table that are not in the set and returns the number of deleted nodes.
+// The table may be written concurrently. To keep a node that became reachable again after marking,
+// remark (if any) is called in every deleting transaction before the deletion to add such nodes to the set.
+// If the deleting transaction conflicts with a concurrent write, the batch is retried,
+// so remark sees everything committed before the nodes are deleted.
+func Sweep(
+	ctx context.Context,
+	txFabric db.DB,
+	shardId types.ShardId,
+	tableName db.ShardedTableName,
+	set NodeSet,
+	remark func(tx db.RoTx) error,
+) (int, error) {
+	garbage, err := collectGarbage(ctx, txFabric, shardId, tableName, set)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for start := 0; start < len(garbage); start += sweepBatchSize {
+		end := min(start+sweepBatchSize, len(garbage))
+		for {
+			deleted, err := deleteNodes(ctx, txFabric, shardId, tableName, garbage[start:end], set, remark)
+			if errors.Is(err, db.ErrConflict) && ctx.Err() == nil {
+				continue
+			}
+			if err != nil {
+				return total, err
+			}
+			total += deleted
+			break
+		}
+	}
+	return total, nil
+}
+
+func collectGarbage(
+	ctx context.Context, txFabric db.DB, shardId types.ShardId, tableName db.ShardedTableName, set NodeSet,
+) ([][]byte, error) {
+	tx, err := txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	iter, err := tx.RangeByShard(shardId, tableName, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var garbage [][]byte
+	for iter.HasNext() {
+		key, _, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := set[string(key)]; !ok {
+			garbage = append(garbage, key)
+		}
+	}
+	return garbage, nil
+}
+
+func deleteNodes(
+	ctx context.Context,
+	txFabric db.DB,
+	shardId types.ShardId,
+	tableName db.ShardedTableName,
+	keys [][]byte,
+	set NodeSet,
+	remark func(tx db.RoTx) error,
+) (int, error) {
+	tx, err := txFabric.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if remark != nil {
+		if err := remark(tx); err != nil {
+			return 0, err
+		}
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if _, ok := set[string(key)]; ok {
+			continue
+		}
+		if err := tx.DeleteFromShard(shardId, tableName, key); err != nil {
+			return 0, err
+		}
+		deleted++
+	}
+	return deleted, tx.Commit()
+}
@@ -18,6 +18,14 @@ type Config struct {
 	DHTEnabled        bool          `yaml:"dhtEnabled,omitempty"`
 	DHTBootstrapPeers AddrInfoSlice `yaml:"dhtBootstrapPeers,omitempty"`
 	DHTMode           dht.ModeOpt   `yaml:"-,omitempty"`
+
+	// MaxInboundPeers and MaxOutboundPeers limit the numbers of the peers connected by them and by the node.
+	// Zero means no limit.
+	MaxInboundPeers  int `yaml:"maxInboundPeers,omitempty"`
+	MaxOutboundPeers int `yaml:"maxOutboundPeers,omitempty"`
+
+	// Reputation configures the scoring and the banning of the misbehaving peers (the defaults are used if nil).
+	Reputation *ReputationConfig `yaml:"reputation,omitempty"`
 }
 
 func NewDefaultConfig() *Config {
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// connectionGater refuses the connections to and from the banned peers
+// and the connections to new peers above the limits of the config.
+type connectionGater struct {
+	reputation  *reputationTracker
+	maxInbound  int
+	maxOutbound int
+
+	// network is set once the host is created, the limits are not checked before that.
+	network atomic.Pointer[network.Network]
+}
+
+var _ connmgr.ConnectionGater = (*connectionGater)(nil)
+
+func newConnectionGater(conf *Config, reputation *reputationTracker) *connectionGater {
+	return &connectionGater{
+		reputation:  reputation,
+		maxInbound:  conf.MaxInboundPeers,
+		maxOutbound: conf.MaxOutboundPeers,
+	}
+}
+
+func (g *connectionGater) setNetwork(n network.Network) {
+	g.network.Store(&n)
+}
+
+func (g *connectionGater) InterceptPeerDial(p PeerID) bool {
+	return !g.reputation.isBanned(p)
+}
+
+func (g *connectionGater) InterceptAddrDial(PeerID, ma.Multiaddr) bool {
+	return true
+}
+
+func (g *connectionGater) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *connectionGater) InterceptSecured(dir network.Direction, p PeerID, _ network.ConnMultiaddrs) bool {
+	if g.reputation.isBanned(p) {
+		return false
+	}
+
+	limit := g.maxOutbound
+	if dir == network.DirInbound {
+		limit = g.maxInbound
+	}
+	if limit <= 0 {
+		return true
+	}
+
+	n := g.network.Load()
+	if n == nil {
+		return true
+	}
+	// Additional connections to the connected peers don't count.
+	if (*n).Connectedness(p) == network.Connected {
+		return true
+	}
+	return countPeers(*n, dir) < limit
+}
+
+func (g *connectionGater) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// countPeers returns the number of the connected peers whose first connection has the direction.
+func countPeers(n network.Network, dir network.Direction) int {
+	count := 0
+	for _, p := range n.Peers() {
+		if conns := n.ConnsToPeer(p); len(conns) > 0 && conns[0].Stat().Direction == dir {
+			count++
+		}
+	}
+	return count
+}
@@ -20,7 +20,9 @@ type Host = host.Host
 
 var defaultGracePeriod = connmgr.WithGracePeriod(time.Minute)
 
-func getCommonOptions(ctx context.Context, privateKey libp2pcrypto.PrivKey) ([]libp2p.Option, error) {
+func getCommonOptions(
+	ctx context.Context, privateKey libp2pcrypto.PrivKey, gater *connectionGater,
+) ([]libp2p.Option, error) {
 	cm, err := connmgr.NewConnManager(100, 400, defaultGracePeriod)
 	if err != nil {
 		return nil, err
@@ -41,17 +43,27 @@ func getCommonOptions(ctx context.Context, privateKey libp2pcrypto.PrivKey) ([]l
 		libp2p.ConnectionManager(cm),
 		libp2p.Identity(privateKey),
 		libp2p.BandwidthReporter(metrics),
+		libp2p.ConnectionGater(gater),
 	}, nil
 }
 
+func newHostWithGater(gater *connectionGater, options ...libp2p.Option) (Host, error) {
+	h, err := libp2p.New(options...)
+	if err != nil {
+		return nil, err
+	}
+	gater.setNetwork(h.Network())
+	return h, nil
+}
+
 // newHost creates a new libp2p host. It must be closed after use.
-func newHost(ctx context.Context, conf *Config) (Host, error) {
+func newHost(ctx context.Context, conf *Config, gater *connectionGater) (Host, error) {
 	addr := conf.IPV4Address
 	if addr == "" {
 		addr = "0.0.0.0"
 	}
 
-	options, err := getCommonOptions(ctx, conf.PrivateKey)
+	options, err := getCommonOptions(ctx, conf.PrivateKey, gater)
 	if err != nil {
 		return nil, err
 	}
@@ -70,11 +82,11 @@ func newHost(ctx context.Context, conf *Config) (Host, error) {
 		)
 	}
 
-	return libp2p.New(options...)
+	return newHostWithGater(gater, options...)
 }
 
 // newClient creates a new libp2p host that doesn't listen to any port. It must be closed after use.
-func newClient(ctx context.Context, conf *Config) (Host, error) {
+func newClient(ctx context.Context, conf *Config, gater *connectionGater) (Host, error) {
 	var privateKey libp2pcrypto.PrivKey
 	if conf != nil && conf.PrivateKey != nil {
 		privateKey = conf.PrivateKey
@@ -87,10 +99,10 @@ func newClient(ctx context.Context, conf *Config) (Host, error) {
 		}
 	}
 
-	options, err := getCommonOptions(ctx, privateKey)
+	options, err := getCommonOptions(ctx, privateKey, gater)
 	if err != nil {
 		return nil, err
 	}
 	options = append(options, libp2p.NoListenAddrs)
-	return libp2p.New(options...)
+	return newHostWithGater(gater, options...)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -20,9 +21,10 @@ type Manager struct {
 	ctx    context.Context
 	prefix string
 
-	host   Host
-	pubSub *PubSub
-	dht    *DHT
+	host       Host
+	pubSub     *PubSub
+	dht        *DHT
+	reputation *reputationTracker
 
 	meter telemetry.Meter
 
@@ -52,7 +54,9 @@ func connectToDhtBootstrapPeers(ctx context.Context, conf *Config, h Host, logge
 	connectToPeers(ctx, conf.DHTBootstrapPeers, h, logger)
 }
 
-func newManagerFromHost(ctx context.Context, conf *Config, h host.Host) (*Manager, error) {
+func newManagerFromHost(
+	ctx context.Context, conf *Config, h host.Host, reputation *reputationTracker,
+) (*Manager, error) {
 	logger := internal.Logger.With().
 		Stringer(logging.FieldP2PIdentity, h.ID()).
 		Logger()
@@ -66,19 +70,20 @@ func newManagerFromHost(ctx context.Context, conf *Config, h host.Host) (*Manage
 		return nil, err
 	}
 
-	ps, err := newPubSub(ctx, h, conf, logger)
+	ps, err := newPubSub(ctx, h, conf, reputation, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Manager{
-		ctx:    ctx,
-		prefix: conf.Prefix,
-		host:   h,
-		pubSub: ps,
-		dht:    dht,
-		meter:  telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/network"),
-		logger: logger,
+		ctx:        ctx,
+		prefix:     conf.Prefix,
+		host:       h,
+		pubSub:     ps,
+		dht:        dht,
+		reputation: reputation,
+		meter:      telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/network"),
+		logger:     logger,
 	}, nil
 }
 
@@ -95,19 +100,21 @@ func NewManager(ctx context.Context, conf *Config) (*Manager, error) {
 		return nil, ErrPrivateKeyMissing
 	}
 
-	h, err := newHost(ctx, conf)
+	reputation := newReputationTracker(conf.Reputation)
+	h, err := newHost(ctx, conf, newConnectionGater(conf, reputation))
 	if err != nil {
 		return nil, err
 	}
-	return newManagerFromHost(ctx, conf, h)
+	return newManagerFromHost(ctx, conf, h, reputation)
 }
 
 func NewClientManager(ctx context.Context, conf *Config) (*Manager, error) {
-	h, err := newClient(ctx, conf)
+	reputation := newReputationTracker(conf.Reputation)
+	h, err := newClient(ctx, conf, newConnectionGater(conf, reputation))
 	if err != nil {
 		return nil, err
 	}
-	return newManagerFromHost(ctx, conf, h)
+	return newManagerFromHost(ctx, conf, h, reputation)
 }
 
 func (m *Manager) PubSub() *PubSub {
@@ -168,6 +175,83 @@ func (m *Manager) Connect(ctx context.Context, addr AddrInfo) (PeerID, error) {
 	return addr.ID, nil
 }
 
+// ReportPeer decreases the reputation of the peer for the misbehavior.
+// The peer is disconnected and banned once its reputation falls to the ban threshold.
+func (m *Manager) ReportPeer(id PeerID, misbehavior Misbehavior) {
+	if id == m.host.ID() {
+		return
+	}
+
+	logger := m.logger.With().
+		Stringer(logging.FieldPeerId, id).
+		Stringer("misbehavior", misbehavior).
+		Logger()
+	if !m.reputation.report(id, misbehavior) {
+		logger.Debug().Msg("Peer misbehaved")
+		return
+	}
+
+	logger.Warn().
+		Time("bannedUntil", m.reputation.bannedUntil(id)).
+		Msg("Peer is banned")
+	if err := m.host.Network().ClosePeer(id); err != nil {
+		m.logErrorWithLogger(logger, err, "Failed to disconnect banned peer")
+	}
+}
+
+// PeerInfo describes a connected or a banned peer.
+type PeerInfo struct {
+	Id PeerID `json:"id"`
+	// Direction is "Inbound" or "Outbound" for the connected peers.
+	Direction string   `json:"direction,omitempty"`
+	Addrs     []string `json:"addrs,omitempty"`
+	// Reputation is the score of the peer based on the reported misbehavior.
+	Reputation float64 `json:"reputation"`
+	// GossipScore is the score of the peer in the pub-sub router, which includes the reputation.
+	GossipScore float64    `json:"gossipScore"`
+	BannedUntil *time.Time `json:"bannedUntil,omitempty"`
+}
+
+// Peers returns the connected peers and the banned ones.
+func (m *Manager) Peers() []PeerInfo {
+	net := m.host.Network()
+	scores := m.pubSub.Scores()
+	tracked := m.reputation.snapshot()
+
+	newInfo := func(id PeerID) PeerInfo {
+		info := PeerInfo{
+			Id:          id,
+			Reputation:  m.reputation.score(id),
+			GossipScore: scores[id],
+		}
+		if until := m.reputation.bannedUntil(id); !until.IsZero() {
+			info.BannedUntil = &until
+		}
+		return info
+	}
+
+	res := make([]PeerInfo, 0, len(net.Peers()))
+	for _, id := range net.Peers() {
+		info := newInfo(id)
+		if conns := net.ConnsToPeer(id); len(conns) > 0 {
+			info.Direction = conns[0].Stat().Direction.String()
+			info.Addrs = []string{conns[0].RemoteMultiaddr().String()}
+		}
+		res = append(res, info)
+		delete(tracked, id)
+	}
+	for id := range tracked {
+		if info := newInfo(id); info.BannedUntil != nil {
+			res = append(res, info)
+		}
+	}
+
+	slices.SortFunc(res, func(a, b PeerInfo) int {
+		return strings.Compare(string(a.Id), string(b.Id))
+	})
+	return res
+}
+
 func (m *Manager) Close() {
 	if m.dht != nil {
 		if err := m.dht.Close(); err != nil {
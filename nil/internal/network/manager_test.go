@@ -3,6 +3,7 @@ package network
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/stretchr/testify/suite"
@@ -108,6 +109,63 @@ func (s *ManagerSuite) TestReqResp() {
 	})
 }
 
+func (s *ManagerSuite) TestBanPeer() {
+	m1 := s.newManager()
+	defer m1.Close()
+	m2 := s.newManager()
+	defer m2.Close()
+
+	_, id2 := ConnectManagers(s.T(), m1, m2)
+
+	m1.ReportPeer(id2, MisbehaviorInvalidTransaction)
+	peers := m1.Peers()
+	s.Require().Len(peers, 1)
+	s.Equal(id2, peers[0].Id)
+	s.Equal("Outbound", peers[0].Direction)
+	s.Negative(peers[0].Reputation)
+	s.Nil(peers[0].BannedUntil)
+
+	m1.ReportPeer(id2, MisbehaviorInvalidBlock)
+	m1.ReportPeer(id2, MisbehaviorInvalidBlock)
+	s.Require().Eventually(func() bool {
+		return len(m1.host.Network().Peers()) == 0
+	}, 5*time.Second, 100*time.Millisecond)
+
+	peers = m1.Peers()
+	s.Require().Len(peers, 1)
+	s.NotNil(peers[0].BannedUntil)
+	s.Empty(peers[0].Direction)
+
+	_, err := m1.Connect(s.context, CalcAddress(m2))
+	s.Require().Error(err)
+
+	// The dialer may consider the connection established before the banning side closes it.
+	_, _ = m2.Connect(s.context, CalcAddress(m1))
+	s.Never(func() bool {
+		return len(m1.host.Network().Peers()) > 0
+	}, time.Second, 100*time.Millisecond)
+}
+
+func (s *ManagerSuite) TestConnectionLimits() {
+	m1 := s.newManagerWithBaseConfig(&Config{MaxInboundPeers: 1})
+	defer m1.Close()
+	m2 := s.newManager()
+	defer m2.Close()
+	m3 := s.newManager()
+	defer m3.Close()
+
+	ConnectManagers(s.T(), m2, m1)
+
+	// The dialer may consider the connection established before the limiting side closes it.
+	_, _ = m3.Connect(s.context, CalcAddress(m1))
+	s.Never(func() bool {
+		return len(m1.host.Network().Peers()) > 1
+	}, time.Second, 100*time.Millisecond)
+
+	// The outbound connections are not limited.
+	ConnectManagers(s.T(), m1, m3)
+}
+
 func TestManager(t *testing.T) {
 	t.Parallel()
 
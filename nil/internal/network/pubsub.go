@@ -3,8 +3,10 @@ package network
 import (
 	"context"
 	"errors"
+	"maps"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
@@ -13,7 +15,39 @@ import (
 	"github.com/rs/zerolog"
 )
 
-const subscriptionChannelSize = 100
+const (
+	subscriptionChannelSize = 100
+
+	// scoreInspectInterval is the period of the update of the gossip scores reported by PubSub.Scores.
+	scoreInspectInterval = 10 * time.Second
+)
+
+// The gossip score of a peer is the sum of its reputation and the penalties for the gossip protocol violations.
+// The thresholds are fractions of the default ban threshold, so that the gossip with a misbehaving peer
+// is reduced step by step before it gets banned.
+var peerScoreThresholds = &pubsub.PeerScoreThresholds{
+	GossipThreshold:             defaultBanThreshold / 4,
+	PublishThreshold:            defaultBanThreshold / 2,
+	GraylistThreshold:           defaultBanThreshold * 3 / 4,
+	AcceptPXThreshold:           10,
+	OpportunisticGraftThreshold: 5,
+}
+
+func newPeerScoreParams(reputation *reputationTracker) *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		Topics:            make(map[string]*pubsub.TopicScoreParams),
+		AppSpecificScore:  reputation.score,
+		AppSpecificWeight: 1,
+		// All the nodes of a local network share an address, so the colocation is not penalized.
+		IPColocationFactorWeight:  0,
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(10 * time.Minute),
+		DecayInterval:             pubsub.DefaultDecayInterval,
+		DecayToZero:               pubsub.DefaultDecayToZero,
+		RetainScore:               time.Hour,
+	}
+}
 
 type PubSub struct {
 	impl   *pubsub.PubSub // +checklocksignore: mu is not required, it just happens to be held always.
@@ -21,6 +55,7 @@ type PubSub struct {
 
 	mu     sync.Mutex
 	topics map[string]*pubsub.Topic // +checklocks:mu
+	scores map[PeerID]float64       // +checklocks:mu
 	self   PeerID
 
 	meter         telemetry.Meter
@@ -34,6 +69,13 @@ type SubscriptionCounters struct {
 	SkippedMessages atomic.Uint32
 }
 
+// Message is a message received from a topic.
+type Message struct {
+	Data []byte
+	// From is the author of the message (rather than the peer that forwarded it).
+	From PeerID
+}
+
 type Subscription struct {
 	impl *pubsub.Subscription
 	self PeerID
@@ -45,8 +87,22 @@ type Subscription struct {
 }
 
 // newPubSub creates a new PubSub instance. It must be closed after use.
-func newPubSub(ctx context.Context, h Host, conf *Config, logger zerolog.Logger) (*PubSub, error) {
-	impl, err := pubsub.NewGossipSub(ctx, h)
+func newPubSub(
+	ctx context.Context, h Host, conf *Config, reputation *reputationTracker, logger zerolog.Logger,
+) (*PubSub, error) {
+	ps := &PubSub{
+		prefix: conf.Prefix,
+		topics: make(map[string]*pubsub.Topic),
+		self:   h.ID(),
+		logger: logger.With().
+			Str(logging.FieldComponent, "pub-sub").
+			Logger(),
+	}
+
+	impl, err := pubsub.NewGossipSub(ctx, h,
+		pubsub.WithPeerScore(newPeerScoreParams(reputation), peerScoreThresholds),
+		pubsub.WithPeerScoreInspect(pubsub.PeerScoreInspectFn(ps.setScores), scoreInspectInterval),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -61,18 +117,26 @@ func newPubSub(ctx context.Context, h Host, conf *Config, logger zerolog.Logger)
 		return nil, err
 	}
 
-	return &PubSub{
-		prefix:        conf.Prefix,
-		impl:          impl,
-		topics:        make(map[string]*pubsub.Topic),
-		self:          h.ID(),
-		meter:         meter,
-		published:     published,
-		publishedSize: publishedSize,
-		logger: logger.With().
-			Str(logging.FieldComponent, "pub-sub").
-			Logger(),
-	}, nil
+	ps.impl = impl
+	ps.meter = meter
+	ps.published = published
+	ps.publishedSize = publishedSize
+	return ps, nil
+}
+
+func (ps *PubSub) setScores(scores map[PeerID]float64) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.scores = scores
+}
+
+// Scores returns the gossip scores of the peers as of the last update.
+func (ps *PubSub) Scores() map[PeerID]float64 {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return maps.Clone(ps.scores)
 }
 
 func (ps *PubSub) Close() error {
@@ -192,7 +256,22 @@ func (ps *PubSub) getTopic(topic string) (*pubsub.Topic, error) {
 }
 
 func (s *Subscription) Start(ctx context.Context, skipSelfMessages bool) <-chan []byte {
-	msgCh := make(chan []byte, subscriptionChannelSize)
+	return start(s, ctx, skipSelfMessages, func(msg *pubsub.Message) []byte {
+		return msg.Data
+	})
+}
+
+// StartMessages is Start that reports the authors of the messages along with the data.
+func (s *Subscription) StartMessages(ctx context.Context, skipSelfMessages bool) <-chan *Message {
+	return start(s, ctx, skipSelfMessages, func(msg *pubsub.Message) *Message {
+		return &Message{Data: msg.Data, From: msg.GetFrom()}
+	})
+}
+
+func start[T any](
+	s *Subscription, ctx context.Context, skipSelfMessages bool, convert func(*pubsub.Message) T,
+) <-chan T {
+	msgCh := make(chan T, subscriptionChannelSize)
 
 	go func() {
 		s.logger.Debug().Msg("Starting subscription loop...")
@@ -223,7 +302,7 @@ func (s *Subscription) Start(ctx context.Context, skipSelfMessages bool) <-chan
 			s.receivedSize.Add(ctx, int64(len(msg.Data)), attrs)
 			s.logger.Trace().Msg("Received message")
 
-			msgCh <- msg.Data
+			msgCh <- convert(msg)
 		}
 
 		close(msgCh)
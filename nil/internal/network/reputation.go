@@ -0,0 +1,192 @@
+package network
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBanThreshold   = -100
+	defaultBanDuration    = time.Hour
+	defaultScoreHalfLife  = 10 * time.Minute
+	forgottenScoreEpsilon = 0.1
+)
+
+// Misbehavior is a kind of the invalid data received from a peer.
+type Misbehavior int
+
+const (
+	// MisbehaviorMalformedMessage is a message or a response that can't be decoded.
+	MisbehaviorMalformedMessage Misbehavior = iota
+	// MisbehaviorInvalidTransaction is a transaction that can never be accepted, e.g., with a wrong chain id.
+	MisbehaviorInvalidTransaction
+	// MisbehaviorInvalidBlock is a block that fails the verification.
+	MisbehaviorInvalidBlock
+)
+
+func (m Misbehavior) String() string {
+	switch m {
+	case MisbehaviorMalformedMessage:
+		return "malformed message"
+	case MisbehaviorInvalidTransaction:
+		return "invalid transaction"
+	case MisbehaviorInvalidBlock:
+		return "invalid block"
+	}
+	return "unknown"
+}
+
+// penalty is the decrease of the reputation of the peer for the misbehavior.
+func (m Misbehavior) penalty() float64 {
+	switch m {
+	case MisbehaviorMalformedMessage:
+		return 20
+	case MisbehaviorInvalidTransaction:
+		return 10
+	case MisbehaviorInvalidBlock:
+		return 50
+	}
+	return 0
+}
+
+// ReputationConfig configures the reputation of the peers.
+// The reputation of a peer starts at zero, goes down for every reported misbehavior and returns to zero over time.
+// The peers whose reputation falls to the threshold are disconnected and banned.
+type ReputationConfig struct {
+	// BanThreshold is the (negative) reputation that gets the peer banned.
+	BanThreshold float64 `yaml:"banThreshold,omitempty"`
+	// BanDuration is the time during which the connections to and from the banned peer are refused.
+	BanDuration time.Duration `yaml:"banDuration,omitempty"`
+	// ScoreHalfLife is the time in which a penalty is halved.
+	ScoreHalfLife time.Duration `yaml:"scoreHalfLife,omitempty"`
+}
+
+func NewDefaultReputationConfig() *ReputationConfig {
+	return &ReputationConfig{
+		BanThreshold:  defaultBanThreshold,
+		BanDuration:   defaultBanDuration,
+		ScoreHalfLife: defaultScoreHalfLife,
+	}
+}
+
+type peerReputation struct {
+	score       float64
+	updatedAt   time.Time
+	bannedUntil time.Time
+}
+
+// reputationTracker keeps the reputation of the peers that misbehaved recently.
+type reputationTracker struct {
+	config ReputationConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	peers map[PeerID]*peerReputation // +checklocks:mu
+}
+
+func newReputationTracker(conf *ReputationConfig) *reputationTracker {
+	config := *NewDefaultReputationConfig()
+	if conf != nil {
+		if conf.BanThreshold != 0 {
+			config.BanThreshold = -math.Abs(conf.BanThreshold)
+		}
+		if conf.BanDuration != 0 {
+			config.BanDuration = conf.BanDuration
+		}
+		if conf.ScoreHalfLife != 0 {
+			config.ScoreHalfLife = conf.ScoreHalfLife
+		}
+	}
+	return &reputationTracker{
+		config: config,
+		now:    time.Now,
+		peers:  make(map[PeerID]*peerReputation),
+	}
+}
+
+// decay brings the score of the peer up to date.
+func (t *reputationTracker) decay(r *peerReputation, now time.Time) {
+	if elapsed := now.Sub(r.updatedAt); elapsed > 0 {
+		r.score *= math.Exp2(-float64(elapsed) / float64(t.config.ScoreHalfLife))
+		r.updatedAt = now
+	}
+}
+
+// report decreases the reputation of the peer and returns true if the peer got banned by this report.
+func (t *reputationTracker) report(id PeerID, m Misbehavior) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.forgetLocked(now)
+
+	r, ok := t.peers[id]
+	if !ok {
+		r = &peerReputation{updatedAt: now}
+		t.peers[id] = r
+	}
+	t.decay(r, now)
+	r.score -= m.penalty()
+
+	if r.score > t.config.BanThreshold || now.Before(r.bannedUntil) {
+		return false
+	}
+	r.bannedUntil = now.Add(t.config.BanDuration)
+	// The banned peer starts from scratch after the ban.
+	r.score = 0
+	return true
+}
+
+// forgetLocked removes the peers that are not banned and whose reputation is restored.
+// +checklocks:t.mu
+func (t *reputationTracker) forgetLocked(now time.Time) {
+	for id, r := range t.peers {
+		t.decay(r, now)
+		if !now.Before(r.bannedUntil) && r.score > -forgottenScoreEpsilon {
+			delete(t.peers, id)
+		}
+	}
+}
+
+// score returns the current reputation of the peer.
+func (t *reputationTracker) score(id PeerID) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.peers[id]
+	if !ok {
+		return 0
+	}
+	t.decay(r, t.now())
+	return r.score
+}
+
+// bannedUntil returns the end of the ban of the peer or the zero time if the peer is not banned.
+func (t *reputationTracker) bannedUntil(id PeerID) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.peers[id]
+	if !ok || !t.now().Before(r.bannedUntil) {
+		return time.Time{}
+	}
+	return r.bannedUntil
+}
+
+func (t *reputationTracker) isBanned(id PeerID) bool {
+	return !t.bannedUntil(id).IsZero()
+}
+
+// snapshot returns the peers with a non-zero reputation or an active ban.
+func (t *reputationTracker) snapshot() map[PeerID]peerReputation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.forgetLocked(t.now())
+	res := make(map[PeerID]peerReputation, len(t.peers))
+	for id, r := range t.peers {
+		res[id] = *r
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReputationTracker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	tracker := newReputationTracker(&ReputationConfig{
+		BanThreshold:  50,
+		BanDuration:   time.Hour,
+		ScoreHalfLife: time.Minute,
+	})
+	tracker.now = func() time.Time { return now }
+
+	const peer = PeerID("peer")
+
+	assert.Zero(t, tracker.score(peer))
+	assert.False(t, tracker.report(peer, MisbehaviorInvalidTransaction))
+	assert.InDelta(t, -10, tracker.score(peer), 1e-9)
+
+	// The penalties decay over time.
+	now = now.Add(time.Minute)
+	assert.InDelta(t, -5, tracker.score(peer), 1e-9)
+
+	// The threshold is negative regardless of the sign in the config.
+	assert.False(t, tracker.report(peer, MisbehaviorMalformedMessage))
+	assert.False(t, tracker.isBanned(peer))
+	require.True(t, tracker.report(peer, MisbehaviorInvalidBlock))
+	assert.True(t, tracker.isBanned(peer))
+	assert.Equal(t, now.Add(time.Hour), tracker.bannedUntil(peer))
+
+	// Further reports don't extend the ban.
+	assert.False(t, tracker.report(peer, MisbehaviorInvalidBlock))
+	assert.Contains(t, tracker.snapshot(), peer)
+
+	now = now.Add(time.Hour)
+	assert.False(t, tracker.isBanned(peer))
+	assert.Zero(t, tracker.bannedUntil(peer))
+
+	// The peers with the restored reputation are forgotten.
+	now = now.Add(time.Hour)
+	assert.Empty(t, tracker.snapshot())
+}
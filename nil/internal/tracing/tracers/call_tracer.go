@@ -0,0 +1,164 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+// CallTracerConfig contains options of the call tracer.
+type CallTracerConfig struct {
+	// OnlyTopCall disables tracing of nested calls.
+	OnlyTopCall bool `json:"onlyTopCall,omitempty"`
+	// WithLog enables collecting of logs emitted by the calls.
+	WithLog bool `json:"withLog,omitempty"`
+}
+
+// CallLog is a log emitted by a call.
+type CallLog struct {
+	Address types.Address `json:"address"`
+	Topics  []common.Hash `json:"topics"`
+	Data    hexutil.Bytes `json:"data"`
+}
+
+// CallFrame is a node of the call tree.
+type CallFrame struct {
+	Type         string         `json:"type"`
+	From         types.Address  `json:"from"`
+	To           *types.Address `json:"to,omitempty"`
+	Value        *hexutil.Big   `json:"value,omitempty"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Output       hexutil.Bytes  `json:"output,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Calls        []*CallFrame   `json:"calls,omitempty"`
+	Logs         []CallLog      `json:"logs,omitempty"`
+}
+
+// CallTracer builds a tree of calls performed during the execution.
+type CallTracer struct {
+	cfg CallTracerConfig
+
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+var _ Tracer = (*CallTracer)(nil)
+
+// NewCallTracer creates a call tracer with the given options.
+func NewCallTracer(cfg CallTracerConfig) *CallTracer {
+	return &CallTracer{cfg: cfg}
+}
+
+func (t *CallTracer) Hooks() *tracing.Hooks {
+	hooks := &tracing.Hooks{
+		OnEnter: t.onEnter,
+		OnExit:  t.onExit,
+	}
+	if t.cfg.WithLog {
+		hooks.OnOpcode = t.onOpcode
+	}
+	return hooks
+}
+
+func (t *CallTracer) onEnter(depth int, typ byte, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int) {
+	if t.cfg.OnlyTopCall && depth > 0 {
+		return
+	}
+	frame := &CallFrame{
+		Type:  vm.OpCode(typ).String(),
+		From:  from,
+		To:    &to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+
+	if t.root == nil {
+		t.root = frame
+	} else if len(t.stack) > 0 {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *CallTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.cfg.OnlyTopCall && depth > 0 {
+		return
+	}
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.Output = common.CopyBytes(output)
+	if err == nil {
+		return
+	}
+	frame.Error = err.Error()
+	// The output of a reverted frame may contain an ABI-encoded Error(string).
+	if reason, unpackErr := abi.UnpackRevert(output); unpackErr == nil {
+		frame.RevertReason = reason
+	}
+	// Logs of the failed frame and its subcalls are discarded along with their state changes.
+	clearLogs(frame)
+}
+
+func clearLogs(frame *CallFrame) {
+	frame.Logs = nil
+	for _, call := range frame.Calls {
+		clearLogs(call)
+	}
+}
+
+func (t *CallTracer) onOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	opcode := vm.OpCode(op)
+	if err != nil || opcode < vm.LOG0 || opcode > vm.LOG4 || len(t.stack) == 0 {
+		return
+	}
+	// Frames skipped by OnlyTopCall have no place to store the logs.
+	if t.cfg.OnlyTopCall && depth > 1 {
+		return
+	}
+
+	stack := scope.StackData()
+	size := int(opcode - vm.LOG0)
+	if len(stack) < size+2 {
+		return
+	}
+	offset := stack[len(stack)-1]
+	length := stack[len(stack)-2]
+	topics := make([]common.Hash, size)
+	for i := range size {
+		topics[i] = common.Hash(stack[len(stack)-3-i].Bytes32())
+	}
+
+	memory := scope.MemoryData()
+	var data []byte
+	if offset.IsUint64() && length.IsUint64() && offset.Uint64()+length.Uint64() <= uint64(len(memory)) {
+		data = common.CopyBytes(memory[offset.Uint64() : offset.Uint64()+length.Uint64()])
+	}
+
+	frame := t.stack[len(t.stack)-1]
+	frame.Logs = append(frame.Logs, CallLog{Address: scope.Address(), Topics: topics, Data: data})
+}
+
+func (t *CallTracer) GetResult() (json.RawMessage, error) {
+	if t.root == nil {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(t.root)
+}
@@ -0,0 +1,178 @@
+package tracers
+
+import (
+	"encoding/json"
+	"maps"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/holiman/uint256"
+)
+
+// StructLog is a single executed opcode.
+type StructLog struct {
+	Pc         uint64                      `json:"pc"`
+	Op         string                      `json:"op"`
+	Gas        uint64                      `json:"gas"`
+	GasCost    uint64                      `json:"gasCost"`
+	Depth      int                         `json:"depth"`
+	Error      string                      `json:"error,omitempty"`
+	Stack      []hexutil.Big               `json:"stack,omitempty"`
+	Memory     hexutil.Bytes               `json:"memory,omitempty"`
+	ReturnData hexutil.Bytes               `json:"returnData,omitempty"`
+	Storage    map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// StructLoggerResult is the output of the struct logger.
+type StructLoggerResult struct {
+	Gas         uint64        `json:"gas"`
+	Failed      bool          `json:"failed"`
+	ReturnValue hexutil.Bytes `json:"returnValue"`
+	StructLogs  []StructLog   `json:"structLogs"`
+}
+
+// pendingLoad is an SLOAD whose result is taken from the stack at the next opcode.
+type pendingLoad struct {
+	depth   int
+	address types.Address
+	key     common.Hash
+	log     int
+}
+
+// StructLogger records every executed opcode, optionally with the stack, memory and storage.
+type StructLogger struct {
+	cfg *Config
+
+	logs    []StructLog
+	storage map[types.Address]map[common.Hash]common.Hash
+	load    *pendingLoad
+
+	output  []byte
+	gasUsed uint64
+	failed  bool
+}
+
+var _ Tracer = (*StructLogger)(nil)
+
+// NewStructLogger creates a struct logger with options from cfg.
+func NewStructLogger(cfg *Config) *StructLogger {
+	return &StructLogger{
+		cfg:     cfg,
+		storage: make(map[types.Address]map[common.Hash]common.Hash),
+	}
+}
+
+func (l *StructLogger) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnOpcode: l.onOpcode,
+		OnExit:   l.onExit,
+	}
+}
+
+func (l *StructLogger) onOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	stack := scope.StackData()
+	l.resolvePendingLoad(depth, stack)
+
+	if l.cfg.Limit != 0 && len(l.logs) >= l.cfg.Limit {
+		return
+	}
+
+	log := StructLog{
+		Pc:      pc,
+		Op:      vm.OpCode(op).String(),
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+	if !l.cfg.DisableStack {
+		log.Stack = make([]hexutil.Big, len(stack))
+		for i := range stack {
+			log.Stack[i] = hexutil.Big(*stack[i].ToBig())
+		}
+	}
+	if l.cfg.EnableMemory {
+		log.Memory = common.CopyBytes(scope.MemoryData())
+	}
+	if l.cfg.EnableReturnData {
+		log.ReturnData = common.CopyBytes(rData)
+	}
+	if !l.cfg.DisableStorage {
+		l.captureStorage(&log, vm.OpCode(op), scope, stack, depth)
+	}
+	l.logs = append(l.logs, log)
+}
+
+// captureStorage attaches the storage slots of the contract touched so far to SLOAD and SSTORE logs.
+func (l *StructLogger) captureStorage(log *StructLog, op vm.OpCode, scope tracing.OpContext, stack []uint256.Int, depth int) {
+	if op != vm.SLOAD && op != vm.SSTORE {
+		return
+	}
+
+	address := scope.Address()
+	storage, ok := l.storage[address]
+	if !ok {
+		storage = make(map[common.Hash]common.Hash)
+		l.storage[address] = storage
+	}
+
+	switch {
+	case op == vm.SLOAD && len(stack) >= 1:
+		// The loaded value is known only after the opcode is executed.
+		l.load = &pendingLoad{
+			depth:   depth,
+			address: address,
+			key:     common.Hash(stack[len(stack)-1].Bytes32()),
+			log:     len(l.logs),
+		}
+	case op == vm.SSTORE && len(stack) >= 2:
+		key := common.Hash(stack[len(stack)-1].Bytes32())
+		storage[key] = common.Hash(stack[len(stack)-2].Bytes32())
+	}
+	log.Storage = maps.Clone(storage)
+}
+
+func (l *StructLogger) resolvePendingLoad(depth int, stack []uint256.Int) {
+	if l.load == nil {
+		return
+	}
+	load := l.load
+	l.load = nil
+	// The frame might have been exited due to an error, in which case there is no result.
+	if load.depth != depth || len(stack) == 0 {
+		return
+	}
+
+	value := common.Hash(stack[len(stack)-1].Bytes32())
+	l.storage[load.address][load.key] = value
+	if load.log < len(l.logs) {
+		l.logs[load.log].Storage[load.key] = value
+	}
+}
+
+func (l *StructLogger) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if depth != 0 {
+		return
+	}
+	l.output = common.CopyBytes(output)
+	l.gasUsed = gasUsed
+	l.failed = err != nil
+}
+
+func (l *StructLogger) GetResult() (json.RawMessage, error) {
+	logs := l.logs
+	if logs == nil {
+		logs = []StructLog{}
+	}
+	return json.Marshal(&StructLoggerResult{
+		Gas:         l.gasUsed,
+		Failed:      l.failed,
+		ReturnValue: l.output,
+		StructLogs:  logs,
+	})
+}
@@ -0,0 +1,59 @@
+// Package tracers implements EVM tracers that produce the output of debug_traceTransaction and debug_traceCall.
+package tracers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+)
+
+const (
+	// StructLoggerName is the name of the opcode-level tracer. It is used when no tracer is specified.
+	StructLoggerName = "structLogger"
+	// CallTracerName is the name of the tracer that produces a tree of nested calls.
+	CallTracerName = "callTracer"
+)
+
+// Config specifies the tracer and its options.
+type Config struct {
+	// Tracer is the name of the tracer to use, StructLoggerName by default.
+	Tracer string `json:"tracer,omitempty"`
+
+	// Options of the struct logger.
+	EnableMemory     bool `json:"enableMemory,omitempty"`
+	DisableStack     bool `json:"disableStack,omitempty"`
+	DisableStorage   bool `json:"disableStorage,omitempty"`
+	EnableReturnData bool `json:"enableReturnData,omitempty"`
+	// Limit is the maximum number of logged opcodes, zero means no limit.
+	Limit int `json:"limit,omitempty"`
+
+	// TracerConfig holds options of the named tracer.
+	TracerConfig *CallTracerConfig `json:"tracerConfig,omitempty"`
+}
+
+// Tracer collects EVM events via hooks and renders the result.
+type Tracer interface {
+	Hooks() *tracing.Hooks
+	GetResult() (json.RawMessage, error)
+}
+
+// New creates a tracer specified by cfg. Nil config creates a struct logger with default options.
+func New(cfg *Config) (Tracer, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	switch cfg.Tracer {
+	case "", StructLoggerName:
+		return NewStructLogger(cfg), nil
+	case CallTracerName:
+		var tracerCfg CallTracerConfig
+		if cfg.TracerConfig != nil {
+			tracerCfg = *cfg.TracerConfig
+		}
+		return NewCallTracer(tracerCfg), nil
+	default:
+		return nil, fmt.Errorf("unknown tracer %q", cfg.Tracer)
+	}
+}
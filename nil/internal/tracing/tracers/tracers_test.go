@@ -0,0 +1,128 @@
+package tracers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeAndLoadCode stores 42 into slot 0, reads it back and returns it.
+const storeAndLoadCode = "602a60005560005460005260206000f3"
+
+func traceCall(t *testing.T, cfg *Config) json.RawMessage {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	cfgAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+	require.NoError(t, config.SetParamGasPrice(cfgAccessor, &config.ParamGasPrice{
+		Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+	}))
+
+	es, err := execution.NewExecutionState(tx, types.BaseShardId, execution.StateParams{
+		ConfigAccessor: cfgAccessor,
+	})
+	require.NoError(t, err)
+	es.BaseFee = types.DefaultGasPrice
+
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetCode(addr, hexutil.FromHex(storeAndLoadCode)))
+
+	tracer, err := New(cfg)
+	require.NoError(t, err)
+	es.SetEvmTracer(tracer.Hooks())
+
+	txn := types.NewEmptyTransaction()
+	txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+	txn.To = addr
+	txn.From = addr
+	txn.FeeCredit = types.GasToValue(100_000)
+	txn.MaxFeePerGas = types.MaxFeePerGasDefault
+
+	es.AddInTransaction(txn)
+	res := es.HandleTransaction(t.Context(), txn, execution.NewDummyPayer())
+	require.False(t, res.Failed(), "%v", res.GetError())
+
+	result, err := tracer.GetResult()
+	require.NoError(t, err)
+	return result
+}
+
+func TestStructLogger(t *testing.T) {
+	t.Parallel()
+
+	var res StructLoggerResult
+	require.NoError(t, json.Unmarshal(traceCall(t, nil), &res))
+
+	assert.False(t, res.Failed)
+	assert.Positive(t, res.Gas)
+	assert.Equal(t, common.IntToHash(42).Bytes(), []byte(res.ReturnValue))
+
+	ops := make([]string, len(res.StructLogs))
+	for i, log := range res.StructLogs {
+		ops[i] = log.Op
+	}
+	assert.Equal(t, []string{
+		"PUSH1", "PUSH1", "SSTORE", "PUSH1", "SLOAD", "PUSH1", "MSTORE", "PUSH1", "PUSH1", "RETURN",
+	}, ops)
+
+	sload := res.StructLogs[4]
+	assert.Equal(t, map[common.Hash]common.Hash{common.EmptyHash: common.IntToHash(42)}, sload.Storage)
+	assert.Len(t, sload.Stack, 1)
+	assert.Empty(t, sload.Memory)
+}
+
+func TestStructLoggerOptions(t *testing.T) {
+	t.Parallel()
+
+	var res StructLoggerResult
+	require.NoError(t, json.Unmarshal(traceCall(t, &Config{
+		DisableStack:   true,
+		DisableStorage: true,
+		EnableMemory:   true,
+		Limit:          9,
+	}), &res))
+
+	require.Len(t, res.StructLogs, 9)
+	for _, log := range res.StructLogs {
+		assert.Empty(t, log.Stack)
+		assert.Empty(t, log.Storage)
+	}
+	// PUSH1 before RETURN sees the memory written by MSTORE.
+	assert.Equal(t, common.IntToHash(42).Bytes(), []byte(res.StructLogs[8].Memory))
+}
+
+func TestCallTracer(t *testing.T) {
+	t.Parallel()
+
+	var frame CallFrame
+	require.NoError(t, json.Unmarshal(traceCall(t, &Config{Tracer: CallTracerName}), &frame))
+
+	assert.Equal(t, "CALL", frame.Type)
+	assert.NotNil(t, frame.To)
+	assert.Positive(t, frame.GasUsed)
+	assert.Empty(t, frame.Error)
+	assert.Empty(t, frame.Calls)
+	assert.Equal(t, common.IntToHash(42).Bytes(), []byte(frame.Output))
+}
+
+func TestUnknownTracer(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&Config{Tracer: "prestateTracer"})
+	require.ErrorContains(t, err, "unknown tracer")
+}
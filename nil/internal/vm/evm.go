@@ -123,9 +123,17 @@ func (evm *EVM) Interpreter() *EVMInterpreter {
 // parameters. It also handles any necessary value transfer required and takes
 // the necessary steps to create accounts and reverses the state in case of an
 // execution error or failed value transfer.
-func (evm *EVM) Call(caller ContractRef, addr types.Address, input []byte, gas uint64, value *uint256.Int) ([]byte, uint64, error) {
+func (evm *EVM) Call(caller ContractRef, addr types.Address, input []byte, gas uint64, value *uint256.Int) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = false
 
+	// Capture the tracer start/end events in debug mode
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, CALL, caller.Address(), addr, input, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -142,7 +150,6 @@ func (evm *EVM) Call(caller ContractRef, addr types.Address, input []byte, gas u
 	snapshot := evm.StateDB.Snapshot()
 	p, isPrecompile := evm.precompile(addr)
 
-	var ret []byte
 	var runErr error
 	if isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, value, caller, readOnly)
@@ -220,9 +227,17 @@ func (evm *EVM) Call(caller ContractRef, addr types.Address, input []byte, gas u
 //
 // CallCode differs from Call in the sense that it executes the given address'
 // code with the caller as context.
-func (evm *EVM) CallCode(caller ContractRef, addr types.Address, input []byte, gas uint64, value *uint256.Int) ([]byte, uint64, error) {
+func (evm *EVM) CallCode(caller ContractRef, addr types.Address, input []byte, gas uint64, value *uint256.Int) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = false
 
+	// Invoke tracer hooks that signal entering/exiting a call frame
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, CALLCODE, caller.Address(), addr, input, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -240,7 +255,6 @@ func (evm *EVM) CallCode(caller ContractRef, addr types.Address, input []byte, g
 	snapshot := evm.StateDB.Snapshot()
 
 	// It is allowed to call precompiles, even via delegatecall
-	var ret []byte
 	var runErr error
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, value, caller, readOnly)
@@ -275,9 +289,18 @@ func (evm *EVM) CallCode(caller ContractRef, addr types.Address, input []byte, g
 //
 // DelegateCall differs from CallCode in the sense that it executes the given address'
 // code with the caller as context and the caller is set to the caller of the caller.
-func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byte, gas uint64) ([]byte, uint64, error) {
+func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byte, gas uint64) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = false
 
+	// Invoke tracer hooks that signal entering/exiting a call frame
+	if evm.Config.Tracer != nil {
+		// DELEGATECALL inherits value from parent call
+		evm.captureBegin(evm.depth, DELEGATECALL, caller.Address(), addr, input, gas, nil)
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -285,7 +308,6 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byt
 	snapshot := evm.StateDB.Snapshot()
 
 	// It is allowed to call precompiles, even via delegatecall
-	var ret []byte
 	var runErr error
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, nil, caller, readOnly)
@@ -318,9 +340,17 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr types.Address, input []byt
 // as parameters while disallowing any modifications to the state during the call.
 // Opcodes that attempt to perform such modifications will result in exceptions
 // instead of performing the modifications.
-func (evm *EVM) StaticCall(caller ContractRef, addr types.Address, input []byte, gas uint64) ([]byte, uint64, error) {
+func (evm *EVM) StaticCall(caller ContractRef, addr types.Address, input []byte, gas uint64) (ret []byte, leftOverGas uint64, err error) {
 	const readOnly = true
 
+	// Invoke tracer hooks that signal entering/exiting a call frame
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, STATICCALL, caller.Address(), addr, input, gas, nil)
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
@@ -332,7 +362,6 @@ func (evm *EVM) StaticCall(caller ContractRef, addr types.Address, input []byte,
 	// We could change this, but for now it's left for legacy reasons
 	snapshot := evm.StateDB.Snapshot()
 
-	var ret []byte
 	var runErr error
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
 		ret, gas, runErr = RunPrecompiledContract(p, evm, input, gas, evm.Config.Tracer, nil, caller, readOnly)
@@ -366,7 +395,14 @@ func (evm *EVM) StaticCall(caller ContractRef, addr types.Address, input []byte,
 }
 
 // create creates a new contract using code as deployment code.
-func (evm *EVM) create(caller ContractRef, codeAndHash types.Code, gas uint64, value *uint256.Int, address types.Address) ([]byte, types.Address, uint64, error) {
+func (evm *EVM) create(caller ContractRef, codeAndHash types.Code, gas uint64, value *uint256.Int, address types.Address, typ OpCode) (ret []byte, createAddress types.Address, leftOverGas uint64, err error) {
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, typ, caller.Address(), address, codeAndHash, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, leftOverGas, ret, err)
+		}(gas)
+	}
+
 	// Depth check execution. Fail if we're trying to execute above the
 	// limit.
 	if evm.depth > int(params.CallCreateDepth) {
@@ -438,7 +474,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash types.Code, gas uint64, v
 	contract.SetCallCode(address, codeAndHash.Hash(), codeAndHash)
 	contract.IsDeployment = true
 
-	ret, err := evm.interpreter.Run(contract, nil, false)
+	ret, err = evm.interpreter.Run(contract, nil, false)
 
 	// Check whether the max code size has been exceeded (EIP-158)
 	if err == nil && len(ret) > params.MaxCodeSize {
@@ -474,14 +510,14 @@ func (evm *EVM) create(caller ContractRef, codeAndHash types.Code, gas uint64, v
 
 // Deploy deploys a new contract from a deployment transaction
 func (evm *EVM) Deploy(addr types.Address, caller ContractRef, code []byte, gas uint64, value *uint256.Int) (ret []byte, deployAddr types.Address, leftOverGas uint64, err error) {
-	return evm.create(caller, code, gas, value, addr)
+	return evm.create(caller, code, gas, value, addr, CREATE)
 }
 
 // Create creates a new contract using code as deployment code.
 func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *uint256.Int) (ret []byte, contractAddr types.Address, leftOverGas uint64, err error) {
 	payload := types.BuildDeployPayload(code, common.EmptyHash)
 	contractAddr = types.CreateAddress(caller.Address().ShardId(), payload)
-	return evm.create(caller, code, gas, value, contractAddr)
+	return evm.create(caller, code, gas, value, contractAddr, CREATE)
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -490,7 +526,27 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *uint2
 // instead of the usual sender-and-nonce-hash as the address where the contract is initialized at.
 func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *uint256.Int, salt *uint256.Int) (ret []byte, contractAddr types.Address, leftOverGas uint64, err error) {
 	contractAddr = types.CreateAddressForCreate2(caller.Address(), code, common.BytesToHash(salt.Bytes()))
-	return evm.create(caller, code, gas, endowment, contractAddr)
+	return evm.create(caller, code, gas, endowment, contractAddr, CREATE2)
+}
+
+func (evm *EVM) captureBegin(depth int, typ OpCode, from types.Address, to types.Address, input []byte, startGas uint64, value *big.Int) {
+	tracer := evm.Config.Tracer
+	if tracer.OnEnter != nil {
+		tracer.OnEnter(depth, byte(typ), from, to, input, startGas, value)
+	}
+	if tracer.OnGasChange != nil {
+		tracer.OnGasChange(0, startGas, tracing.GasChangeCallInitialBalance)
+	}
+}
+
+func (evm *EVM) captureEnd(depth int, startGas uint64, leftOverGas uint64, ret []byte, err error) {
+	tracer := evm.Config.Tracer
+	if leftOverGas != 0 && tracer.OnGasChange != nil {
+		tracer.OnGasChange(leftOverGas, 0, tracing.GasChangeCallLeftOverReturned)
+	}
+	if tracer.OnExit != nil {
+		tracer.OnExit(depth, ret, startGas-leftOverGas, err, err != nil)
+	}
 }
 
 // canTransfer checks whether there are enough funds in the address' account to make a transfer.
@@ -1,6 +1,10 @@
 package admin
 
+import "github.com/NilFoundation/nil/nil/internal/network"
+
 type ServerConfig struct {
 	Enabled        bool
 	UnixSocketPath string
+	// NetworkManager (if any) serves the peers of the node.
+	NetworkManager *network.Manager
 }
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -33,6 +34,8 @@ func StartAdminServer(ctx context.Context, cfg *ServerConfig, logger zerolog.Log
 	// GET http:/./set_log_level?level=info
 	srv.mux.HandleFunc("/set_log_level", srv.setLogLevel)
 	srv.mux.HandleFunc("/ping", srv.ping)
+	// GET http:/./peers
+	srv.mux.HandleFunc("/peers", srv.peers)
 
 	if err := srv.serve(ctx); err != nil {
 		return fmt.Errorf("error starting admin server: %w", err)
@@ -81,3 +84,16 @@ func (s *adminServer) setLogLevel(w http.ResponseWriter, r *http.Request) {
 func (s *adminServer) ping(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
+
+func (s *adminServer) peers(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.NetworkManager == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprint(w, "error: network is disabled")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.cfg.NetworkManager.Peers()); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to write peers")
+	}
+}
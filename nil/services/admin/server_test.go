@@ -54,4 +54,9 @@ func TestAdminServer(t *testing.T) {
 	check(t, "warn", http.StatusOK, zerolog.WarnLevel)
 
 	check(t, "invalid", http.StatusBadRequest, zerolog.WarnLevel)
+
+	response, err := client.Get("http://unix/peers")
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
 }
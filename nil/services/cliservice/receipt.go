@@ -2,6 +2,8 @@ package cliservice
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
@@ -30,3 +32,91 @@ func (s *Service) FetchReceiptByHashJson(hash common.Hash) ([]byte, error) {
 	}
 	return receiptDataJSON, nil
 }
+
+// FetchTransactionTree fetches the tree of transactions caused by the transaction with the given hash
+func (s *Service) FetchTransactionTree(hash common.Hash) (*jsonrpc.RPCTransactionTreeNode, error) {
+	tree, err := s.client.GetTransactionTree(s.ctx, hash)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to fetch transaction tree")
+		return nil, err
+	}
+	return tree, nil
+}
+
+// FetchTransactionTreeText fetches the tree of transactions as JSON or as a human-readable text
+func (s *Service) FetchTransactionTreeText(hash common.Hash, jsonOutput bool, noColor bool) ([]byte, error) {
+	tree, err := s.FetchTransactionTree(hash)
+	if err != nil {
+		return nil, err
+	}
+	if jsonOutput {
+		treeJSON, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to marshal transaction tree to JSON")
+			return nil, err
+		}
+		return treeJSON, nil
+	}
+	return transactionTreeToText(tree, !noColor), nil
+}
+
+func transactionTreeToText(tree *jsonrpc.RPCTransactionTreeNode, useColor bool) []byte {
+	colors := map[string]string{
+		"blue":    "\033[94m",
+		"green":   "\033[32m",
+		"magenta": "\033[95m",
+		"red":     "\033[31m",
+		"yellow":  "\033[93m",
+		"reset":   "\033[0m",
+		"bold":    "\033[1m",
+	}
+	if !useColor {
+		for k := range colors {
+			colors[k] = ""
+		}
+	}
+
+	var sb strings.Builder
+	tree.Walk(func(node *jsonrpc.RPCTransactionTreeNode, depth int) {
+		indent := strings.Repeat("  ", depth)
+		marker := "■"
+		if len(node.Children) > 0 {
+			marker = "▼"
+		}
+
+		var statusColor string
+		switch node.Status {
+		case jsonrpc.TransactionTreeStatusSuccess:
+			statusColor = colors["green"]
+		case jsonrpc.TransactionTreeStatusFailed:
+			statusColor = colors["red"]
+		case jsonrpc.TransactionTreeStatusPending:
+			statusColor = colors["yellow"]
+		}
+
+		fmt.Fprintf(&sb, "%s%s [%s%s%s] @ %d shard: %s%s%s\n",
+			indent, marker, colors["bold"], node.TxnHash, colors["reset"], node.ShardId,
+			statusColor, node.Status, colors["reset"])
+		if node.Receipt != nil && !node.Receipt.Success {
+			fmt.Fprintf(&sb, "%s  Status: %s%s%s\n", indent, colors["red"], node.Receipt.Status, colors["reset"])
+		}
+		if node.ErrorMessage != "" {
+			fmt.Fprintf(&sb, "%s  Error: %s%s%s\n", indent, colors["red"], node.ErrorMessage, colors["reset"])
+		}
+		if node.Receipt == nil || !node.Receipt.Temporary {
+			fmt.Fprintf(&sb, "%s  %s%s%s => %s%s%s\n",
+				indent, colors["blue"], node.From, colors["reset"], colors["magenta"], node.To, colors["reset"])
+			fmt.Fprintf(&sb, "%s  Flags: %s, Value: %s\n", indent, node.Flags, node.Value)
+		}
+		if node.SourceShardId != nil && node.SourceBlockNumber != nil {
+			fmt.Fprintf(&sb, "%s  Emitted by block #%d @ %d shard\n", indent, *node.SourceBlockNumber, *node.SourceShardId)
+		}
+		if node.Status != jsonrpc.TransactionTreeStatusPending {
+			if node.BlockNumber != nil {
+				fmt.Fprintf(&sb, "%s  Block: #%d\n", indent, *node.BlockNumber)
+			}
+			fmt.Fprintf(&sb, "%s  GasUsed: %d, GasPrice: %s, Fee: %s\n", indent, node.GasUsed, node.GasPrice, node.Fee)
+		}
+	})
+	return []byte(strings.TrimSuffix(sb.String(), "\n"))
+}
@@ -0,0 +1,87 @@
+package cliservice
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionTreeToText(t *testing.T) {
+	t.Parallel()
+
+	sourceShardId := types.ShardId(1)
+	sourceBlock := types.BlockNumber(5)
+	block := types.BlockNumber(7)
+	from := types.BytesToAddress(hexutil.FromHex("0x01"))
+	to := types.BytesToAddress(hexutil.FromHex("0x02"))
+	flags := types.NewTransactionFlags(types.TransactionFlagInternal)
+
+	tree := &jsonrpc.RPCTransactionTreeNode{
+		TxnHash:     common.HexToHash("0x1"),
+		ShardId:     1,
+		Status:      jsonrpc.TransactionTreeStatusSuccess,
+		Flags:       flags,
+		From:        from,
+		To:          from,
+		Value:       types.NewValueFromUint64(100),
+		BlockNumber: &sourceBlock,
+		GasUsed:     10,
+		GasPrice:    types.NewValueFromUint64(2),
+		Fee:         types.NewValueFromUint64(20),
+		Receipt:     &jsonrpc.RPCReceipt{Success: true},
+		Children: []*jsonrpc.RPCTransactionTreeNode{
+			{
+				TxnHash:           common.HexToHash("0x2"),
+				ShardId:           2,
+				Status:            jsonrpc.TransactionTreeStatusFailed,
+				Flags:             flags,
+				From:              from,
+				To:                to,
+				Value:             types.NewValueFromUint64(1),
+				SourceShardId:     &sourceShardId,
+				SourceBlockNumber: &sourceBlock,
+				BlockNumber:       &block,
+				GasUsed:           5,
+				GasPrice:          types.NewValueFromUint64(2),
+				Fee:               types.NewValueFromUint64(10),
+				ErrorMessage:      "out of gas",
+				Receipt:           &jsonrpc.RPCReceipt{Success: false, Status: "OutOfGas"},
+			},
+			{
+				TxnHash:           common.HexToHash("0x3"),
+				ShardId:           2,
+				Status:            jsonrpc.TransactionTreeStatusPending,
+				Flags:             flags,
+				From:              from,
+				To:                to,
+				Value:             types.NewValueFromUint64(2),
+				SourceShardId:     &sourceShardId,
+				SourceBlockNumber: &sourceBlock,
+			},
+		},
+	}
+
+	expected := `▼ [0x0000000000000000000000000000000000000000000000000000000000000001] @ 1 shard: success
+  0x0000000000000000000000000000000000000001 => 0x0000000000000000000000000000000000000001
+  Flags: ` + flags.String() + `, Value: 100
+  Block: #5
+  GasUsed: 10, GasPrice: 2, Fee: 20
+  ■ [0x0000000000000000000000000000000000000000000000000000000000000002] @ 2 shard: failed
+    Status: OutOfGas
+    Error: out of gas
+    0x0000000000000000000000000000000000000001 => 0x0000000000000000000000000000000000000002
+    Flags: ` + flags.String() + `, Value: 1
+    Emitted by block #5 @ 1 shard
+    Block: #7
+    GasUsed: 5, GasPrice: 2, Fee: 10
+  ■ [0x0000000000000000000000000000000000000000000000000000000000000003] @ 2 shard: pending
+    0x0000000000000000000000000000000000000001 => 0x0000000000000000000000000000000000000002
+    Flags: ` + flags.String() + `, Value: 2
+    Emitted by block #5 @ 1 shard`
+
+	require.Equal(t, expected, string(transactionTreeToText(tree, false)))
+}
@@ -159,24 +159,53 @@ func (c *Client) CompileContract(inputJsonFile string) (*ContractData, error) {
 }
 
 func (c *Client) RegisterContractFromFile(inputJsonFile string, address types.Address) error {
+	inputJson, err := readCompilerTaskFile(inputJsonFile)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest("cometa_registerContract", []any{inputJson, address})
+	return err
+}
+
+func (c *Client) VerifyContractFromFile(inputJsonFile string, address types.Address) (VerificationStatus, error) {
+	inputJson, err := readCompilerTaskFile(inputJsonFile)
+	if err != nil {
+		return "", err
+	}
+	return c.VerifyContract(inputJson, address)
+}
+
+func (c *Client) VerifyContract(inputJson string, address types.Address) (VerificationStatus, error) {
+	response, err := c.sendRequest("cometa_verifyContract", []any{inputJson, address})
+	if err != nil {
+		return "", err
+	}
+	var status VerificationStatus
+	if err := json.Unmarshal(response, &status); err != nil {
+		return "", fmt.Errorf("failed to unmarshal verification status: %w", err)
+	}
+	return status, nil
+}
+
+// readCompilerTaskFile reads the compiler task and embeds the sources referenced by it.
+func readCompilerTaskFile(inputJsonFile string) (string, error) {
 	inputJson, err := os.ReadFile(inputJsonFile)
 	if err != nil {
-		return fmt.Errorf("failed to read input json: %w", err)
+		return "", fmt.Errorf("failed to read input json: %w", err)
 	}
 	task, err := NewCompilerTask(string(inputJson))
 	if err != nil {
-		return fmt.Errorf("failed to read input json: %w", err)
+		return "", fmt.Errorf("failed to read input json: %w", err)
 	}
 	if err = task.Normalize(filepath.Dir(inputJsonFile)); err != nil {
-		return fmt.Errorf("failed to normalize compiler task: %w", err)
+		return "", fmt.Errorf("failed to normalize compiler task: %w", err)
 	}
 	inputJson, err = json.Marshal(task)
 	if err != nil {
-		return fmt.Errorf("failed to marshal input json: %w", err)
+		return "", fmt.Errorf("failed to marshal input json: %w", err)
 	}
-
-	_, err = c.sendRequest("cometa_registerContract", []any{string(inputJson), address})
-	return err
+	return string(inputJson), nil
 }
 
 func (c *Client) RegisterContract(inputJson string, address types.Address) error {
@@ -55,6 +55,12 @@ type ContractData struct {
 
 	// MethodIdentifiers holds a map of method identifiers: {signature -> methodId}. E.g. "test(uint256)": "29e99f07"
 	MethodIdentifiers map[string]string `json:"methodIdentifiers,omitempty"`
+
+	// ImmutableReferences holds the locations of the immutable variables in the runtime bytecode.
+	ImmutableReferences []CodeRange `json:"immutableReferences,omitempty"`
+
+	// Verification shows whether the runtime bytecode was proven to match the deployed contract.
+	Verification VerificationStatus `json:"verification,omitempty"`
 }
 
 func NewCompilerTask(inputJson string) (*CompilerTask, error) {
@@ -209,6 +215,7 @@ func CreateContractData(input *CompilerJsonInput, outputJson *CompilerJsonOutput
 	contractData.Metadata = contractDescr.Metadata
 	contractData.Code = hexutil.MustDecode(contractDescr.Evm.DeployedBytecode.Object)
 	contractData.InitCode = hexutil.MustDecode(contractDescr.Evm.Bytecode.Object)
+	contractData.ImmutableReferences = immutableRanges(contractDescr.Evm.DeployedBytecode.ImmutableReferences)
 	abiJson, err := json.Marshal(contractDescr.Abi)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal abi: %w", err)
@@ -39,6 +39,7 @@ type CometaJsonRpc interface {
 	GetSourceCode(ctx context.Context, address types.Address) (map[string]string, error)
 	CompileContract(ctx context.Context, inputJson string) (*ContractData, error)
 	RegisterContract(ctx context.Context, inputJson string, address types.Address) error
+	VerifyContract(ctx context.Context, inputJson string, address types.Address) (VerificationStatus, error)
 	RegisterContractData(ctx context.Context, contractData *ContractData, address types.Address) error
 	GetVersion(ctx context.Context) (string, error)
 	DecodeTransactionsCallData(ctx context.Context, request []TransactionInfo) ([]string, error)
@@ -134,21 +135,21 @@ func (s *Service) Run(ctx context.Context, cfg *Config) error {
 	return s.startRpcServer(ctx, cfg.OwnEndpoint)
 }
 
+// RegisterContractData stores the metadata supplied by the user. The runtime bytecode must be equal to the deployed one,
+// but since the metadata was not compiled by Cometa, the contract is registered as unverified.
 func (s *Service) RegisterContractData(ctx context.Context, contractData *ContractData, address types.Address) error {
 	logger.Info().Msg("Register contract...")
-	code, err := s.client.GetCode(ctx, address, "latest")
+	code, err := s.getDeployedCode(ctx, address)
 	if err != nil {
-		return fmt.Errorf("failed to get code: %w", err)
-	}
-	if len(code) == 0 {
-		return fmt.Errorf("contract does not exist at address %s", address)
+		return err
 	}
 
 	if !bytes.Equal(code, contractData.Code) {
 		return errors.New("compiled bytecode is not equal to the deployed one")
 	}
 
-	if err = s.storage.StoreContract(ctx, contractData, address); err != nil {
+	contractData.Verification = VerificationStatusUnverified
+	if err = s.storeContract(ctx, contractData, address); err != nil {
 		return err
 	}
 
@@ -157,16 +158,65 @@ func (s *Service) RegisterContractData(ctx context.Context, contractData *Contra
 	return nil
 }
 
+// RegisterContract compiles the input and registers the contract if it matches the deployed one.
 func (s *Service) RegisterContract(ctx context.Context, inputJson string, address types.Address) error {
+	if _, err := s.VerifyContract(ctx, inputJson, address); err != nil {
+		return fmt.Errorf("failed to register contract: %w", err)
+	}
+	return nil
+}
+
+// VerifyContract compiles the input and compares the runtime bytecode with the code deployed at the address
+// ignoring the immutables and the metadata hash. On success the contract is registered with the resulting status.
+func (s *Service) VerifyContract(ctx context.Context, inputJson string, address types.Address) (VerificationStatus, error) {
 	contractData, err := s.CompileContract(ctx, inputJson)
 	if err != nil {
-		return fmt.Errorf("failed to compile contract: %w", err)
+		return "", fmt.Errorf("failed to compile contract: %w", err)
 	}
 
-	if err := s.RegisterContractData(ctx, contractData, address); err != nil {
-		return fmt.Errorf("failed to register contract: %w", err)
+	code, err := s.getDeployedCode(ctx, address)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := MatchBytecode(contractData.Code, code, contractData.ImmutableReferences)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify contract %s: %w", contractData.Name, err)
+	}
+
+	// Keep the deployed code, so that the contracts with the same code are found by its hash.
+	contractData.Code = code
+	contractData.Verification = status
+	if err := s.storeContract(ctx, contractData, address); err != nil {
+		return "", err
 	}
-	return err
+
+	logger.Info().
+		Str("contract", contractData.Name).
+		Stringer("address", address).
+		Str("status", string(status)).
+		Msg("Contract has been verified.")
+
+	return status, nil
+}
+
+func (s *Service) getDeployedCode(ctx context.Context, address types.Address) (types.Code, error) {
+	code, err := s.client.GetCode(ctx, address, "latest")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("contract does not exist at address %s", address)
+	}
+	return code, nil
+}
+
+func (s *Service) storeContract(ctx context.Context, contractData *ContractData, address types.Address) error {
+	if err := s.storage.StoreContract(ctx, contractData, address); err != nil {
+		return err
+	}
+	s.contractsCache.Remove(address)
+	return nil
 }
 
 func (s *Service) CompileContract(ctx context.Context, inputJson string) (*ContractData, error) {
@@ -218,6 +268,10 @@ func (s *Service) GetContractControl(ctx context.Context, address types.Address)
 			}
 			logger.Info().Str("contract", data.Name).Msg("Found twin contract")
 		}
+		// Contracts registered before the verification was introduced have no status.
+		if data.Verification == "" {
+			data.Verification = VerificationStatusUnverified
+		}
 		contract, err = NewContractFromData(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create contract from data: %w", err)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/client"
@@ -129,6 +130,47 @@ func (s *SuiteServiceTest) TestTwinContracts() {
 	s.Require().Equal(contract, contract2)
 }
 
+func (s *SuiteServiceTest) TestVerifyContract() {
+	task := s.getCompilerTask("input_2")
+	inputJson, err := json.Marshal(task)
+	s.Require().NoError(err)
+
+	contractData, err := Compile(task)
+	s.Require().NoError(err)
+
+	code := slices.Clone(contractData.Code)
+	address := types.CreateAddress(types.ShardId(1), types.BuildDeployPayload(code, common.HexToHash("0x9abc")))
+	s.client.GetCodeFunc = func(ctx context.Context, addr types.Address, blockId any) (types.Code, error) {
+		return code, nil
+	}
+
+	status, err := s.service.VerifyContract(s.ctx, string(inputJson), address)
+	s.Require().NoError(err)
+	s.Require().Equal(VerificationStatusFull, status)
+
+	contract, err := s.service.GetContract(s.ctx, address)
+	s.Require().NoError(err)
+	s.Require().Equal(VerificationStatusFull, contract.Verification)
+
+	// The deployed code differs in the metadata hash only.
+	code[len(code)-3] ^= 0xff
+	status, err = s.service.VerifyContract(s.ctx, string(inputJson), address)
+	s.Require().NoError(err)
+	s.Require().Equal(VerificationStatusPartial, status)
+
+	// The deployed code differs in the instructions.
+	code[0] ^= 0xff
+	_, err = s.service.VerifyContract(s.ctx, string(inputJson), address)
+	s.Require().ErrorIs(err, ErrBytecodeMismatch)
+
+	// Registration of user-supplied data is not a verification.
+	code = contractData.Code
+	s.Require().NoError(s.service.RegisterContractData(s.ctx, contractData, address))
+	contract, err = s.service.GetContract(s.ctx, address)
+	s.Require().NoError(err)
+	s.Require().Equal(VerificationStatusUnverified, contract.Verification)
+}
+
 func (s *SuiteServiceTest) TestErrorContract() {
 	task := s.getCompilerTask("input_3")
 
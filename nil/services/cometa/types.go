@@ -97,13 +97,13 @@ type EvmOutput struct {
 }
 
 type CompilerOutputEvm struct {
-	Object              string            `json:"object,omitempty"`
-	Opcodes             string            `json:"opcodes,omitempty"`
-	SourceMap           string            `json:"sourceMap,omitempty"`
-	LinkReferences      any               `json:"linkReferences,omitempty"`
-	ImmutableReferences any               `json:"immutableReferences,omitempty"`
-	FunctionDebugData   FunctionDebugData `json:"functionDebugData"`
-	GeneratedSources    []GeneratedSource `json:"generatedSources,omitempty"`
+	Object              string                 `json:"object,omitempty"`
+	Opcodes             string                 `json:"opcodes,omitempty"`
+	SourceMap           string                 `json:"sourceMap,omitempty"`
+	LinkReferences      any                    `json:"linkReferences,omitempty"`
+	ImmutableReferences map[string][]CodeRange `json:"immutableReferences,omitempty"`
+	FunctionDebugData   FunctionDebugData      `json:"functionDebugData"`
+	GeneratedSources    []GeneratedSource      `json:"generatedSources,omitempty"`
 }
 
 type GeneratedSource struct {
@@ -226,6 +226,7 @@ func (t *CompilerTask) ToCompilerJsonInput() (*CompilerJsonInput, error) {
 				"evm.deployedBytecode.sourceMap",
 				"evm.deployedBytecode.generatedSources",
 				"evm.deployedBytecode.functionDebugData",
+				"evm.deployedBytecode.immutableReferences",
 				"evm.methodIdentifiers",
 			},
 		},
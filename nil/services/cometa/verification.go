@@ -0,0 +1,85 @@
+package cometa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+)
+
+// VerificationStatus shows how the registered contract metadata was proven to match the deployed contract.
+type VerificationStatus string
+
+const (
+	// VerificationStatusUnverified means that the metadata was supplied by the user and was not compiled by Cometa.
+	VerificationStatusUnverified VerificationStatus = "unverified"
+	// VerificationStatusPartial means that the compiled runtime bytecode matches the deployed one
+	// except for the metadata hash, i.e. the sources may differ in comments or file names.
+	VerificationStatusPartial VerificationStatus = "partial"
+	// VerificationStatusFull means that the compiled runtime bytecode matches the deployed one exactly
+	// except for the values of the immutables.
+	VerificationStatusFull VerificationStatus = "full"
+)
+
+var ErrBytecodeMismatch = errors.New("compiled bytecode does not match the deployed one")
+
+// CodeRange is a range of the bytecode, e.g. the location of an immutable variable.
+type CodeRange struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// MatchBytecode compares the compiled runtime bytecode with the deployed one. The values of the immutables are set
+// during deployment, so they are ignored. If the codes differ only in the metadata appended by the compiler,
+// the match is partial.
+func MatchBytecode(compiled, deployed []byte, immutables []CodeRange) (VerificationStatus, error) {
+	if len(compiled) != len(deployed) {
+		return "", ErrBytecodeMismatch
+	}
+
+	deployed = slices.Clone(deployed)
+	for _, r := range immutables {
+		if r.Start < 0 || r.Length < 0 || r.Start+r.Length > len(deployed) {
+			return "", errors.New("immutable reference is out of the bytecode")
+		}
+		copy(deployed[r.Start:r.Start+r.Length], compiled[r.Start:r.Start+r.Length])
+	}
+
+	if bytes.Equal(compiled, deployed) {
+		return VerificationStatusFull, nil
+	}
+	if bytes.Equal(stripMetadata(compiled), stripMetadata(deployed)) {
+		return VerificationStatusPartial, nil
+	}
+	return "", ErrBytecodeMismatch
+}
+
+// stripMetadata removes the CBOR-encoded metadata that solc appends to the runtime bytecode.
+// The metadata is followed by its length encoded as two big-endian bytes.
+func stripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	length := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - length
+	if length == 0 || start < 0 {
+		return code
+	}
+	// The metadata is a CBOR map.
+	if code[start]&0xe0 != 0xa0 {
+		return code
+	}
+	return code[:start]
+}
+
+// immutableRanges flattens the immutable references of the compiler output.
+func immutableRanges(refs map[string][]CodeRange) []CodeRange {
+	var res []CodeRange
+	for _, ranges := range refs {
+		res = append(res, ranges...)
+	}
+	slices.SortFunc(res, func(a, b CodeRange) int {
+		return a.Start - b.Start
+	})
+	return res
+}
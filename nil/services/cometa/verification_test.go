@@ -0,0 +1,64 @@
+package cometa
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchBytecode(t *testing.T) {
+	t.Parallel()
+
+	// The metadata is a CBOR map (0xa1) followed by its length.
+	metadata := []byte{0xa1, 0x01, 0x02, 0x03, 0x00, 0x04}
+	body := []byte{0x60, 0x80, 0x7f, 0x00, 0x00, 0x00, 0x00, 0x50}
+	compiled := append(slices.Clone(body), metadata...)
+	immutables := []CodeRange{{Start: 3, Length: 4}}
+
+	withImmutable := slices.Clone(compiled)
+	copy(withImmutable[3:], []byte{0xde, 0xad, 0xbe, 0xef})
+
+	otherMetadata := slices.Clone(withImmutable)
+	otherMetadata[len(body)+1] = 0xff
+
+	for _, test := range []struct {
+		name       string
+		deployed   []byte
+		immutables []CodeRange
+		status     VerificationStatus
+		err        error
+	}{
+		{name: "Exact", deployed: compiled, status: VerificationStatusFull},
+		{name: "Immutables", deployed: withImmutable, immutables: immutables, status: VerificationStatusFull},
+		{name: "UnknownImmutables", deployed: withImmutable, err: ErrBytecodeMismatch},
+		{name: "Metadata", deployed: otherMetadata, immutables: immutables, status: VerificationStatusPartial},
+		{name: "Length", deployed: compiled[1:], err: ErrBytecodeMismatch},
+		{name: "Body", deployed: append([]byte{0x61}, compiled[1:]...), err: ErrBytecodeMismatch},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := MatchBytecode(compiled, test.deployed, test.immutables)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.status, status)
+		})
+	}
+
+	_, err := MatchBytecode(compiled, compiled, []CodeRange{{Start: len(compiled) - 1, Length: 2}})
+	require.Error(t, err)
+}
+
+func TestStripMetadata(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []byte{0x60}, stripMetadata([]byte{0x60, 0xa0, 0x00, 0x01}))
+	// Not a CBOR map.
+	require.Equal(t, []byte{0x60, 0x10, 0x00, 0x01}, stripMetadata([]byte{0x60, 0x10, 0x00, 0x01}))
+	// The length exceeds the code.
+	require.Equal(t, []byte{0xa0, 0x00, 0x05}, stripMetadata([]byte{0xa0, 0x00, 0x05}))
+}
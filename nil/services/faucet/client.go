@@ -70,12 +70,28 @@ func (c *Client) sendRequest(method string, params []any) (json.RawMessage, erro
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	if errorMsg, ok := rpcResponse["error"]; ok {
-		return nil, fmt.Errorf("rpc error: %s", errorMsg)
+		return nil, decodeError(errorMsg)
 	}
 
 	return rpcResponse["result"], nil
 }
 
+// decodeError returns LimitExceededError for the rejected top-ups, so that the callers can find out the limit
+// and when to retry.
+func decodeError(errorMsg json.RawMessage) error {
+	var rpcErr struct {
+		Code int             `json:"code"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(errorMsg, &rpcErr); err == nil && rpcErr.Code == ErrorCodeLimitExceeded {
+		limitErr := new(LimitExceededError)
+		if err := json.Unmarshal(rpcErr.Data, limitErr); err == nil {
+			return limitErr
+		}
+	}
+	return fmt.Errorf("rpc error: %s", errorMsg)
+}
+
 func (c *Client) TopUpViaFaucet(faucetAddress, contractAddressTo types.Address, amount types.Value) (common.Hash, error) {
 	response, err := c.sendRequest("faucet_topUpViaFaucet", []any{faucetAddress, contractAddressTo, amount})
 	if err != nil {
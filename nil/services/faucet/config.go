@@ -0,0 +1,78 @@
+package faucet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const DefaultLimitsWindow = 24 * time.Hour
+
+// Limits restrict the top-ups of a faucet within a sliding time window. Zero values mean no limit.
+type Limits struct {
+	// Window is the period the grants are counted in. DefaultLimitsWindow is used if it is zero.
+	Window time.Duration `yaml:"window,omitempty"`
+	// MaxRequestsPerAddress is the maximum number of top-ups of one address.
+	MaxRequestsPerAddress int `yaml:"maxRequestsPerAddress,omitempty"`
+	// MaxRequestsPerClient is the maximum number of top-ups requested from one client IP.
+	MaxRequestsPerClient int `yaml:"maxRequestsPerClient,omitempty"`
+	// MaxAmount is the maximum total amount of the token sent to one address and requested by one client IP.
+	MaxAmount types.Value `yaml:"maxAmount,omitempty"`
+}
+
+func (l *Limits) window() time.Duration {
+	if l.Window == 0 {
+		return DefaultLimitsWindow
+	}
+	return l.Window
+}
+
+type Config struct {
+	// DbPath is the path to the DB with the record of the grants. The grants are kept in memory if it is empty,
+	// so the limits are reset on restart.
+	DbPath string `yaml:"dbPath,omitempty"`
+	// TrustForwardedFor makes the faucet take the client IP from the X-Forwarded-For header.
+	// Enable it only if the faucet is behind a reverse proxy that sets the header.
+	TrustForwardedFor bool `yaml:"trustForwardedFor,omitempty"`
+	// DefaultLimits apply to the faucets without their own limits. No limits are applied if it is nil.
+	DefaultLimits *Limits `yaml:"defaultLimits,omitempty"`
+	// Limits maps the token name or the address of a faucet (see GetFaucets) to its limits.
+	Limits map[string]*Limits `yaml:"limits,omitempty"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{}
+}
+
+// faucetLimits resolves the limits of every faucet. The faucets without limits are omitted.
+func (c *Config) faucetLimits() (map[types.Address]*Limits, error) {
+	res := make(map[types.Address]*Limits)
+	if c == nil {
+		return res, nil
+	}
+
+	faucets := types.GetTokens()
+	if c.DefaultLimits != nil {
+		for _, addr := range faucets {
+			res[addr] = c.DefaultLimits
+		}
+	}
+	for key, limits := range c.Limits {
+		addr, ok := faucets[key]
+		if !ok {
+			if err := addr.Set(key); err != nil {
+				return nil, fmt.Errorf("invalid faucet %q in limits: %w", key, err)
+			}
+			if types.GetTokenName(types.TokenId(addr)) == "" {
+				return nil, fmt.Errorf("unknown faucet %q in limits", key)
+			}
+		}
+		if limits == nil {
+			delete(res, addr)
+			continue
+		}
+		res[addr] = limits
+	}
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package faucet
+
+import (
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+// ErrorCodeLimitExceeded is the JSON-RPC error code of the rejected top-ups (see EIP-1474).
+const ErrorCodeLimitExceeded = -32005
+
+// Kinds of the faucet limits.
+const (
+	LimitRequestsPerAddress = "requestsPerAddress"
+	LimitRequestsPerClient  = "requestsPerClient"
+	LimitAmountPerAddress   = "amountPerAddress"
+	LimitAmountPerClient    = "amountPerClient"
+)
+
+// LimitExceededError is returned when the top-up would exceed a limit of the faucet.
+// It is passed to the JSON-RPC clients as the error data.
+type LimitExceededError struct {
+	Faucet types.Address `json:"faucet"`
+	Limit  string        `json:"limit"`
+	// RetryAfter is the number of seconds after which the same request can succeed.
+	// Zero means that the request can't ever succeed, e.g. the amount exceeds the limit by itself.
+	RetryAfter uint64 `json:"retryAfter"`
+}
+
+var (
+	_ transport.Error     = (*LimitExceededError)(nil)
+	_ transport.DataError = (*LimitExceededError)(nil)
+)
+
+func (e *LimitExceededError) ErrorCode() int {
+	return ErrorCodeLimitExceeded
+}
+
+func (e *LimitExceededError) ErrorData() any {
+	return e
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.RetryAfter == 0 {
+		return fmt.Sprintf("faucet %s: %s limit exceeded", e.Faucet, e.Limit)
+	}
+	return fmt.Sprintf("faucet %s: %s limit exceeded, retry after %d seconds", e.Faucet, e.Limit, e.RetryAfter)
+}
+
+func newUnknownFaucetError(faucetAddress types.Address) error {
+	return &transport.InvalidParamsError{Message: fmt.Sprintf("unknown faucet %s", faucetAddress)}
+}
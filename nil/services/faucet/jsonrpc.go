@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -21,51 +25,119 @@ type API interface {
 }
 
 type APIImpl struct {
-	client client.Client
+	client            client.Client
+	limiter           *limiter
+	trustForwardedFor bool
 
-	// Requests are served by one which is the easiest way to avoid seqno gaps.
+	// faucets is filled on creation and is read-only afterwards.
+	faucets map[types.Address]*faucetState
+}
+
+type faucetState struct {
+	// Requests to a faucet are served one by one which is the easiest way to avoid seqno gaps.
 	mu sync.Mutex
 	// As long as we have only one faucet, we can manage seqnos locally
 	// which can be more correct than getting tx count each time.
-	seqnos map[types.Address]types.Seqno
+	seqno types.Seqno
 }
 
 var _ API = (*APIImpl)(nil)
 
-func NewAPI(client client.Client) *APIImpl {
-	return &APIImpl{
-		client: client,
-		seqnos: make(map[types.Address]types.Seqno),
+// NewAPI creates the faucet API. The grants are recorded in the database, which must not be closed while the API is used.
+// The database may be nil if the config is nil, i.e. no limits are applied.
+func NewAPI(ctx context.Context, cfg *Config, client client.Client, database db.DB) (*APIImpl, error) {
+	limits, err := cfg.faucetLimits()
+	if err != nil {
+		return nil, err
 	}
+	limiter := newLimiter(database, limits)
+	if err := limiter.load(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load grants: %w", err)
+	}
+
+	faucets := make(map[types.Address]*faucetState)
+	for _, addr := range types.GetTokens() {
+		faucets[addr] = &faucetState{}
+	}
+	return &APIImpl{
+		client:            client,
+		limiter:           limiter,
+		trustForwardedFor: cfg != nil && cfg.TrustForwardedFor,
+		faucets:           faucets,
+	}, nil
 }
 
 func (c *APIImpl) fetchSeqno(ctx context.Context, addr types.Address) (types.Seqno, error) {
 	return c.client.GetTransactionCount(ctx, addr, transport.BlockNumberOrHash(transport.PendingBlock))
 }
 
-func (c *APIImpl) getOrFetchSeqno(ctx context.Context, faucetAddress types.Address) (types.Seqno, error) {
+func (c *APIImpl) getOrFetchSeqno(ctx context.Context, faucetAddress types.Address, faucet *faucetState) (types.Seqno, error) {
 	// todo: currently, no better solution than to fetch seqno each time
 	// Keeping in-memory seqno is not reliable because of possible desync with the chain.
-	// seqno, ok := c.seqnos[faucetAddress]
-	// if ok {
-	//	return seqno, nil
-	// }
+	// return faucet.seqno, nil
 
 	seqno, err := c.fetchSeqno(ctx, faucetAddress)
 	if err != nil {
 		return 0, err
 	}
 
-	c.seqnos[faucetAddress] = seqno
+	faucet.seqno = seqno
 
 	return seqno, nil
 }
 
+// clientIP returns the IP of the client that sent the request or an empty string if it is unknown.
+func (c *APIImpl) clientIP(ctx context.Context) string {
+	if c.trustForwardedFor {
+		if headers, ok := ctx.Value(transport.HeadersContextKey).(http.Header); ok {
+			// The first address is the original client, the others are the proxies.
+			if ip, _, _ := strings.Cut(headers.Get("X-Forwarded-For"), ","); strings.TrimSpace(ip) != "" {
+				return strings.TrimSpace(ip)
+			}
+		}
+	}
+	addr, _ := ctx.Value(transport.RemoteAddrContextKey).(string)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
 func (c *APIImpl) TopUpViaFaucet(ctx context.Context, faucetAddress, contractAddressTo types.Address, amount types.Value) (common.Hash, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	faucet, ok := c.faucets[faucetAddress]
+	if !ok {
+		return common.EmptyHash, newUnknownFaucetError(faucetAddress)
+	}
+
+	faucet.mu.Lock()
+	defer faucet.mu.Unlock()
 
-	seqno, err := c.getOrFetchSeqno(ctx, faucetAddress)
+	grant, err := c.limiter.reserve(faucetAddress, contractAddressTo, c.clientIP(ctx), amount)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	hash, err := c.sendTopUp(ctx, faucetAddress, faucet, contractAddressTo, amount)
+	if err != nil {
+		c.limiter.release(grant)
+		return common.EmptyHash, err
+	}
+
+	if grant != nil {
+		grant.TxnHash = hash
+	}
+	if err := c.limiter.record(ctx, grant); err != nil {
+		return common.EmptyHash, fmt.Errorf("failed to record grant of transaction %s: %w", hash, err)
+	}
+
+	return hash, nil
+}
+
+// sendTopUp sends the withdrawal transaction of the faucet, it must be called under the lock of the faucet.
+func (c *APIImpl) sendTopUp(
+	ctx context.Context, faucetAddress types.Address, faucet *faucetState, contractAddressTo types.Address, amount types.Value,
+) (common.Hash, error) {
+	seqno, err := c.getOrFetchSeqno(ctx, faucetAddress, faucet)
 	if err != nil {
 		return common.EmptyHash, err
 	}
@@ -116,8 +188,7 @@ func (c *APIImpl) TopUpViaFaucet(ctx context.Context, faucetAddress, contractAdd
 		seqno = actualSeqno
 	}
 
-	c.seqnos[faucetAddress] = seqno + 1
-
+	faucet.seqno = seqno + 1
 	return hash, nil
 }
 
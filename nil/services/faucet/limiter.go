@@ -0,0 +1,245 @@
+package faucet
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// grantsTable stores the top-ups sent by the faucets. Key: unix time in nanoseconds (big-endian), Value: Grant.
+const grantsTable db.TableName = "FaucetGrants"
+
+// Grant is a record of a top-up sent by a faucet.
+type Grant struct {
+	Faucet  types.Address `json:"faucet"`
+	To      types.Address `json:"to"`
+	Client  string        `json:"client,omitempty"`
+	Amount  types.Value   `json:"amount"`
+	Time    time.Time     `json:"time"`
+	TxnHash common.Hash   `json:"txnHash"`
+}
+
+func grantKey(t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano()))
+}
+
+// limiter checks the requests against the limits of the faucets and records the grants.
+// The recent grants are kept in memory, the DB is used to restore them on restart.
+type limiter struct {
+	database db.DB
+	limits   map[types.Address]*Limits
+	// retention is the longest window of the limits, older grants are not needed for the checks.
+	retention time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex
+	// grants are the grants of the last retention period in the order of their time.
+	grants []*Grant
+}
+
+func newLimiter(database db.DB, limits map[types.Address]*Limits) *limiter {
+	l := &limiter{
+		database: database,
+		limits:   limits,
+		now:      time.Now,
+	}
+	for _, lim := range limits {
+		l.retention = max(l.retention, lim.window())
+	}
+	return l
+}
+
+// load restores the recent grants from the DB.
+func (l *limiter) load(ctx context.Context) error {
+	if l.retention == 0 {
+		return nil
+	}
+
+	tx, err := l.database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	iter, err := tx.Range(grantsTable, grantKey(l.now().Add(-l.retention)), nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.HasNext() {
+		_, value, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		grant := new(Grant)
+		if err := json.Unmarshal(value, grant); err != nil {
+			return err
+		}
+		l.grants = append(l.grants, grant)
+	}
+	return nil
+}
+
+// check returns LimitExceededError if the top-up would exceed a limit of the faucet.
+// Grants without the client IP are not counted by the client limits.
+func (l *limiter) check(faucet, to types.Address, client string, amount types.Value) error {
+	lim, ok := l.limits[faucet]
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+	return l.checkLocked(lim, faucet, to, client, amount, now)
+}
+
+// reserve checks the top-up like check and counts it as granted until it is recorded or released,
+// so that the top-ups being sent can't exceed the limits together.
+// The returned grant is nil if the faucet has no limits.
+func (l *limiter) reserve(faucet, to types.Address, client string, amount types.Value) (*Grant, error) {
+	lim, ok := l.limits[faucet]
+	if !ok {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+	if err := l.checkLocked(lim, faucet, to, client, amount, now); err != nil {
+		return nil, err
+	}
+
+	// The time is the key of the grant, so it must be unique.
+	grant := &Grant{Faucet: faucet, To: to, Client: client, Amount: amount, Time: now}
+	if n := len(l.grants); n > 0 && !grant.Time.After(l.grants[n-1].Time) {
+		grant.Time = l.grants[n-1].Time.Add(time.Nanosecond)
+	}
+	l.grants = append(l.grants, grant)
+	return grant, nil
+}
+
+// release drops the reserved grant whose top-up was not sent.
+func (l *limiter) release(grant *Grant) {
+	if grant == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.grants = slices.DeleteFunc(l.grants, func(g *Grant) bool {
+		return g == grant
+	})
+}
+
+func (l *limiter) checkLocked(lim *Limits, faucet, to types.Address, client string, amount types.Value, now time.Time) error {
+	from := now.Add(-lim.window())
+	var byAddress, byClient []*Grant
+	for _, grant := range l.grants {
+		if grant.Faucet != faucet || grant.Time.Before(from) {
+			continue
+		}
+		if grant.To == to {
+			byAddress = append(byAddress, grant)
+		}
+		if client != "" && grant.Client == client {
+			byClient = append(byClient, grant)
+		}
+	}
+
+	newError := func(limit string, retryAt time.Time) error {
+		err := &LimitExceededError{Faucet: faucet, Limit: limit}
+		if !retryAt.IsZero() {
+			err.RetryAfter = uint64(math.Ceil(retryAt.Sub(now).Seconds()))
+		}
+		return err
+	}
+
+	if lim.MaxRequestsPerAddress > 0 && len(byAddress) >= lim.MaxRequestsPerAddress {
+		return newError(LimitRequestsPerAddress, byAddress[len(byAddress)-lim.MaxRequestsPerAddress].Time.Add(lim.window()))
+	}
+	if client != "" && lim.MaxRequestsPerClient > 0 && len(byClient) >= lim.MaxRequestsPerClient {
+		return newError(LimitRequestsPerClient, byClient[len(byClient)-lim.MaxRequestsPerClient].Time.Add(lim.window()))
+	}
+	if !lim.MaxAmount.IsZero() {
+		if retryAt, ok := amountRetryTime(byAddress, amount, lim); !ok {
+			return newError(LimitAmountPerAddress, retryAt)
+		}
+		if client != "" {
+			if retryAt, ok := amountRetryTime(byClient, amount, lim); !ok {
+				return newError(LimitAmountPerClient, retryAt)
+			}
+		}
+	}
+	return nil
+}
+
+// amountRetryTime reports whether the amount can be granted in addition to the grants.
+// If it can't, the time when enough grants leave the window is returned,
+// or zero time if the amount exceeds the limit by itself.
+func amountRetryTime(grants []*Grant, amount types.Value, lim *Limits) (time.Time, bool) {
+	if amount.Cmp(lim.MaxAmount) > 0 {
+		return time.Time{}, false
+	}
+	total := amount
+	for _, grant := range grants {
+		total = total.Add(grant.Amount)
+	}
+	if total.Cmp(lim.MaxAmount) <= 0 {
+		return time.Time{}, true
+	}
+	for _, grant := range grants {
+		total = total.Sub(grant.Amount)
+		if total.Cmp(lim.MaxAmount) <= 0 {
+			return grant.Time.Add(lim.window()), false
+		}
+	}
+	return time.Time{}, false
+}
+
+// record stores the reserved grant of the sent top-up.
+func (l *limiter) record(ctx context.Context, grant *Grant) error {
+	if grant == nil {
+		return nil
+	}
+
+	value, err := json.Marshal(grant)
+	if err != nil {
+		return err
+	}
+
+	tx, err := l.database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Put(grantsTable, grantKey(grant.Time), value); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// prune drops the grants that can't affect the checks anymore from memory. The DB keeps the full record.
+func (l *limiter) prune(now time.Time) {
+	from := now.Add(-l.retention)
+	i := 0
+	for i < len(l.grants) && l.grants[i].Time.Before(from) {
+		i++
+	}
+	l.grants = l.grants[i:]
+}
@@ -0,0 +1,170 @@
+package faucet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	cfg := &Config{
+		DefaultLimits: &Limits{
+			Window:                time.Hour,
+			MaxRequestsPerAddress: 2,
+			MaxRequestsPerClient:  3,
+			MaxAmount:             types.NewValueFromUint64(1000),
+		},
+		Limits: map[string]*Limits{
+			"ETH": nil,
+		},
+	}
+	limits, err := cfg.faucetLimits()
+	require.NoError(t, err)
+
+	now := time.Unix(1_000_000, 0)
+	newTestLimiter := func() *limiter {
+		t.Helper()
+
+		l := newLimiter(database, limits)
+		l.now = func() time.Time { return now }
+		require.NoError(t, l.load(ctx))
+		return l
+	}
+	l := newTestLimiter()
+
+	faucet := types.FaucetAddress
+	addr1 := types.GenerateRandomAddress(types.BaseShardId)
+	addr2 := types.GenerateRandomAddress(types.BaseShardId)
+	amount := types.NewValueFromUint64(100)
+
+	// grant records a top-up a minute after the previous event.
+	grant := func(to types.Address, client string, amount types.Value) {
+		t.Helper()
+
+		now = now.Add(time.Minute)
+		g, err := l.reserve(faucet, to, client, amount)
+		require.NoError(t, err)
+		require.NoError(t, l.record(ctx, g))
+	}
+	requireLimit := func(err error, limit string, retryAfter uint64) {
+		t.Helper()
+
+		var limitErr *LimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, faucet, limitErr.Faucet)
+		assert.Equal(t, limit, limitErr.Limit)
+		assert.Equal(t, retryAfter, limitErr.RetryAfter)
+	}
+
+	t.Run("RequestsPerAddress", func(t *testing.T) {
+		grant(addr1, "1.1.1.1", amount)
+		now = now.Add(10 * time.Minute)
+		grant(addr1, "2.2.2.2", amount)
+
+		// The first grant leaves the window in 1 + 60 - 12 minutes.
+		requireLimit(l.check(faucet, addr1, "3.3.3.3", amount), LimitRequestsPerAddress, 49*60)
+
+		// Other faucets are counted separately.
+		require.NoError(t, l.check(types.BtcFaucetAddress, addr1, "3.3.3.3", amount))
+		// The faucets without limits are not restricted.
+		require.NoError(t, l.check(types.EthFaucetAddress, addr1, "3.3.3.3", amount))
+	})
+
+	t.Run("RequestsPerClient", func(t *testing.T) {
+		grant(addr2, "1.1.1.1", amount)
+		grant(types.GenerateRandomAddress(types.BaseShardId), "1.1.1.1", amount)
+
+		requireLimit(l.check(faucet, types.GenerateRandomAddress(types.BaseShardId), "1.1.1.1", amount),
+			LimitRequestsPerClient, 47*60)
+
+		// The requests of unknown clients are not limited by the client limits.
+		require.NoError(t, l.check(faucet, types.GenerateRandomAddress(types.BaseShardId), "", amount))
+	})
+
+	t.Run("Amount", func(t *testing.T) {
+		// addr2 got 100 from 1.1.1.1 which has got 300 in total.
+		require.NoError(t, l.check(faucet, addr2, "4.4.4.4", types.NewValueFromUint64(900)))
+		requireLimit(l.check(faucet, addr2, "4.4.4.4", types.NewValueFromUint64(901)), LimitAmountPerAddress, 59*60)
+		requireLimit(l.check(faucet, addr2, "4.4.4.4", types.NewValueFromUint64(1001)), LimitAmountPerAddress, 0)
+
+		grant(types.GenerateRandomAddress(types.BaseShardId), "5.5.5.5", types.NewValueFromUint64(600))
+		grant(types.GenerateRandomAddress(types.BaseShardId), "5.5.5.5", types.NewValueFromUint64(300))
+		requireLimit(l.check(faucet, types.GenerateRandomAddress(types.BaseShardId), "5.5.5.5", types.NewValueFromUint64(200)),
+			LimitAmountPerClient, 59*60)
+	})
+
+	t.Run("Reservation", func(t *testing.T) {
+		addr := types.GenerateRandomAddress(types.BaseShardId)
+
+		// The top-ups being sent are counted before they are recorded.
+		first, err := l.reserve(faucet, addr, "6.6.6.6", types.NewValueFromUint64(600))
+		require.NoError(t, err)
+		_, err = l.reserve(faucet, addr, "7.7.7.7", types.NewValueFromUint64(600))
+		var limitErr *LimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, LimitAmountPerAddress, limitErr.Limit)
+
+		// The top-up that failed to be sent is not counted.
+		l.release(first)
+		second, err := l.reserve(faucet, addr, "7.7.7.7", types.NewValueFromUint64(600))
+		require.NoError(t, err)
+		l.release(second)
+
+		// Nothing is reserved for the faucets without limits.
+		g, err := l.reserve(types.EthFaucetAddress, addr, "6.6.6.6", amount)
+		require.NoError(t, err)
+		assert.Nil(t, g)
+	})
+
+	t.Run("Restart", func(t *testing.T) {
+		l = newTestLimiter()
+		requireLimit(l.check(faucet, addr1, "3.3.3.3", amount), LimitRequestsPerAddress, 45*60)
+	})
+
+	t.Run("WindowPassed", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		require.NoError(t, l.check(faucet, addr1, "1.1.1.1", types.NewValueFromUint64(1000)))
+
+		// The old grants are not loaded on restart.
+		l = newTestLimiter()
+		assert.Empty(t, l.grants)
+	})
+}
+
+func TestConfigFaucetLimits(t *testing.T) {
+	t.Parallel()
+
+	limits := &Limits{MaxRequestsPerAddress: 1}
+
+	res, err := (*Config)(nil).faucetLimits()
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	res, err = (&Config{Limits: map[string]*Limits{
+		"USDT":                       limits,
+		types.BtcFaucetAddress.Hex(): limits,
+	}}).faucetLimits()
+	require.NoError(t, err)
+	assert.Equal(t, map[types.Address]*Limits{
+		types.UsdtFaucetAddress: limits,
+		types.BtcFaucetAddress:  limits,
+	}, res)
+
+	_, err = (&Config{Limits: map[string]*Limits{"DOGE": limits}}).faucetLimits()
+	require.Error(t, err)
+
+	_, err = (&Config{Limits: map[string]*Limits{types.MainSmartAccountAddress.Hex(): limits}}).faucetLimits()
+	require.Error(t, err)
+}
@@ -2,23 +2,54 @@ package faucet
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/services/rpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 )
 
 type Service struct {
-	impl API
+	impl     API
+	database db.DB
 }
 
-func NewService(client client.Client) (*Service, error) {
-	return &Service{impl: NewAPI(client)}, nil
+// NewService creates the faucet service. A nil config means that no limits are applied.
+func NewService(ctx context.Context, cfg *Config, client client.Client) (*Service, error) {
+	s := &Service{}
+	if cfg != nil {
+		var err error
+		if cfg.DbPath != "" {
+			s.database, err = db.NewBadgerDb(cfg.DbPath)
+		} else {
+			s.database, err = db.NewBadgerDbInMemory()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open faucet db: %w", err)
+		}
+	}
+
+	impl, err := NewAPI(ctx, cfg, client, s.database)
+	if err != nil {
+		s.close()
+		return nil, err
+	}
+	s.impl = impl
+	return s, nil
+}
+
+func (s *Service) close() {
+	if s.database != nil {
+		s.database.Close()
+	}
 }
 
 func (s *Service) Run(ctx context.Context, endpoint string) error {
+	defer s.close()
+
 	err := s.startRpcServer(ctx, endpoint)
 	return err
 }
@@ -41,6 +72,7 @@ func (s *Service) startRpcServer(ctx context.Context, endpoint string) error {
 		TraceRequests:   true,
 		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
 		HttpCORSDomain:  []string{"*"},
+		KeepHeaders:     []string{"X-Forwarded-For"},
 	}
 
 	apiList := []transport.API{
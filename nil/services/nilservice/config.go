@@ -16,6 +16,8 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
 var Logger = logging.NewLogger("config")
@@ -42,10 +44,20 @@ type Config struct {
 	// RPC
 	RPCPort        int                   `yaml:"rpcPort,omitempty"`
 	BootstrapPeers network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
+	// TxnRelay makes the node send the transactions to the shards it doesn't serve via the pub-sub relay
+	// instead of the requests to the nodes that serve them.
+	TxnRelay bool `yaml:"txnRelay,omitempty"`
+	// MaxLogsBlockRange limits the number of blocks in the range of eth_getLogs, zero means the default.
+	MaxLogsBlockRange uint64 `yaml:"maxLogsBlockRange,omitempty"`
+	// MaxLogsPerQuery limits the number of logs returned by eth_getLogs, zero means the default.
+	MaxLogsPerQuery uint64 `yaml:"maxLogsPerQuery,omitempty"`
 
 	// Profiling
 	PprofPort int `yaml:"pprofPort,omitempty"`
 
+	// MaxGasInBlock is the gas limit of the blocks produced by the collators.
+	MaxGasInBlock types.Gas `yaml:"maxGasInBlock,omitempty"`
+
 	// Admin
 	AdminSocketPath string `yaml:"adminSocket,omitempty"`
 
@@ -78,6 +90,10 @@ type Config struct {
 	Replay    *ReplayConfig              `yaml:"replay,omitempty"`
 	Cometa    *cometa.Config             `yaml:"cometa,omitempty"`
 	RpcNode   *RpcNodeConfig             `yaml:"rpcNode,omitempty"`
+	TxnPool   *txnpool.Config            `yaml:"txnPool,omitempty"`
+
+	// StatePruning removes the historical state of the shards. Archive nodes always keep the full history.
+	StatePruning *execution.StatePruningConfig `yaml:"statePruning,omitempty"`
 
 	L1Fetcher rollup.L1BlockFetcher `yaml:"-"`
 
@@ -107,7 +123,15 @@ func NewDefaultConfig() *Config {
 		Telemetry: telemetry.NewDefaultConfig(),
 		Replay:    NewDefaultReplayConfig(),
 		RpcNode:   NewDefaultRpcNodeConfig(),
+		TxnPool:   txnpool.NewDefaultConfig(),
 		PprofPort: int(DefaultPprofPort),
+
+		MaxGasInBlock: types.DefaultMaxGasInBlock,
+
+		MaxLogsBlockRange: filters.DefaultMaxLogsBlockRange,
+		MaxLogsPerQuery:   filters.DefaultMaxLogsPerQuery,
+
+		StatePruning: execution.NewDefaultStatePruningConfig(),
 	}
 }
 
@@ -132,6 +156,16 @@ func NewDefaultRpcNodeConfig() *RpcNodeConfig {
 	return &RpcNodeConfig{}
 }
 
+// TxnPoolConfig returns the configuration of the transaction pool of the given shard.
+func (c *Config) TxnPoolConfig(shardId types.ShardId) txnpool.Config {
+	if c.TxnPool == nil {
+		return txnpool.NewConfig(shardId)
+	}
+	cfg := *c.TxnPool
+	cfg.ShardId = shardId
+	return cfg
+}
+
 func (c *Config) GetMyShards() []uint {
 	shards := c.MyShards
 	if len(shards) > 0 {
@@ -174,6 +208,10 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.StatePruning.Enabled() && (c.RunMode == ArchiveRunMode || c.RunMode == BlockReplayRunMode) {
+		return errors.New("state pruning is not supported by archive and block replay nodes, they need the full state history")
+	}
+
 	return nil
 }
 
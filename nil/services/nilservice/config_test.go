@@ -39,3 +39,17 @@ func TestValidateInvalidNShards(t *testing.T) {
 	cfg.NShards = 2
 	require.NoError(t, cfg.Validate())
 }
+
+func TestValidateStatePruning(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDefaultConfig()
+	cfg.StatePruning.KeepBlocks = 100
+	require.NoError(t, cfg.Validate())
+
+	cfg.RunMode = ArchiveRunMode
+	require.ErrorContains(t, cfg.Validate(), "state pruning is not supported")
+
+	cfg.StatePruning.KeepBlocks = 0
+	require.NoError(t, cfg.Validate())
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/faucet"
 	"github.com/NilFoundation/nil/nil/services/rollup"
 	"github.com/NilFoundation/nil/nil/services/rpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
@@ -40,7 +41,10 @@ import (
 // syncer will pull blocks actively if no blocks appear for 5 rounds
 const syncTimeoutFactor = 5
 
-func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db db.ReadOnlyDB, client client.Client) error {
+func startRpcServer(
+	ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool, client client.Client,
+) error {
 	logger := logging.NewLogger("RPC")
 
 	addr := cfg.HttpUrl
@@ -49,30 +53,50 @@ func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db
 	}
 
 	httpConfig := &httpcfg.HttpCfg{
-		HttpURL:         addr,
-		HttpCompression: true,
-		TraceRequests:   true,
-		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
-		HttpCORSDomain:  []string{"*"},
-		KeepHeaders:     []string{"Client-Version", "Client-Type", "X-UID"},
+		HttpURL:          addr,
+		HttpCompression:  true,
+		TraceRequests:    true,
+		WebsocketEnabled: true,
+		HTTPTimeouts:     httpcfg.DefaultHTTPTimeouts,
+		HttpCORSDomain:   []string{"*"},
+		KeepHeaders:      []string{"Client-Version", "Client-Type", "X-UID"},
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	pollBlocksForLogs := cfg.RunMode == NormalRunMode
 
+	ethApiConfig := jsonrpc.EthAPIConfig{
+		MaxGasInBlock: cfg.MaxGasInBlock,
+		FeeCalculator: cfg.FeeCalculator,
+		LogQueryLimits: filters.LogQueryLimits{
+			MaxBlockRange: cfg.MaxLogsBlockRange,
+			MaxLogs:       cfg.MaxLogsPerQuery,
+		},
+	}
 	var ethApiService any
 	if cfg.RunMode == NormalRunMode || cfg.RunMode == RpcRunMode {
-		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents, ethApiConfig)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	} else {
-		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents, ethApiConfig)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	}
+
+	// Subscriptions read blocks from the local database, so they are only served for local shards
+	var subscriptionShards []types.ShardId
+	if cfg.RunMode == NormalRunMode || cfg.RunMode == ArchiveRunMode {
+		for _, shardId := range cfg.GetMyShards() {
+			subscriptionShards = append(subscriptionShards, types.ShardId(shardId))
+		}
+	}
+	subscriptionImpl := jsonrpc.NewEthSubscriptionAPI(ctx, db, subscriptionShards, txnPools)
+	defer subscriptionImpl.Shutdown()
 	defer cancel()
 
 	debugImpl := jsonrpc.NewDebugAPI(rawApi, logger)
+	txPoolImpl := jsonrpc.NewTxPoolAPI(rawApi, logger)
 
 	apiList := []transport.API{
 		{
@@ -81,12 +105,24 @@ func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db
 			Service:   ethApiService,
 			Version:   "1.0",
 		},
+		{
+			Namespace: "eth",
+			Public:    true,
+			Service:   jsonrpc.EthSubscriptionAPI(subscriptionImpl),
+			Version:   "1.0",
+		},
 		{
 			Namespace: "debug",
 			Public:    true,
 			Service:   jsonrpc.DebugAPI(debugImpl),
 			Version:   "1.0",
 		},
+		{
+			Namespace: "txpool",
+			Public:    true,
+			Service:   jsonrpc.TxPoolAPI(txPoolImpl),
+			Version:   "1.0",
+		},
 	}
 
 	if cfg.Cometa != nil {
@@ -98,7 +134,7 @@ func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db
 	}
 
 	if cfg.IsFaucetApiEnabled() {
-		faucet, err := faucet.NewService(client)
+		faucet, err := faucet.NewService(ctx, nil, client)
 		if err != nil {
 			return fmt.Errorf("failed to create faucet service: %w", err)
 		}
@@ -118,10 +154,11 @@ func startRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db
 	return rpc.StartRpcServer(ctx, httpConfig, apiList, logger, nil)
 }
 
-func startAdminServer(ctx context.Context, cfg *Config) error {
+func startAdminServer(ctx context.Context, cfg *Config, networkManager *network.Manager) error {
 	config := &admin.ServerConfig{
 		Enabled:        cfg.AdminSocketPath != "",
 		UnixSocketPath: cfg.AdminSocketPath,
+		NetworkManager: networkManager,
 	}
 	return admin.StartAdminServer(ctx, config, logging.NewLogger("admin"))
 }
@@ -133,7 +170,23 @@ type ServiceInterop struct {
 	TxnPools map[types.ShardId]txnpool.Pool
 }
 
-func getRawApi(cfg *Config, networkManager *network.Manager, database db.DB, txnPools map[types.ShardId]txnpool.Pool) (*rawapi.NodeApiOverShardApis, error) {
+func createTxnRelay(
+	ctx context.Context, cfg *Config, networkManager *network.Manager, txnPools map[types.ShardId]txnpool.Pool,
+) (*txnpool.Relay, error) {
+	if !cfg.TxnRelay || networkManager == nil {
+		return nil, nil
+	}
+	return txnpool.NewRelay(ctx, txnpool.RelayConfig{
+		NShards:  cfg.NShards,
+		Topology: collate.GetShardTopologyById(cfg.Topology),
+		Pools:    txnPools,
+	}, networkManager)
+}
+
+func getRawApi(
+	cfg *Config, networkManager *network.Manager, database db.DB, txnPools map[types.ShardId]txnpool.Pool,
+	relay *txnpool.Relay,
+) (*rawapi.NodeApiOverShardApis, error) {
 	var myShards []uint
 	switch cfg.RunMode {
 	case BlockReplayRunMode:
@@ -159,6 +212,9 @@ func getRawApi(cfg *Config, networkManager *network.Manager, database db.DB, txn
 			}
 		} else {
 			shardApis[shardId], err = rawapi.NewNetworkRawApiAccessor(shardId, networkManager)
+			if err == nil && relay != nil {
+				shardApis[shardId] = rawapi.NewRelayShardApi(shardApis[shardId], relay)
+			}
 		}
 		if err != nil {
 			return nil, err
@@ -439,14 +495,20 @@ func CreateNode(ctx context.Context, name string, cfg *Config, database db.DB, i
 	}
 
 	funcs = append(funcs, func(ctx context.Context) error {
-		if err := startAdminServer(ctx, cfg); err != nil {
+		if err := startAdminServer(ctx, cfg, networkManager); err != nil {
 			logger.Error().Err(err).Msg("Admin server goroutine failed")
 			return err
 		}
 		return nil
 	})
 
-	rawApi, err := getRawApi(cfg, networkManager, database, txnPools)
+	relay, err := createTxnRelay(ctx, cfg, networkManager, txnPools)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create transaction relay")
+		return nil, err
+	}
+
+	rawApi, err := getRawApi(cfg, networkManager, database, txnPools, relay)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create raw API")
 		return nil, err
@@ -465,7 +527,7 @@ func CreateNode(ctx context.Context, name string, cfg *Config, database db.DB, i
 					return fmt.Errorf("failed to create node client: %w", err)
 				}
 			}
-			if err := startRpcServer(ctx, cfg, rawApi, database, cl); err != nil {
+			if err := startRpcServer(ctx, cfg, rawApi, database, txnPools, cl); err != nil {
 				logger.Error().Err(err).Msg("RPC server goroutine failed")
 				return err
 			}
@@ -567,7 +629,7 @@ func createValidators(ctx context.Context, cfg *Config, database db.DB, networkM
 		var err error
 		var txpool *txnpool.TxnPool
 		if cfg.IsShardActive(shardId) {
-			txpool, err = txnpool.New(ctx, txnpool.NewConfig(shardId), networkManager)
+			txpool, err = txnpool.New(ctx, cfg.TxnPoolConfig(shardId), networkManager)
 			if err != nil {
 				return nil, err
 			}
@@ -634,9 +696,11 @@ func createShards(
 func createCollateParams(shard types.ShardId, cfg *Config, collatorTickPeriod time.Duration) *collate.Params {
 	return &collate.Params{
 		BlockGeneratorParams: cfg.BlockGeneratorParams(shard),
+		MaxGasInBlock:        cfg.MaxGasInBlock,
 		CollatorTickPeriod:   collatorTickPeriod,
 		Timeout:              collatorTickPeriod,
 		Topology:             collate.GetShardTopologyById(cfg.Topology),
 		L1Fetcher:            cfg.L1Fetcher,
+		StatePruning:         cfg.StatePruning,
 	}
 }
@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -21,7 +20,6 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
-	"github.com/holiman/uint256"
 )
 
 var logger = logging.NewLogger("filters")
@@ -38,9 +36,11 @@ type Filter struct {
 
 // FilterQuery contains options for contract log filtering.
 type FilterQuery struct {
-	BlockHash *common.Hash    // used by eth_getLogs, return logs only from block with this hash
-	FromBlock *uint256.Int    // beginning of the queried range, nil means genesis block
-	ToBlock   *uint256.Int    // end of the range, nil means the latest block
+	BlockHash *common.Hash // used by eth_getLogs, return logs only from block with this hash
+	// FromBlock and ToBlock are the bounds of the queried range, nil means the latest block.
+	// Block tags are resolved against the last block of the shard when the query runs.
+	FromBlock *transport.BlockNumber
+	ToBlock   *transport.BlockNumber
 	Addresses []types.Address // restricts matches to events created by specific contracts
 
 	// The Topic list restricts matches to particular event topics. Each event has a list
@@ -69,18 +69,29 @@ type FiltersManager struct {
 	blockSubs map[SubscriptionID]chan<- *types.Block
 	mutex     sync.RWMutex
 	lastHash  common.Hash
+	limits    LogQueryLimits
 	wg        sync.WaitGroup
 }
 
 func NewFiltersManager(ctx context.Context, db db.ReadOnlyDB, noPolling bool) *FiltersManager {
+	return NewShardFiltersManager(ctx, db, types.MainShardId, noPolling)
+}
+
+// NewShardFiltersManager creates a filters manager that tracks blocks of the given shard.
+func NewShardFiltersManager(ctx context.Context, db db.ReadOnlyDB, shardId types.ShardId, noPolling bool) *FiltersManager {
 	f := &FiltersManager{
 		ctx:       ctx,
 		db:        db,
+		shardId:   shardId,
 		filters:   make(map[SubscriptionID]*Filter),
 		blockSubs: make(map[SubscriptionID]chan<- *types.Block),
 		lastHash:  common.EmptyHash,
 	}
 
+	// Start from the current head, so that subscribers get only the blocks produced after they subscribed
+	// instead of the whole history of the shard.
+	f.initLastHash()
+
 	if !noPolling {
 		f.wg.Add(1)
 		go f.PollBlocks(200 * time.Millisecond)
@@ -89,6 +100,11 @@ func NewFiltersManager(ctx context.Context, db db.ReadOnlyDB, noPolling bool) *F
 	return f
 }
 
+// SetLogQueryLimits sets the limits of the log queries. It must be called before the manager is used.
+func (f *FiltersManager) SetLogQueryLimits(limits LogQueryLimits) {
+	f.limits = limits
+}
+
 func (f *FiltersManager) WaitForShutdown() {
 	f.wg.Wait()
 }
@@ -193,46 +209,38 @@ func (m *FiltersManager) PollBlocks(delay time.Duration) {
 	}
 }
 
-// / If FromBlock is set in the filter, then processBlocksRange processes all blocks in the range [FromBlock..ToBlock].
+// processBlocksRange sends the logs of the blocks in the range [FromBlock..ToBlock] matching the filter to its output.
 func (m *FiltersManager) processBlocksRange(filter *Filter) error {
-	tx, err := m.db.CreateRoTx(m.ctx)
+	logs, err := m.FindLogs(m.ctx, m.shardId, filter.query)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
-
-	var fromBlockNum, lastBlockNum uint64
-
-	if filter.query.FromBlock != nil {
-		fromBlockNum = filter.query.FromBlock.Uint64()
-	} else {
-		fromBlockNum = 0
+	for _, log := range logs {
+		filter.output <- log
 	}
+	return nil
+}
 
-	if filter.query.ToBlock != nil {
-		lastBlockNum = filter.query.ToBlock.Uint64()
-	} else {
-		lastBlock, _, err := db.ReadLastBlock(tx, m.shardId)
-		if err != nil {
-			return err
-		}
-		lastBlockNum = uint64(lastBlock.Id)
+// FindLogs returns the logs of the shard matching the query.
+func (m *FiltersManager) FindLogs(ctx context.Context, shardId types.ShardId, query *FilterQuery) ([]*MetaLog, error) {
+	tx, err := m.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	for ; fromBlockNum <= lastBlockNum; fromBlockNum++ {
-		block, err := db.ReadBlockByNumber(tx, m.shardId, types.BlockNumber(fromBlockNum))
-		if err != nil {
-			return err
-		}
-		receipts, err := m.readReceipts(tx, block)
-		if err != nil {
-			return err
-		}
-		if err = m.processFilter(block, filter, receipts); err != nil {
-			return err
-		}
+	return FindLogs(tx, shardId, query, m.limits)
+}
+
+// GetFilterLogs returns all the logs matching the criteria of the filter, not only the ones since the last poll.
+func (m *FiltersManager) GetFilterLogs(ctx context.Context, id SubscriptionID) ([]*MetaLog, error) {
+	m.mutex.RLock()
+	filter, ok := m.filters[id]
+	m.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("filter does not exist: %s", id)
 	}
-	return nil
+	return m.FindLogs(ctx, m.shardId, filter.query)
 }
 
 func (m *FiltersManager) readReceipts(tx db.RoTx, block *types.Block) ([]*types.Receipt, error) {
@@ -262,35 +270,13 @@ func (m *FiltersManager) processBlockHash(lastHash common.Hash) (*types.Block, e
 }
 
 func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, receipts types.Receipts) error {
-	if filter.query.ToBlock != nil && uint64(block.Id) > filter.query.ToBlock.Uint64() {
+	// the filters ending with a block tag follow the head of the shard
+	if to := filter.query.ToBlock; to != nil && !to.IsSpecial() && block.Id > to.BlockNumber() {
 		return nil
 	}
 	for _, receipt := range receipts {
-		if len(filter.query.Addresses) != 0 && !slices.Contains(filter.query.Addresses, receipt.ContractAddress) {
-			continue
-		}
 		for _, log := range receipt.Logs {
-			found := true
-			for i, topics := range filter.query.Topics {
-				if i >= log.TopicsNum() {
-					found = false
-					break
-				}
-				switch len(topics) {
-				case 0:
-					continue
-				case 1: // valid case, process after switch block
-				default:
-					panic("TODO: Topics disjunction isn't supported yet")
-				}
-
-				logTopic := log.Topics[i]
-				if logTopic != topics[0] {
-					found = false
-					break
-				}
-			}
-			if found {
+			if filter.query.matchLog(log) {
 				filter.output <- &MetaLog{log, block.Id}
 			}
 		}
@@ -312,6 +298,17 @@ func (m *FiltersManager) process(block *types.Block, receipts types.Receipts) er
 func (m *FiltersManager) OnNewBlock(block *types.Block) {
 }
 
+func (m *FiltersManager) initLastHash() {
+	lastHash, err := m.getLastBlockHash()
+	if err != nil {
+		if !errors.Is(err, db.ErrKeyNotFound) {
+			logger.Warn().Err(err).Msg("getLastBlockHash failed")
+		}
+		return
+	}
+	m.lastHash = lastHash
+}
+
 func (m *FiltersManager) getLastBlockHash() (common.Hash, error) {
 	tx, err := m.db.CreateRoTx(m.ctx)
 	if err != nil {
@@ -364,13 +361,8 @@ func (args *FilterQuery) UnmarshalJSON(data []byte) error {
 		}
 		args.BlockHash = raw.BlockHash
 	} else {
-		if raw.FromBlock != nil {
-			args.FromBlock = uint256.NewInt(raw.FromBlock.Uint64())
-		}
-
-		if raw.ToBlock != nil {
-			args.ToBlock = uint256.NewInt(raw.ToBlock.Uint64())
-		}
+		args.FromBlock = raw.FromBlock
+		args.ToBlock = raw.ToBlock
 	}
 
 	args.Addresses = []types.Address{}
@@ -445,6 +437,25 @@ func (args *FilterQuery) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON encodes the query in the format accepted by UnmarshalJSON.
+func (args FilterQuery) MarshalJSON() ([]byte, error) {
+	type output struct {
+		BlockHash *common.Hash           `json:"blockHash,omitempty"`
+		FromBlock *transport.BlockNumber `json:"fromBlock,omitempty"`
+		ToBlock   *transport.BlockNumber `json:"toBlock,omitempty"`
+		Addresses []types.Address        `json:"address,omitempty"`
+		Topics    [][]common.Hash        `json:"topics,omitempty"`
+	}
+
+	return json.Marshal(output{
+		BlockHash: args.BlockHash,
+		FromBlock: args.FromBlock,
+		ToBlock:   args.ToBlock,
+		Addresses: args.Addresses,
+		Topics:    args.Topics,
+	})
+}
+
 func decodeAddress(s string) (types.Address, error) {
 	b, err := hexutil.Decode(s)
 	if err == nil && len(b) != types.AddrSize {
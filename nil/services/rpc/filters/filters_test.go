@@ -2,6 +2,7 @@ package filters
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -9,7 +10,8 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/mpt"
 	"github.com/NilFoundation/nil/nil/internal/types"
-	"github.com/holiman/uint256"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -95,6 +97,17 @@ func (s *SuiteFilters) TestMatcherOneReceipt() {
 	s.Equal((<-f.LogsChannel()).Log, logs[0])
 	s.Equal((<-f.LogsChannel()).Log, logs[1])
 	filters.RemoveFilter(id)
+
+	// Only logs with [1 or 3, 2] topics
+	id, f = filters.NewFilter(&FilterQuery{Addresses: []types.Address{address1}, Topics: [][]common.Hash{{{0x01}, {0x03}}, {{0x02}}}})
+	s.NotEmpty(id)
+	s.NotNil(f)
+
+	s.Require().NoError(filters.process(&block, receipts))
+	s.Len(f.output, 2)
+	s.Equal((<-f.LogsChannel()).Log, logs[0])
+	s.Equal((<-f.LogsChannel()).Log, logs[1])
+	filters.RemoveFilter(id)
 }
 
 func (s *SuiteFilters) TestMatcherTwoReceipts() {
@@ -275,6 +288,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	}
 
 	receipt := &types.Receipt{ContractAddress: address, Logs: logsInput}
+	receipts := []*types.Receipt{receipt}
 	receiptEncoded, err := receipt.MarshalSSZ()
 	s.Require().NoError(err)
 	key, err := receipt.HashTreeRoot()
@@ -289,7 +303,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	}
 	blockHash := block.Hash(types.MainShardId)
 	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, blockHash, &block))
-	blockResult := &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block}
+	blockResult := &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block, Receipts: receipts}
 	err = execution.PostprocessBlock(tx, types.MainShardId, blockResult)
 	s.Require().NoError(err)
 
@@ -301,7 +315,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	}
 	blockHash = block.Hash(types.MainShardId)
 	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, blockHash, &block))
-	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block}
+	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block, Receipts: receipts}
 	err = execution.PostprocessBlock(tx, types.MainShardId, blockResult)
 	s.Require().NoError(err)
 
@@ -313,7 +327,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	}
 	blockHash = block.Hash(types.MainShardId)
 	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, blockHash, &block))
-	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block}
+	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block, Receipts: receipts}
 	err = execution.PostprocessBlock(tx, types.MainShardId, blockResult)
 	s.Require().NoError(err)
 
@@ -325,7 +339,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	}
 	blockHash = block.Hash(types.MainShardId)
 	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, blockHash, &block))
-	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block}
+	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block, Receipts: receipts}
 	err = execution.PostprocessBlock(tx, types.MainShardId, blockResult)
 	s.Require().NoError(err)
 	s.Require().NoError(tx.Commit())
@@ -333,8 +347,8 @@ func (s *SuiteFilters) TestBlocksRange() {
 	topics := [][]common.Hash{{{3}}}
 	query := &FilterQuery{
 		BlockHash: nil,
-		FromBlock: uint256.NewInt(1),
-		ToBlock:   uint256.NewInt(2),
+		FromBlock: blockNumber(1),
+		ToBlock:   blockNumber(2),
 		Addresses: []types.Address{address},
 		Topics:    topics,
 	}
@@ -349,7 +363,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	topics = [][]common.Hash{{{3}}}
 	query = &FilterQuery{
 		BlockHash: nil,
-		FromBlock: uint256.NewInt(1),
+		FromBlock: blockNumber(1),
 		ToBlock:   nil,
 		Addresses: []types.Address{address},
 		Topics:    topics,
@@ -363,11 +377,11 @@ func (s *SuiteFilters) TestBlocksRange() {
 	s.Equal(logsInput[0], (<-filter2.LogsChannel()).Log)
 	s.Equal(logsInput[0], (<-filter2.LogsChannel()).Log)
 
-	// Check with toBlock but without fromBlock
+	// Check with toBlock but without fromBlock, which defaults to the latest block
 	query = &FilterQuery{
 		BlockHash: nil,
 		FromBlock: nil,
-		ToBlock:   uint256.NewInt(0),
+		ToBlock:   blockNumber(0),
 		Addresses: []types.Address{address},
 		Topics:    topics,
 	}
@@ -375,8 +389,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	s.Require().NotNil(filter3)
 	s.Require().NotEmpty(id3)
 
-	s.Len(filter3.output, 1)
-	s.Equal(logsInput[0], (<-filter3.LogsChannel()).Log)
+	s.Empty(filter3.output)
 
 	tx, err = s.db.CreateRwTx(s.ctx)
 	s.Require().NoError(err)
@@ -389,7 +402,7 @@ func (s *SuiteFilters) TestBlocksRange() {
 	}
 	blockHash = block.Hash(types.MainShardId)
 	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, blockHash, &block))
-	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block}
+	blockResult = &execution.BlockGenerationResult{BlockHash: blockHash, Block: &block, Receipts: receipts}
 	err = execution.PostprocessBlock(tx, types.MainShardId, blockResult)
 	s.Require().NoError(err)
 	s.Require().NoError(tx.Commit())
@@ -400,6 +413,202 @@ func (s *SuiteFilters) TestBlocksRange() {
 	s.GreaterOrEqual(len(filter2.output), 1)
 }
 
+// writeBlock writes the block with a single receipt containing the logs.
+// Blocks that are not indexed emulate the blocks committed before the log index was introduced.
+func (s *SuiteFilters) writeBlock(tx db.RwTx, id types.BlockNumber, indexed bool, logs ...*types.Log) common.Hash {
+	s.T().Helper()
+
+	receipts := []*types.Receipt{{Logs: logs}}
+	receiptsMpt := mpt.NewDbMPT(tx, types.MainShardId, db.ReceiptTrieTable)
+	receiptEncoded, err := receipts[0].MarshalSSZ()
+	s.Require().NoError(err)
+	s.Require().NoError(receiptsMpt.Set(types.TransactionIndex(0).Bytes(), receiptEncoded))
+
+	block := &types.Block{
+		BlockData: types.BlockData{Id: id, ReceiptsRoot: receiptsMpt.RootHash()},
+		LogsBloom: types.CreateBloom(receipts),
+	}
+	blockHash := block.Hash(types.MainShardId)
+	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, blockHash, block))
+
+	blockResult := &execution.BlockGenerationResult{BlockHash: blockHash, Block: block}
+	if indexed {
+		blockResult.Receipts = receipts
+	}
+	s.Require().NoError(execution.PostprocessBlock(tx, types.MainShardId, blockResult))
+	if !indexed {
+		// Drop the index start written by the postprocessing.
+		s.Require().NoError(tx.Delete(db.LogIndexStartTable, types.MainShardId.Bytes()))
+	}
+	return blockHash
+}
+
+func (s *SuiteFilters) TestFindLogs() {
+	s.filters = NewFiltersManager(s.ctx, s.db, true)
+
+	address1 := types.HexToAddress("0x1111111111")
+	address2 := types.HexToAddress("0x2222222222")
+	log := func(address types.Address, data byte, topics ...common.Hash) *types.Log {
+		return &types.Log{Address: address, Topics: topics, Data: []byte{data}}
+	}
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	logs := [][]*types.Log{
+		{log(address1, 0, common.Hash{1}, common.Hash{2})},
+		{log(address2, 1, common.Hash{2})},
+		{log(address1, 2, common.Hash{2}, common.Hash{1}), log(address2, 3, common.Hash{1})},
+		{},
+		{log(address1, 4, common.Hash{1}, common.Hash{3})},
+	}
+	var hashes []common.Hash
+	for i, blockLogs := range logs {
+		// The first two blocks are not indexed.
+		hashes = append(hashes, s.writeBlock(tx, types.BlockNumber(i), i >= 2, blockLogs...))
+	}
+	s.Require().NoError(tx.Commit())
+
+	start, err := func() (types.BlockNumber, error) {
+		tx, err := s.db.CreateRoTx(s.ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+		return db.ReadLogIndexStart(tx, types.MainShardId)
+	}()
+	s.Require().NoError(err)
+	s.Require().Equal(types.BlockNumber(2), start)
+
+	find := func(query *FilterQuery) []byte {
+		s.T().Helper()
+
+		res, err := s.filters.FindLogs(s.ctx, types.MainShardId, query)
+		s.Require().NoError(err)
+		data := make([]byte, 0, len(res))
+		for _, log := range res {
+			data = append(data, log.Log.Data[0])
+		}
+		return data
+	}
+
+	earliest := blockNumber(0)
+
+	s.Run("All", func() {
+		s.Equal([]byte{0, 1, 2, 3, 4}, find(&FilterQuery{FromBlock: earliest}))
+	})
+
+	s.Run("Address", func() {
+		s.Equal([]byte{0, 2, 4}, find(&FilterQuery{FromBlock: earliest, Addresses: []types.Address{address1}}))
+		s.Equal([]byte{0, 1, 2, 3, 4}, find(&FilterQuery{
+			FromBlock: earliest,
+			Addresses: []types.Address{address1, address2},
+		}))
+	})
+
+	s.Run("TopicPosition", func() {
+		s.Equal([]byte{0, 3, 4}, find(&FilterQuery{FromBlock: earliest, Topics: [][]common.Hash{{{1}}}}))
+		s.Equal([]byte{0, 4}, find(&FilterQuery{FromBlock: earliest, Topics: [][]common.Hash{{}, {{2}, {3}}}}))
+		s.Empty(find(&FilterQuery{FromBlock: earliest, Topics: [][]common.Hash{{}, {}, {{1}}}}))
+	})
+
+	s.Run("AddressAndTopic", func() {
+		s.Equal([]byte{1, 2}, find(&FilterQuery{
+			FromBlock: earliest,
+			Addresses: []types.Address{address2, address1},
+			Topics:    [][]common.Hash{{{2}}},
+		}))
+	})
+
+	s.Run("Range", func() {
+		s.Equal([]byte{1, 2, 3}, find(&FilterQuery{FromBlock: blockNumber(1), ToBlock: blockNumber(3)}))
+		s.Equal([]byte{4}, find(&FilterQuery{FromBlock: blockNumber(4), ToBlock: blockNumber(100)}))
+		s.Empty(find(&FilterQuery{FromBlock: blockNumber(5)}))
+	})
+
+	s.Run("Tags", func() {
+		findJSON := func(query string) []byte {
+			s.T().Helper()
+
+			var q FilterQuery
+			s.Require().NoError(json.Unmarshal([]byte(query), &q))
+			return find(&q)
+		}
+
+		s.Equal([]byte{4}, findJSON(`{"fromBlock":"latest"}`))
+		s.Equal([]byte{4}, findJSON(`{"fromBlock":"pending","toBlock":"pending"}`))
+		s.Equal([]byte{0, 1, 2, 3, 4}, findJSON(`{"fromBlock":"earliest","toBlock":"latest"}`))
+		s.Equal([]byte{2, 3}, findJSON(`{"fromBlock":"0x2","toBlock":"0x3"}`))
+		s.Empty(findJSON(`{"fromBlock":"latest","toBlock":"0x3"}`))
+
+		// The omitted fromBlock is the latest block.
+		s.Equal([]byte{4}, find(&FilterQuery{}))
+		s.Equal([]byte{4}, find(&FilterQuery{ToBlock: blockNumber(100)}))
+	})
+
+	s.Run("BlockHash", func() {
+		s.Equal([]byte{2, 3}, find(&FilterQuery{BlockHash: &hashes[2]}))
+		s.Equal([]byte{3}, find(&FilterQuery{BlockHash: &hashes[2], Addresses: []types.Address{address2}}))
+	})
+
+	s.Run("FilterLogs", func() {
+		id, filter := s.filters.NewFilter(&FilterQuery{FromBlock: earliest, Addresses: []types.Address{address2}})
+		s.Require().NotNil(filter)
+
+		for range 2 {
+			res, err := s.filters.GetFilterLogs(s.ctx, id)
+			s.Require().NoError(err)
+			s.Require().Len(res, 2)
+			s.Equal(logs[1][0], res[0].Log)
+			s.Equal(types.BlockNumber(2), res[1].BlockId)
+		}
+
+		_, err := s.filters.GetFilterLogs(s.ctx, "unknown")
+		s.Require().Error(err)
+	})
+
+	s.Run("Limits", func() {
+		s.filters.SetLogQueryLimits(LogQueryLimits{MaxBlockRange: 3, MaxLogs: 2})
+		defer s.filters.SetLogQueryLimits(LogQueryLimits{})
+
+		_, err := s.filters.FindLogs(s.ctx, types.MainShardId, &FilterQuery{FromBlock: earliest})
+		s.Require().ErrorIs(err, ErrBlockRangeTooLarge)
+
+		// The default range of the queries and the filters is the latest block only.
+		s.Equal([]byte{4}, find(&FilterQuery{}))
+		id, filter := s.filters.NewFilter(&FilterQuery{Addresses: []types.Address{address1}})
+		s.Require().NotNil(filter)
+		res, err := s.filters.GetFilterLogs(s.ctx, id)
+		s.Require().NoError(err)
+		s.Require().Len(res, 1)
+		s.Equal(types.BlockNumber(4), res[0].BlockId)
+
+		// The range ends at the last block.
+		s.Equal([]byte{2, 4}, find(&FilterQuery{
+			FromBlock: blockNumber(2),
+			ToBlock:   blockNumber(100),
+			Addresses: []types.Address{address1},
+		}))
+
+		_, err = s.filters.FindLogs(s.ctx, types.MainShardId,
+			&FilterQuery{FromBlock: blockNumber(1), ToBlock: blockNumber(3)})
+		s.Require().ErrorIs(err, ErrTooManyLogs)
+		s.Equal([]byte{2, 3}, find(&FilterQuery{BlockHash: &hashes[2]}))
+	})
+}
+
+func blockNumber(n int64) *transport.BlockNumber {
+	bn := transport.BlockNumber(n)
+	return &bn
+}
+
+func TestIntersectBlockIds(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []types.BlockNumber{2, 5}, intersectBlockIds([]types.BlockNumber{1, 2, 5, 7}, []types.BlockNumber{2, 3, 5, 8}))
+	require.Empty(t, intersectBlockIds([]types.BlockNumber{1, 2}, nil))
+	require.Equal(t, []types.BlockNumber{1, 3}, uniqueBlockIds([]types.BlockNumber{3, 1, 3, 1}))
+}
+
 func TestFilters(t *testing.T) {
 	t.Parallel()
 
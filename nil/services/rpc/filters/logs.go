@@ -0,0 +1,259 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+const (
+	// DefaultMaxLogsBlockRange is the default limit of the number of blocks in the range of a log query.
+	DefaultMaxLogsBlockRange = 10_000
+	// DefaultMaxLogsPerQuery is the default limit of the number of logs returned by a log query.
+	DefaultMaxLogsPerQuery = 10_000
+)
+
+var (
+	ErrBlockRangeTooLarge = errors.New("block range is too large")
+	ErrTooManyLogs        = errors.New("query returned too many logs")
+)
+
+// LogQueryLimits restricts the resources spent on a single log query. Zero fields mean the default limits.
+type LogQueryLimits struct {
+	// MaxBlockRange is the maximum number of blocks in the range of a query.
+	MaxBlockRange uint64
+	// MaxLogs is the maximum number of logs returned by a query.
+	MaxLogs uint64
+}
+
+func (l LogQueryLimits) maxBlockRange() uint64 {
+	if l.MaxBlockRange == 0 {
+		return DefaultMaxLogsBlockRange
+	}
+	return l.MaxBlockRange
+}
+
+func (l LogQueryLimits) maxLogs() uint64 {
+	if l.MaxLogs == 0 {
+		return DefaultMaxLogsPerQuery
+	}
+	return l.MaxLogs
+}
+
+// matchLog reports whether the log satisfies the address and topic criteria of the query.
+func (q *FilterQuery) matchLog(log *types.Log) bool {
+	if len(q.Addresses) != 0 && !slices.Contains(q.Addresses, log.Address) {
+		return false
+	}
+	for i, topics := range q.Topics {
+		if i >= log.TopicsNum() {
+			return false
+		}
+		if len(topics) != 0 && !slices.Contains(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchBloom reports whether the block with the bloom can contain logs matching the query.
+func (q *FilterQuery) matchBloom(bloom types.Bloom) bool {
+	if len(q.Addresses) != 0 && !slices.ContainsFunc(q.Addresses, func(address types.Address) bool {
+		return types.BloomLookup(bloom, address)
+	}) {
+		return false
+	}
+	for _, topics := range q.Topics {
+		if len(topics) != 0 && !slices.ContainsFunc(topics, func(topic common.Hash) bool {
+			return types.BloomLookup(bloom, topic)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveBlockNumber returns the number of the block the bound of the range refers to.
+// The omitted bound and the tags other than "earliest" refer to the last block of the shard,
+// there are no pending blocks, and the finalized ones are not tracked by the shards.
+func resolveBlockNumber(number *transport.BlockNumber, last types.BlockNumber) types.BlockNumber {
+	if number == nil || number.IsSpecial() {
+		return last
+	}
+	return number.BlockNumber()
+}
+
+// FindLogs returns the logs of the shard matching the query in the order of their blocks.
+// Blocks added to the log index are looked up in the index, the older blocks are filtered by their bloom.
+// The query fails if its block range or the number of the matching logs exceeds the limits.
+func FindLogs(tx db.RoTx, shardId types.ShardId, query *FilterQuery, limits LogQueryLimits) ([]*MetaLog, error) {
+	finder := logFinder{tx: tx, shardId: shardId, query: query, maxLogs: limits.maxLogs()}
+
+	if query.BlockHash != nil {
+		block, err := db.ReadBlock(tx, shardId, *query.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		return finder.logs, finder.addBlock(block)
+	}
+
+	lastBlock, _, err := db.ReadLastBlock(tx, shardId)
+	if err != nil {
+		return nil, err
+	}
+	from := resolveBlockNumber(query.FromBlock, lastBlock.Id)
+	to := min(resolveBlockNumber(query.ToBlock, lastBlock.Id), lastBlock.Id)
+	if from > to {
+		return nil, nil
+	}
+	if blocks := uint64(to-from) + 1; blocks > limits.maxBlockRange() {
+		return nil, fmt.Errorf("%w: %d blocks requested, the limit is %d", ErrBlockRangeTooLarge, blocks, limits.maxBlockRange())
+	}
+
+	indexStart, err := db.ReadLogIndexStart(tx, shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		indexStart = to + 1
+	} else if err != nil {
+		return nil, err
+	}
+
+	for blockId := from; blockId <= to && blockId < indexStart; blockId++ {
+		block, err := db.ReadBlockByNumber(tx, shardId, blockId)
+		if err != nil {
+			return nil, err
+		}
+		if !query.matchBloom(block.LogsBloom) {
+			continue
+		}
+		if err := finder.addBlock(block); err != nil {
+			return nil, err
+		}
+	}
+
+	if to < indexStart {
+		return finder.logs, nil
+	}
+	blockIds, err := finder.lookupIndex(max(from, indexStart), to)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockId := range blockIds {
+		block, err := db.ReadBlockByNumber(tx, shardId, blockId)
+		if err != nil {
+			return nil, err
+		}
+		if err := finder.addBlock(block); err != nil {
+			return nil, err
+		}
+	}
+	return finder.logs, nil
+}
+
+type logFinder struct {
+	tx      db.RoTx
+	shardId types.ShardId
+	query   *FilterQuery
+	maxLogs uint64
+	logs    []*MetaLog
+}
+
+// lookupIndex returns the blocks of [from, to] that contain logs matching every criterion of the query.
+// Without criteria all the blocks of the range are returned.
+func (f *logFinder) lookupIndex(from, to types.BlockNumber) ([]types.BlockNumber, error) {
+	var res []types.BlockNumber
+	filtered := false
+	intersect := func(blockIds []types.BlockNumber) {
+		if filtered {
+			res = intersectBlockIds(res, blockIds)
+		} else {
+			res = blockIds
+			filtered = true
+		}
+	}
+
+	if len(f.query.Addresses) != 0 {
+		var blockIds []types.BlockNumber
+		for _, address := range f.query.Addresses {
+			ids, err := db.ReadLogBlocksByAddress(f.tx, f.shardId, address, from, to)
+			if err != nil {
+				return nil, err
+			}
+			blockIds = append(blockIds, ids...)
+		}
+		intersect(uniqueBlockIds(blockIds))
+	}
+
+	for i, topics := range f.query.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		var blockIds []types.BlockNumber
+		for _, topic := range topics {
+			ids, err := db.ReadLogBlocksByTopic(f.tx, f.shardId, i, topic, from, to)
+			if err != nil {
+				return nil, err
+			}
+			blockIds = append(blockIds, ids...)
+		}
+		intersect(uniqueBlockIds(blockIds))
+	}
+
+	if !filtered {
+		res = make([]types.BlockNumber, 0, to-from+1)
+		for blockId := from; blockId <= to; blockId++ {
+			res = append(res, blockId)
+		}
+	}
+	return res, nil
+}
+
+func (f *logFinder) addBlock(block *types.Block) error {
+	reader := execution.NewDbReceiptTrieReader(f.tx, f.shardId)
+	reader.SetRootHash(block.ReceiptsRoot)
+	receipts, err := reader.Values()
+	if err != nil {
+		return err
+	}
+
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			if !f.query.matchLog(log) {
+				continue
+			}
+			if uint64(len(f.logs)) == f.maxLogs {
+				return fmt.Errorf("%w: the limit is %d, narrow the block range or the filter", ErrTooManyLogs, f.maxLogs)
+			}
+			f.logs = append(f.logs, &MetaLog{log, block.Id})
+		}
+	}
+	return nil
+}
+
+func uniqueBlockIds(blockIds []types.BlockNumber) []types.BlockNumber {
+	slices.Sort(blockIds)
+	return slices.Compact(blockIds)
+}
+
+// intersectBlockIds returns the common elements of two ascending slices.
+func intersectBlockIds(a, b []types.BlockNumber) []types.BlockNumber {
+	var res []types.BlockNumber
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
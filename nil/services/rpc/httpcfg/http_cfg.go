@@ -41,6 +41,8 @@ type HttpCfg struct {
 	HttpCORSDomain  []string
 	HttpCompression bool
 
+	WebsocketEnabled bool // Serve WebSocket upgrade requests on the HTTP endpoint
+
 	TraceRequests      bool // Print requests to logs at INFO level
 	DebugSingleRequest bool // Print single-request-related debugging info to logs at INFO level
 	HTTPTimeouts       HTTPTimeouts
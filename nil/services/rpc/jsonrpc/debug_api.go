@@ -2,10 +2,12 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
@@ -17,6 +19,9 @@ type DebugAPI interface {
 	GetBlockByNumber(ctx context.Context, shardId types.ShardId, number transport.BlockNumber, withTransactions bool) (*DebugRPCBlock, error)
 	GetBlockByHash(ctx context.Context, hash common.Hash, withTransactions bool) (*DebugRPCBlock, error)
 	GetContract(ctx context.Context, contractAddr types.Address, blockNrOrHash transport.BlockNumberOrHash) (*DebugRPCContract, error)
+	GetTransactionTree(ctx context.Context, hash common.Hash) (*RPCTransactionTreeNode, error)
+	TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error)
+	TraceCall(ctx context.Context, args CallArgs, mainBlockNrOrHash transport.BlockNumberOrHash, overrides *StateOverrides, cfg *tracers.Config) (json.RawMessage, error)
 }
 
 type DebugAPIImpl struct {
@@ -94,3 +99,113 @@ func (api *DebugAPIImpl) GetContract(ctx context.Context, contractAddr types.Add
 		AsyncContext: contract.AsyncContext,
 	}, nil
 }
+
+// GetTransactionTree implements debug_getTransactionTree.
+// Returns the tree of transactions caused by the transaction with the given hash across all shards.
+// Transactions that are not processed yet are included with the pending status.
+func (api *DebugAPIImpl) GetTransactionTree(ctx context.Context, hash common.Hash) (*RPCTransactionTreeNode, error) {
+	shardId := types.ShardIdFromHash(hash)
+	info, err := api.rawApi.GetInTransactionReceipt(ctx, shardId, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &RPCTransactionTreeNode{
+		TxnHash: hash,
+		ShardId: shardId,
+	}
+	if info != nil && !info.Temporary {
+		txnInfo, err := api.rawApi.GetInTransaction(ctx, shardId, rawapitypes.TransactionRequest{
+			ByHash: &rawapitypes.TransactionRequestByHash{Hash: hash},
+		})
+		if err != nil {
+			return nil, err
+		}
+		txn, err := decodeTransaction(txnInfo.TransactionSSZ)
+		if err != nil {
+			return nil, err
+		}
+		root.setTransaction(txn)
+	}
+
+	if err := api.fillTransactionTreeNode(ctx, root, info); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// fillTransactionTreeNode sets the result of the transaction processing and recursively adds the outbound transactions.
+// The receipt info contains the receipts of the whole subtree, the outbound transactions themselves are looked up
+// in the shard that emitted them.
+func (api *DebugAPIImpl) fillTransactionTreeNode(ctx context.Context, node *RPCTransactionTreeNode, info *rawapitypes.ReceiptInfo) error {
+	if info == nil {
+		node.Status = TransactionTreeStatusPending
+		return nil
+	}
+
+	// The receipts of the outbound transactions are attached to the children.
+	hop := *info
+	hop.OutReceipts = nil
+	receipt, err := NewRPCReceipt(&hop)
+	if err != nil {
+		return err
+	}
+	node.setReceipt(receipt)
+
+	node.Children = make([]*RPCTransactionTreeNode, len(info.OutTransactions))
+	for i, outHash := range info.OutTransactions {
+		outInfo, err := api.rawApi.GetOutTransaction(ctx, node.ShardId, outHash)
+		if err != nil {
+			return fmt.Errorf("failed to get outbound transaction %s: %w", outHash, err)
+		}
+		txn, err := decodeTransaction(outInfo.TransactionSSZ)
+		if err != nil {
+			return err
+		}
+
+		sourceShardId := node.ShardId
+		sourceBlockNumber := outInfo.BlockId
+		child := &RPCTransactionTreeNode{
+			TxnHash:           outHash,
+			ShardId:           txn.To.ShardId(),
+			SourceShardId:     &sourceShardId,
+			SourceBlockNumber: &sourceBlockNumber,
+		}
+		child.setTransaction(txn)
+
+		var outReceipt *rawapitypes.ReceiptInfo
+		if i < len(info.OutReceipts) {
+			outReceipt = info.OutReceipts[i]
+		}
+		if err := api.fillTransactionTreeNode(ctx, child, outReceipt); err != nil {
+			return err
+		}
+		node.Children[i] = child
+	}
+	return nil
+}
+
+func decodeTransaction(data []byte) (*types.Transaction, error) {
+	txn := &types.Transaction{}
+	if err := txn.UnmarshalSSZ(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+	return txn, nil
+}
+
+// TraceTransaction implements debug_traceTransaction.
+// Re-executes the transaction on top of the state of its parent block and returns the trace produced by the tracer from cfg.
+func (api *DebugAPIImpl) TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error) {
+	return api.rawApi.TraceTransaction(ctx, types.ShardIdFromHash(hash), hash, cfg)
+}
+
+// TraceCall implements debug_traceCall. Executes the call in the same way as eth_call and returns its trace.
+func (api *DebugAPIImpl) TraceCall(
+	ctx context.Context, args CallArgs, mainBlockNrOrHash transport.BlockNumberOrHash, overrides *StateOverrides, cfg *tracers.Config,
+) (json.RawMessage, error) {
+	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
+	if args.Fee.FeeCredit.IsZero() {
+		args.Fee = types.NewFeePackFromGas(1_000_000_000_000_000_000)
+	}
+	return api.rawApi.TraceCall(ctx, args, blockRef, overrides, cfg)
+}
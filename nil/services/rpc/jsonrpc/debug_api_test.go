@@ -214,3 +214,133 @@ func TestSuiteDbgContracts(t *testing.T) {
 
 	suite.Run(t, new(SuiteDbgContracts))
 }
+
+func TestDebugGetTransactionTree(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	newTxn := func(from, to types.Address, seqno types.Seqno) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+		txn.From = from
+		txn.To = to
+		txn.Seqno = seqno
+		txn.Value = types.NewValueFromUint64(uint64(seqno) + 1)
+		return txn
+	}
+
+	sender := types.GenerateRandomAddress(1)
+	receiver := types.GenerateRandomAddress(2)
+	root := newTxn(sender, sender, 0)
+	processed := newTxn(sender, receiver, 1)
+	pending := newTxn(sender, receiver, 2)
+
+	writeBlock := func(
+		shardId types.ShardId, id types.BlockNumber, inTxn *types.Transaction, receipt *types.Receipt, outTxns ...*types.Transaction,
+	) {
+		t.Helper()
+
+		inTxns := execution.NewDbTransactionTrie(tx, shardId)
+		require.NoError(t, inTxns.Update(0, inTxn))
+		receipts := execution.NewDbReceiptTrie(tx, shardId)
+		require.NoError(t, receipts.Update(0, receipt))
+		outTxnTrie := execution.NewDbTransactionTrie(tx, shardId)
+		outHashes := make([]common.Hash, len(outTxns))
+		for i, txn := range outTxns {
+			require.NoError(t, outTxnTrie.Update(types.TransactionIndex(i), txn))
+			outHashes[i] = txn.Hash()
+		}
+
+		block := &types.Block{
+			BlockData: types.BlockData{
+				Id:                  id,
+				InTransactionsRoot:  inTxns.RootHash(),
+				OutTransactionsRoot: outTxnTrie.RootHash(),
+				ReceiptsRoot:        receipts.RootHash(),
+				BaseFee:             types.NewValueFromUint64(10),
+			},
+		}
+		res := &execution.BlockGenerationResult{
+			Block:        block,
+			BlockHash:    block.Hash(shardId),
+			InTxns:       []*types.Transaction{inTxn},
+			InTxnHashes:  []common.Hash{inTxn.Hash()},
+			OutTxns:      outTxns,
+			OutTxnHashes: outHashes,
+		}
+		require.NoError(t, db.WriteBlock(tx, shardId, res.BlockHash, block))
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, res))
+	}
+
+	writeBlock(1, 5, root, &types.Receipt{
+		Success:         true,
+		Status:          types.ErrorSuccess,
+		GasUsed:         100,
+		OutTxnIndex:     0,
+		OutTxnNum:       2,
+		TxnHash:         root.Hash(),
+		ContractAddress: sender,
+	}, processed, pending)
+	writeBlock(2, 7, processed, &types.Receipt{
+		Success:         false,
+		Status:          types.ErrorExecutionReverted,
+		GasUsed:         50,
+		TxnHash:         processed.Hash(),
+		ContractAddress: receiver,
+	})
+	require.NoError(t, tx.Commit())
+
+	localShardApis := make(map[types.ShardId]rawapi.ShardApi)
+	for shardId := range types.ShardId(3) {
+		localShardApis[shardId] = rawapi.NewLocalShardApi(shardId, database, nil)
+	}
+	api := NewDebugAPI(rawapi.NewNodeApiOverShardApis(localShardApis), log.Logger)
+
+	tree, err := api.GetTransactionTree(ctx, root.Hash())
+	require.NoError(t, err)
+
+	require.Equal(t, TransactionTreeStatusSuccess, tree.Status)
+	require.Equal(t, root.Hash(), tree.TxnHash)
+	require.Equal(t, types.ShardId(1), tree.ShardId)
+	require.Equal(t, sender, tree.From)
+	require.Nil(t, tree.SourceShardId)
+	require.Equal(t, types.BlockNumber(5), *tree.BlockNumber)
+	require.Equal(t, types.NewValueFromUint64(1000), tree.Fee)
+	require.Len(t, tree.Children, 2)
+	require.False(t, tree.IsComplete())
+
+	child := tree.Children[0]
+	require.Equal(t, processed.Hash(), child.TxnHash)
+	require.Equal(t, TransactionTreeStatusFailed, child.Status)
+	require.Equal(t, types.ShardId(2), child.ShardId)
+	require.Equal(t, types.ShardId(1), *child.SourceShardId)
+	require.Equal(t, types.BlockNumber(5), *child.SourceBlockNumber)
+	require.Equal(t, types.BlockNumber(7), *child.BlockNumber)
+	require.Equal(t, types.Gas(50), child.GasUsed)
+	require.Equal(t, types.NewValueFromUint64(500), child.Fee)
+	require.Equal(t, processed.Value, child.Value)
+	require.Empty(t, child.Children)
+
+	child = tree.Children[1]
+	require.Equal(t, pending.Hash(), child.TxnHash)
+	require.Equal(t, TransactionTreeStatusPending, child.Status)
+	require.Equal(t, receiver, child.To)
+	require.Equal(t, types.BlockNumber(5), *child.SourceBlockNumber)
+	require.Nil(t, child.BlockNumber)
+	require.Nil(t, child.Receipt)
+
+	var hashes []common.Hash
+	tree.Walk(func(node *RPCTransactionTreeNode, depth int) {
+		hashes = append(hashes, node.TxnHash)
+	})
+	require.Equal(t, []common.Hash{root.Hash(), processed.Hash(), pending.Hash()}, hashes)
+}
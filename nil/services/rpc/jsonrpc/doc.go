@@ -15,8 +15,8 @@ package jsonrpc
 // @component Encoded encoded string "The encoded bytecode of the transaction."
 // @component FilterQuery filterQuery object "The query structure of the filter."
 // @componentprop BlockHash blockHash string false "The hash of the blocks whose logs should be retrieved by the filter."
-// @componentprop FromBlock fromBlock integer false "The beginning of the range of the blocks whose logs should be retrieved by the filter."
-// @componentprop ToBlock toBlock integer false "The end of the range of the blocks whose logs should be retrieved by the filter."
+// @componentprop FromBlock fromBlock string false "The beginning of the range of the blocks whose logs should be retrieved by the filter: the block number or tag, the latest block by default."
+// @componentprop ToBlock toBlock string false "The end of the range of the blocks whose logs should be retrieved by the filter: the block number or tag, the latest block by default."
 // @componentprop Addresses addresses array true "The addresses of the accounts/contracts the logs for whose events should be retrieved by the filter."
 // @componentprop Topics topics array true "The topics of the events whose lgos should be retrieved by the filter."
 // @component Value value integer "The amount of tokens."
@@ -25,12 +25,20 @@ package jsonrpc
 // @component UninstallFilterId id string "The ID of the filter that should be uninstalled."
 // @component FilterId id string "The ID of the filter."
 // @component FilterChanges filterChanges array "The array of logs, block headers or pending transactions that have occurred since the last poll of the filter."
-// @component FilterLogs filterLogs array "The array of logs that have been recorded since the last poll of the filter."
+// @component FilterLogs filterLogs array "The array of all logs matching the criteria of the filter."
+// @component LogsShardId shardId integer "The ID of the shard whose logs are requested."
+// @component Logs logs array "The array of logs matching the query. At most 10000 logs are returned."
 // @component ShardIds shardIds array "The array of shard IDs."
 // @component NumShards numShards integer "The number of shards."
 // @component GasShardId shardId integer "The ID of the shard whose gas price is requested."
 // @component BaseFee baseFee integer "The current base fee the given shard."
 // @component GasPrice gasPrice integer "The current gas price in the given shard."
+// @component FeeHistoryShardId shardId integer "The ID of the shard whose fee history is requested."
+// @component BlockCount blockCount integer "The number of blocks in the requested range. At most 1024 blocks are returned."
+// @component NewestBlock newestBlock integer "The number of the last block in the requested range."
+// @component RewardPercentiles rewardPercentiles array "(Optional) The ascending percentiles of the gas used in a block to sample the priority fees at."
+// @component StorageKeys storageKeys array "The storage slots of the account to prove."
+// @component ProofTokens tokens array "(Optional) The IDs of the tokens of the account to prove."
 // @component ChainId chainId integer "The chain ID of the network."
 // @component ReturnedValue returnedValue string "The returned value of the executed contract."
 // @component FullTx fullTx boolean "The flag that determines whether full transaction information is returned in the output."
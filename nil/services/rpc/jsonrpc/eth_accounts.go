@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -42,6 +43,21 @@ func (api *APIImplRo) GetCode(ctx context.Context, address types.Address, blockN
 	return hexutil.Bytes(code), nil
 }
 
+// GetProof implements eth_getProof. Returns the Merkle proofs of the account and of the given storage slots and tokens.
+func (api *APIImplRo) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, blockNrOrHash transport.BlockNumberOrHash, tokens *[]types.TokenId,
+) (*RPCAccountProof, error) {
+	var tokenIds []types.TokenId
+	if tokens != nil {
+		tokenIds = *tokens
+	}
+	proof, err := api.rawapi.GetProof(ctx, address, storageKeys, tokenIds, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCAccountProof(address, proof)
+}
+
 func blockNrToBlockReference(num transport.BlockNumber) rawapitypes.BlockReference {
 	var ref rawapitypes.BlockReference
 	if num <= 0 {
@@ -136,6 +136,19 @@ type EthAPIRo interface {
 	*/
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*RPCReceipt, error)
 
+	/*
+		@name GetReceiptInclusionProof
+		@summary Returns the proof that the receipt of the transaction is included into the given main shard block.
+		@description The receipt is proven against its block, the block is chained by the headers to the shard block referenced by the main block, and the reference is proven against the main block. Choose a main block close after the block of the receipt to keep the chain short.
+		@tags [Receipts]
+		@param hash TransactionHash
+		@param blockNumberOrHash BlockNumberOrHash
+		@returns receiptInclusionProof ReceiptInclusionProof
+	*/
+	GetReceiptInclusionProof(
+		ctx context.Context, hash common.Hash, mainBlockNrOrHash transport.BlockNumberOrHash,
+	) (*RPCReceiptInclusionProof, error)
+
 	/*
 		@name GetBalance
 		@summary Returns the balance of the account with the given address and at the given block.
@@ -147,6 +160,21 @@ type EthAPIRo interface {
 	*/
 	GetBalance(ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash) (*hexutil.Big, error)
 
+	/*
+		@name GetProof
+		@summary Returns the Merkle proofs of the account and of the given storage slots and tokens at the given block.
+		@description Implements eth_getProof. The proofs can be checked against the block header without trusting the node.
+		@tags [Accounts]
+		@param address Address
+		@param storageKeys StorageKeys
+		@param blockNumberOrHash BlockNumberOrHash
+		@param tokens ProofTokens
+		@returns accountProof AccountProof
+	*/
+	GetProof(
+		ctx context.Context, address types.Address, storageKeys []common.Hash, blockNrOrHash transport.BlockNumberOrHash, tokens *[]types.TokenId,
+	) (*RPCAccountProof, error)
+
 	/*
 		@name GasPrice
 		@summary Returns the current gas price in the network.
@@ -157,6 +185,21 @@ type EthAPIRo interface {
 	*/
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 
+	/*
+		@name FeeHistory
+		@summary Returns the base fees and the priority fee percentiles of a range of blocks of the shard.
+		@description Implements eth_feeHistory.
+		@tags [Transactions]
+		@param shardId FeeHistoryShardId
+		@param blockCount BlockCount
+		@param newestBlock NewestBlock
+		@param rewardPercentiles RewardPercentiles
+		@returns feeHistory FeeHistory
+	*/
+	FeeHistory(
+		ctx context.Context, shardId types.ShardId, blockCount hexutil.Uint64, newestBlock transport.BlockNumber, rewardPercentiles []float64,
+	) (*FeeHistory, error)
+
 	/*
 		@name GetTransactionCount
 		@summary Returns the transaction count of the account with the given address and at the given block.
@@ -229,13 +272,24 @@ type EthAPIRo interface {
 
 	/*
 		@name GetFilterLogs
-		@summary Polls the filter with the given id for logs.
+		@summary Returns all logs matching the filter with the given id.
 		@description Implements eth_getFilterLogs.
 		@tags [Filters]
 		@param id PollFilterId
 		@returns filterLogs FilterLogs
 	*/
-	GetFilterLogs(_ context.Context, id string) ([]*RPCLog, error)
+	GetFilterLogs(ctx context.Context, id string) ([]*RPCLog, error)
+
+	/*
+		@name GetLogs
+		@summary Returns the logs of the shard matching the given query.
+		@description Implements eth_getLogs.
+		@tags [Filters]
+		@param shardId LogsShardId
+		@param query FilterQuery
+		@returns logs Logs
+	*/
+	GetLogs(ctx context.Context, shardId types.ShardId, query filters.FilterQuery) ([]*RPCLog, error)
 
 	/*
 		@name GetShardsIdList
@@ -322,6 +376,19 @@ type APIImplRo struct {
 	logger          zerolog.Logger
 	clientEventsLog logging.CHLogger
 	rawapi          rawapi.NodeApi
+	maxGasInBlock   types.Gas
+	feeCalculator   execution.FeeCalculator
+}
+
+// EthAPIConfig contains the settings of the node the eth API depends on.
+type EthAPIConfig struct {
+	// MaxGasInBlock is the gas limit of the blocks produced by the collators,
+	// zero means types.DefaultMaxGasInBlock.
+	MaxGasInBlock types.Gas
+	// FeeCalculator is the base fee calculator of the collators, nil means the default one.
+	FeeCalculator execution.FeeCalculator
+	// LogQueryLimits restricts eth_getLogs and eth_getFilterLogs.
+	LogQueryLimits filters.LogQueryLimits
 }
 
 // APIImpl is implementation of the EthAPI interface based on remote Db access
@@ -334,15 +401,22 @@ var (
 	_ EthAPIRo = (*APIImplRo)(nil)
 )
 
-func NewEthAPIRo(ctx context.Context, rawapi rawapi.NodeApi, db db.ReadOnlyDB, pollBlocksForLogs, logClientEvents bool) *APIImplRo {
+func NewEthAPIRo(
+	ctx context.Context, rawapi rawapi.NodeApi, db db.ReadOnlyDB, pollBlocksForLogs, logClientEvents bool, cfg EthAPIConfig,
+) *APIImplRo {
 	accessor := execution.NewStateAccessor()
 	api := &APIImplRo{
 		logger:          logging.NewLogger("eth-api"),
 		accessor:        accessor,
 		rawapi:          rawapi,
 		clientEventsLog: logging.NewCHLogger("eth-api", "rpc_requests"),
+		maxGasInBlock:   cfg.MaxGasInBlock,
+		feeCalculator:   cfg.FeeCalculator,
+	}
+	if api.maxGasInBlock == 0 {
+		api.maxGasInBlock = types.DefaultMaxGasInBlock
 	}
-	api.logs = NewLogsAggregator(ctx, db, pollBlocksForLogs)
+	api.logs = NewLogsAggregator(ctx, db, pollBlocksForLogs, cfg.LogQueryLimits)
 	if !logClientEvents {
 		api.clientEventsLog = api.clientEventsLog.Disable()
 	}
@@ -350,8 +424,10 @@ func NewEthAPIRo(ctx context.Context, rawapi rawapi.NodeApi, db db.ReadOnlyDB, p
 }
 
 // NewEthAPI returns APIImpl instance
-func NewEthAPI(ctx context.Context, rawapi rawapi.NodeApi, db db.ReadOnlyDB, pollBlocksForLogs, logClientEvents bool) *APIImpl {
-	roApi := NewEthAPIRo(ctx, rawapi, db, pollBlocksForLogs, logClientEvents)
+func NewEthAPI(
+	ctx context.Context, rawapi rawapi.NodeApi, db db.ReadOnlyDB, pollBlocksForLogs, logClientEvents bool, cfg EthAPIConfig,
+) *APIImpl {
+	roApi := NewEthAPIRo(ctx, rawapi, db, pollBlocksForLogs, logClientEvents, cfg)
 	return &APIImpl{roApi}
 }
 
@@ -17,11 +17,12 @@ func sszToRPCBlock(shardId types.ShardId, raw *types.RawBlockWithExtractedData,
 	}
 
 	block := &BlockWithEntities{
-		Block:          data.Block,
-		Receipts:       data.Receipts,
-		InTransactions: data.InTransactions,
-		ChildBlocks:    data.ChildBlocks,
-		DbTimestamp:    data.DbTimestamp,
+		Block:           data.Block,
+		Receipts:        data.Receipts,
+		InTransactions:  data.InTransactions,
+		OutTransactions: data.OutTransactions,
+		ChildBlocks:     data.ChildBlocks,
+		DbTimestamp:     data.DbTimestamp,
 	}
 	return NewRPCBlock(shardId, block, fullTx)
 }
@@ -70,9 +71,10 @@ func (api *APIImplRo) GetBlockTransactionCountByHash(
 }
 
 type BlockWithEntities struct {
-	Block          *types.Block
-	Receipts       []*types.Receipt
-	InTransactions []*types.Transaction
-	ChildBlocks    []common.Hash
-	DbTimestamp    uint64
+	Block           *types.Block
+	Receipts        []*types.Receipt
+	InTransactions  []*types.Transaction
+	OutTransactions []*types.Transaction
+	ChildBlocks     []common.Hash
+	DbTimestamp     uint64
 }
@@ -0,0 +1,188 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+const (
+	// maxFeeHistoryBlockCount limits the number of blocks processed by a single eth_feeHistory request.
+	maxFeeHistoryBlockCount = 1024
+	// maxFeeHistoryPercentiles limits the number of reward percentiles of a single eth_feeHistory request.
+	maxFeeHistoryPercentiles = 100
+)
+
+var errInvalidPercentile = errors.New("invalid reward percentile")
+
+// FeeHistory implements eth_feeHistory. Returns the base fees, gas used ratios and priority fee percentiles
+// of the given number of blocks of the shard ending with newestBlock.
+func (api *APIImplRo) FeeHistory(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockCount hexutil.Uint64,
+	newestBlock transport.BlockNumber,
+	rewardPercentiles []float64,
+) (*FeeHistory, error) {
+	if newestBlock < transport.LatestBlockNumber {
+		return nil, errNotImplemented
+	}
+	if err := checkRewardPercentiles(rewardPercentiles); err != nil {
+		return nil, err
+	}
+
+	gasPrice, err := api.rawapi.GasPrice(ctx, shardId)
+	if err != nil {
+		return nil, err
+	}
+	if blockCount == 0 {
+		return &FeeHistory{GasPrice: gasPrice}, nil
+	}
+	if blockCount > maxFeeHistoryBlockCount {
+		blockCount = maxFeeHistoryBlockCount
+	}
+
+	newest, err := api.getFeeHistoryBlock(ctx, shardId, blockNrToBlockReference(newestBlock))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(blockCount) > uint64(newest.Block.Id)+1 {
+		blockCount = hexutil.Uint64(newest.Block.Id + 1)
+	}
+
+	oldest := newest.Block.Id + 1 - types.BlockNumber(blockCount)
+	res := &FeeHistory{
+		OldestBlock:  oldest,
+		BaseFee:      make([]types.Value, 0, blockCount+1),
+		GasUsedRatio: make([]float64, 0, blockCount),
+		GasPrice:     gasPrice,
+	}
+	if len(rewardPercentiles) > 0 {
+		res.Reward = make([][]types.Value, 0, blockCount)
+	}
+
+	for number := oldest; number <= newest.Block.Id; number++ {
+		data := newest
+		if number != newest.Block.Id {
+			data, err = api.getFeeHistoryBlock(ctx, shardId, rawapitypes.BlockNumberAsBlockReference(number))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		res.BaseFee = append(res.BaseFee, data.Block.BaseFee)
+		res.GasUsedRatio = append(res.GasUsedRatio,
+			float64(data.Block.GasUsed.Uint64())/float64(api.maxGasInBlock.Uint64()))
+		if len(rewardPercentiles) > 0 {
+			res.Reward = append(res.Reward, feeHistoryRewards(data, rewardPercentiles))
+		}
+	}
+	feeParams, err := api.getFeeParams(ctx, shardId, newest)
+	if err != nil {
+		return nil, err
+	}
+	res.BaseFee = append(res.BaseFee, execution.NextBaseFee(shardId, api.feeCalculator, newest.Block, feeParams))
+	return res, nil
+}
+
+// getFeeParams returns the fee curve parameters of the shard from the config of the main block
+// referenced by the block. Nil is returned if the parameters are not set.
+func (api *APIImplRo) getFeeParams(
+	ctx context.Context, shardId types.ShardId, data *types.BlockWithExtractedData,
+) (*config.FeeParams, error) {
+	if mainHash := data.Block.GetMainShardHash(shardId); !shardId.IsMainShard() && !mainHash.Empty() {
+		mainData, err := api.getFeeHistoryBlock(ctx, types.MainShardId, rawapitypes.BlockHashAsBlockReference(mainHash))
+		if err != nil {
+			return nil, err
+		}
+		data = mainData
+	}
+	return config.GetShardFeeParams(config.NewConfigAccessorFromMap(data.Config), shardId)
+}
+
+func (api *APIImplRo) getFeeHistoryBlock(
+	ctx context.Context, shardId types.ShardId, ref rawapitypes.BlockReference,
+) (*types.BlockWithExtractedData, error) {
+	raw, err := api.rawapi.GetFullBlockData(ctx, shardId, ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := raw.DecodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+	if len(data.InTransactions) != len(data.Receipts) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d receipts",
+			data.Block.Id, len(data.InTransactions), len(data.Receipts))
+	}
+	return data, nil
+}
+
+func checkRewardPercentiles(percentiles []float64) error {
+	if len(percentiles) > maxFeeHistoryPercentiles {
+		return fmt.Errorf("%w: too many percentiles, at most %d are allowed",
+			errInvalidPercentile, maxFeeHistoryPercentiles)
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("%w: %f is out of range [0, 100]", errInvalidPercentile, p)
+		}
+		if i > 0 && p <= percentiles[i-1] {
+			return fmt.Errorf("%w: %f is not greater than %f", errInvalidPercentile, p, percentiles[i-1])
+		}
+	}
+	return nil
+}
+
+// feeHistoryRewards returns the effective priority fees paid at the given percentiles of the gas used
+// by the transactions of the block. The fees are computed the same way the collator charges them.
+func feeHistoryRewards(data *types.BlockWithExtractedData, percentiles []float64) []types.Value {
+	rewards := make([]types.Value, len(percentiles))
+	for i := range rewards {
+		rewards[i] = types.Value0
+	}
+
+	type txnReward struct {
+		gasUsed types.Gas
+		reward  types.Value
+	}
+	txns := make([]txnReward, 0, len(data.InTransactions))
+	var totalGasUsed types.Gas
+	for i, txn := range data.InTransactions {
+		reward, ok := execution.GetEffectivePriorityFee(data.Block.BaseFee, txn)
+		if !ok {
+			// Such transaction fails without charging anything.
+			continue
+		}
+		gasUsed := data.Receipts[i].GasUsed
+		txns = append(txns, txnReward{gasUsed: gasUsed, reward: reward})
+		totalGasUsed = totalGasUsed.Add(gasUsed)
+	}
+	if len(txns) == 0 {
+		return rewards
+	}
+
+	slices.SortStableFunc(txns, func(a, b txnReward) int {
+		return a.reward.Cmp(b.reward)
+	})
+
+	var txnIndex int
+	sumGasUsed := txns[0].gasUsed
+	for i, p := range percentiles {
+		threshold := types.Gas(float64(totalGasUsed) * p / 100)
+		for sumGasUsed < threshold && txnIndex < len(txns)-1 {
+			txnIndex++
+			sumGasUsed = sumGasUsed.Add(txns[txnIndex].gasUsed)
+		}
+		rewards[i] = txns[txnIndex].reward
+	}
+	return rewards
+}
@@ -0,0 +1,178 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRewardPercentiles(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkRewardPercentiles(nil))
+	require.NoError(t, checkRewardPercentiles([]float64{0, 25, 50.5, 100}))
+
+	require.ErrorIs(t, checkRewardPercentiles([]float64{-1}), errInvalidPercentile)
+	require.ErrorIs(t, checkRewardPercentiles([]float64{100.1}), errInvalidPercentile)
+	require.ErrorIs(t, checkRewardPercentiles([]float64{50, 50}), errInvalidPercentile)
+	require.ErrorIs(t, checkRewardPercentiles([]float64{50, 10}), errInvalidPercentile)
+	require.ErrorIs(t, checkRewardPercentiles(make([]float64, maxFeeHistoryPercentiles+1)), errInvalidPercentile)
+}
+
+func TestFeeHistoryRewards(t *testing.T) {
+	t.Parallel()
+
+	baseFee := types.NewValueFromUint64(100)
+	newTxn := func(maxFee, maxPriorityFee uint64) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.MaxFeePerGas = types.NewValueFromUint64(maxFee)
+		txn.MaxPriorityFeePerGas = types.NewValueFromUint64(maxPriorityFee)
+		return txn
+	}
+	data := &types.BlockWithExtractedData{
+		Block: &types.Block{BlockData: types.BlockData{BaseFee: baseFee}},
+		InTransactions: []*types.Transaction{
+			// The priority fee is limited by MaxFeePerGas.
+			newTxn(130, 50),
+			newTxn(1000, 10),
+			// MaxFeePerGas is below the base fee, nothing is charged.
+			newTxn(90, 5),
+			newTxn(1000, 20),
+		},
+		Receipts: []*types.Receipt{
+			{GasUsed: 100},
+			{GasUsed: 300},
+			{GasUsed: 1000},
+			{GasUsed: 600},
+		},
+	}
+
+	rewards := feeHistoryRewards(data, []float64{0, 30, 31, 90, 100})
+	assert.Equal(t, []types.Value{
+		types.NewValueFromUint64(10),
+		types.NewValueFromUint64(10),
+		types.NewValueFromUint64(20),
+		types.NewValueFromUint64(20),
+		types.NewValueFromUint64(30),
+	}, rewards)
+
+	empty := &types.BlockWithExtractedData{Block: &types.Block{BlockData: types.BlockData{BaseFee: baseFee}}}
+	assert.Equal(t, []types.Value{types.Value0, types.Value0}, feeHistoryRewards(empty, []float64{10, 90}))
+}
+
+func TestFeeHistoryArguments(t *testing.T) {
+	t.Parallel()
+
+	api := &APIImplRo{}
+	_, err := api.FeeHistory(t.Context(), types.BaseShardId, 1, -3, nil)
+	require.ErrorIs(t, err, errNotImplemented)
+
+	_, err = api.FeeHistory(t.Context(), types.BaseShardId, 1, 0, []float64{90, 10})
+	require.ErrorIs(t, err, errInvalidPercentile)
+}
+
+func TestFeeHistoryGasUsedRatio(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	cfgAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+	require.NoError(t, config.SetParamGasPrice(cfgAccessor, &config.ParamGasPrice{
+		Shards: []types.Uint256{*types.NewUint256(10)},
+	}))
+
+	var prevBlock *types.Block
+	for i, gasUsed := range []types.Gas{0, 250_000} {
+		if prevBlock != nil {
+			cfgAccessor, err = config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, types.MainShardId)
+			require.NoError(t, err)
+		}
+		es, err := execution.NewExecutionState(tx, types.MainShardId, execution.StateParams{
+			Block:          prevBlock,
+			ConfigAccessor: cfgAccessor,
+		})
+		require.NoError(t, err)
+		es.BaseFee = types.DefaultGasPrice
+		es.GasUsed = gasUsed
+
+		blockRes, err := es.Commit(types.BlockNumber(i), nil)
+		require.NoError(t, err)
+		require.NoError(t, execution.PostprocessBlock(tx, types.MainShardId, blockRes))
+		require.NoError(t, db.WriteBlockTimestamp(tx, types.MainShardId, blockRes.BlockHash, 0))
+		prevBlock = blockRes.Block
+	}
+	require.NoError(t, tx.Commit())
+
+	shardApi, err := rawapi.NewLocalRawApiAccessor(
+		types.MainShardId, rawapi.NewLocalShardApi(types.MainShardId, database, nil))
+	require.NoError(t, err)
+	api := NewEthAPI(t.Context(), rawapi.NewNodeApiOverShardApis(map[types.ShardId]rawapi.ShardApi{
+		types.MainShardId: shardApi,
+	}), database, false, false, EthAPIConfig{MaxGasInBlock: 1_000_000})
+	defer api.Shutdown()
+
+	// The ratio is computed against the gas limit the collators are configured with.
+	res, err := api.FeeHistory(t.Context(), types.MainShardId, 2, transport.LatestBlockNumber, nil)
+	require.NoError(t, err)
+	assert.Equal(t, types.BlockNumber(0), res.OldestBlock)
+	assert.Equal(t, []float64{0, 0.25}, res.GasUsedRatio)
+}
+
+func TestFeeHistoryNextBaseFee(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	cfgAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	require.NoError(t, err)
+	require.NoError(t, config.SetParamGasPrice(cfgAccessor, &config.ParamGasPrice{
+		Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+	}))
+	// the gas price is read from the config of the main shard
+	for _, shardId := range []types.ShardId{types.MainShardId, types.BaseShardId} {
+		es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+			ConfigAccessor: cfgAccessor,
+		})
+		require.NoError(t, err)
+		es.BaseFee = types.DefaultGasPrice
+		blockRes, err := es.Commit(0, nil)
+		require.NoError(t, err)
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes))
+		require.NoError(t, db.WriteBlockTimestamp(tx, shardId, blockRes.BlockHash, 0))
+	}
+	require.NoError(t, tx.Commit())
+
+	shardApi, err := rawapi.NewLocalRawApiAccessor(
+		types.BaseShardId, rawapi.NewLocalShardApi(types.BaseShardId, database, nil))
+	require.NoError(t, err)
+	nextBaseFee := types.NewValueFromUint64(777)
+	api := NewEthAPI(t.Context(), rawapi.NewNodeApiOverShardApis(map[types.ShardId]rawapi.ShardApi{
+		types.BaseShardId: shardApi,
+	}), database, false, false, EthAPIConfig{FeeCalculator: &execution.ConstFeeCalculator{Value: nextBaseFee}})
+	defer api.Shutdown()
+
+	// The base fee of the next block is predicted with the calculator the collators are configured with.
+	res, err := api.FeeHistory(t.Context(), types.BaseShardId, 1, transport.LatestBlockNumber, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Value{types.DefaultGasPrice, nextBaseFee}, res.BaseFee)
+}
@@ -18,9 +18,13 @@ type LogsAggregator struct {
 	blocksMap *concurrent.Map[filters.SubscriptionID, []*types.Block]
 }
 
-func NewLogsAggregator(ctx context.Context, db db.ReadOnlyDB, pollBlocksForLogs bool) *LogsAggregator {
+func NewLogsAggregator(
+	ctx context.Context, db db.ReadOnlyDB, pollBlocksForLogs bool, limits filters.LogQueryLimits,
+) *LogsAggregator {
+	manager := filters.NewFiltersManager(ctx, db, !pollBlocksForLogs)
+	manager.SetLogQueryLimits(limits)
 	return &LogsAggregator{
-		filters:   filters.NewFiltersManager(ctx, db, !pollBlocksForLogs),
+		filters:   manager,
 		logsMap:   concurrent.NewMap[filters.SubscriptionID, []*filters.MetaLog](),
 		blocksMap: concurrent.NewMap[filters.SubscriptionID, []*types.Block](),
 	}
@@ -144,20 +148,29 @@ func (api *APIImplRo) GetFilterChanges(_ context.Context, id string) ([]any, err
 }
 
 // GetFilterLogs implements eth_getFilterLogs.
-// Polling method for a previously-created filter
-// returns an array of logs which occurred since last poll.
-func (api *APIImplRo) GetFilterLogs(_ context.Context, id string) ([]*RPCLog, error) {
-	// TODO: It is legacy from Erigon, probably we need to fix it. The problem: seems that we need to return all logs
-	// matching the criteria, but we return only changes since last Poll.
+// Returns all logs matching the criteria of a previously-created filter.
+func (api *APIImplRo) GetFilterLogs(ctx context.Context, id string) ([]*RPCLog, error) {
 	id = strings.TrimPrefix(id, "0x")
-	logs, ok := api.logs.GetLogs(filters.SubscriptionID(id))
-	if !ok {
-		return nil, fmt.Errorf("filter does not exist: %s", id)
+	logs, err := api.logs.filters.GetFilterLogs(ctx, filters.SubscriptionID(id))
+	if err != nil {
+		return nil, err
 	}
+	return toRPCLogs(logs), nil
+}
+
+// GetLogs implements eth_getLogs. Returns the logs of the shard matching the query.
+func (api *APIImplRo) GetLogs(ctx context.Context, shardId types.ShardId, query filters.FilterQuery) ([]*RPCLog, error) {
+	logs, err := api.logs.filters.FindLogs(ctx, shardId, &query)
+	if err != nil {
+		return nil, err
+	}
+	return toRPCLogs(logs), nil
+}
 
+func toRPCLogs(logs []*filters.MetaLog) []*RPCLog {
 	result := make([]*RPCLog, len(logs))
 	for i, metaLog := range logs {
 		result[i] = NewRPCLog(metaLog.Log, metaLog.BlockId)
 	}
-	return result, nil
+	return result
 }
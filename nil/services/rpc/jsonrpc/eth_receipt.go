@@ -5,6 +5,7 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 )
 
 func (api *APIImplRo) GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*RPCReceipt, error) {
@@ -14,3 +15,15 @@ func (api *APIImplRo) GetInTransactionReceipt(ctx context.Context, hash common.H
 	}
 	return NewRPCReceipt(info)
 }
+
+// GetReceiptInclusionProof implements eth_getReceiptInclusionProof.
+// Returns the proof of the receipt inclusion into the main shard block.
+func (api *APIImplRo) GetReceiptInclusionProof(
+	ctx context.Context, hash common.Hash, mainBlockNrOrHash transport.BlockNumberOrHash,
+) (*RPCReceiptInclusionProof, error) {
+	proof, err := api.rawapi.GetReceiptInclusionProof(ctx, types.ShardIdFromHash(hash), hash, toBlockReference(mainBlockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCReceiptInclusionProof(hash, proof)
+}
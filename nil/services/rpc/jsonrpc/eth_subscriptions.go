@@ -0,0 +1,256 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	NewHeadsSubscription               = "newHeads"
+	LogsSubscription                   = "logs"
+	NewPendingTransactionsSubscription = "newPendingTransactions"
+)
+
+// EthSubscriptionAPI is a collection of push-based counterparts of the filter methods.
+// It is only available over connections that support notifications (WebSocket).
+type EthSubscriptionAPI interface {
+	/*
+		@name Subscribe
+		@summary Creates a new subscription for events of the given kind in the given shard.
+		@description Implements eth_subscribe. Supported kinds are "newHeads", "logs" and "newPendingTransactions".
+		@tags [Filters]
+		@param shardId ShardId
+		@param kind SubscriptionKind
+		@param query FilterQuery
+		@returns subscriptionId SubscriptionId
+	*/
+	Subscribe(ctx context.Context, shardId types.ShardId, kind string, query *filters.FilterQuery) (transport.SubscriptionID, error)
+
+	/*
+		@name Unsubscribe
+		@summary Cancels the subscription with the given id.
+		@description Implements eth_unsubscribe.
+		@tags [Filters]
+		@param id SubscriptionId
+		@returns isDeleted IsDeleted
+	*/
+	Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error)
+}
+
+type EthSubscriptionAPIImpl struct {
+	ctx      context.Context
+	db       db.ReadOnlyDB
+	shards   []types.ShardId
+	txnPools map[types.ShardId]txnpool.Pool
+	logger   zerolog.Logger
+
+	mutex    sync.Mutex
+	managers map[types.ShardId]*filters.FiltersManager
+}
+
+var _ EthSubscriptionAPI = (*EthSubscriptionAPIImpl)(nil)
+
+// NewEthSubscriptionAPI creates a subscription API for the given shards.
+// Blocks and logs are read from db, pending transactions come from txnPools.
+func NewEthSubscriptionAPI(
+	ctx context.Context, db db.ReadOnlyDB, shards []types.ShardId, txnPools map[types.ShardId]txnpool.Pool,
+) *EthSubscriptionAPIImpl {
+	return &EthSubscriptionAPIImpl{
+		ctx:      ctx,
+		db:       db,
+		shards:   shards,
+		txnPools: txnPools,
+		logger:   logging.NewLogger("eth-subscriptions"),
+		managers: make(map[types.ShardId]*filters.FiltersManager),
+	}
+}
+
+func (api *EthSubscriptionAPIImpl) Shutdown() {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	for _, m := range api.managers {
+		m.WaitForShutdown()
+	}
+}
+
+// filtersManager returns the filters manager of the shard, creating it on first use,
+// so that nodes without subscribers don't poll the database.
+func (api *EthSubscriptionAPIImpl) filtersManager(shardId types.ShardId) (*filters.FiltersManager, error) {
+	if api.db == nil || !slices.Contains(api.shards, shardId) {
+		return nil, fmt.Errorf("shard %d is not served by this node", shardId)
+	}
+
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	m, ok := api.managers[shardId]
+	if !ok {
+		m = filters.NewShardFiltersManager(api.ctx, api.db, shardId, false)
+		api.managers[shardId] = m
+	}
+	return m, nil
+}
+
+// Subscribe implements eth_subscribe.
+func (api *EthSubscriptionAPIImpl) Subscribe(
+	ctx context.Context, shardId types.ShardId, kind string, query *filters.FilterQuery,
+) (transport.SubscriptionID, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return "", transport.ErrNotificationsUnsupported
+	}
+
+	switch kind {
+	case NewHeadsSubscription:
+		return api.subscribeNewHeads(notifier, shardId)
+	case LogsSubscription:
+		if query == nil {
+			query = &filters.FilterQuery{}
+		}
+		if query.BlockHash != nil || query.FromBlock != nil || query.ToBlock != nil {
+			return "", errors.New("logs subscription doesn't support block range")
+		}
+		return api.subscribeLogs(notifier, shardId, query)
+	case NewPendingTransactionsSubscription:
+		return api.subscribePendingTransactions(notifier, shardId)
+	default:
+		return "", fmt.Errorf("unsupported subscription kind %q", kind)
+	}
+}
+
+// Unsubscribe implements eth_unsubscribe.
+func (api *EthSubscriptionAPIImpl) Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return false, transport.ErrNotificationsUnsupported
+	}
+	if err := notifier.Unsubscribe(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (api *EthSubscriptionAPIImpl) subscribeNewHeads(
+	notifier *transport.Notifier, shardId types.ShardId,
+) (transport.SubscriptionID, error) {
+	m, err := api.filtersManager(shardId)
+	if err != nil {
+		return "", err
+	}
+
+	id, blocks := m.AddBlocksListener()
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer m.RemoveBlocksListener(id)
+		for {
+			select {
+			case <-sub.Done():
+				return
+			case block, ok := <-blocks:
+				if !ok {
+					return
+				}
+				header, err := NewRPCBlock(shardId, &BlockWithEntities{Block: block}, false)
+				if err != nil {
+					api.logger.Error().Err(err).Msg("Failed to encode block header")
+					continue
+				}
+				if err := notifier.Notify(sub, header); err != nil {
+					api.logNotifyError(err, sub)
+					return
+				}
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (api *EthSubscriptionAPIImpl) subscribeLogs(
+	notifier *transport.Notifier, shardId types.ShardId, query *filters.FilterQuery,
+) (transport.SubscriptionID, error) {
+	m, err := api.filtersManager(shardId)
+	if err != nil {
+		return "", err
+	}
+
+	id, filter := m.NewFilter(query)
+	if filter == nil {
+		return "", errors.New("cannot create new filter")
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer func() {
+			// The manager may be blocked on sending to the filter, so drain it until it's removed.
+			go m.RemoveFilter(id)
+			for range filter.LogsChannel() {
+			}
+		}()
+		for {
+			select {
+			case <-sub.Done():
+				return
+			case log, ok := <-filter.LogsChannel():
+				if !ok {
+					return
+				}
+				if err := notifier.Notify(sub, NewRPCLog(log.Log, log.BlockId)); err != nil {
+					api.logNotifyError(err, sub)
+					return
+				}
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (api *EthSubscriptionAPIImpl) subscribePendingTransactions(
+	notifier *transport.Notifier, shardId types.ShardId,
+) (transport.SubscriptionID, error) {
+	pool, ok := api.txnPools[shardId]
+	if !ok || pool == nil {
+		return "", fmt.Errorf("transaction pool of shard %d is not available on this node", shardId)
+	}
+
+	txns, remove := pool.AddPendingListener()
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer remove()
+		for {
+			select {
+			case <-sub.Done():
+				return
+			case txn, ok := <-txns:
+				if !ok {
+					// the pool drops the listener that doesn't keep up, so the subscription can't be continued
+					api.logger.Warn().
+						Str("subscription", string(sub.ID)).
+						Msg("Pending transactions subscription is too slow, closing it")
+					_ = notifier.Unsubscribe(sub.ID)
+					return
+				}
+				if err := notifier.Notify(sub, txn.Hash()); err != nil {
+					api.logNotifyError(err, sub)
+					return
+				}
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (api *EthSubscriptionAPIImpl) logNotifyError(err error, sub *transport.Subscription) {
+	if errors.Is(err, transport.ErrSubscriptionNotFound) {
+		return
+	}
+	api.logger.Debug().Err(err).Str("subscription", string(sub.ID)).Msg("Failed to send notification")
+}
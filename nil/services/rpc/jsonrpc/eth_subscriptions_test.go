@@ -0,0 +1,249 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+const subscriptionWaitTimeout = 5 * time.Second
+
+// pendingListenerPool is a pool that only provides the pending transactions listener.
+type pendingListenerPool struct {
+	txnpool.Pool
+	pending chan *types.TxnWithHash
+}
+
+func (p *pendingListenerPool) AddPendingListener() (<-chan *types.TxnWithHash, func()) {
+	return p.pending, func() {}
+}
+
+type wsTestMessage struct {
+	Id     *int            `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Params struct {
+		Subscription transport.SubscriptionID `json:"subscription"`
+		Result       json.RawMessage          `json:"result"`
+	} `json:"params"`
+}
+
+type SuiteEthSubscriptions struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+	db     db.DB
+	pool   *pendingListenerPool
+	api    *EthSubscriptionAPIImpl
+	server *transport.Server
+	http   *httptest.Server
+	conn   *websocket.Conn
+
+	requestId     int
+	lastBlock     types.BlockNumber
+	lastBlockHash common.Hash
+}
+
+func (s *SuiteEthSubscriptions) SetupTest() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	s.lastBlock = 0
+	s.lastBlockHash = common.EmptyHash
+	s.requestId = 0
+	s.appendBlock(nil)
+
+	s.pool = &pendingListenerPool{pending: make(chan *types.TxnWithHash, 1)}
+	s.api = NewEthSubscriptionAPI(
+		s.ctx, s.db, []types.ShardId{types.MainShardId}, map[types.ShardId]txnpool.Pool{types.MainShardId: s.pool})
+
+	s.server = transport.NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	s.Require().NoError(s.server.RegisterName("eth", s.api))
+	s.http = httptest.NewServer(s.server.WebsocketHandler([]string{"*"}))
+
+	s.conn, _, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.http.URL, "http"), nil)
+	s.Require().NoError(err)
+}
+
+func (s *SuiteEthSubscriptions) TearDownTest() {
+	s.conn.Close()
+	s.http.Close()
+	s.server.Stop()
+	s.cancel()
+	s.api.Shutdown()
+	s.db.Close()
+}
+
+// appendBlock writes a new main shard block with the given receipts on top of the last one.
+func (s *SuiteEthSubscriptions) appendBlock(receipts []*types.Receipt) {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	if s.lastBlockHash != common.EmptyHash {
+		s.lastBlock++
+	}
+	block := &types.Block{
+		BlockData: types.BlockData{
+			Id:           s.lastBlock,
+			PrevBlock:    s.lastBlockHash,
+			ReceiptsRoot: writeReceipts(s.T(), tx, types.MainShardId, receipts).RootHash(),
+		},
+	}
+	s.lastBlockHash = block.Hash(types.MainShardId)
+	s.Require().NoError(db.WriteBlock(tx, types.MainShardId, s.lastBlockHash, block))
+	s.Require().NoError(db.WriteLastBlockHash(tx, types.MainShardId, s.lastBlockHash))
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *SuiteEthSubscriptions) send(method string, params ...any) int {
+	s.T().Helper()
+
+	s.requestId++
+	s.Require().NoError(s.conn.WriteJSON(map[string]any{
+		"jsonrpc": "2.0", "id": s.requestId, "method": method, "params": params,
+	}))
+	return s.requestId
+}
+
+func (s *SuiteEthSubscriptions) read() *wsTestMessage {
+	s.T().Helper()
+
+	s.Require().NoError(s.conn.SetReadDeadline(time.Now().Add(subscriptionWaitTimeout)))
+	msg := &wsTestMessage{}
+	s.Require().NoError(s.conn.ReadJSON(msg))
+	return msg
+}
+
+func (s *SuiteEthSubscriptions) subscribe(params ...any) transport.SubscriptionID {
+	s.T().Helper()
+
+	id := s.send("eth_subscribe", params...)
+	msg := s.read()
+	s.Require().NotNil(msg.Id)
+	s.Require().Equal(id, *msg.Id)
+	s.Require().Nil(msg.Error)
+
+	var subId transport.SubscriptionID
+	s.Require().NoError(json.Unmarshal(msg.Result, &subId))
+	s.Require().NotEmpty(subId)
+	return subId
+}
+
+func (s *SuiteEthSubscriptions) requireSubscribeError(expected string, params ...any) {
+	s.T().Helper()
+
+	s.send("eth_subscribe", params...)
+	msg := s.read()
+	s.Require().NotNil(msg.Error)
+	s.Require().Contains(msg.Error.Message, expected)
+}
+
+func (s *SuiteEthSubscriptions) readNotification(subId transport.SubscriptionID, result any) {
+	s.T().Helper()
+
+	msg := s.read()
+	s.Require().Nil(msg.Id)
+	s.Require().Equal("eth_subscription", msg.Method)
+	s.Require().Equal(subId, msg.Params.Subscription)
+	s.Require().NoError(json.Unmarshal(msg.Params.Result, result))
+}
+
+func (s *SuiteEthSubscriptions) TestNewHeads() {
+	subId := s.subscribe(types.MainShardId, NewHeadsSubscription)
+
+	// The blocks existing before the subscription are not sent, the first notification is about the new block.
+	s.appendBlock(nil)
+
+	var header RPCBlock
+	s.readNotification(subId, &header)
+	s.Equal(types.BlockNumber(1), header.Number)
+	s.Equal(s.lastBlockHash, header.Hash)
+
+	s.appendBlock(nil)
+	s.readNotification(subId, &header)
+	s.Equal(types.BlockNumber(2), header.Number)
+}
+
+func (s *SuiteEthSubscriptions) TestLogs() {
+	address := types.HexToAddress("0x1111111111")
+	other := types.HexToAddress("0x2222222222")
+	subId := s.subscribe(types.MainShardId, LogsSubscription, filters.FilterQuery{Addresses: []types.Address{address}})
+
+	s.appendBlock([]*types.Receipt{
+		{ContractAddress: other, Logs: []*types.Log{{Address: other, Data: []byte{0x01}}}},
+		{ContractAddress: address, Logs: []*types.Log{{Address: address, Topics: []common.Hash{{0x02}}, Data: []byte{0x02}}}},
+	})
+
+	var log RPCLog
+	s.readNotification(subId, &log)
+	s.Equal(address, log.Address)
+	s.Equal(types.BlockNumber(1), log.BlockNumber)
+	s.Equal([]common.Hash{{0x02}}, log.Topics)
+}
+
+func (s *SuiteEthSubscriptions) TestPendingTransactions() {
+	subId := s.subscribe(types.MainShardId, NewPendingTransactionsSubscription)
+
+	txn := types.NewTxnWithHash(types.NewEmptyTransaction())
+	s.pool.pending <- txn
+
+	var hash common.Hash
+	s.readNotification(subId, &hash)
+	s.Equal(txn.Hash(), hash)
+}
+
+func (s *SuiteEthSubscriptions) TestUnsubscribe() {
+	subId := s.subscribe(types.MainShardId, NewHeadsSubscription)
+
+	id := s.send("eth_unsubscribe", subId)
+	msg := s.read()
+	s.Require().Equal(id, *msg.Id)
+	s.Require().JSONEq("true", string(msg.Result))
+
+	// The subscription is gone, so no notifications are sent for the new block.
+	s.appendBlock(nil)
+	id = s.send("eth_unsubscribe", subId)
+	msg = s.read()
+	s.Require().Equal(id, *msg.Id)
+	s.Require().NotNil(msg.Error)
+}
+
+func (s *SuiteEthSubscriptions) TestInvalidSubscriptions() {
+	s.requireSubscribeError("unsupported subscription kind", types.MainShardId, "unknown")
+	s.requireSubscribeError("is not served by this node", types.BaseShardId, NewHeadsSubscription)
+	s.requireSubscribeError("doesn't support block range",
+		types.MainShardId, LogsSubscription, map[string]any{"fromBlock": "0x1"})
+}
+
+func (s *SuiteEthSubscriptions) TestNotificationsUnsupported() {
+	_, err := s.api.Subscribe(s.ctx, types.MainShardId, NewHeadsSubscription, nil)
+	s.Require().ErrorIs(err, transport.ErrNotificationsUnsupported)
+}
+
+func TestSuiteEthSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteEthSubscriptions))
+}
@@ -0,0 +1,91 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	"github.com/rs/zerolog"
+)
+
+type TxPoolAPI interface {
+	Content(ctx context.Context, shardId types.ShardId) (*TxPoolContent, error)
+	Status(ctx context.Context, shardId types.ShardId) (*TxPoolStatus, error)
+	Inspect(ctx context.Context, shardId types.ShardId) (*TxPoolInspect, error)
+}
+
+type TxPoolAPIImpl struct {
+	logger zerolog.Logger
+	rawApi rawapi.NodeApi
+}
+
+var _ TxPoolAPI = &TxPoolAPIImpl{}
+
+func NewTxPoolAPI(rawApi rawapi.NodeApi, logger zerolog.Logger) *TxPoolAPIImpl {
+	return &TxPoolAPIImpl{
+		logger: logger,
+		rawApi: rawApi,
+	}
+}
+
+// Content implements txpool_content. Returns the transactions in the pool of the shard grouped by receiver and seqno.
+func (api *TxPoolAPIImpl) Content(ctx context.Context, shardId types.ShardId) (*TxPoolContent, error) {
+	content, err := api.rawApi.GetTxpoolContent(ctx, shardId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TxPoolContent{
+		BaseFee: content.BaseFee,
+		Pending: make(TxPoolTransactions[*RPCPoolTransaction]),
+		Queued:  make(TxPoolTransactions[*RPCPoolTransaction]),
+	}
+	for _, txn := range content.Pending {
+		res.Pending.add(txn, NewRPCPoolTransaction(txn, content.BaseFee))
+	}
+	for _, txn := range content.Queued {
+		res.Queued.add(txn, NewRPCPoolTransaction(txn, content.BaseFee))
+	}
+	return res, nil
+}
+
+// Status implements txpool_status. Returns the number of transactions in the pool of the shard.
+func (api *TxPoolAPIImpl) Status(ctx context.Context, shardId types.ShardId) (*TxPoolStatus, error) {
+	content, err := api.rawApi.GetTxpoolContent(ctx, shardId)
+	if err != nil {
+		return nil, err
+	}
+	return &TxPoolStatus{
+		Pending: hexutil.Uint(len(content.Pending)),
+		Queued:  hexutil.Uint(len(content.Queued)),
+	}, nil
+}
+
+// Inspect implements txpool_inspect. Returns a short textual summary of each transaction in the pool of the shard.
+func (api *TxPoolAPIImpl) Inspect(ctx context.Context, shardId types.ShardId) (*TxPoolInspect, error) {
+	content, err := api.rawApi.GetTxpoolContent(ctx, shardId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TxPoolInspect{
+		Pending: make(TxPoolTransactions[string]),
+		Queued:  make(TxPoolTransactions[string]),
+	}
+	for _, txn := range content.Pending {
+		res.Pending.add(txn, inspectPoolTransaction(txn, content.BaseFee))
+	}
+	for _, txn := range content.Queued {
+		res.Queued.add(txn, inspectPoolTransaction(txn, content.BaseFee))
+	}
+	return res, nil
+}
+
+func inspectPoolTransaction(txn *types.TxnWithHash, baseFee types.Value) string {
+	effectivePriorityFee, _ := execution.GetEffectivePriorityFee(baseFee, txn.Transaction)
+	return fmt.Sprintf("%s: %s value + %s fee credit, priority fee %s (max %s), max fee %s",
+		txn.From, txn.Value, txn.FeeCredit, effectivePriorityFee, txn.MaxPriorityFeePerGas, txn.MaxFeePerGas)
+}
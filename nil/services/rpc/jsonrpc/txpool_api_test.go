@@ -0,0 +1,78 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPoolAPI(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	pools := NewPools(t, ctx, 2)
+	shardApis := make(map[types.ShardId]rawapi.ShardApi)
+	for shardId, pool := range pools {
+		shardApis[shardId], err = rawapi.NewLocalRawApiAccessor(shardId, rawapi.NewLocalShardApi(shardId, database, pool))
+		require.NoError(t, err)
+	}
+	api := NewTxPoolAPI(rawapi.NewNodeApiOverShardApis(shardApis), logging.NewLogger("Test"))
+
+	baseFee := types.NewValueFromUint64(100)
+	require.NoError(t, pools[types.BaseShardId].OnCommitted(ctx, baseFee, nil))
+
+	address := types.ShardAndHexToAddress(types.BaseShardId, "11")
+	newTxn := func(seqno types.Seqno, maxFee uint64) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.To = address
+		txn.Seqno = seqno
+		txn.MaxPriorityFeePerGas = types.NewValueFromUint64(10)
+		txn.MaxFeePerGas = types.NewValueFromUint64(maxFee)
+		txn.Value = types.NewValueFromUint64(1)
+		return txn
+	}
+	txn0 := newTxn(0, 105)
+	txn1 := newTxn(1, 90)
+	reasons, err := pools[types.BaseShardId].Add(ctx, txn0, txn1)
+	require.NoError(t, err)
+	require.Len(t, reasons, 2)
+
+	status, err := api.Status(ctx, types.BaseShardId)
+	require.NoError(t, err)
+	assert.Equal(t, &TxPoolStatus{Pending: 1, Queued: 1}, status)
+
+	content, err := api.Content(ctx, types.BaseShardId)
+	require.NoError(t, err)
+	assert.Equal(t, baseFee, content.BaseFee)
+	require.Contains(t, content.Pending, address)
+	pending := content.Pending[address][0]
+	require.NotNil(t, pending)
+	assert.Equal(t, txn0.Hash(), pending.Hash)
+	assert.Equal(t, types.NewValueFromUint64(5), pending.EffectivePriorityFee)
+	assert.Equal(t, txn1.Hash(), content.Queued[address][1].Hash)
+	assert.Equal(t, hexutil.Uint64(1), content.Queued[address][1].Seqno)
+
+	inspect, err := api.Inspect(ctx, types.BaseShardId)
+	require.NoError(t, err)
+	assert.Contains(t, inspect.Pending[address][0], "priority fee 5 (max 10), max fee 105")
+	assert.Contains(t, inspect.Queued[address][1], "priority fee 0 (max 10), max fee 90")
+
+	// The other shard has an empty pool.
+	status, err = api.Status(ctx, types.MainShardId)
+	require.NoError(t, err)
+	assert.Equal(t, &TxPoolStatus{}, status)
+
+	_, err = api.Status(ctx, types.ShardId(10))
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -36,28 +37,30 @@ type (
 // @componentprop To to string true "The address where the transaction was sent."
 // @componentprop Value value string true "The transaction value."
 // @componentprop Token value array true "Token values."
+// @componentprop RequestChain requestChain array false "The chain of the async requests the transaction responds to."
 type RPCInTransaction struct {
-	Flags                types.TransactionFlags `json:"flags"`
-	Success              bool                   `json:"success"`
-	RequestId            uint64                 `json:"requestId"`
-	Data                 hexutil.Bytes          `json:"data"`
-	BlockHash            common.Hash            `json:"blockHash"`
-	BlockNumber          types.BlockNumber      `json:"blockNumber"`
-	From                 types.Address          `json:"from"`
-	GasUsed              types.Gas              `json:"gasUsed"`
-	FeeCredit            types.Value            `json:"feeCredit,omitempty"`
-	MaxPriorityFeePerGas types.Value            `json:"maxPriorityFeePerGas,omitempty"`
-	MaxFeePerGas         types.Value            `json:"maxFeePerGas,omitempty"`
-	Hash                 common.Hash            `json:"hash"`
-	Seqno                hexutil.Uint64         `json:"seqno"`
-	To                   types.Address          `json:"to"`
-	RefundTo             types.Address          `json:"refundTo"`
-	BounceTo             types.Address          `json:"bounceTo"`
-	Index                hexutil.Uint64         `json:"index"`
-	Value                types.Value            `json:"value"`
-	Token                []types.TokenBalance   `json:"token,omitempty"`
-	ChainID              types.ChainId          `json:"chainId,omitempty"`
-	Signature            types.Signature        `json:"signature"`
+	Flags                types.TransactionFlags    `json:"flags"`
+	Success              bool                      `json:"success"`
+	RequestId            uint64                    `json:"requestId"`
+	Data                 hexutil.Bytes             `json:"data"`
+	BlockHash            common.Hash               `json:"blockHash"`
+	BlockNumber          types.BlockNumber         `json:"blockNumber"`
+	From                 types.Address             `json:"from"`
+	GasUsed              types.Gas                 `json:"gasUsed"`
+	FeeCredit            types.Value               `json:"feeCredit,omitempty"`
+	MaxPriorityFeePerGas types.Value               `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         types.Value               `json:"maxFeePerGas,omitempty"`
+	Hash                 common.Hash               `json:"hash"`
+	Seqno                hexutil.Uint64            `json:"seqno"`
+	To                   types.Address             `json:"to"`
+	RefundTo             types.Address             `json:"refundTo"`
+	BounceTo             types.Address             `json:"bounceTo"`
+	Index                hexutil.Uint64            `json:"index"`
+	Value                types.Value               `json:"value"`
+	Token                []types.TokenBalance      `json:"token,omitempty"`
+	ChainID              types.ChainId             `json:"chainId,omitempty"`
+	Signature            types.Signature           `json:"signature"`
+	RequestChain         []*types.AsyncRequestInfo `json:"requestChain,omitempty"`
 }
 
 // @component RPCBlock rpcBlock object "The block whose information was requested."
@@ -69,22 +72,24 @@ type RPCInTransaction struct {
 // @componentprop ParentHash parentHash string true "The hash of the parent block."
 // @componentprop ReceiptsRoot receiptsRoot string true "The root of the block receipts."
 // @componentprop ShardId shardId integer true "The ID of the shard where the block was generated."
+// @componentprop ForwardTransactions forwardTransactions array false "The transactions of other shards forwarded by the block."
 type RPCBlock struct {
-	Number              types.BlockNumber   `json:"number"`
-	Hash                common.Hash         `json:"hash"`
-	ParentHash          common.Hash         `json:"parentHash"`
-	InTransactionsRoot  common.Hash         `json:"inTransactionsRoot"`
-	ReceiptsRoot        common.Hash         `json:"receiptsRoot"`
-	ChildBlocksRootHash common.Hash         `json:"childBlocksRootHash"`
-	ShardId             types.ShardId       `json:"shardId"`
-	Transactions        []*RPCInTransaction `json:"transactions,omitempty"`
-	TransactionHashes   []common.Hash       `json:"transactionHashes,omitempty"`
-	ChildBlocks         []common.Hash       `json:"childBlocks"`
-	MainChainHash       common.Hash         `json:"mainChainHash"`
-	DbTimestamp         uint64              `json:"dbTimestamp"`
-	BaseFee             types.Value         `json:"baseFee"`
-	L1Number            uint64              `json:"l1Number"`
-	LogsBloom           hexutil.Bytes       `json:"logsBloom,omitempty"`
+	Number              types.BlockNumber    `json:"number"`
+	Hash                common.Hash          `json:"hash"`
+	ParentHash          common.Hash          `json:"parentHash"`
+	InTransactionsRoot  common.Hash          `json:"inTransactionsRoot"`
+	ReceiptsRoot        common.Hash          `json:"receiptsRoot"`
+	ChildBlocksRootHash common.Hash          `json:"childBlocksRootHash"`
+	ShardId             types.ShardId        `json:"shardId"`
+	Transactions        []*RPCInTransaction  `json:"transactions,omitempty"`
+	TransactionHashes   []common.Hash        `json:"transactionHashes,omitempty"`
+	ChildBlocks         []common.Hash        `json:"childBlocks"`
+	MainChainHash       common.Hash          `json:"mainChainHash"`
+	DbTimestamp         uint64               `json:"dbTimestamp"`
+	BaseFee             types.Value          `json:"baseFee"`
+	L1Number            uint64               `json:"l1Number"`
+	LogsBloom           hexutil.Bytes        `json:"logsBloom,omitempty"`
+	ForwardTransactions []*types.Transaction `json:"forwardTransactions,omitempty"`
 }
 
 type DebugRPCBlock struct {
@@ -101,6 +106,7 @@ type ChainConfig struct {
 	Validators  *config.ParamValidators  `json:"validators"`
 	GasPrices   *config.ParamGasPrice    `json:"gasPrices"`
 	L1BlockInfo *config.ParamL1BlockInfo `json:"l1BlockInfo"`
+	Fees        *config.ParamFees        `json:"fees,omitempty"`
 }
 
 func NewChainConfigFromMap(data map[string][]byte) (*ChainConfig, error) {
@@ -120,10 +126,15 @@ func NewChainConfigFromMap(data map[string][]byte) (*ChainConfig, error) {
 	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
 		return nil, err
 	}
+	fees, err := config.GetParamFees(configAccessor)
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, err
+	}
 	return &ChainConfig{
 		Validators:  validators,
 		GasPrices:   gasPrices,
 		L1BlockInfo: l1BlockInfo,
+		Fees:        fees,
 	}, nil
 }
 
@@ -150,6 +161,13 @@ func (c *ChainConfig) ToMap() (map[string][]byte, error) {
 		}
 		result[config.NameL1Block] = l1BlockInfo
 	}
+	if c.Fees != nil {
+		fees, err := c.Fees.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		result[config.NameFees] = fees
+	}
 	return result, nil
 }
 
@@ -331,6 +349,7 @@ func NewRPCInTransaction(
 		Token:                transaction.Token,
 		ChainID:              transaction.ChainId,
 		Signature:            transaction.Signature,
+		RequestChain:         transaction.RequestChain,
 	}
 
 	return result, nil
@@ -349,6 +368,7 @@ func NewRPCBlock(shardId types.ShardId, data *BlockWithEntities, fullTx bool) (*
 
 	transactionsRes := make([]*RPCInTransaction, 0, len(transactions))
 	transactionHashesRes := make([]common.Hash, 0, len(transactions))
+	var forwardTransactions []*types.Transaction
 	blockHash := block.Hash(shardId)
 	blockId := block.Id
 	if fullTx {
@@ -359,6 +379,7 @@ func NewRPCBlock(shardId types.ShardId, data *BlockWithEntities, fullTx bool) (*
 			}
 			transactionsRes = append(transactionsRes, txn)
 		}
+		forwardTransactions, _ = execution.SplitOutTransactions(data.OutTransactions, shardId)
 	} else {
 		for _, m := range transactions {
 			transactionHashesRes = append(transactionHashesRes, m.Hash())
@@ -390,6 +411,7 @@ func NewRPCBlock(shardId types.ShardId, data *BlockWithEntities, fullTx bool) (*
 		BaseFee:             block.BaseFee,
 		LogsBloom:           bloom,
 		L1Number:            block.L1BlockNumber,
+		ForwardTransactions: forwardTransactions,
 	}, nil
 }
 
@@ -463,6 +485,103 @@ func NewRPCReceipt(info *rawapitypes.ReceiptInfo) (*RPCReceipt, error) {
 	return res, nil
 }
 
+type TransactionTreeStatus string
+
+const (
+	TransactionTreeStatusPending TransactionTreeStatus = "pending"
+	TransactionTreeStatusSuccess TransactionTreeStatus = "success"
+	TransactionTreeStatusFailed  TransactionTreeStatus = "failed"
+)
+
+// @component RPCTransactionTreeNode rpcTransactionTreeNode object "A hop of the tree of transactions caused by the root transaction."
+// @componentprop TxnHash transactionHash string true "The hash of the transaction."
+// @componentprop ShardId shardId integer true "The shard that processes the transaction."
+// @componentprop Status status string true "One of pending, success or failed. Pending transactions are not processed by the shard yet."
+// @componentprop Flags flags string false "The array of transaction flags."
+// @componentprop From from string false "The address from where the transaction was sent."
+// @componentprop To to string false "The address where the transaction was sent."
+// @componentprop Value value string false "The transaction value."
+// @componentprop RequestId requestId integer false "The identifier of the async request or response."
+// @componentprop SourceShardId sourceShardId integer false "The shard that emitted the transaction. Not set for the root transaction."
+// @componentprop SourceBlockNumber sourceBlockNumber integer false "The number of the block that emitted the transaction. Not set for the root transaction."
+// @componentprop BlockNumber blockNumber integer false "The number of the block containing the processed transaction."
+// @componentprop GasUsed gasUsed string false "The amount of gas spent on the transaction."
+// @componentprop GasPrice gasPrice string false "The gas price at the time of processing the transaction."
+// @componentprop Fee fee string false "The fee paid for the gas spent on the transaction."
+// @componentprop ErrorMessage errorMessage string false "The error in case the transaction processing was unsuccessful."
+// @componentprop Receipt receipt object false "The receipt of the processed transaction without the receipts of the outgoing transactions."
+// @componentprop Children children array false "The outgoing transactions emitted by the transaction."
+type RPCTransactionTreeNode struct {
+	TxnHash           common.Hash               `json:"transactionHash"`
+	ShardId           types.ShardId             `json:"shardId"`
+	Status            TransactionTreeStatus     `json:"status"`
+	Flags             types.TransactionFlags    `json:"flags"`
+	From              types.Address             `json:"from"`
+	To                types.Address             `json:"to"`
+	Value             types.Value               `json:"value"`
+	RequestId         uint64                    `json:"requestId,omitempty"`
+	SourceShardId     *types.ShardId            `json:"sourceShardId,omitempty"`
+	SourceBlockNumber *types.BlockNumber        `json:"sourceBlockNumber,omitempty"`
+	BlockNumber       *types.BlockNumber        `json:"blockNumber,omitempty"`
+	GasUsed           types.Gas                 `json:"gasUsed"`
+	GasPrice          types.Value               `json:"gasPrice"`
+	Fee               types.Value               `json:"fee"`
+	ErrorMessage      string                    `json:"errorMessage,omitempty"`
+	Receipt           *RPCReceipt               `json:"receipt,omitempty"`
+	Children          []*RPCTransactionTreeNode `json:"children,omitempty"`
+}
+
+func (n *RPCTransactionTreeNode) setTransaction(txn *types.Transaction) {
+	n.Flags = txn.Flags
+	n.From = txn.From
+	n.To = txn.To
+	n.Value = txn.Value
+	n.RequestId = txn.RequestId
+}
+
+func (n *RPCTransactionTreeNode) setReceipt(receipt *RPCReceipt) {
+	n.Receipt = receipt
+	n.Status = TransactionTreeStatusFailed
+	if receipt.Success {
+		n.Status = TransactionTreeStatusSuccess
+	}
+	n.GasUsed = receipt.GasUsed
+	n.GasPrice = receipt.GasPrice
+	if receipt.GasPrice.Uint256 != nil {
+		n.Fee = receipt.GasUsed.ToValue(receipt.GasPrice)
+	}
+	n.ErrorMessage = receipt.ErrorMessage
+	if !receipt.Temporary {
+		blockNumber := receipt.BlockNumber
+		n.BlockNumber = &blockNumber
+	}
+}
+
+// IsComplete returns true if none of the transactions of the tree is pending.
+func (n *RPCTransactionTreeNode) IsComplete() bool {
+	if n == nil || n.Status == TransactionTreeStatusPending {
+		return false
+	}
+	for _, child := range n.Children {
+		if !child.IsComplete() {
+			return false
+		}
+	}
+	return true
+}
+
+// Walk calls f for every node of the tree in depth-first order.
+func (n *RPCTransactionTreeNode) Walk(f func(node *RPCTransactionTreeNode, depth int)) {
+	n.walk(f, 0)
+}
+
+func (n *RPCTransactionTreeNode) walk(f func(node *RPCTransactionTreeNode, depth int), depth int) {
+	f(n, depth)
+	for _, child := range n.Children {
+		child.walk(f, depth+1)
+	}
+}
+
 // @component DebugRPCContract debugRpcContract object "The debug contract whose structure is requested."
 // @componentprop Code HEX-encoded contract code
 // @componentprop Contract serialized types.SmartContract structure
@@ -565,3 +684,250 @@ type EstimateFeeRes struct {
 	AveragePriorityFee types.Value `json:"averagePriorityFee"`
 	MaxBasFee          types.Value `json:"maxBaseFee"`
 }
+
+// @component FeeHistory feeHistory object "The fee history of the shard."
+// @componentprop OldestBlock oldestBlock integer true "The number of the oldest block in the range."
+// @componentprop BaseFee baseFeePerGas array true "The base fees of the blocks in the range followed by the base fee of the next block."
+// @componentprop GasUsedRatio gasUsedRatio array true "The ratios of the gas used by the blocks to the block gas limit."
+// @componentprop Reward reward array false "The effective priority fees paid at the requested percentiles of the gas used in each block."
+// @componentprop GasPrice gasPrice string true "The current gas price of the shard as set in the config of the main shard."
+type FeeHistory struct {
+	OldestBlock  types.BlockNumber `json:"oldestBlock"`
+	BaseFee      []types.Value     `json:"baseFeePerGas"`
+	GasUsedRatio []float64         `json:"gasUsedRatio"`
+	Reward       [][]types.Value   `json:"reward,omitempty"`
+	GasPrice     types.Value       `json:"gasPrice"`
+}
+
+// @component AccountProof accountProof object "The Merkle proofs of the account and of the requested storage slots and tokens."
+// @componentprop Address address string true "The address of the account."
+// @componentprop BlockHash blockHash string true "The hash of the block whose state is proven."
+// @componentprop BlockNumber blockNumber integer true "The number of the block whose state is proven."
+// @componentprop Contract contract string false "The SSZ-encoded account. Empty if the account doesn't exist."
+// @componentprop AccountProof accountProof string true "The encoded proof of the account against the contract trie root of the block."
+// @componentprop Balance balance integer true "The balance of the account."
+// @componentprop CodeHash codeHash string true "The hash of the code of the account."
+// @componentprop Seqno seqno integer true "The seqno of the account."
+// @componentprop ExtSeqno extSeqno integer true "The external seqno of the account."
+// @componentprop StorageHash storageHash string true "The root of the storage trie of the account."
+// @componentprop TokenHash tokenHash string true "The root of the token trie of the account."
+// @componentprop StorageProof storageProof array true "The proofs of the requested storage slots."
+// @componentprop TokenProof tokenProof array true "The proofs of the requested tokens."
+type RPCAccountProof struct {
+	Address      types.Address     `json:"address"`
+	BlockHash    common.Hash       `json:"blockHash"`
+	BlockNumber  types.BlockNumber `json:"blockNumber"`
+	Contract     hexutil.Bytes     `json:"contract,omitempty"`
+	AccountProof hexutil.Bytes     `json:"accountProof"`
+	Balance      types.Value       `json:"balance"`
+	CodeHash     common.Hash       `json:"codeHash"`
+	Seqno        hexutil.Uint64    `json:"seqno"`
+	ExtSeqno     hexutil.Uint64    `json:"extSeqno"`
+	StorageHash  common.Hash       `json:"storageHash"`
+	TokenHash    common.Hash       `json:"tokenHash"`
+	StorageProof []RPCStorageProof `json:"storageProof"`
+	TokenProof   []RPCTokenProof   `json:"tokenProof"`
+}
+
+// @component RPCStorageProof rpcStorageProof object "The proof of a storage slot of the account."
+// @componentprop Key key string true "The storage slot."
+// @componentprop Value value string true "The value of the slot, zero if the slot is not set."
+// @componentprop Proof proof string true "The encoded proof of the slot against the storage trie root of the account."
+type RPCStorageProof struct {
+	Key   common.Hash   `json:"key"`
+	Value types.Uint256 `json:"value"`
+	Proof hexutil.Bytes `json:"proof"`
+}
+
+// @component RPCTokenProof rpcTokenProof object "The proof of a token balance of the account."
+// @componentprop Token token string true "The ID of the token."
+// @componentprop Value value integer true "The balance of the token, zero if the account has none."
+// @componentprop Proof proof string true "The encoded proof of the token against the token trie root of the account."
+type RPCTokenProof struct {
+	Token types.TokenId `json:"token"`
+	Value types.Value   `json:"value"`
+	Proof hexutil.Bytes `json:"proof"`
+}
+
+// NewRPCAccountProof converts the proofs returned by the raw API.
+func NewRPCAccountProof(address types.Address, proof *rawapitypes.AccountProof) (*RPCAccountProof, error) {
+	res := &RPCAccountProof{
+		Address:      address,
+		BlockHash:    proof.BlockHash,
+		BlockNumber:  proof.BlockId,
+		Contract:     proof.ContractSSZ,
+		AccountProof: proof.ProofEncoded,
+		Balance:      types.NewZeroValue(),
+		StorageProof: make([]RPCStorageProof, len(proof.StorageProofs)),
+		TokenProof:   make([]RPCTokenProof, len(proof.TokenProofs)),
+	}
+
+	if len(proof.ContractSSZ) != 0 {
+		contract := new(types.SmartContract)
+		if err := contract.UnmarshalSSZ(proof.ContractSSZ); err != nil {
+			return nil, err
+		}
+		res.Balance = contract.Balance
+		res.CodeHash = contract.CodeHash
+		res.Seqno = hexutil.Uint64(contract.Seqno)
+		res.ExtSeqno = hexutil.Uint64(contract.ExtSeqno)
+		res.StorageHash = contract.StorageRoot
+		res.TokenHash = contract.TokenRoot
+	}
+
+	for i, p := range proof.StorageProofs {
+		res.StorageProof[i] = RPCStorageProof{Key: p.Key, Value: p.Value, Proof: p.ProofEncoded}
+	}
+	for i, p := range proof.TokenProofs {
+		res.TokenProof[i] = RPCTokenProof{Token: p.Token, Value: p.Value, Proof: p.ProofEncoded}
+	}
+	return res, nil
+}
+
+// @component ReceiptInclusionProof receiptInclusionProof object "The chained proof of the receipt inclusion into the main shard block."
+// @componentprop TransactionHash transactionHash string true "The hash of the transaction."
+// @componentprop ShardId shardId integer true "The shard of the receipt."
+// @componentprop Receipt receipt string true "The SSZ-encoded receipt."
+// @componentprop ReceiptIndex receiptIndex integer true "The index of the receipt in the block."
+// @componentprop ReceiptProof receiptProof string true "The encoded proof of the receipt against the receipt trie root of the first block header."
+// @componentprop BlockHeaders blockHeaders array true "The SSZ-encoded headers of the consecutive shard blocks from the block of the receipt to the block referenced by the main block."
+// @componentprop MainBlockHash mainBlockHash string true "The hash of the main shard block."
+// @componentprop MainBlockNumber mainBlockNumber integer true "The number of the main shard block."
+// @componentprop ShardBlockProof shardBlockProof string false "The encoded proof of the last block header against the shard blocks trie root of the main block. Empty for the main shard receipts."
+type RPCReceiptInclusionProof struct {
+	TransactionHash common.Hash       `json:"transactionHash"`
+	ShardId         types.ShardId     `json:"shardId"`
+	Receipt         hexutil.Bytes     `json:"receipt"`
+	ReceiptIndex    hexutil.Uint64    `json:"receiptIndex"`
+	ReceiptProof    hexutil.Bytes     `json:"receiptProof"`
+	BlockHeaders    []hexutil.Bytes   `json:"blockHeaders"`
+	MainBlockHash   common.Hash       `json:"mainBlockHash"`
+	MainBlockNumber types.BlockNumber `json:"mainBlockNumber"`
+	ShardBlockProof hexutil.Bytes     `json:"shardBlockProof,omitempty"`
+}
+
+// NewRPCReceiptInclusionProof converts the proof returned by the raw API.
+func NewRPCReceiptInclusionProof(hash common.Hash, proof *rawapitypes.ReceiptInclusionProof) (*RPCReceiptInclusionProof, error) {
+	if len(proof.Headers) == 0 {
+		return nil, errors.New("receipt inclusion proof has no block headers")
+	}
+
+	shardId := types.ShardIdFromHash(hash)
+	res := &RPCReceiptInclusionProof{
+		TransactionHash: hash,
+		ShardId:         shardId,
+		Receipt:         proof.ReceiptSSZ,
+		ReceiptIndex:    hexutil.Uint64(proof.Index),
+		ReceiptProof:    proof.ProofEncoded,
+		BlockHeaders:    make([]hexutil.Bytes, len(proof.Headers)),
+	}
+	for i, header := range proof.Headers {
+		res.BlockHeaders[i] = hexutil.Bytes(header)
+	}
+
+	if proof.ShardBlockProof != nil {
+		res.MainBlockHash = proof.ShardBlockProof.MainBlockHash
+		res.MainBlockNumber = proof.ShardBlockProof.MainBlockId
+		res.ShardBlockProof = proof.ShardBlockProof.ProofEncoded
+		return res, nil
+	}
+
+	// The main shard receipts are chained up to the main block itself.
+	mainBlock := new(types.Block)
+	if err := mainBlock.UnmarshalSSZ(proof.Headers[len(proof.Headers)-1]); err != nil {
+		return nil, err
+	}
+	res.MainBlockHash = mainBlock.Hash(shardId)
+	res.MainBlockNumber = mainBlock.Id
+	return res, nil
+}
+
+// @component RPCPoolTransaction rpcPoolTransaction object "The transaction waiting in the pool for inclusion into a block."
+// @componentprop Hash hash string true "The transaction hash."
+// @componentprop Flags flags string true "The array of transaction flags."
+// @componentprop From from string true "The address from where the transaction was sent."
+// @componentprop To to string true "The address where the transaction was sent."
+// @componentprop Seqno seqno string true "The sequence number of the transaction."
+// @componentprop Value value string true "The transaction value."
+// @componentprop FeeCredit feeCredit string true "The fee credit for the transaction."
+// @componentprop MaxPriorityFeePerGas maxPriorityFeePerGas string true "Priority fee for the transaction."
+// @componentprop MaxFeePerGas maxFeePerGas string true "Maximum fee per gas for the transaction."
+// @componentprop EffectivePriorityFee effectivePriorityFee string true "The priority fee the transaction pays at the current base fee."
+type RPCPoolTransaction struct {
+	Hash                 common.Hash            `json:"hash"`
+	Flags                types.TransactionFlags `json:"flags"`
+	From                 types.Address          `json:"from"`
+	To                   types.Address          `json:"to"`
+	RefundTo             types.Address          `json:"refundTo"`
+	BounceTo             types.Address          `json:"bounceTo"`
+	Seqno                hexutil.Uint64         `json:"seqno"`
+	Value                types.Value            `json:"value"`
+	Token                []types.TokenBalance   `json:"token,omitempty"`
+	Data                 hexutil.Bytes          `json:"data"`
+	FeeCredit            types.Value            `json:"feeCredit"`
+	MaxPriorityFeePerGas types.Value            `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         types.Value            `json:"maxFeePerGas"`
+	EffectivePriorityFee types.Value            `json:"effectivePriorityFee"`
+	ChainID              types.ChainId          `json:"chainId,omitempty"`
+	Signature            types.Signature        `json:"signature"`
+}
+
+func NewRPCPoolTransaction(txn *types.TxnWithHash, baseFee types.Value) *RPCPoolTransaction {
+	effectivePriorityFee, _ := execution.GetEffectivePriorityFee(baseFee, txn.Transaction)
+	return &RPCPoolTransaction{
+		Hash:                 txn.Hash(),
+		Flags:                txn.Flags,
+		From:                 txn.From,
+		To:                   txn.To,
+		RefundTo:             txn.RefundTo,
+		BounceTo:             txn.BounceTo,
+		Seqno:                hexutil.Uint64(txn.Seqno),
+		Value:                txn.Value,
+		Token:                txn.Token,
+		Data:                 hexutil.Bytes(txn.Data),
+		FeeCredit:            txn.FeeCredit,
+		MaxPriorityFeePerGas: txn.MaxPriorityFeePerGas,
+		MaxFeePerGas:         txn.MaxFeePerGas,
+		EffectivePriorityFee: effectivePriorityFee,
+		ChainID:              txn.ChainId,
+		Signature:            txn.Signature,
+	}
+}
+
+// TxPoolTransactions maps receivers to their transactions in the pool by seqno.
+type TxPoolTransactions[T any] map[types.Address]map[types.Seqno]T
+
+func (m TxPoolTransactions[T]) add(txn *types.TxnWithHash, value T) {
+	byReceiver, ok := m[txn.To]
+	if !ok {
+		byReceiver = make(map[types.Seqno]T)
+		m[txn.To] = byReceiver
+	}
+	byReceiver[txn.Seqno] = value
+}
+
+// @component TxPoolContent txPoolContent object "The transactions in the pool of the shard."
+// @componentprop BaseFee baseFee string true "The base fee the pool uses to check whether the transactions are executable."
+// @componentprop Pending pending object true "The transactions that can be included into a block, by receiver and seqno."
+// @componentprop Queued queued object true "The transactions whose max fee is below the base fee, by receiver and seqno."
+type TxPoolContent struct {
+	BaseFee types.Value                             `json:"baseFee"`
+	Pending TxPoolTransactions[*RPCPoolTransaction] `json:"pending"`
+	Queued  TxPoolTransactions[*RPCPoolTransaction] `json:"queued"`
+}
+
+// @component TxPoolStatus txPoolStatus object "The number of transactions in the pool of the shard."
+// @componentprop Pending pending integer true "The number of transactions that can be included into a block."
+// @componentprop Queued queued integer true "The number of transactions whose max fee is below the base fee."
+type TxPoolStatus struct {
+	Pending hexutil.Uint `json:"pending"`
+	Queued  hexutil.Uint `json:"queued"`
+}
+
+// @component TxPoolInspect txPoolInspect object "The textual summary of the transactions in the pool of the shard."
+// @componentprop Pending pending object true "The summaries of the transactions that can be included into a block, by receiver and seqno."
+// @componentprop Queued queued object true "The summaries of the transactions whose max fee is below the base fee, by receiver and seqno."
+type TxPoolInspect struct {
+	Pending TxPoolTransactions[string] `json:"pending"`
+	Queued  TxPoolTransactions[string] `json:"queued"`
+}
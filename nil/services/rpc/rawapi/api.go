@@ -2,10 +2,12 @@ package rawapi
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -20,20 +22,36 @@ type NodeApiRo interface {
 
 	GetInTransaction(ctx context.Context, shardId types.ShardId, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
 	GetInTransactionReceipt(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetReceiptInclusionProof(
+		ctx context.Context, shardId types.ShardId, hash common.Hash, mainBlockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ReceiptInclusionProof, error)
+	GetOutTransaction(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.TransactionInfo, error)
 
 	GetBalance(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
 	GetCode(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error)
 	GetTokens(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (map[types.TokenId]types.Value, error)
 	GetTransactionCount(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
 	GetContract(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.SmartContract, error)
+	GetProof(
+		ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.AccountProof, error)
 
 	Call(
 		ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
 
+	TraceTransaction(ctx context.Context, shardId types.ShardId, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error)
+	TraceCall(
+		ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, cfg *tracers.Config,
+	) (json.RawMessage, error)
+
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
+	GetTxpoolContent(ctx context.Context, shardId types.ShardId) (*txnpool.Content, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	GetShardBlockProof(
+		ctx context.Context, shardId types.ShardId, mainBlockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ShardBlockProof, error)
 }
 
 type NodeApi interface {
@@ -48,20 +66,36 @@ type ShardApiRo interface {
 
 	GetInTransaction(ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetReceiptInclusionProof(
+		ctx context.Context, hash common.Hash, mainBlockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ReceiptInclusionProof, error)
+	GetOutTransaction(ctx context.Context, hash common.Hash) (*rawapitypes.TransactionInfo, error)
 
 	GetBalance(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
 	GetCode(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error)
 	GetTokens(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (map[types.TokenId]types.Value, error)
 	GetTransactionCount(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
 	GetContract(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.SmartContract, error)
+	GetProof(
+		ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.AccountProof, error)
 
 	Call(
 		ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
 
+	TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error)
+	TraceCall(
+		ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, cfg *tracers.Config,
+	) (json.RawMessage, error)
+
 	GasPrice(ctx context.Context) (types.Value, error)
+	GetTxpoolContent(ctx context.Context) (*txnpool.Content, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	GetShardBlockProof(
+		ctx context.Context, shardId types.ShardId, mainBlockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ShardBlockProof, error)
 
 	setAsP2pRequestHandlersIfAllowed(ctx context.Context, networkManager *network.Manager, readonly bool, logger zerolog.Logger) error
 	setNodeApi(nodeApi NodeApi)
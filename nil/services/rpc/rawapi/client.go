@@ -2,6 +2,7 @@ package rawapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -12,6 +13,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -145,12 +147,28 @@ func (api *ShardApiAccessor) GetContract(ctx context.Context, address types.Addr
 	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.SmartContract](ctx, api, "GetContract", address, blockReference)
 }
 
+func (api *ShardApiAccessor) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountProof, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.AccountProof](ctx, api, "GetProof", address, storageKeys, tokens, blockReference)
+}
+
 func (api *ShardApiAccessor) Call(
 	ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides,
 ) (*rpctypes.CallResWithGasPrice, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[*rpctypes.CallResWithGasPrice](ctx, api, "Call", args, mainBlockReferenceOrHashWithChildren, overrides)
 }
 
+func (api *ShardApiAccessor) TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[json.RawMessage](ctx, api, "TraceTransaction", hash, cfg)
+}
+
+func (api *ShardApiAccessor) TraceCall(
+	ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, cfg *tracers.Config,
+) (json.RawMessage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[json.RawMessage](ctx, api, "TraceCall", args, mainBlockReferenceOrHashWithChildren, overrides, cfg)
+}
+
 func (api *ShardApiAccessor) GetInTransaction(ctx context.Context, request rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TransactionInfo](ctx, api, "GetInTransaction", request)
 }
@@ -159,10 +177,30 @@ func (api *ShardApiAccessor) GetInTransactionReceipt(ctx context.Context, hash c
 	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ReceiptInfo](ctx, api, "GetInTransactionReceipt", hash)
 }
 
+func (api *ShardApiAccessor) GetReceiptInclusionProof(
+	ctx context.Context, hash common.Hash, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.ReceiptInclusionProof, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ReceiptInclusionProof](ctx, api, "GetReceiptInclusionProof", hash, mainBlockReference)
+}
+
+func (api *ShardApiAccessor) GetOutTransaction(ctx context.Context, hash common.Hash) (*rawapitypes.TransactionInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TransactionInfo](ctx, api, "GetOutTransaction", hash)
+}
+
 func (api *ShardApiAccessor) GasPrice(ctx context.Context) (types.Value, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GasPrice")
 }
 
+func (api *ShardApiAccessor) GetTxpoolContent(ctx context.Context) (*txnpool.Content, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*txnpool.Content](ctx, api, "GetTxpoolContent")
+}
+
+func (api *ShardApiAccessor) GetShardBlockProof(
+	ctx context.Context, shardId types.ShardId, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.ShardBlockProof, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ShardBlockProof](ctx, api, "GetShardBlockProof", shardId, mainBlockReference)
+}
+
 func (api *ShardApiAccessor) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[[]types.ShardId](ctx, api, "GetShardIdList")
 }
@@ -166,6 +166,98 @@ func (api *LocalShardApi) GetContract(ctx context.Context, address types.Address
 	}, nil
 }
 
+func (api *LocalShardApi) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountProof, error) {
+	if address.ShardId() != api.ShardId {
+		return nil, fmt.Errorf("address is not in the shard %d", api.ShardId)
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+	block, err := db.ReadBlock(tx, api.ShardId, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.accessor.RawAccess(tx, api.ShardId).CheckStateAvailable(block.Id); err != nil {
+		return nil, err
+	}
+
+	res := &rawapitypes.AccountProof{
+		BlockHash: blockHash,
+		BlockId:   block.Id,
+	}
+
+	contracts := mpt.NewDbReader(tx, api.ShardId, db.ContractTrieTable)
+	contracts.SetRootHash(block.SmartContractsRoot)
+	contractRaw, err := contracts.Get(address.Hash().Bytes())
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, err
+	}
+	if res.ProofEncoded, err = buildEncodedProof(contracts, address.Hash().Bytes()); err != nil {
+		return nil, err
+	}
+	if contractRaw == nil {
+		return res, nil
+	}
+	res.ContractSSZ = contractRaw
+
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(contractRaw); err != nil {
+		return nil, err
+	}
+
+	storage := execution.NewDbStorageTrieReader(tx, api.ShardId)
+	storage.SetRootHash(contract.StorageRoot)
+	for _, key := range storageKeys {
+		p := rawapitypes.StorageProof{Key: key}
+		value, err := storage.Fetch(key)
+		if err == nil {
+			p.Value = *value
+		} else if !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+		if p.ProofEncoded, err = buildEncodedProof(storage.Reader, key.Bytes()); err != nil {
+			return nil, err
+		}
+		res.StorageProofs = append(res.StorageProofs, p)
+	}
+
+	tokenReader := execution.NewDbTokenTrieReader(tx, api.ShardId)
+	tokenReader.SetRootHash(contract.TokenRoot)
+	for _, token := range tokens {
+		p := rawapitypes.TokenProof{Token: token, Value: types.NewZeroValue()}
+		value, err := tokenReader.Fetch(token)
+		if err == nil {
+			p.Value = *value
+		} else if !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+		if p.ProofEncoded, err = buildEncodedProof(tokenReader.Reader, token[:]); err != nil {
+			return nil, err
+		}
+		res.TokenProofs = append(res.TokenProofs, p)
+	}
+
+	return res, nil
+}
+
+func buildEncodedProof(reader *mpt.Reader, key []byte) ([]byte, error) {
+	proof, err := mpt.BuildProof(reader, key, mpt.ReadMPTOperation)
+	if err != nil {
+		return nil, err
+	}
+	return proof.Encode()
+}
+
 type proofBuilder = func(operation mpt.MPTOperation) (mpt.Proof, error)
 
 func makeProofBuilder(root *mpt.Reader, key []byte) proofBuilder {
@@ -186,6 +278,9 @@ func (api *LocalShardApi) getRawSmartContract(tx db.RoTx, address types.Address,
 	if err := block.UnmarshalSSZ(rawBlock.Block); err != nil {
 		return nil, nil, err
 	}
+	if err := api.accessor.RawAccess(tx, api.ShardId).CheckStateAvailable(block.Id); err != nil {
+		return nil, nil, err
+	}
 
 	root := mpt.NewDbReader(tx, api.ShardId, db.ContractTrieTable)
 	root.SetRootHash(block.SmartContractsRoot)
@@ -126,17 +126,21 @@ func (api *LocalShardApi) handleOutTransactions(
 	return outTransactions, nil
 }
 
-func (api *LocalShardApi) Call(
-	ctx context.Context, args rpctypes.CallArgs,
+// callContext is the state prepared for executing a transaction passed to Call.
+type callContext struct {
+	txn           *types.Transaction
+	es            *execution.ExecutionState
+	payer         execution.Payer
+	block         *types.Block
+	mainBlockHash common.Hash
+	childBlocks   []common.Hash
+}
+
+func (api *LocalShardApi) prepareCall(
+	ctx context.Context, tx db.RoTx, methodName string, args rpctypes.CallArgs,
 	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 	overrides *rpctypes.StateOverrides,
-) (*rpctypes.CallResWithGasPrice, error) {
-	tx, err := api.db.CreateRoTx(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
+) (*callContext, error) {
 	txn, err := args.ToTransaction()
 	if err != nil {
 		return nil, err
@@ -168,7 +172,7 @@ func (api *LocalShardApi) Call(
 	if !shardId.IsMainShard() {
 		if len(childBlocks) < int(shardId) {
 			return nil, fmt.Errorf("%w: main shard includes only %d blocks",
-				makeShardNotFoundError(methodNameChecked("Call"), shardId), len(childBlocks))
+				makeShardNotFoundError(methodName, shardId), len(childBlocks))
 		}
 		hash = childBlocks[shardId-1]
 	} else {
@@ -179,6 +183,9 @@ func (api *LocalShardApi) Call(
 	if err != nil {
 		return nil, fmt.Errorf("failed to read block %s: %w", hash, err)
 	}
+	if err := api.accessor.RawAccess(tx, shardId).CheckStateAvailable(block.Id); err != nil {
+		return nil, err
+	}
 
 	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, block, shardId)
 	if err != nil {
@@ -225,8 +232,37 @@ func (api *LocalShardApi) Call(
 		payer = execution.NewAccountPayer(toAs, txn)
 	}
 
+	return &callContext{
+		txn:           txn,
+		es:            es,
+		payer:         payer,
+		block:         block,
+		mainBlockHash: mainBlockHash,
+		childBlocks:   childBlocks,
+	}, nil
+}
+
+func (api *LocalShardApi) Call(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("Call")
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.prepareCall(ctx, tx, methodName, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+	txn, es, shardId := call.txn, call.es, call.txn.To.ShardId()
+
 	txnHash := es.AddInTransaction(txn)
-	res := es.HandleTransaction(ctx, txn, payer)
+	res := es.HandleTransaction(ctx, txn, call.payer)
 
 	result := &rpctypes.CallResWithGasPrice{
 		Data:      res.ReturnData,
@@ -241,7 +277,7 @@ func (api *LocalShardApi) Call(
 	}
 
 	esOld, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
-		Block:          block,
+		Block:          call.block,
 		ConfigAccessor: config.GetStubAccessor(),
 	})
 	if err != nil {
@@ -256,8 +292,8 @@ func (api *LocalShardApi) Call(
 	outTransactions, err := api.handleOutTransactions(
 		ctx,
 		execOutTransactions,
-		mainBlockHash,
-		childBlocks,
+		call.mainBlockHash,
+		call.childBlocks,
 		&stateOverrides,
 	)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	fastssz "github.com/NilFoundation/fastssz"
 	"github.com/NilFoundation/nil/nil/common"
@@ -140,6 +141,85 @@ func (api *LocalShardApi) GetInTransactionReceipt(ctx context.Context, hash comm
 	}, nil
 }
 
+// maxInclusionProofHeaders limits the number of the shard block headers in a receipt inclusion proof.
+const maxInclusionProofHeaders = 1024
+
+func (api *LocalShardApi) GetReceiptInclusionProof(
+	ctx context.Context, hash common.Hash, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.ReceiptInclusionProof, error) {
+	res := &rawapitypes.ReceiptInclusionProof{}
+
+	// The headers are chained up to the block referenced by the main block, which is the main block itself for
+	// the main shard receipts.
+	lastBlockReference := mainBlockReference
+	if !api.ShardId.IsMainShard() {
+		var err error
+		res.ShardBlockProof, err = api.nodeApi.GetShardBlockProof(ctx, api.ShardId, mainBlockReference)
+		if err != nil {
+			return nil, err
+		}
+		lastBlockReference = rawapitypes.BlockHashAsBlockReference(res.ShardBlockProof.BlockHash)
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, indexes, err := api.getBlockAndInTransactionIndexByTransactionHash(tx, api.ShardId, hash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, fmt.Errorf("transaction %s is not included in a block of the shard %d", hash, api.ShardId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := execution.NewDbReceiptTrieReader(tx, api.ShardId)
+	receipts.SetRootHash(block.ReceiptsRoot)
+	res.Index = indexes.TransactionIndex
+	if res.ReceiptSSZ, err = receipts.Get(res.Index.Bytes()); err != nil {
+		return nil, err
+	}
+	if res.ProofEncoded, err = buildEncodedProof(receipts.Reader, res.Index.Bytes()); err != nil {
+		return nil, err
+	}
+
+	blockHash, err := api.getBlockHashByReference(tx, lastBlockReference)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		raw, err := db.ReadBlockSSZ(tx, api.ShardId, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		header := new(types.Block)
+		if err := header.UnmarshalSSZ(raw); err != nil {
+			return nil, err
+		}
+		if header.Id < block.Id {
+			return nil, fmt.Errorf("block %d of the transaction is not included in the main block yet", block.Id)
+		}
+		if len(res.Headers) == maxInclusionProofHeaders {
+			return nil, fmt.Errorf("block %d of the transaction is more than %d blocks behind the main block reference, "+
+				"choose an earlier main block", block.Id, maxInclusionProofHeaders)
+		}
+		res.Headers = append(res.Headers, raw)
+
+		if header.Id == block.Id {
+			if blockHash != indexes.BlockHash {
+				return nil, fmt.Errorf("block %d of the transaction is not in the chain of the main block", block.Id)
+			}
+			break
+		}
+		blockHash = header.PrevBlock
+	}
+	slices.Reverse(res.Headers)
+
+	return res, nil
+}
+
 func (api *LocalShardApi) getBlockAndInTransactionIndexByTransactionHash(tx db.RoTx, shardId types.ShardId, hash common.Hash) (*types.Block, db.BlockHashAndTransactionIndex, error) {
 	var index db.BlockHashAndTransactionIndex
 	value, err := tx.GetFromShard(shardId, db.BlockHashAndInTransactionIndexByTransactionHash, hash.Bytes())
@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 func (api *LocalShardApi) GasPrice(ctx context.Context) (types.Value, error) {
@@ -50,6 +51,53 @@ func (api *LocalShardApi) GetShardIdList(ctx context.Context) ([]types.ShardId,
 	return treeShards.Keys()
 }
 
+func (api *LocalShardApi) GetShardBlockProof(
+	ctx context.Context, shardId types.ShardId, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.ShardBlockProof, error) {
+	if api.ShardId != types.MainShardId {
+		return nil, errors.New("GetShardBlockProof is only supported for the main shard")
+	}
+	if shardId.IsMainShard() {
+		return nil, errors.New("main shard blocks are not referenced by the main shard blocks")
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	mainBlockHash, err := api.getBlockHashByReference(tx, mainBlockReference)
+	if err != nil {
+		return nil, err
+	}
+	mainBlock, err := db.ReadBlock(tx, types.MainShardId, mainBlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	treeShards := execution.NewDbShardBlocksTrieReader(tx, types.MainShardId, mainBlock.Id)
+	treeShards.SetRootHash(mainBlock.ChildBlocksRootHash)
+	blockHash, err := treeShards.Fetch(shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, fmt.Errorf("shard %d is not referenced by the main block %d", shardId, mainBlock.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := buildEncodedProof(treeShards.Reader, shardId.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.ShardBlockProof{
+		MainBlockHash: mainBlockHash,
+		MainBlockId:   mainBlock.Id,
+		BlockHash:     *blockHash,
+		ProofEncoded:  proof,
+	}, nil
+}
+
 func (api *LocalShardApi) GetNumShards(ctx context.Context) (uint64, error) {
 	shards, err := api.GetShardIdList(ctx)
 	if err != nil {
@@ -0,0 +1,71 @@
+package rawapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+func (api *LocalShardApi) TraceTransaction(ctx context.Context, hash common.Hash, cfg *tracers.Config) (json.RawMessage, error) {
+	tracer, err := tracers.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, index, err := api.getBlockAndInTransactionIndexByTransactionHash(tx, api.ShardId, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction %s: %w", hash, err)
+	}
+
+	if _, err := execution.ReplayTransaction(
+		ctx, tx, api.accessor, api.ShardId, index.BlockHash, index.TransactionIndex, tracer.Hooks(),
+	); err != nil {
+		return nil, err
+	}
+	return tracer.GetResult()
+}
+
+func (api *LocalShardApi) TraceCall(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	cfg *tracers.Config,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceCall")
+
+	tracer, err := tracers.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.prepareCall(ctx, tx, methodName, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	// Outbound transactions are not followed: the trace covers the execution on this shard only.
+	call.es.SetEvmTracer(tracer.Hooks())
+	call.es.AddInTransaction(call.txn)
+	if res := call.es.HandleTransaction(ctx, call.txn, call.payer); res.FatalError != nil {
+		return nil, res.FatalError
+	}
+	return tracer.GetResult()
+}
@@ -0,0 +1,175 @@
+package rawapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	// storeAndLoadCode stores 42 into slot 0, reads it back and returns it.
+	storeAndLoadCode = "602a60005560005460005260206000f3"
+
+	// proxyCodePrefix calls the contract with the address appended to the prefix
+	// and returns the first 32 bytes of its output.
+	proxyCodePrefix = "60206000600060006000" + "73"
+	proxyCodeSuffix = "5af1506020" + "6000f3"
+)
+
+type TraceTransactionTestSuite struct {
+	suite.Suite
+
+	ctx context.Context
+	db  db.DB
+	api *LocalShardApi
+
+	proxy   types.Address
+	storage types.Address
+	txnHash common.Hash
+}
+
+func TestTraceTransactionTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TraceTransactionTestSuite))
+}
+
+// SetupSuite commits two blocks: the first one deploys the contracts,
+// the second one contains a transaction to the proxy contract which calls the storage contract.
+func (s *TraceTransactionTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	cfgAccessor, err := config.NewConfigAccessorTx(tx, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(config.SetParamGasPrice(cfgAccessor, &config.ParamGasPrice{
+		Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+	}))
+
+	es, err := execution.NewExecutionState(tx, types.MainShardId, execution.StateParams{
+		ConfigAccessor: cfgAccessor,
+	})
+	s.Require().NoError(err)
+	es.BaseFee = types.DefaultGasPrice
+
+	s.storage = types.GenerateRandomAddress(types.MainShardId)
+	s.proxy = types.GenerateRandomAddress(types.MainShardId)
+	s.Require().NoError(es.CreateAccount(s.storage))
+	s.Require().NoError(es.SetCode(s.storage, hexutil.FromHex(storeAndLoadCode)))
+	s.Require().NoError(es.CreateAccount(s.proxy))
+	s.Require().NoError(es.SetCode(s.proxy, hexutil.FromHex(proxyCodePrefix+s.storage.Hex()[2:]+proxyCodeSuffix)))
+	s.Require().NoError(es.SetBalance(s.proxy, types.GasToValue(1_000_000)))
+
+	prevBlock := s.commitBlock(tx, es, 0)
+
+	cfgAccessor, err = config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, types.MainShardId)
+	s.Require().NoError(err)
+	es, err = execution.NewExecutionState(tx, types.MainShardId, execution.StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: cfgAccessor,
+	})
+	s.Require().NoError(err)
+	es.BaseFee = types.DefaultGasPrice
+
+	txn := types.NewEmptyTransaction()
+	txn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+	txn.To = s.proxy
+	txn.From = s.proxy
+	txn.FeeCredit = types.GasToValue(100_000)
+	txn.MaxFeePerGas = types.MaxFeePerGasDefault
+	s.txnHash = txn.Hash()
+
+	es.AddInTransaction(txn)
+	res := es.HandleTransaction(s.ctx, txn, execution.NewTransactionPayer(txn, es))
+	s.Require().False(res.Failed(), "%v", res.GetError())
+	es.AddReceipt(res)
+
+	s.commitBlock(tx, es, 1)
+	s.Require().NoError(tx.Commit())
+
+	s.api = NewLocalShardApi(types.MainShardId, s.db, nil)
+}
+
+func (s *TraceTransactionTestSuite) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *TraceTransactionTestSuite) commitBlock(tx db.RwTx, es *execution.ExecutionState, id types.BlockNumber) *types.Block {
+	s.T().Helper()
+
+	blockRes, err := es.Commit(id, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(execution.PostprocessBlock(tx, types.MainShardId, blockRes))
+	s.Require().NoError(db.WriteBlockTimestamp(tx, types.MainShardId, blockRes.BlockHash, 0))
+	return blockRes.Block
+}
+
+func (s *TraceTransactionTestSuite) trace(cfg *tracers.Config, result any) {
+	s.T().Helper()
+
+	data, err := s.api.TraceTransaction(s.ctx, s.txnHash, cfg)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(data, result))
+}
+
+func (s *TraceTransactionTestSuite) Test_CallTracer_Nested_Call() {
+	var frame tracers.CallFrame
+	s.trace(&tracers.Config{Tracer: tracers.CallTracerName}, &frame)
+
+	s.Equal("CALL", frame.Type)
+	s.Require().NotNil(frame.To)
+	s.Equal(s.proxy, *frame.To)
+	s.Empty(frame.Error)
+	s.Equal(common.IntToHash(42).Bytes(), []byte(frame.Output))
+
+	s.Require().Len(frame.Calls, 1)
+	inner := frame.Calls[0]
+	s.Equal("CALL", inner.Type)
+	s.Equal(s.proxy, inner.From)
+	s.Require().NotNil(inner.To)
+	s.Equal(s.storage, *inner.To)
+	s.Empty(inner.Error)
+	s.Empty(inner.Calls)
+	s.Equal(common.IntToHash(42).Bytes(), []byte(inner.Output))
+	s.Positive(uint64(inner.GasUsed))
+	s.Less(uint64(inner.GasUsed), uint64(frame.GasUsed))
+}
+
+func (s *TraceTransactionTestSuite) Test_StructLogger_Nested_Call() {
+	var res tracers.StructLoggerResult
+	s.trace(nil, &res)
+
+	s.False(res.Failed)
+	s.Equal(common.IntToHash(42).Bytes(), []byte(res.ReturnValue))
+
+	ops := make(map[int][]string)
+	for _, log := range res.StructLogs {
+		ops[log.Depth] = append(ops[log.Depth], log.Op)
+	}
+	s.Equal([]string{
+		"PUSH1", "PUSH1", "PUSH1", "PUSH1", "PUSH1", "PUSH20", "GAS", "CALL", "POP", "PUSH1", "PUSH1", "RETURN",
+	}, ops[1])
+	s.Equal([]string{
+		"PUSH1", "PUSH1", "SSTORE", "PUSH1", "SLOAD", "PUSH1", "MSTORE", "PUSH1", "PUSH1", "RETURN",
+	}, ops[2])
+}
+
+func (s *TraceTransactionTestSuite) Test_Unknown_Transaction() {
+	_, err := s.api.TraceTransaction(s.ctx, common.IntToHash(1), nil)
+	s.Require().ErrorContains(err, "failed to find transaction")
+}
@@ -108,3 +108,31 @@ func (api *LocalShardApi) GetInTransaction(ctx context.Context, request rawapity
 	}
 	return api.getInTransactionByBlockRefAndIndex(tx, request.ByBlockRefAndIndex.BlockRef, request.ByBlockRefAndIndex.Index)
 }
+
+// GetOutTransaction returns the outbound transaction emitted by the shard.
+// It is looked up in the index of outbound transactions, so ReceiptSSZ of the result is always empty.
+func (api *LocalShardApi) GetOutTransaction(ctx context.Context, hash common.Hash) (*rawapitypes.TransactionInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	data, err := api.accessor.Access(tx, api.ShardId).GetOutTransaction().ByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionSSZ, err := data.Transaction().MarshalSSZ()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	block := data.Block()
+	return &rawapitypes.TransactionInfo{
+		TransactionSSZ: transactionSSZ,
+		Index:          data.Index(),
+		BlockHash:      block.Hash(api.ShardId),
+		BlockId:        block.Id,
+	}, nil
+}
@@ -9,9 +9,11 @@ import (
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
+var errTxnPoolNotAvailable = errors.New("transaction pool is not available")
+
 func (api *LocalShardApi) SendTransaction(ctx context.Context, encoded []byte) (txnpool.DiscardReason, error) {
 	if api.txnpool == nil {
-		return 0, errors.New("transaction pool is not available")
+		return 0, errTxnPoolNotAvailable
 	}
 
 	var extTxn types.ExternalTransaction
@@ -25,3 +27,10 @@ func (api *LocalShardApi) SendTransaction(ctx context.Context, encoded []byte) (
 	}
 	return reasons[0], nil
 }
+
+func (api *LocalShardApi) GetTxpoolContent(ctx context.Context) (*txnpool.Content, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+	return api.txnpool.Content(), nil
+}
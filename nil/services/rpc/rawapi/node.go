@@ -2,6 +2,7 @@ package rawapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -150,6 +152,22 @@ func (api *NodeApiOverShardApis) GetContract(ctx context.Context, address types.
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountProof, error) {
+	methodName := methodNameChecked("GetProof")
+	shardId := address.ShardId()
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetProof(ctx, address, storageKeys, tokens, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) Call(
 	ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides,
 ) (*rpctypes.CallResWithGasPrice, error) {
@@ -172,6 +190,43 @@ func (api *NodeApiOverShardApis) Call(
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) TraceTransaction(
+	ctx context.Context, shardId types.ShardId, hash common.Hash, cfg *tracers.Config,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceTransaction")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceTransaction(ctx, hash, cfg)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *NodeApiOverShardApis) TraceCall(
+	ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, cfg *tracers.Config,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceCall")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceCall(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, cfg)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GetInTransaction(ctx context.Context, shardId types.ShardId, request rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error) {
 	methodName := methodNameChecked("GetInTransaction")
 	shardApi, ok := api.Apis[shardId]
@@ -198,6 +253,34 @@ func (api *NodeApiOverShardApis) GetInTransactionReceipt(ctx context.Context, sh
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetReceiptInclusionProof(
+	ctx context.Context, shardId types.ShardId, hash common.Hash, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.ReceiptInclusionProof, error) {
+	methodName := methodNameChecked("GetReceiptInclusionProof")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetReceiptInclusionProof(ctx, hash, mainBlockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *NodeApiOverShardApis) GetOutTransaction(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.TransactionInfo, error) {
+	methodName := methodNameChecked("GetOutTransaction")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetOutTransaction(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
 	methodName := methodNameChecked("GasPrice")
 	shardApi, ok := api.Apis[shardId]
@@ -211,6 +294,19 @@ func (api *NodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.Sha
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetTxpoolContent(ctx context.Context, shardId types.ShardId) (*txnpool.Content, error) {
+	methodName := methodNameChecked("GetTxpoolContent")
+	shardApi, ok := api.Apis[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTxpoolContent(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
 	methodName := methodNameChecked("GetShardIdList")
 	shardId := types.MainShardId
@@ -225,6 +321,21 @@ func (api *NodeApiOverShardApis) GetShardIdList(ctx context.Context) ([]types.Sh
 	return result, nil
 }
 
+func (api *NodeApiOverShardApis) GetShardBlockProof(
+	ctx context.Context, shardId types.ShardId, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.ShardBlockProof, error) {
+	methodName := methodNameChecked("GetShardBlockProof")
+	shardApi, ok := api.Apis[types.MainShardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, types.MainShardId)
+	}
+	result, err := shardApi.GetShardBlockProof(ctx, shardId, mainBlockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, types.MainShardId, err)
+	}
+	return result, nil
+}
+
 func (api *NodeApiOverShardApis) GetNumShards(ctx context.Context) (uint64, error) {
 	methodName := methodNameChecked("GetNumShards")
 	shardId := types.MainShardId
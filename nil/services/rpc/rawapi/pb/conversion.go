@@ -2,6 +2,7 @@ package pb
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -9,6 +10,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -144,6 +146,170 @@ func (br *BlockRequest) PackProtoMessage(blockReference rawapitypes.BlockReferen
 	return br.Reference.PackProtoMessage(blockReference)
 }
 
+// ShardBlockProofRequest converters
+
+func (r *ShardBlockProofRequest) PackProtoMessage(shardId types.ShardId, mainBlockReference rawapitypes.BlockReference) error {
+	r.ShardId = uint32(shardId)
+	r.MainBlockReference = &BlockReference{}
+	return r.MainBlockReference.PackProtoMessage(mainBlockReference)
+}
+
+func (r *ShardBlockProofRequest) UnpackProtoMessage() (types.ShardId, rawapitypes.BlockReference, error) {
+	ref, err := r.MainBlockReference.UnpackProtoMessage()
+	if err != nil {
+		return 0, rawapitypes.BlockReference{}, err
+	}
+	return types.ShardId(r.ShardId), ref, nil
+}
+
+// ShardBlockProof converters
+
+func (p *ShardBlockProof) PackProtoMessage(proof *rawapitypes.ShardBlockProof) error {
+	p.MainBlockHash = new(Hash)
+	if err := p.MainBlockHash.PackProtoMessage(proof.MainBlockHash); err != nil {
+		return err
+	}
+	p.MainBlockId = uint64(proof.MainBlockId)
+	p.BlockHash = new(Hash)
+	if err := p.BlockHash.PackProtoMessage(proof.BlockHash); err != nil {
+		return err
+	}
+	p.ProofEncoded = proof.ProofEncoded
+	return nil
+}
+
+func (p *ShardBlockProof) UnpackProtoMessage() (*rawapitypes.ShardBlockProof, error) {
+	mainBlockHash, err := p.MainBlockHash.UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	blockHash, err := p.BlockHash.UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.ShardBlockProof{
+		MainBlockHash: mainBlockHash,
+		MainBlockId:   types.BlockNumber(p.MainBlockId),
+		BlockHash:     blockHash,
+		ProofEncoded:  p.ProofEncoded,
+	}, nil
+}
+
+// ShardBlockProofResponse converters
+
+func (r *ShardBlockProofResponse) PackProtoMessage(proof *rawapitypes.ShardBlockProof, err error) error {
+	if err != nil {
+		r.Result = &ShardBlockProofResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := new(ShardBlockProof)
+	if err := data.PackProtoMessage(proof); err != nil {
+		return err
+	}
+	r.Result = &ShardBlockProofResponse_Data{Data: data}
+	return nil
+}
+
+func (r *ShardBlockProofResponse) UnpackProtoMessage() (*rawapitypes.ShardBlockProof, error) {
+	switch r.Result.(type) {
+	case *ShardBlockProofResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ShardBlockProofResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// ReceiptInclusionProofRequest converters
+
+func (r *ReceiptInclusionProofRequest) PackProtoMessage(hash common.Hash, mainBlockReference rawapitypes.BlockReference) error {
+	r.Hash = new(Hash)
+	if err := r.Hash.PackProtoMessage(hash); err != nil {
+		return err
+	}
+	r.MainBlockReference = &BlockReference{}
+	return r.MainBlockReference.PackProtoMessage(mainBlockReference)
+}
+
+func (r *ReceiptInclusionProofRequest) UnpackProtoMessage() (common.Hash, rawapitypes.BlockReference, error) {
+	hash, err := r.Hash.UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+	ref, err := r.MainBlockReference.UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+	return hash, ref, nil
+}
+
+// ReceiptInclusionProof converters
+
+func (p *ReceiptInclusionProof) PackProtoMessage(proof *rawapitypes.ReceiptInclusionProof) error {
+	p.ReceiptSSZ = proof.ReceiptSSZ
+	p.Index = uint64(proof.Index)
+	p.ProofEncoded = proof.ProofEncoded
+	p.Headers = make([][]byte, len(proof.Headers))
+	for i, header := range proof.Headers {
+		p.Headers[i] = header
+	}
+	if proof.ShardBlockProof != nil {
+		p.ShardBlockProof = new(ShardBlockProof)
+		if err := p.ShardBlockProof.PackProtoMessage(proof.ShardBlockProof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ReceiptInclusionProof) UnpackProtoMessage() (*rawapitypes.ReceiptInclusionProof, error) {
+	proof := &rawapitypes.ReceiptInclusionProof{
+		ReceiptSSZ:   p.ReceiptSSZ,
+		Index:        types.TransactionIndex(p.Index),
+		ProofEncoded: p.ProofEncoded,
+		Headers:      make([]sszx.SSZEncodedData, len(p.Headers)),
+	}
+	for i, header := range p.Headers {
+		proof.Headers[i] = header
+	}
+	if p.ShardBlockProof != nil {
+		var err error
+		if proof.ShardBlockProof, err = p.ShardBlockProof.UnpackProtoMessage(); err != nil {
+			return nil, err
+		}
+	}
+	return proof, nil
+}
+
+// ReceiptInclusionProofResponse converters
+
+func (r *ReceiptInclusionProofResponse) PackProtoMessage(proof *rawapitypes.ReceiptInclusionProof, err error) error {
+	if err != nil {
+		r.Result = &ReceiptInclusionProofResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := new(ReceiptInclusionProof)
+	if err := data.PackProtoMessage(proof); err != nil {
+		return err
+	}
+	r.Result = &ReceiptInclusionProofResponse_Data{Data: data}
+	return nil
+}
+
+func (r *ReceiptInclusionProofResponse) UnpackProtoMessage() (*rawapitypes.ReceiptInclusionProof, error) {
+	switch r.Result.(type) {
+	case *ReceiptInclusionProofResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ReceiptInclusionProofResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
 // AccountRequest
 
 func (a *Address) UnpackProtoMessage() types.Address {
@@ -518,6 +684,150 @@ func (rcr *RawContractResponse) UnpackProtoMessage() (*rawapitypes.SmartContract
 	return nil, errors.New("unexpected response type")
 }
 
+// ProofRequest converters
+
+func (pr *ProofRequest) PackProtoMessage(
+	address types.Address, storageKeys []common.Hash, tokens []types.TokenId, blockReference rawapitypes.BlockReference,
+) error {
+	pr.Address = new(Address).PackProtoMessage(address)
+	pr.StorageKeys = make([]*Hash, len(storageKeys))
+	for i, key := range storageKeys {
+		pr.StorageKeys[i] = new(Hash)
+		if err := pr.StorageKeys[i].PackProtoMessage(key); err != nil {
+			return err
+		}
+	}
+	pr.Tokens = make([]*Address, len(tokens))
+	for i, token := range tokens {
+		pr.Tokens[i] = new(Address).PackProtoMessage(types.Address(token))
+	}
+	pr.BlockReference = &BlockReference{}
+	return pr.BlockReference.PackProtoMessage(blockReference)
+}
+
+func (pr *ProofRequest) UnpackProtoMessage() (types.Address, []common.Hash, []types.TokenId, rawapitypes.BlockReference, error) {
+	blockReference, err := pr.BlockReference.UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, nil, nil, rawapitypes.BlockReference{}, err
+	}
+
+	storageKeys := make([]common.Hash, len(pr.StorageKeys))
+	for i, key := range pr.StorageKeys {
+		if storageKeys[i], err = key.UnpackProtoMessage(); err != nil {
+			return types.EmptyAddress, nil, nil, rawapitypes.BlockReference{}, err
+		}
+	}
+	tokens := make([]types.TokenId, len(pr.Tokens))
+	for i, token := range pr.Tokens {
+		tokens[i] = types.TokenId(token.UnpackProtoMessage())
+	}
+	return pr.Address.UnpackProtoMessage(), storageKeys, tokens, blockReference, nil
+}
+
+// AccountProof converters
+
+func (ap *AccountProof) PackProtoMessage(proof *rawapitypes.AccountProof) error {
+	ap.BlockHash = new(Hash)
+	if err := ap.BlockHash.PackProtoMessage(proof.BlockHash); err != nil {
+		return err
+	}
+	ap.BlockId = uint64(proof.BlockId)
+	ap.ContractSSZ = proof.ContractSSZ
+	ap.ProofEncoded = proof.ProofEncoded
+
+	ap.StorageProofs = make([]*StorageProof, len(proof.StorageProofs))
+	for i, p := range proof.StorageProofs {
+		key := new(Hash)
+		if err := key.PackProtoMessage(p.Key); err != nil {
+			return err
+		}
+		ap.StorageProofs[i] = &StorageProof{
+			Key:          key,
+			Value:        new(Uint256).PackProtoMessage(p.Value),
+			ProofEncoded: p.ProofEncoded,
+		}
+	}
+
+	ap.TokenProofs = make([]*TokenProof, len(proof.TokenProofs))
+	for i, p := range proof.TokenProofs {
+		var value types.Uint256
+		if p.Value.Uint256 != nil {
+			value = *p.Value.Uint256
+		}
+		ap.TokenProofs[i] = &TokenProof{
+			Token:        new(Address).PackProtoMessage(types.Address(p.Token)),
+			Value:        new(Uint256).PackProtoMessage(value),
+			ProofEncoded: p.ProofEncoded,
+		}
+	}
+	return nil
+}
+
+func (ap *AccountProof) UnpackProtoMessage() (*rawapitypes.AccountProof, error) {
+	blockHash, err := ap.BlockHash.UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	proof := &rawapitypes.AccountProof{
+		BlockHash:     blockHash,
+		BlockId:       types.BlockNumber(ap.BlockId),
+		ContractSSZ:   ap.ContractSSZ,
+		ProofEncoded:  ap.ProofEncoded,
+		StorageProofs: make([]rawapitypes.StorageProof, len(ap.StorageProofs)),
+		TokenProofs:   make([]rawapitypes.TokenProof, len(ap.TokenProofs)),
+	}
+
+	for i, p := range ap.StorageProofs {
+		key, err := p.Key.UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		proof.StorageProofs[i] = rawapitypes.StorageProof{
+			Key:          key,
+			Value:        p.Value.UnpackProtoMessage(),
+			ProofEncoded: p.ProofEncoded,
+		}
+	}
+
+	for i, p := range ap.TokenProofs {
+		value := p.Value.UnpackProtoMessage()
+		proof.TokenProofs[i] = rawapitypes.TokenProof{
+			Token:        types.TokenId(p.Token.UnpackProtoMessage()),
+			Value:        types.Value{Uint256: &value},
+			ProofEncoded: p.ProofEncoded,
+		}
+	}
+	return proof, nil
+}
+
+// AccountProofResponse converters
+
+func (apr *AccountProofResponse) PackProtoMessage(proof *rawapitypes.AccountProof, err error) error {
+	if err != nil {
+		apr.Result = &AccountProofResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := new(AccountProof)
+	if err := data.PackProtoMessage(proof); err != nil {
+		return err
+	}
+
+	apr.Result = &AccountProofResponse_Data{Data: data}
+	return nil
+}
+
+func (apr *AccountProofResponse) UnpackProtoMessage() (*rawapitypes.AccountProof, error) {
+	switch apr.Result.(type) {
+	case *AccountProofResponse_Error:
+		return nil, apr.GetError().UnpackProtoMessage()
+
+	case *AccountProofResponse_Data:
+		return apr.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
 func (c *Contract) PackProtoMessage(contract rpctypes.Contract) *Contract {
 	if contract.Seqno != nil {
 		c.Seqno = (*uint64)(contract.Seqno)
@@ -1261,3 +1571,186 @@ func (r *SendTransactionRequest) PackProtoMessage(transactionSSZ []byte) error {
 func (r *SendTransactionRequest) UnpackProtoMessage() ([]byte, error) {
 	return r.TransactionSSZ, nil
 }
+
+// Trace converters
+
+func (c *TraceConfig) PackProtoMessage(cfg *tracers.Config) *TraceConfig {
+	if cfg == nil {
+		return nil
+	}
+	c.Tracer = cfg.Tracer
+	c.EnableMemory = cfg.EnableMemory
+	c.DisableStack = cfg.DisableStack
+	c.DisableStorage = cfg.DisableStorage
+	c.EnableReturnData = cfg.EnableReturnData
+	c.Limit = uint64(cfg.Limit)
+	if cfg.TracerConfig != nil {
+		c.TracerConfig = &CallTracerConfig{
+			OnlyTopCall: cfg.TracerConfig.OnlyTopCall,
+			WithLog:     cfg.TracerConfig.WithLog,
+		}
+	}
+	return c
+}
+
+func (c *TraceConfig) UnpackProtoMessage() *tracers.Config {
+	if c == nil {
+		return nil
+	}
+	cfg := &tracers.Config{
+		Tracer:           c.Tracer,
+		EnableMemory:     c.EnableMemory,
+		DisableStack:     c.DisableStack,
+		DisableStorage:   c.DisableStorage,
+		EnableReturnData: c.EnableReturnData,
+		Limit:            int(c.Limit),
+	}
+	if c.TracerConfig != nil {
+		cfg.TracerConfig = &tracers.CallTracerConfig{
+			OnlyTopCall: c.TracerConfig.OnlyTopCall,
+			WithLog:     c.TracerConfig.WithLog,
+		}
+	}
+	return cfg
+}
+
+func (r *TraceTransactionRequest) PackProtoMessage(hash common.Hash, cfg *tracers.Config) error {
+	r.Hash = &Hash{}
+	if err := r.Hash.PackProtoMessage(hash); err != nil {
+		return err
+	}
+	r.Config = new(TraceConfig).PackProtoMessage(cfg)
+	return nil
+}
+
+func (r *TraceTransactionRequest) UnpackProtoMessage() (common.Hash, *tracers.Config, error) {
+	hash, err := r.Hash.UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, nil, err
+	}
+	return hash, r.Config.UnpackProtoMessage(), nil
+}
+
+func (r *TraceCallRequest) PackProtoMessage(
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	cfg *tracers.Config,
+) error {
+	r.Call = &CallRequest{}
+	if err := r.Call.PackProtoMessage(args, mainBlockReferenceOrHashWithChildren, overrides); err != nil {
+		return err
+	}
+	r.Config = new(TraceConfig).PackProtoMessage(cfg)
+	return nil
+}
+
+func (r *TraceCallRequest) UnpackProtoMessage() (
+	rpctypes.CallArgs, rawapitypes.BlockReferenceOrHashWithChildren, *rpctypes.StateOverrides, *tracers.Config, error,
+) {
+	args, br, overrides, err := r.Call.UnpackProtoMessage()
+	if err != nil {
+		return rpctypes.CallArgs{}, rawapitypes.BlockReferenceOrHashWithChildren{}, nil, nil, err
+	}
+	return args, br, overrides, r.Config.UnpackProtoMessage(), nil
+}
+
+func (r *TraceResponse) PackProtoMessage(trace json.RawMessage, err error) error {
+	if err != nil {
+		r.Result = &TraceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &TraceResponse_Data{Data: trace}
+	return nil
+}
+
+func (r *TraceResponse) UnpackProtoMessage() (json.RawMessage, error) {
+	switch r.Result.(type) {
+	case *TraceResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *TraceResponse_Data:
+		return r.GetData(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// Txpool converters
+
+func packTransactionsSSZ(txns []*types.TxnWithHash) ([][]byte, error) {
+	res := make([][]byte, len(txns))
+	for i, txn := range txns {
+		var err error
+		res[i], err = txn.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func unpackTransactionsSSZ(data [][]byte) ([]*types.TxnWithHash, error) {
+	res := make([]*types.TxnWithHash, len(data))
+	for i, txnSSZ := range data {
+		txn := &types.Transaction{}
+		if err := txn.UnmarshalSSZ(txnSSZ); err != nil {
+			return nil, err
+		}
+		res[i] = types.NewTxnWithHash(txn)
+	}
+	return res, nil
+}
+
+func (c *TxpoolContent) PackProtoMessage(content *txnpool.Content) error {
+	baseFee := content.BaseFee.Uint256
+	if baseFee == nil {
+		baseFee = &types.Uint256{}
+	}
+	c.BaseFee = new(Uint256).PackProtoMessage(*baseFee)
+
+	var err error
+	if c.PendingSSZ, err = packTransactionsSSZ(content.Pending); err != nil {
+		return err
+	}
+	c.QueuedSSZ, err = packTransactionsSSZ(content.Queued)
+	return err
+}
+
+func (c *TxpoolContent) UnpackProtoMessage() (*txnpool.Content, error) {
+	content := &txnpool.Content{BaseFee: newValueFromUint256(c.BaseFee)}
+
+	var err error
+	if content.Pending, err = unpackTransactionsSSZ(c.PendingSSZ); err != nil {
+		return nil, err
+	}
+	if content.Queued, err = unpackTransactionsSSZ(c.QueuedSSZ); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (r *TxpoolContentResponse) PackProtoMessage(content *txnpool.Content, err error) error {
+	if err != nil {
+		r.Result = &TxpoolContentResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &TxpoolContent{}
+	if err := data.PackProtoMessage(content); err != nil {
+		return err
+	}
+	r.Result = &TxpoolContentResponse_Data{Data: data}
+	return nil
+}
+
+func (r *TxpoolContentResponse) UnpackProtoMessage() (*txnpool.Content, error) {
+	switch r.Result.(type) {
+	case *TxpoolContentResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *TxpoolContentResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
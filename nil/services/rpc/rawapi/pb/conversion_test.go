@@ -1,14 +1,17 @@
 package pb
 
 import (
+	"encoding/json"
 	"strconv"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -243,3 +246,95 @@ func TestErrorMap_PackUnpack(t *testing.T) {
 	errorsUnpacked := unpackErrorMap(unpacked.Errors)
 	assert.Equal(t, errors, errorsUnpacked)
 }
+
+func TestTraceCallRequest_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	for i, cfg := range []*tracers.Config{
+		nil,
+		{EnableMemory: true, DisableStorage: true, Limit: 10},
+		{Tracer: tracers.CallTracerName, TracerConfig: &tracers.CallTracerConfig{WithLog: true}},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+
+			callArgs := getCallArgs()
+			blockRef := getBlockHashWithChildren()
+			overrides := getStateOverrides()
+
+			req := &TraceCallRequest{}
+			require.NoError(t, req.PackProtoMessage(callArgs, blockRef, overrides, cfg))
+
+			data, err := proto.Marshal(req)
+			require.NoError(t, err)
+
+			var unpacked TraceCallRequest
+			require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+			unpackedCallArgs, unpackedBlockRef, unpackedOverrides, unpackedCfg, err := unpacked.UnpackProtoMessage()
+			require.NoError(t, err)
+			assert.Equal(t, callArgs, unpackedCallArgs)
+			assert.Equal(t, blockRef, unpackedBlockRef)
+			assert.Equal(t, overrides, unpackedOverrides)
+			assert.Equal(t, cfg, unpackedCfg)
+		})
+	}
+}
+
+func TestTraceResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	trace := json.RawMessage(`{"type":"CALL"}`)
+	resp := &TraceResponse{}
+	require.NoError(t, resp.PackProtoMessage(trace, nil))
+
+	data, err := proto.Marshal(resp)
+	require.NoError(t, err)
+
+	var unpacked TraceResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedTrace, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(trace), string(unpackedTrace))
+}
+
+func TestTxpoolContentResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	newTxn := func(seqno types.Seqno) *types.TxnWithHash {
+		txn := types.NewEmptyTransaction()
+		txn.To = types.GenerateRandomAddress(types.BaseShardId)
+		txn.Seqno = seqno
+		txn.MaxPriorityFeePerGas = types.NewValueFromUint64(uint64(seqno))
+		return types.NewTxnWithHash(txn)
+	}
+	content := &txnpool.Content{
+		BaseFee: types.NewValueFromUint64(100),
+		Pending: []*types.TxnWithHash{newTxn(1), newTxn(2)},
+		Queued:  []*types.TxnWithHash{newTxn(3)},
+	}
+
+	resp := &TxpoolContentResponse{}
+	require.NoError(t, resp.PackProtoMessage(content, nil))
+
+	data, err := proto.Marshal(resp)
+	require.NoError(t, err)
+
+	var unpacked TxpoolContentResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedContent, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, content.BaseFee, unpackedContent.BaseFee)
+
+	hashes := func(txns []*types.TxnWithHash) []common.Hash {
+		res := make([]common.Hash, len(txns))
+		for i, txn := range txns {
+			res[i] = txn.Hash()
+		}
+		return res
+	}
+	assert.Equal(t, hashes(content.Pending), hashes(unpackedContent.Pending))
+	assert.Equal(t, hashes(content.Queued), hashes(unpackedContent.Queued))
+}
@@ -0,0 +1,35 @@
+package rawapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+// relayShardApi sends the transactions to a shard that is not served by the node through the transaction relay
+// instead of a request to one of the nodes that serve it. The request is still sent when there is no route to relay.
+type relayShardApi struct {
+	ShardApi
+	relay *txnpool.Relay
+}
+
+var _ ShardApi = (*relayShardApi)(nil)
+
+func NewRelayShardApi(api ShardApi, relay *txnpool.Relay) ShardApi {
+	return &relayShardApi{ShardApi: api, relay: relay}
+}
+
+func (api *relayShardApi) SendTransaction(ctx context.Context, encoded []byte) (txnpool.DiscardReason, error) {
+	var extTxn types.ExternalTransaction
+	if err := extTxn.UnmarshalSSZ(encoded); err != nil {
+		return 0, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	reason, err := api.relay.Relay(ctx, extTxn.ToTransaction())
+	if errors.Is(err, txnpool.ErrNoRoute) {
+		return api.ShardApi.SendTransaction(ctx, encoded)
+	}
+	return reason, err
+}
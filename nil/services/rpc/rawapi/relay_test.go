@@ -0,0 +1,58 @@
+package rawapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sendingShardApi records the transactions sent through the wrapped api.
+type sendingShardApi struct {
+	ShardApi
+	sent [][]byte
+}
+
+func (api *sendingShardApi) SendTransaction(_ context.Context, encoded []byte) (txnpool.DiscardReason, error) {
+	api.sent = append(api.sent, encoded)
+	return txnpool.Unverified, nil
+}
+
+// noNeighborsTopology has no routes between the shards.
+type noNeighborsTopology struct{}
+
+func (noNeighborsTopology) GetNeighbors(types.ShardId, uint32, bool) []types.ShardId {
+	return nil
+}
+
+func (noNeighborsTopology) ShouldPropagateTxn(types.ShardId, types.ShardId, types.ShardId) bool {
+	return false
+}
+
+func TestRelayShardApiFallback(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	nms := network.NewTestManagers(t, ctx, 9230, 1)
+	defer nms[0].Close()
+
+	relay, err := txnpool.NewRelay(ctx, txnpool.RelayConfig{NShards: 3, Topology: noNeighborsTopology{}}, nms[0])
+	require.NoError(t, err)
+
+	txn := types.ExternalTransaction{To: types.ShardAndHexToAddress(2, "11"), Seqno: 1}
+	encoded, err := txn.MarshalSSZ()
+	require.NoError(t, err)
+
+	// The node has no peers, so the request goes to the nodes serving the shard.
+	fallback := &sendingShardApi{}
+	reason, err := NewRelayShardApi(fallback, relay).SendTransaction(ctx, encoded)
+	require.NoError(t, err)
+	assert.Equal(t, txnpool.Unverified, reason)
+	assert.Equal(t, [][]byte{encoded}, fallback.sent)
+}
@@ -23,17 +23,25 @@ type NetworkTransportProtocolRo interface {
 
 	GetInTransaction(pb.TransactionRequest) pb.TransactionResponse
 	GetInTransactionReceipt(pb.Hash) pb.ReceiptResponse
+	GetReceiptInclusionProof(pb.ReceiptInclusionProofRequest) pb.ReceiptInclusionProofResponse
+	GetOutTransaction(pb.Hash) pb.TransactionResponse
 
 	GetBalance(request pb.AccountRequest) pb.BalanceResponse
 	GetCode(request pb.AccountRequest) pb.CodeResponse
 	GetTokens(request pb.AccountRequest) pb.TokensResponse
 	GetTransactionCount(pb.AccountRequest) pb.Uint64Response
 	GetContract(request pb.AccountRequest) pb.RawContractResponse
+	GetProof(request pb.ProofRequest) pb.AccountProofResponse
 
 	Call(pb.CallRequest) pb.CallResponse
 
+	TraceTransaction(pb.TraceTransactionRequest) pb.TraceResponse
+	TraceCall(pb.TraceCallRequest) pb.TraceResponse
+
 	GasPrice() pb.GasPriceResponse
+	GetTxpoolContent() pb.TxpoolContentResponse
 	GetShardIdList() pb.ShardIdListResponse
+	GetShardBlockProof(pb.ShardBlockProofRequest) pb.ShardBlockProofResponse
 	GetNumShards() pb.Uint64Response
 }
 
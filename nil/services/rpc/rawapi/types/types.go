@@ -4,6 +4,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
@@ -151,3 +152,49 @@ type SmartContract struct {
 	Tokens       map[types.TokenId]types.Value
 	AsyncContext map[types.TransactionIndex]types.AsyncContext
 }
+
+// AccountProof contains the Merkle proof of the account against the contract trie of the block
+// and the proofs of the requested storage slots and tokens against the tries of the account.
+type AccountProof struct {
+	BlockHash common.Hash
+	BlockId   types.BlockNumber
+	// ContractSSZ is empty if the account doesn't exist. In this case the storage and token proofs are empty too.
+	ContractSSZ   []byte
+	ProofEncoded  []byte
+	StorageProofs []StorageProof
+	TokenProofs   []TokenProof
+}
+
+type StorageProof struct {
+	Key          common.Hash
+	Value        types.Uint256
+	ProofEncoded []byte
+}
+
+type TokenProof struct {
+	Token        types.TokenId
+	Value        types.Value
+	ProofEncoded []byte
+}
+
+// ShardBlockProof contains the Merkle proof of the shard block hash against the shard blocks trie of the main block.
+type ShardBlockProof struct {
+	MainBlockHash common.Hash
+	MainBlockId   types.BlockNumber
+	BlockHash     common.Hash
+	ProofEncoded  []byte
+}
+
+// ReceiptInclusionProof links the receipt to the main block: the receipt is proven against the receipt trie
+// of its block, the block is linked by the headers to the shard block referenced by the main block,
+// and the reference is proven against the shard blocks trie of the main block.
+type ReceiptInclusionProof struct {
+	ReceiptSSZ   []byte
+	Index        types.TransactionIndex
+	ProofEncoded []byte
+	// Headers are the headers of the consecutive shard blocks starting with the block of the receipt.
+	// The last one is the block referenced by the main block, or the main block itself for the main shard receipts.
+	Headers []sszx.SSZEncodedData
+	// ShardBlockProof is nil for the main shard receipts.
+	ShardBlockProof *ShardBlockProof
+}
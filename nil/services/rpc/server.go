@@ -50,6 +50,9 @@ func StartRpcServer(
 			nil,
 			cfg.HttpCompression)
 	}
+	if cfg.WebsocketEnabled {
+		httpHandler = newWebsocketOrHttpHandler(srv.WebsocketHandler(cfg.HttpCORSDomain), httpHandler)
+	}
 
 	listener, httpAddr, err := http.StartHTTPEndpoint(httpEndpoint, &http.HttpEndpointConfig{
 		Timeouts: cfg.HTTPTimeouts,
@@ -75,3 +78,15 @@ func StartRpcServer(
 	<-ctx.Done()
 	return nil
 }
+
+// newWebsocketOrHttpHandler routes WebSocket upgrade requests to ws and all other requests to next.
+// WebSocket requests bypass the HTTP handler stack because compression doesn't support hijacking.
+func newWebsocketOrHttpHandler(ws, next net_http.Handler) net_http.Handler {
+	return net_http.HandlerFunc(func(w net_http.ResponseWriter, r *net_http.Request) {
+		if transport.IsWebsocketRequest(r) {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,9 +27,10 @@ import (
 //	h.handleMsg(message)
 type handler struct {
 	reg        *serviceRegistry
-	rootCtx    context.Context // canceled by close()
-	cancelRoot func()          // cancel function for rootCtx
-	conn       JsonWriter      // where responses will be sent
+	rootCtx    context.Context       // canceled by close()
+	cancelRoot func()                // cancel function for rootCtx
+	conn       JsonWriter            // where responses will be sent
+	subs       *subscriptionRegistry // nil if the connection doesn't support notifications
 	logger     zerolog.Logger
 
 	maxBatchConcurrency uint
@@ -188,6 +190,7 @@ func (h *handler) handleBatch(msgs []*Message) {
 	if len(answers) > 0 {
 		_ = h.conn.WriteJSON(h.rootCtx, answers)
 	}
+	h.activateSubscriptions()
 }
 
 // handleMsg handles a single message.
@@ -199,6 +202,22 @@ func (h *handler) handleMsg(msg *Message) {
 		_, _ = stream.Write(buffer)
 	}
 	_ = h.conn.WriteJSON(h.rootCtx, json.RawMessage(stream.Buffer()))
+	h.activateSubscriptions()
+}
+
+// activateSubscriptions enables notifications for subscriptions created while handling the last message.
+func (h *handler) activateSubscriptions() {
+	if h.subs != nil {
+		h.subs.activate()
+	}
+}
+
+// close cancels all subscriptions and pending requests of the connection.
+func (h *handler) close() {
+	if h.subs != nil {
+		h.subs.closeAll()
+	}
+	h.cancelRoot()
 }
 
 // handleCallMsg executes a call message and returns the answer.
@@ -254,6 +273,10 @@ func (h *handler) handleCall(ctx context.Context, msg *Message, stream *jsoniter
 	if err != nil {
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
+	if h.subs != nil {
+		namespace, _, _ := strings.Cut(msg.Method, serviceMethodSeparator)
+		ctx = context.WithValue(ctx, notifierKey{}, &Notifier{conn: h.conn, subs: h.subs, namespace: namespace})
+	}
 	return h.runMethod(ctx, msg, callb, args, stream)
 }
 
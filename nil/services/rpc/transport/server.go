@@ -26,7 +26,11 @@ const (
 
 type ContextKey string
 
-var HeadersContextKey ContextKey = "headers"
+var (
+	HeadersContextKey ContextKey = "headers"
+	// RemoteAddrContextKey is the key of the peer address of the connection that sent the request.
+	RemoteAddrContextKey ContextKey = "remoteAddr"
+)
 
 // Server is an RPC server.
 type Server struct {
@@ -119,6 +123,7 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		headers.Add(h, r.Header.Get(h))
 	}
 	ctx = context.WithValue(ctx, HeadersContextKey, headers)
+	ctx = context.WithValue(ctx, RemoteAddrContextKey, r.RemoteAddr)
 
 	h := newHandler(ctx, codec, &s.services, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
 
@@ -140,6 +145,47 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 	}
 }
 
+// ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
+// the response back using the given codec. It will block until the codec is closed.
+// Requests served this way may create subscriptions.
+func (s *Server) ServeCodec(ctx context.Context, codec ServerCodec, headers http.Header) {
+	defer codec.Close()
+
+	// Don't serve if the server is stopped.
+	if atomic.LoadInt32(&s.run) == 0 {
+		return
+	}
+
+	s.codecs.Add(codec)
+	defer s.codecs.Remove(codec)
+
+	ctx = context.WithValue(ctx, HeadersContextKey, headers)
+	ctx = context.WithValue(ctx, RemoteAddrContextKey, codec.RemoteAddr())
+
+	h := newHandler(ctx, codec, &s.services, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
+	h.subs = newSubscriptionRegistry()
+	defer h.close()
+
+	for {
+		reqs, batch, err := codec.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				s.logger.Debug().Err(err).Str("remote", codec.RemoteAddr()).Msg("Failed to read from connection")
+			}
+			return
+		}
+		if batch {
+			if s.batchLimit > 0 && len(reqs) > s.batchLimit {
+				_ = codec.WriteJSON(ctx, errorMessage(fmt.Errorf("batch limit %d exceeded. Requested batch of size: %d", s.batchLimit, len(reqs))))
+			} else {
+				h.handleBatch(reqs)
+			}
+		} else {
+			h.handleMsg(reqs[0])
+		}
+	}
+}
+
 // Stop stops reading new requests, waits for stopPendingRequestTimeout to allow pending
 // requests to finish, then closes all codecs that will cancel pending requests.
 func (s *Server) Stop() {
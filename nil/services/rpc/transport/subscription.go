@@ -0,0 +1,177 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+const notificationMethodSuffix = "_subscription"
+
+var (
+	// ErrNotificationsUnsupported is returned when the connection doesn't support notifications (e.g., plain HTTP).
+	ErrNotificationsUnsupported = &CustomError{Code: -32001, Message: "notifications not supported"}
+	// ErrSubscriptionNotFound is returned when there is no subscription with the given id on the connection.
+	ErrSubscriptionNotFound = &CustomError{Code: -32002, Message: "subscription not found"}
+)
+
+// SubscriptionID is a per-connection identifier of a subscription.
+type SubscriptionID string
+
+func newSubscriptionID() SubscriptionID {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(err)
+	}
+	return SubscriptionID("0x" + hex.EncodeToString(id[:]))
+}
+
+// Subscription is created by a Notifier and is tied to the connection it was created on.
+type Subscription struct {
+	ID        SubscriptionID
+	namespace string
+
+	ready   chan struct{} // closed once the response with the subscription id is written
+	done    chan struct{} // closed when the subscription is cancelled or the connection is closed
+	closeMu sync.Once
+}
+
+// Done returns a channel that is closed when the subscription is cancelled by the client
+// or the underlying connection is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) cancel() {
+	s.closeMu.Do(func() {
+		close(s.done)
+	})
+}
+
+type subscriptionNotification struct {
+	Version string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  subscriptionEvent `json:"params"`
+}
+
+type subscriptionEvent struct {
+	Subscription SubscriptionID `json:"subscription"`
+	Result       any            `json:"result"`
+}
+
+// subscriptionRegistry holds subscriptions of a single connection.
+type subscriptionRegistry struct {
+	mu       sync.Mutex
+	subs     map[SubscriptionID]*Subscription
+	inactive []*Subscription
+}
+
+func newSubscriptionRegistry() *subscriptionRegistry {
+	return &subscriptionRegistry{subs: make(map[SubscriptionID]*Subscription)}
+}
+
+func (r *subscriptionRegistry) add(sub *Subscription) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[sub.ID] = sub
+	r.inactive = append(r.inactive, sub)
+}
+
+// activate enables notifications for all subscriptions created since the previous call.
+// It must be called after the responses of the corresponding requests are written.
+func (r *subscriptionRegistry) activate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.inactive {
+		close(sub.ready)
+	}
+	r.inactive = nil
+}
+
+func (r *subscriptionRegistry) remove(id SubscriptionID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.subs[id]
+	if ok {
+		sub.cancel()
+		delete(r.subs, id)
+	}
+	return ok
+}
+
+func (r *subscriptionRegistry) closeAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, sub := range r.subs {
+		sub.cancel()
+		delete(r.subs, id)
+	}
+	r.inactive = nil
+}
+
+// Notifier is tied to an RPC connection that supports subscriptions.
+// Server callbacks use the notifier to send notifications.
+type Notifier struct {
+	conn      JsonWriter
+	subs      *subscriptionRegistry
+	namespace string
+}
+
+type notifierKey struct{}
+
+// NotifierFromContext returns the Notifier value stored in ctx, if any.
+func NotifierFromContext(ctx context.Context) (*Notifier, bool) {
+	n, ok := ctx.Value(notifierKey{}).(*Notifier)
+	return n, ok
+}
+
+// CreateSubscription returns a new subscription that is coupled to the RPC connection.
+// Notifications sent before the response with the subscription id is written are held back.
+func (n *Notifier) CreateSubscription() *Subscription {
+	sub := &Subscription{
+		ID:        newSubscriptionID(),
+		namespace: n.namespace,
+		ready:     make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	n.subs.add(sub)
+	return sub
+}
+
+// Notify sends a notification to the client with the given data as payload.
+func (n *Notifier) Notify(sub *Subscription, data any) error {
+	select {
+	case <-sub.ready:
+	case <-sub.done:
+		return ErrSubscriptionNotFound
+	}
+
+	enc, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return n.conn.WriteJSON(context.Background(), &subscriptionNotification{
+		Version: Version,
+		Method:  sub.namespace + notificationMethodSuffix,
+		Params: subscriptionEvent{
+			Subscription: sub.ID,
+			Result:       json.RawMessage(enc),
+		},
+	})
+}
+
+// Unsubscribe cancels the subscription with the given id.
+// It returns ErrSubscriptionNotFound if there is no such subscription on the connection.
+func (n *Notifier) Unsubscribe(id SubscriptionID) error {
+	if !n.subs.remove(id) {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// Closed returns a channel which is closed when the connection is closed.
+func (n *Notifier) Closed() <-chan interface{} {
+	return n.conn.Closed()
+}
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsReadBuffer       = 1024
+	wsWriteBuffer      = 1024
+	wsPingInterval     = 30 * time.Second
+	wsPingWriteTimeout = 5 * time.Second
+	wsMessageSizeLimit = 32 * 1024 * 1024
+)
+
+var wsBufferPool = new(sync.Pool)
+
+// WebsocketHandler returns a handler that serves JSON-RPC to WebSocket connections.
+// Unlike plain HTTP, connections served this way support subscriptions.
+func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  wsReadBuffer,
+		WriteBufferSize: wsWriteBuffer,
+		WriteBufferPool: wsBufferPool,
+		CheckOrigin:     wsOriginChecker(allowedOrigins),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			s.logger.Debug().Err(err).Msg("WebSocket upgrade failed")
+			return
+		}
+
+		headers := http.Header{}
+		for _, h := range s.keepHeaders {
+			headers.Add(h, r.Header.Get(h))
+		}
+		s.ServeCodec(r.Context(), newWebsocketCodec(conn, r.RemoteAddr), headers)
+	})
+}
+
+// IsWebsocketRequest reports whether r is a WebSocket upgrade request.
+func IsWebsocketRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+func wsOriginChecker(allowedOrigins []string) func(*http.Request) bool {
+	allowAll := slices.Contains(allowedOrigins, "*")
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if allowAll || origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		for _, allowed := range allowedOrigins {
+			if strings.EqualFold(allowed, origin) || strings.EqualFold(allowed, u.Host) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// wsConn adapts websocket.Conn to report the remote address of the original HTTP request.
+type wsConn struct {
+	*websocket.Conn
+	remote string
+}
+
+func (c *wsConn) RemoteAddr() string {
+	return c.remote
+}
+
+type websocketCodec struct {
+	ServerCodec
+	conn *websocket.Conn
+	wg   sync.WaitGroup
+}
+
+func newWebsocketCodec(conn *websocket.Conn, remote string) ServerCodec {
+	conn.SetReadLimit(wsMessageSizeLimit)
+	codec := &websocketCodec{
+		ServerCodec: NewFuncCodec(&wsConn{Conn: conn, remote: remote}, conn.WriteJSON, conn.ReadJSON),
+		conn:        conn,
+	}
+	codec.wg.Add(1)
+	go codec.pingLoop()
+	return codec
+}
+
+func (c *websocketCodec) Close() {
+	c.ServerCodec.Close()
+	c.wg.Wait()
+}
+
+// pingLoop keeps the connection alive through proxies that drop idle connections.
+func (c *websocketCodec) pingLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Closed():
+			return
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsPingWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSubscriptionService struct{}
+
+func (s *testSubscriptionService) Count(ctx context.Context, n int) (SubscriptionID, error) {
+	notifier, ok := NotifierFromContext(ctx)
+	if !ok {
+		return "", ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := range n {
+			if err := notifier.Notify(sub, i); err != nil {
+				return
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (s *testSubscriptionService) Unsubscribe(ctx context.Context, id SubscriptionID) (bool, error) {
+	notifier, ok := NotifierFromContext(ctx)
+	if !ok {
+		return false, ErrNotificationsUnsupported
+	}
+	if err := notifier.Unsubscribe(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func TestWebsocketSubscription(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	defer server.Stop()
+	require.NoError(t, server.RegisterName("test", &testSubscriptionService{}))
+
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "test_count", "params": []any{3},
+	}))
+
+	// The response with the id must come before any notification.
+	var resp struct {
+		Id     int            `json:"id"`
+		Result SubscriptionID `json:"result"`
+	}
+	require.NoError(t, conn.ReadJSON(&resp))
+	require.Equal(t, 1, resp.Id)
+	require.NotEmpty(t, resp.Result)
+
+	for i := range 3 {
+		var n struct {
+			Method string `json:"method"`
+			Params struct {
+				Subscription SubscriptionID `json:"subscription"`
+				Result       int            `json:"result"`
+			} `json:"params"`
+		}
+		require.NoError(t, conn.ReadJSON(&n))
+		assert.Equal(t, "test_subscription", n.Method)
+		assert.Equal(t, resp.Result, n.Params.Subscription)
+		assert.Equal(t, i, n.Params.Result)
+	}
+
+	require.NoError(t, conn.WriteJSON(map[string]any{
+		"jsonrpc": "2.0", "id": 2, "method": "test_unsubscribe", "params": []any{resp.Result},
+	}))
+	var unsub struct {
+		Id     int  `json:"id"`
+		Result bool `json:"result"`
+	}
+	require.NoError(t, conn.ReadJSON(&unsub))
+	assert.Equal(t, 2, unsub.Id)
+	assert.True(t, unsub.Result)
+
+	// The second attempt fails since the subscription is gone.
+	require.NoError(t, conn.WriteJSON(map[string]any{
+		"jsonrpc": "2.0", "id": 3, "method": "test_unsubscribe", "params": []any{resp.Result},
+	}))
+	var unsubErr struct {
+		Id    int `json:"id"`
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, conn.ReadJSON(&unsubErr))
+	assert.Equal(t, 3, unsubErr.Id)
+	assert.Equal(t, ErrSubscriptionNotFound.Code, unsubErr.Error.Code)
+}
@@ -13,8 +13,6 @@ import (
 	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches"
-	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
-	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reset"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
@@ -54,6 +52,7 @@ func NewAggregator(
 	rpcClient client.Client,
 	blockStorage AggregatorBlockStorage,
 	taskStorage AggregatorTaskStorage,
+	batchCommitter batches.BatchCommitter,
 	resetter reset.StateResetter,
 	timer common.Timer,
 	logger zerolog.Logger,
@@ -61,19 +60,13 @@ func NewAggregator(
 	pollingDelay time.Duration,
 ) *aggregator {
 	agg := &aggregator{
-		rpcClient:    rpcClient,
-		blockStorage: blockStorage,
-		taskStorage:  taskStorage,
-		batchCommitter: batches.NewBatchCommitter(
-			v1.NewEncoder(logger),
-			blob.NewBuilder(),
-			nil, // TODO
-			logger,
-			batches.DefaultCommitOptions(),
-		),
-		resetter: resetter,
-		timer:    timer,
-		metrics:  metrics,
+		rpcClient:      rpcClient,
+		blockStorage:   blockStorage,
+		taskStorage:    taskStorage,
+		batchCommitter: batchCommitter,
+		resetter:       resetter,
+		timer:          timer,
+		metrics:        metrics,
 	}
 
 	agg.workerAction = concurrent.NewSuspendable(agg.runIteration, pollingDelay)
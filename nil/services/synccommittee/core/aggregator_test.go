@@ -12,8 +12,12 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reset"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
 	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
@@ -55,10 +59,23 @@ func (s *AggregatorTestSuite) SetupSuite() {
 
 	stateResetter := reset.NewStateResetter(logger, s.blockStorage)
 
+	batchCommitter := batches.NewBatchCommitter(
+		v1.NewEncoder(logger),
+		blob.NewBuilder(),
+		s.blockStorage,
+		&rollupcontract.EthClientMock{},
+		batches.ContractParams{},
+		timer,
+		metricsHandler,
+		logger,
+		batches.DefaultCommitOptions(),
+	)
+
 	s.aggregator = NewAggregator(
 		s.rpcClientMock,
 		s.blockStorage,
 		s.taskStorage,
+		batchCommitter,
 		stateResetter,
 		timer,
 		logger,
@@ -230,7 +247,7 @@ func blockGenerator(mainBlock *jsonrpc.RPCBlock) func(context.Context, types.Sha
 // requireNoNewTasks asserts that there are no new tasks available for execution
 func (s *AggregatorTestSuite) requireNoNewTasks() {
 	s.T().Helper()
-	task, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	task, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), scTypes.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 }
@@ -256,7 +273,7 @@ func (s *AggregatorTestSuite) requireMainBlockHandled(mainBlock *jsonrpc.RPCBloc
 
 	// one ProofBlock task per exec block was created
 	for range childIds {
-		taskToExecute, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+		taskToExecute, err := s.taskStorage.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), scTypes.ExecutorCapabilities{})
 		s.Require().NoError(err)
 		s.Require().NotNil(taskToExecute)
 		s.Require().Equal(scTypes.ProofBlock, taskToExecute.TaskType)
@@ -2,7 +2,6 @@ package blob
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
 
@@ -19,81 +18,60 @@ func NewBuilder() *builder {
 }
 
 func (bb *builder) MakeBlobs(rd io.Reader, blobLimit int) ([]kzg4844.Blob, error) {
-	const blobSize = len(kzg4844.Blob{})
-
-	var blobs []kzg4844.Blob
-	eof := false
-	writtenBits := 0
+	// each 2 most significant bits of every u256 word cannot be used,
+	// otherwise the word could exceed the BLS12-381 scalar field modulus
+	const (
+		paddingBits  = 2
+		wordDataBits = 256 - paddingBits
+		blobWords    = blobSize / 32
+	)
+
+	// the batch is limited by a few blobs, so it is simpler to read it at once and know the exact amount of bits
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	dataBits := len(data) * 8
+	if dataBits > blobLimit*blobWords*wordDataBits {
+		return nil, fmt.Errorf("provided batch does not fit into %d blobs (%d bytes) [data = %d bits]",
+			blobLimit, blobSize*blobLimit, dataBits)
+	}
 
-	bitReader := bitio.NewReader(rd) // bit wrapper for reading 254-bit pieces of data to place into the blobs
+	bitReader := bitio.NewReader(bytes.NewReader(data)) // bit wrapper for reading 254-bit pieces of data
+	readBits := 0
 
+	var blobs []kzg4844.Blob
 	var blobBuf bytes.Buffer
 	blobBuf.Grow(blobSize)
-
-	ignoreEOF := func(err error) error {
-		if errors.Is(err, io.EOF) {
-			eof = true
-			return nil
-		}
-		return err
-	}
-
-	align := 0
-	for i := 0; !eof && i < blobLimit; i++ {
+	for readBits < dataBits {
 		blobBuf.Reset()
-
 		blobWriter := bitio.NewWriter(&blobBuf)
-		writtenInBlob := 0
-		for writtenInBlob < blobSize && !eof {
-			var ethWordBuf [31]byte
-
-			read, err := bitReader.Read(ethWordBuf[:])
-			if err := ignoreEOF(err); err != nil {
-				return nil, err
-			}
-
-			wr, err := blobWriter.Write(ethWordBuf[:read])
-			if err != nil {
-				return nil, err
-			}
-			writtenInBlob += wr
-
-			if read < len(ethWordBuf) {
-				writtenBits += read * 8
-				eof = true
-				break
-			}
-
-			const lastByteBits = 6 // each 2 last bits of every u256 word cannot be used
-
-			lastbyte, err := bitReader.ReadBits(lastByteBits)
-			if err := ignoreEOF(err); err != nil {
-				return nil, err
-			}
 
-			if err := blobWriter.WriteBits(lastbyte, lastByteBits); err != nil {
+		for word := 0; word < blobWords && readBits < dataBits; word++ {
+			if err := blobWriter.WriteBits(0, paddingBits); err != nil {
 				return nil, err
 			}
-
-			aligned, err := blobWriter.Align()
-			if err != nil {
-				return nil, err
+			// the last word might be incomplete, the rest of it is filled with zeroes
+			for left := min(wordDataBits, dataBits-readBits); left > 0; {
+				chunk := uint8(min(left, 64))
+				bits, err := bitReader.ReadBits(chunk)
+				if err != nil {
+					return nil, err
+				}
+				if err := blobWriter.WriteBits(bits, chunk); err != nil {
+					return nil, err
+				}
+				left -= int(chunk)
+				readBits += int(chunk)
 			}
-			align += int(aligned)
-			writtenInBlob++
-
-			writtenBits += 32*8 - 2
 		}
-
-		if writtenBits > 0 {
-			var blob kzg4844.Blob
-			copy(blob[:], blobBuf.Bytes())
-			blobs = append(blobs, blob)
+		if err := blobWriter.Close(); err != nil {
+			return nil, err
 		}
-	}
-	if !eof {
-		return nil, fmt.Errorf("provided batch does not fit into %d blobs (%d bytes) [written = %d bits] [align = %d bits]",
-			blobLimit, blobSize*blobLimit, writtenBits, align)
+
+		var blob kzg4844.Blob
+		copy(blob[:], blobBuf.Bytes())
+		blobs = append(blobs, blob)
 	}
 	return blobs, nil
 }
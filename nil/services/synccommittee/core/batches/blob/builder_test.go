@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +22,7 @@ func TestMakeBlobs_ValidInput(t *testing.T) {
 	const u256word = 32
 
 	fullWordWithPadding := bytes.Repeat([]byte{0xFF}, u256word)
-	fullWordWithPadding[len(fullWordWithPadding)-1] = 0xFC
+	fullWordWithPadding[0] = 0x3F
 
 	// check that first blob is fully filled with data
 	for word := range blobSize / u256word {
@@ -40,10 +41,12 @@ func TestMakeBlobs_ValidInput(t *testing.T) {
 		require.Equal(t, fullWordWithPadding, blobs[1][start:end], "invalid data or padding, word %d", word)
 	}
 
-	// check that first 12 bytes of the last word are
+	// check that the last word contains 96 bits of data after the padding
 	lastWordStart := wordsUsedInSecondBlob * u256word
-	lastWordEnd := lastWordStart + 12
-	require.Equal(t, blobs[1][lastWordStart:lastWordEnd], bytes.Repeat([]byte{0xFF}, 12), "invalid end of data")
+	lastWordEnd := lastWordStart + 13
+	lastWord := append([]byte{0x3F}, bytes.Repeat([]byte{0xFF}, 11)...)
+	lastWord = append(lastWord, 0xC0)
+	require.Equal(t, lastWord, blobs[1][lastWordStart:lastWordEnd], "invalid end of data")
 
 	// check that the rest of the buffer is empty
 	require.Equal(t, blobs[1][lastWordEnd:], bytes.Repeat([]byte{0x00}, blobSize-lastWordEnd), "end of blob is not zero padded")
@@ -71,3 +74,36 @@ func TestEmptyData(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, blobs)
 }
+
+func TestMakeBlobs_ValidFieldElements(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilder()
+	input := bytes.Repeat([]byte{0xFF}, blobSize/2)
+	blobs, err := builder.MakeBlobs(bytes.NewReader(input), 1)
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+
+	_, err = kzg4844.BlobToCommitment(&blobs[0])
+	require.NoError(t, err)
+}
+
+func TestMakeBlobs_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// lengths leaving a tail of various size in the last 254-bit word
+	for _, size := range []int{1, 31, 32, 33, 64, 1730, blobSize - 1, blobSize + 1000} {
+		input := make([]byte, size)
+		for i := range input {
+			input[i] = byte(i*7 + 0x55)
+		}
+
+		blobs, err := NewBuilder().MakeBlobs(bytes.NewReader(input), 2)
+		require.NoError(t, err)
+
+		output, err := ReadAll(blobs)
+		require.NoError(t, err)
+		require.Equal(t, input, output[:size], "data of size %d is corrupted", size)
+		require.Equal(t, make([]byte, len(output)-size), output[size:], "padding of size %d is not zero", size)
+	}
+}
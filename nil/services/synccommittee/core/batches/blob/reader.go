@@ -39,8 +39,17 @@ func (r *reader) Read(dst []byte) (int, error) {
 	for !r.eof() && writtenBits < dstBits {
 		r.wordOffset %= 256
 
+		if r.wordOffset == 0 {
+			// skip 2 most significant bits of the word, they are not used for data
+			_, err := r.readBits(2)
+			if err != nil {
+				return writtenBits / 8, err // failed to align
+			}
+			r.wordOffset += 2
+		}
+
 		left := dstBits - writtenBits
-		toRead := uint8(min(left, 64, 254-r.wordOffset))
+		toRead := uint8(min(left, 64, 256-r.wordOffset))
 		bits, err := r.readBits(toRead)
 		if err != nil {
 			return writtenBits / 8, err
@@ -52,14 +61,6 @@ func (r *reader) Read(dst []byte) (int, error) {
 			return writtenBits / 8, err
 		}
 		writtenBits += int(toRead)
-
-		if r.wordOffset == 254 {
-			_, err := r.readBits(2)
-			if err != nil {
-				return writtenBits / 8, err // failed to align
-			}
-			r.wordOffset += 2
-		}
 	}
 	copy(dst, buf.Bytes()) // bytes.NewBuffer is an owning call so it is potentially unsafe to use dst without copying
 
@@ -104,3 +105,16 @@ func (r *reader) advance() bool {
 func (r *reader) eof() bool {
 	return r.curBlobIdx >= len(r.blobs)
 }
+
+// payloadSize is the number of data bytes stored in a single blob, 2 bits of every word are not used
+const payloadSize = blobSize / 32 * 254 / 8
+
+// ReadAll reads all the data stored in the blobs including the zero padding after it
+func ReadAll(blobs []kzg4844.Blob) ([]byte, error) {
+	data := make([]byte, len(blobs)*payloadSize)
+	read, err := NewReader(blobs).Read(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:read], nil
+}
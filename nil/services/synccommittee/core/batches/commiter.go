@@ -3,16 +3,32 @@ package batches
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"math/big"
+	"time"
 
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/rs/zerolog"
 )
 
+// BatchCommitter queues batches for commitment and submits them to L1 in the background,
+// tracking the inclusion of the commit transactions until the batches are finalized.
 type BatchCommitter interface {
+	srv.Worker
 	Commit(ctx context.Context, batch *types.PrunedBatch) error
+
+	// CommitmentBlobs rebuilds the blobs the batch of the commitment is submitted to L1 with.
+	CommitmentBlobs(commitment *types.BatchCommitment) ([]kzg4844.Blob, error)
 }
 
 type batchEncoder interface {
@@ -24,58 +40,434 @@ type blobBuilder interface {
 }
 
 type ethCommitter interface {
-	CommitBatch(ctx context.Context, blobs []kzg4844.Blob, batchIndex string) (*ethtypes.Transaction, error)
+	IsBatchCommitted(ctx context.Context, batchIndex string) (bool, error)
+	FinalizedBatchIndex(ctx context.Context) (string, error)
+	CreateCommitBatchTx(
+		ctx context.Context,
+		blobs []kzg4844.Blob,
+		batchIndex string,
+		replacement *rollupcontract.TxReplacement,
+	) (*ethtypes.Transaction, error)
+	SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error
+	TransactionReceipt(ctx context.Context, txnHash ethcommon.Hash) (*ethtypes.Receipt, error)
+	LatestBlockNumber(ctx context.Context) (uint64, error)
+}
+
+type CommitStorage interface {
+	AddBatchCommitment(ctx context.Context, commitment *types.BatchCommitment) error
+
+	PutBatchCommitment(ctx context.Context, commitment *types.BatchCommitment) error
+
+	TryGetBatchCommitment(ctx context.Context, batchId types.BatchId) (*types.BatchCommitment, error)
+
+	GetBatchCommitments(ctx context.Context) ([]*types.BatchCommitment, error)
+
+	PruneFinalizedCommitments(ctx context.Context, finalizedId types.BatchId) (int, error)
+}
+
+type CommitterMetrics interface {
+	metrics.BasicMetrics
+}
+
+// ContractParams defines the rollup contract the batches are committed to
+type ContractParams struct {
+	ContractAddress  string
+	PrivateKey       string
+	EthClientTimeout time.Duration
 }
 
 type batchCommitter struct {
-	encoder      batchEncoder
-	blobBuilder  blobBuilder
-	ethCommitter ethCommitter
-	logger       zerolog.Logger
-	options      *commitOptions
+	srv.WorkerLoop
+
+	encoder        batchEncoder
+	blobBuilder    blobBuilder
+	storage        CommitStorage
+	ethClient      rollupcontract.EthClient
+	contractParams ContractParams
+	ethCommitter   ethCommitter
+	timer          common.Timer
+	metrics        CommitterMetrics
+	logger         zerolog.Logger
+	options        *commitOptions
 }
 
 func NewBatchCommitter(
 	encoder batchEncoder,
 	blobBuilder blobBuilder,
-	ethCommitter ethCommitter,
+	storage CommitStorage,
+	ethClient rollupcontract.EthClient,
+	contractParams ContractParams,
+	timer common.Timer,
+	metrics CommitterMetrics,
 	logger zerolog.Logger,
 	options *commitOptions,
 ) BatchCommitter {
-	return &batchCommitter{
-		encoder:      encoder,
-		blobBuilder:  blobBuilder,
-		ethCommitter: ethCommitter,
-		logger:       logger,
-		options:      options,
+	committer := &batchCommitter{
+		encoder:        encoder,
+		blobBuilder:    blobBuilder,
+		storage:        storage,
+		ethClient:      ethClient,
+		contractParams: contractParams,
+		timer:          timer,
+		metrics:        metrics,
+		options:        options,
 	}
+
+	committer.WorkerLoop = srv.NewWorkerLoop("batch_committer", options.checkInterval, committer.runIteration)
+	committer.logger = srv.WorkerLogger(logger, committer)
+	return committer
 }
 
 type commitOptions struct {
 	maxBlobCount int
+
+	// checkInterval is the interval between the checks of the submitted transactions
+	checkInterval time.Duration
+
+	// confirmations is the number of L1 blocks, including the one with the commit transaction,
+	// after which the batch is considered to be committed
+	confirmations uint64
+
+	// replaceTimeout is the time after which the transaction not included into L1 is replaced
+	replaceTimeout time.Duration
+
+	// feeBumpPercent is the fee increase of the replacement transaction,
+	// blob pool of geth requires the fees of the replacement to be doubled
+	feeBumpPercent int64
 }
 
 func DefaultCommitOptions() *commitOptions {
 	return &commitOptions{
-		maxBlobCount: 6,
+		maxBlobCount:   6,
+		checkInterval:  12 * time.Second,
+		confirmations:  6,
+		replaceTimeout: 3 * time.Minute,
+		feeBumpPercent: 100,
 	}
 }
 
+// Commit encodes the batch and queues it for submission to L1. Committing the same batch twice has no effect.
 func (bc *batchCommitter) Commit(ctx context.Context, batch *types.PrunedBatch) error {
+	existing, err := bc.storage.TryGetBatchCommitment(ctx, batch.BatchId)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		bc.logger.Warn().Stringer(logging.FieldBatchId, batch.BatchId).Msg("batch is already queued for commitment")
+		return nil
+	}
+
 	var binTransactions bytes.Buffer
 	if err := bc.encoder.Encode(batch, &binTransactions); err != nil {
 		return err
 	}
 	bc.logger.Debug().Int("compressed_batch_len", binTransactions.Len()).Msg("encoded transaction")
 
-	blobs, err := bc.blobBuilder.MakeBlobs(&binTransactions, bc.options.maxBlobCount)
+	// blobs are built here only to ensure the batch fits into the limit, the worker rebuilds them on submission
+	blobs, err := bc.blobBuilder.MakeBlobs(bytes.NewReader(binTransactions.Bytes()), bc.options.maxBlobCount)
 	if err != nil {
 		return err
 	}
 	bc.logger.Debug().Int("batch_blob_count", len(blobs)).Msg("packed batch blobs")
 
-	// TODO add ethCommiter.CommitBatch() call
-	bc.logger.Info().Int("blob_count", len(blobs)).Msg("committed batch")
+	commitment := types.NewBatchCommitment(batch.BatchId, binTransactions.Bytes(), bc.timer.NowTime())
+	if err := bc.storage.AddBatchCommitment(ctx, commitment); err != nil {
+		return fmt.Errorf("failed to queue batch %s for commitment: %w", batch.BatchId, err)
+	}
+
+	bc.logger.Info().
+		Stringer(logging.FieldBatchId, batch.BatchId).
+		Int("blob_count", len(blobs)).
+		Msg("batch queued for commitment")
+	return nil
+}
+
+func (bc *batchCommitter) CommitmentBlobs(commitment *types.BatchCommitment) ([]kzg4844.Blob, error) {
+	return bc.blobBuilder.MakeBlobs(bytes.NewReader(commitment.Data), bc.options.maxBlobCount)
+}
+
+func (bc *batchCommitter) runIteration(ctx context.Context) {
+	if err := bc.processCommitments(ctx); err != nil {
+		bc.logger.Error().Err(err).Msg("failed to process batch commitments")
+		bc.metrics.RecordError(ctx, bc.Name())
+	}
+}
 
+func (bc *batchCommitter) processCommitments(ctx context.Context) error {
+	if err := bc.initEthCommitter(ctx); err != nil {
+		return err
+	}
+
+	if err := bc.pruneFinalized(ctx); err != nil {
+		return err
+	}
+
+	commitments, err := bc.storage.GetBatchCommitments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get batch commitments: %w", err)
+	}
+	if len(commitments) == 0 {
+		return nil
+	}
+
+	head, err := bc.ethCommitter.LatestBlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest L1 block number: %w", err)
+	}
+
+	for _, commitment := range commitments {
+		var err error
+		switch commitment.Status {
+		case types.BatchCommitPending:
+			err = bc.submit(ctx, commitment)
+		case types.BatchCommitSubmitted:
+			err = bc.checkSubmitted(ctx, commitment, head)
+		case types.BatchCommitStatusNone, types.BatchCommitCommitted:
+		}
+		if err != nil {
+			return fmt.Errorf("failed to process commitment of batch %s: %w", commitment.BatchId, err)
+		}
+	}
+	return nil
+}
+
+// initEthCommitter creates the rollup contract wrapper on the first iteration,
+// so the service can be started while L1 is unavailable.
+func (bc *batchCommitter) initEthCommitter(ctx context.Context) error {
+	if bc.ethCommitter != nil {
+		return nil
+	}
+
+	wrapper, err := rollupcontract.NewWrapper(
+		ctx,
+		bc.contractParams.ContractAddress,
+		bc.contractParams.PrivateKey,
+		bc.ethClient,
+		bc.contractParams.EthClientTimeout,
+		bc.logger,
+	)
+	if err != nil {
+		return fmt.Errorf("failed create rollup contract wrapper: %w", err)
+	}
+	bc.ethCommitter = wrapper
+	return nil
+}
+
+// pruneFinalized removes the commitments of the batches finalized on L1, they are not tracked anymore.
+func (bc *batchCommitter) pruneFinalized(ctx context.Context) error {
+	finalizedIndex, err := bc.ethCommitter.FinalizedBatchIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get finalized batch index: %w", err)
+	}
+
+	var finalizedId types.BatchId
+	if err := finalizedId.Set(finalizedIndex); err != nil {
+		bc.logger.Debug().Str("finalizedBatchIndex", finalizedIndex).Msg("finalized batch index is not a batch id")
+		return nil
+	}
+
+	pruned, err := bc.storage.PruneFinalizedCommitments(ctx, finalizedId)
+	if err != nil {
+		return fmt.Errorf("failed to prune finalized commitments: %w", err)
+	}
+	if pruned > 0 {
+		bc.logger.Info().
+			Stringer(logging.FieldBatchId, finalizedId).
+			Int("prunedCount", pruned).
+			Msg("pruned commitments of finalized batches")
+	}
 	return nil
 }
+
+// submit sends the first transaction committing the batch. The commitment is saved before the transaction is sent,
+// so after a restart the batch is not submitted with another nonce.
+func (bc *batchCommitter) submit(ctx context.Context, commitment *types.BatchCommitment) error {
+	committed, err := bc.ethCommitter.IsBatchCommitted(ctx, commitment.BatchId.String())
+	if err != nil {
+		return err
+	}
+	if committed {
+		bc.logger.Info().Stringer(logging.FieldBatchId, commitment.BatchId).Msg("batch is already committed to L1")
+		commitment.Status = types.BatchCommitCommitted
+		return bc.storage.PutBatchCommitment(ctx, commitment)
+	}
+
+	return bc.send(ctx, commitment, nil)
+}
+
+func (bc *batchCommitter) checkSubmitted(ctx context.Context, commitment *types.BatchCommitment, head uint64) error {
+	receipt, err := bc.findReceipt(ctx, commitment)
+	if err != nil {
+		return err
+	}
+
+	if receipt != nil {
+		return bc.onIncluded(ctx, commitment, receipt, head)
+	}
+
+	if commitment.IncludedIn != nil {
+		// the block with the transaction is gone, the transaction is resent with the same fees
+		bc.logger.Warn().
+			Stringer(logging.FieldBatchId, commitment.BatchId).
+			Uint64("includedIn", *commitment.IncludedIn).
+			Msg("commit transaction is dropped from L1 by reorg, resending")
+		commitment.IncludedIn = nil
+		return bc.send(ctx, commitment, bc.replacementOf(commitment.Tx, 0))
+	}
+
+	if bc.timer.NowTime().Sub(commitment.Tx.SentAt) < bc.options.replaceTimeout {
+		return nil
+	}
+
+	bc.logger.Warn().
+		Stringer(logging.FieldBatchId, commitment.BatchId).
+		Uint64("nonce", commitment.Tx.Nonce).
+		Time("sentAt", commitment.Tx.SentAt).
+		Msg("commit transaction is not included, replacing it with higher fees")
+	return bc.send(ctx, commitment, bc.replacementOf(commitment.Tx, bc.options.feeBumpPercent))
+}
+
+// findReceipt returns the receipt of any transaction sent with the current nonce.
+func (bc *batchCommitter) findReceipt(ctx context.Context, commitment *types.BatchCommitment) (*ethtypes.Receipt, error) {
+	for _, hash := range commitment.SentTxHashes {
+		receipt, err := bc.ethCommitter.TransactionReceipt(ctx, ethcommon.Hash(hash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receipt of transaction %s: %w", hash, err)
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (bc *batchCommitter) onIncluded(
+	ctx context.Context,
+	commitment *types.BatchCommitment,
+	receipt *ethtypes.Receipt,
+	head uint64,
+) error {
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		bc.logger.Warn().
+			Stringer(logging.FieldBatchId, commitment.BatchId).
+			Stringer(logging.FieldTransactionHash, receipt.TxHash).
+			Msg("commit transaction is reverted")
+		return bc.onNonceUsed(ctx, commitment)
+	}
+
+	includedIn := receipt.BlockNumber.Uint64()
+	changed := commitment.IncludedIn == nil || *commitment.IncludedIn != includedIn
+	commitment.IncludedIn = &includedIn
+
+	if head >= includedIn && head-includedIn+1 >= bc.options.confirmations {
+		commitment.Status = types.BatchCommitCommitted
+		changed = true
+		bc.logger.Info().
+			Stringer(logging.FieldBatchId, commitment.BatchId).
+			Stringer(logging.FieldTransactionHash, receipt.TxHash).
+			Uint64("includedIn", includedIn).
+			Msg("batch is committed to L1")
+	}
+
+	if !changed {
+		return nil
+	}
+	return bc.storage.PutBatchCommitment(ctx, commitment)
+}
+
+// onNonceUsed handles the case when the nonce of the commitment is used by a transaction which did not commit the batch.
+// If the batch is committed by some other transaction, the commitment is complete, otherwise it is submitted again.
+func (bc *batchCommitter) onNonceUsed(ctx context.Context, commitment *types.BatchCommitment) error {
+	committed, err := bc.ethCommitter.IsBatchCommitted(ctx, commitment.BatchId.String())
+	if err != nil {
+		return err
+	}
+
+	if committed {
+		commitment.Status = types.BatchCommitCommitted
+	} else {
+		bc.logger.Warn().Stringer(logging.FieldBatchId, commitment.BatchId).Msg("batch is not committed, resubmitting it")
+		commitment.Reset()
+	}
+	return bc.storage.PutBatchCommitment(ctx, commitment)
+}
+
+func (bc *batchCommitter) replacementOf(tx *types.CommitTx, bumpPercent int64) *rollupcontract.TxReplacement {
+	bump := func(value *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(value, big.NewInt(100+bumpPercent))
+		return bumped.Div(bumped, big.NewInt(100))
+	}
+	return &rollupcontract.TxReplacement{
+		Nonce:      tx.Nonce,
+		GasTipCap:  bump(tx.GasTipCap),
+		GasFeeCap:  bump(tx.GasFeeCap),
+		BlobFeeCap: bump(tx.BlobFeeCap),
+	}
+}
+
+// send creates the commit transaction, saves it to the storage and sends it to L1.
+func (bc *batchCommitter) send(
+	ctx context.Context,
+	commitment *types.BatchCommitment,
+	replacement *rollupcontract.TxReplacement,
+) error {
+	blobs, err := bc.CommitmentBlobs(commitment)
+	if err != nil {
+		return err
+	}
+
+	tx, err := bc.ethCommitter.CreateCommitBatchTx(ctx, blobs, commitment.BatchId.String(), replacement)
+	if err != nil {
+		return fmt.Errorf("failed to create commit transaction: %w", err)
+	}
+
+	var prevSentAt time.Time
+	if commitment.Tx != nil {
+		prevSentAt = commitment.Tx.SentAt
+	}
+
+	commitment.OnSent(&types.CommitTx{
+		Hash:       common.Hash(tx.Hash()),
+		Nonce:      tx.Nonce(),
+		GasTipCap:  tx.GasTipCap(),
+		GasFeeCap:  tx.GasFeeCap(),
+		BlobFeeCap: tx.BlobGasFeeCap(),
+		SentAt:     bc.timer.NowTime(),
+	})
+	if err := bc.storage.PutBatchCommitment(ctx, commitment); err != nil {
+		return err
+	}
+
+	err = bc.ethCommitter.SendTransaction(ctx, tx)
+	switch {
+	case err == nil:
+		bc.logger.Info().
+			Stringer(logging.FieldBatchId, commitment.BatchId).
+			Stringer(logging.FieldTransactionHash, tx.Hash()).
+			Uint64("nonce", tx.Nonce()).
+			Stringer("gasFeeCap", tx.GasFeeCap()).
+			Stringer("blobFeeCap", tx.BlobGasFeeCap()).
+			Msg("commit transaction is sent")
+		return nil
+
+	case errors.Is(err, rollupcontract.ErrReplaceUnderpriced) && replacement != nil:
+		// the fees of the rejected transaction are kept, so the next replacement bumps them further,
+		// its sending time is not updated since the previous transaction is still waiting for inclusion
+		bc.logger.Warn().Err(err).
+			Stringer(logging.FieldBatchId, commitment.BatchId).
+			Msg("replacement of commit transaction is underpriced")
+		commitment.Tx.SentAt = prevSentAt
+		return bc.storage.PutBatchCommitment(ctx, commitment)
+
+	case errors.Is(err, rollupcontract.ErrNonceTooLow):
+		if receipt, err := bc.findReceipt(ctx, commitment); err != nil || receipt != nil {
+			// one of the previous transactions is included in the meantime, it is handled on the next iteration
+			return err
+		}
+		return bc.onNonceUsed(ctx, commitment)
+
+	default:
+		// the transaction is saved, so it is sent again when the replacement timeout expires
+		return fmt.Errorf("failed to send commit transaction: %w", err)
+	}
+}
@@ -0,0 +1,575 @@
+package batches
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchCommitterTestSuite struct {
+	suite.Suite
+
+	ctx          context.Context
+	cancellation context.CancelFunc
+
+	db        db.DB
+	storage   *storage.BlockStorage
+	timer     *common.TestTimerImpl
+	metrics   *metrics.SyncCommitteeMetricsHandler
+	l1        *simulatedL1
+	params    ContractParams
+	options   *commitOptions
+	committer *batchCommitter
+}
+
+func TestBatchCommitterTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(BatchCommitterTestSuite))
+}
+
+func (s *BatchCommitterTestSuite) SetupSuite() {
+	s.ctx, s.cancellation = context.WithCancel(context.Background())
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	s.metrics, err = metrics.NewSyncCommitteeMetrics()
+	s.Require().NoError(err)
+	s.timer = testaide.NewTestTimer()
+	s.storage = storage.NewBlockStorage(s.db, s.timer, s.metrics, logging.NewLogger("batch_committer_test"))
+
+	privateKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.params = ContractParams{
+		ContractAddress:  "0x796baf7E572948CD0cbC374f345963bA433b47a2",
+		PrivateKey:       hex.EncodeToString(crypto.FromECDSA(privateKey)),
+		EthClientTimeout: time.Second,
+	}
+	s.options = DefaultCommitOptions()
+}
+
+func (s *BatchCommitterTestSuite) SetupTest() {
+	s.Require().NoError(s.db.DropAll())
+	s.l1 = newSimulatedL1()
+	s.committer = s.newCommitter()
+}
+
+func (s *BatchCommitterTestSuite) TearDownSuite() {
+	s.cancellation()
+	s.db.Close()
+}
+
+func (s *BatchCommitterTestSuite) newCommitter() *batchCommitter {
+	s.T().Helper()
+	logger := logging.NewLogger("batch_committer_test")
+	committer := NewBatchCommitter(
+		v1.NewEncoder(logger),
+		blob.NewBuilder(),
+		s.storage,
+		s.l1.ethClient(),
+		s.params,
+		s.timer,
+		s.metrics,
+		logger,
+		s.options,
+	)
+	return committer.(*batchCommitter)
+}
+
+func (s *BatchCommitterTestSuite) commitNewBatch() types.BatchId {
+	s.T().Helper()
+	batch := types.NewPrunedBatch(testaide.NewBlockBatch(2))
+	s.Require().NoError(s.committer.Commit(s.ctx, batch))
+	return batch.BatchId
+}
+
+func (s *BatchCommitterTestSuite) iterate() {
+	s.T().Helper()
+	s.Require().NoError(s.committer.processCommitments(s.ctx))
+}
+
+func (s *BatchCommitterTestSuite) requireCommitment(batchId types.BatchId, status types.BatchCommitStatus) *types.BatchCommitment {
+	s.T().Helper()
+	commitment, err := s.storage.TryGetBatchCommitment(s.ctx, batchId)
+	s.Require().NoError(err)
+	s.Require().NotNil(commitment)
+	s.Require().Equal(status, commitment.Status)
+	return commitment
+}
+
+// confirm mines blocks until the commit transaction has enough confirmations
+func (s *BatchCommitterTestSuite) confirm(batchId types.BatchId) {
+	s.T().Helper()
+	for range s.options.confirmations - 1 {
+		s.l1.mine()
+		s.iterate()
+		s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	}
+	s.l1.mine()
+	s.iterate()
+	s.requireCommitment(batchId, types.BatchCommitCommitted)
+}
+
+func (s *BatchCommitterTestSuite) Test_Commit_Batch() {
+	batchId := s.commitNewBatch()
+	commitment := s.requireCommitment(batchId, types.BatchCommitPending)
+	s.Require().NotEmpty(commitment.Data)
+
+	// committing the same batch twice has no effect
+	s.Require().NoError(s.committer.Commit(s.ctx, &types.PrunedBatch{BatchId: batchId}))
+	commitments, err := s.storage.GetBatchCommitments(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(commitments, 1)
+
+	s.iterate()
+	commitment = s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().Len(s.l1.mempoolTxs(), 1)
+
+	// transaction is not resent while it waits for inclusion
+	s.iterate()
+	s.Require().Equal(1, s.l1.sentCount())
+
+	s.l1.mine()
+	s.iterate()
+	commitment = s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().NotNil(commitment.IncludedIn)
+	s.Require().Equal(s.l1.head(), *commitment.IncludedIn)
+	s.Require().True(s.l1.isCommitted(batchId.String()))
+
+	for range s.options.confirmations - 2 {
+		s.l1.mine()
+		s.iterate()
+		s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	}
+	s.l1.mine()
+	s.iterate()
+	s.requireCommitment(batchId, types.BatchCommitCommitted)
+}
+
+func (s *BatchCommitterTestSuite) Test_Commit_Batches_In_Order() {
+	first := s.commitNewBatch()
+	second := s.commitNewBatch()
+	s.iterate()
+
+	firstTx := s.requireCommitment(first, types.BatchCommitSubmitted).Tx
+	secondTx := s.requireCommitment(second, types.BatchCommitSubmitted).Tx
+	s.Require().Equal(firstTx.Nonce+1, secondTx.Nonce)
+}
+
+func (s *BatchCommitterTestSuite) Test_Reorg_Resends_Transaction() {
+	batchId := s.commitNewBatch()
+	s.iterate()
+	s.l1.mine()
+	s.l1.mine()
+	s.iterate()
+	includedIn := s.requireCommitment(batchId, types.BatchCommitSubmitted).IncludedIn
+	s.Require().NotNil(includedIn)
+
+	// blocks with the transaction are replaced, the transaction is not returned to the pool
+	s.l1.reorg(2)
+	s.Require().False(s.l1.isCommitted(batchId.String()))
+	s.Require().Empty(s.l1.mempoolTxs())
+
+	s.iterate()
+	commitment := s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().Nil(commitment.IncludedIn)
+	s.Require().Len(s.l1.mempoolTxs(), 1)
+	s.Require().Equal(commitment.Tx.Nonce, s.l1.mempoolTxs()[0].Nonce())
+
+	s.confirm(batchId)
+	s.Require().True(s.l1.isCommitted(batchId.String()))
+}
+
+func (s *BatchCommitterTestSuite) Test_Replacement_Underpriced() {
+	batchId := s.commitNewBatch()
+	s.iterate()
+	original := s.requireCommitment(batchId, types.BatchCommitSubmitted).Tx
+
+	// the replacement is not sent before the timeout
+	s.timer.Add(s.options.replaceTimeout / 2)
+	s.iterate()
+	s.Require().Equal(1, s.l1.sentCount())
+
+	s.timer.Add(s.options.replaceTimeout / 2)
+	s.l1.setRejectReplacements(true)
+	s.iterate()
+
+	rejected := s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().Len(rejected.SentTxHashes, 2)
+	s.Require().Equal(original.Nonce, rejected.Tx.Nonce)
+	s.Require().True(original.SentAt.Equal(rejected.Tx.SentAt))
+	s.Require().Equal(original.Hash, common.Hash(s.l1.mempoolTxs()[0].Hash()))
+
+	// the next attempt bumps the fees of the rejected transaction further
+	s.l1.setRejectReplacements(false)
+	s.iterate()
+	replacement := s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().Len(replacement.SentTxHashes, 3)
+	s.Require().Equal(original.Nonce, replacement.Tx.Nonce)
+	s.Require().True(s.timer.NowTime().Equal(replacement.Tx.SentAt))
+	s.Require().Equal(0, replacement.Tx.GasFeeCap.Cmp(new(big.Int).Mul(original.GasFeeCap, big.NewInt(4))))
+	s.Require().Equal(0, replacement.Tx.BlobFeeCap.Cmp(new(big.Int).Mul(original.BlobFeeCap, big.NewInt(4))))
+
+	mempool := s.l1.mempoolTxs()
+	s.Require().Len(mempool, 1)
+	s.Require().Equal(replacement.Tx.Hash, common.Hash(mempool[0].Hash()))
+
+	s.confirm(batchId)
+}
+
+func (s *BatchCommitterTestSuite) Test_Nonce_Used_By_Other_Transaction() {
+	batchId := s.commitNewBatch()
+	s.iterate()
+	original := s.requireCommitment(batchId, types.BatchCommitSubmitted).Tx
+
+	// another transaction of the same account is included with the nonce of the commitment
+	s.l1.mineForeign(original.Nonce)
+	s.timer.Add(s.options.replaceTimeout)
+	s.iterate()
+	s.requireCommitment(batchId, types.BatchCommitPending)
+
+	s.iterate()
+	commitment := s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().Equal(original.Nonce+1, commitment.Tx.Nonce)
+
+	s.confirm(batchId)
+}
+
+func (s *BatchCommitterTestSuite) Test_Crash_Restart() {
+	batchId := s.commitNewBatch()
+	s.iterate()
+	s.requireCommitment(batchId, types.BatchCommitSubmitted)
+
+	// new instance does not submit the batch again
+	s.committer = s.newCommitter()
+	s.iterate()
+	s.Require().Equal(1, s.l1.sentCount())
+
+	s.committer = s.newCommitter()
+	s.confirm(batchId)
+	s.Require().Equal(1, s.l1.sentCount())
+}
+
+func (s *BatchCommitterTestSuite) Test_Crash_Before_Sending() {
+	batchId := s.commitNewBatch()
+
+	// the commitment is saved, but the transaction does not reach L1
+	s.l1.setSendFailure(errors.New("connection reset"))
+	s.Require().Error(s.committer.processCommitments(s.ctx))
+	saved := s.requireCommitment(batchId, types.BatchCommitSubmitted)
+	s.Require().Empty(s.l1.mempoolTxs())
+
+	s.l1.setSendFailure(nil)
+	s.committer = s.newCommitter()
+	s.iterate()
+	s.Require().Empty(s.l1.mempoolTxs())
+
+	// transaction is resent with the same nonce after the timeout
+	s.timer.Add(s.options.replaceTimeout)
+	s.iterate()
+	mempool := s.l1.mempoolTxs()
+	s.Require().Len(mempool, 1)
+	s.Require().Equal(saved.Tx.Nonce, mempool[0].Nonce())
+
+	s.confirm(batchId)
+}
+
+func (s *BatchCommitterTestSuite) Test_Already_Committed_Batch() {
+	batchId := s.commitNewBatch()
+	s.l1.setCommitted(batchId.String())
+
+	s.iterate()
+	s.requireCommitment(batchId, types.BatchCommitCommitted)
+	s.Require().Zero(s.l1.sentCount())
+}
+
+func (s *BatchCommitterTestSuite) Test_Prune_Finalized() {
+	first := s.commitNewBatch()
+	second := s.commitNewBatch()
+	third := s.commitNewBatch()
+	s.iterate()
+
+	s.l1.setFinalized(second.String())
+	s.iterate()
+
+	commitments, err := s.storage.GetBatchCommitments(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(commitments, 1)
+	s.Require().Equal(third, commitments[0].BatchId)
+
+	pruned, err := s.storage.TryGetBatchCommitment(s.ctx, first)
+	s.Require().NoError(err)
+	s.Require().Nil(pruned)
+}
+
+// simulatedL1 is a minimal L1 chain with a single account sending blob transactions to the rollup contract.
+type simulatedL1 struct {
+	mu sync.Mutex
+
+	// blocks contains the transactions included into each block, the first block is genesis
+	blocks  [][]*ethtypes.Transaction
+	mempool map[uint64]*ethtypes.Transaction
+
+	externallyCommitted map[string]bool
+	finalized           string
+
+	rejectReplacements bool
+	sendFailure        error
+	sent               int
+}
+
+func newSimulatedL1() *simulatedL1 {
+	return &simulatedL1{
+		blocks:              [][]*ethtypes.Transaction{nil},
+		mempool:             make(map[uint64]*ethtypes.Transaction),
+		externallyCommitted: make(map[string]bool),
+	}
+}
+
+func (l *simulatedL1) ethClient() *rollupcontract.EthClientMock {
+	return &rollupcontract.EthClientMock{
+		ChainIDFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(1), nil },
+		SuggestGasTipCapFunc: func(ctx context.Context) (*big.Int, error) {
+			return big.NewInt(1_000_000_000), nil
+		},
+		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+			excessBlobGas := uint64(0)
+			return &ethtypes.Header{
+				Number:        new(big.Int).SetUint64(l.head()),
+				BaseFee:       big.NewInt(1_000_000_000),
+				ExcessBlobGas: &excessBlobGas,
+			}, nil
+		},
+		PendingNonceAtFunc: func(ctx context.Context, account ethcommon.Address) (uint64, error) {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			return l.nonce() + uint64(len(l.mempool)), nil
+		},
+		SendTransactionFunc:    l.sendTransaction,
+		TransactionReceiptFunc: l.transactionReceipt,
+		CallContractFunc:       l.callContract,
+	}
+}
+
+func (l *simulatedL1) sendTransaction(_ context.Context, tx *ethtypes.Transaction) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.sendFailure != nil {
+		return l.sendFailure
+	}
+	if tx.Nonce() < l.nonce() {
+		return errors.New("nonce too low")
+	}
+	if existing, ok := l.mempool[tx.Nonce()]; ok {
+		if existing.Hash() == tx.Hash() {
+			return errors.New("already known")
+		}
+		doubled := func(value *big.Int) *big.Int { return new(big.Int).Mul(value, big.NewInt(2)) }
+		if l.rejectReplacements ||
+			tx.GasTipCap().Cmp(doubled(existing.GasTipCap())) < 0 ||
+			tx.GasFeeCap().Cmp(doubled(existing.GasFeeCap())) < 0 ||
+			tx.BlobGasFeeCap().Cmp(doubled(existing.BlobGasFeeCap())) < 0 {
+			return errors.New("replacement transaction underpriced")
+		}
+	}
+	l.mempool[tx.Nonce()] = tx
+	l.sent++
+	return nil
+}
+
+func (l *simulatedL1) transactionReceipt(_ context.Context, hash ethcommon.Hash) (*ethtypes.Receipt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	committed := make(map[string]bool)
+	for number, block := range l.blocks {
+		for _, tx := range block {
+			batchIndex := commitBatchIndex(tx)
+			status := ethtypes.ReceiptStatusSuccessful
+			if committed[batchIndex] || l.externallyCommitted[batchIndex] {
+				status = ethtypes.ReceiptStatusFailed
+			}
+			committed[batchIndex] = true
+			if tx.Hash() == hash {
+				return &ethtypes.Receipt{
+					TxHash:      hash,
+					Status:      status,
+					BlockNumber: big.NewInt(int64(number)),
+				}, nil
+			}
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func (l *simulatedL1) callContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	abi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	method, err := abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "isBatchCommitted":
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		batchIndex, _ := args[0].(string)
+		return method.Outputs.Pack(l.isCommitted(batchIndex))
+	case "getLastFinalizedBatchIndex":
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return method.Outputs.Pack(l.finalized)
+	default:
+		return nil, errors.New("method not simulated")
+	}
+}
+
+// commitBatchIndex returns the batch index passed to `commitBatch` method, or empty string for other transactions
+func commitBatchIndex(tx *ethtypes.Transaction) string {
+	abi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	if err != nil || len(tx.Data()) < 4 {
+		return ""
+	}
+	args, err := abi.Methods["commitBatch"].Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return ""
+	}
+	batchIndex, _ := args[0].(string)
+	return batchIndex
+}
+
+func (l *simulatedL1) nonce() uint64 {
+	var nonce uint64
+	for _, block := range l.blocks {
+		nonce += uint64(len(block))
+	}
+	return nonce
+}
+
+func (l *simulatedL1) head() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return uint64(len(l.blocks) - 1)
+}
+
+// mine includes all transactions from the pool into the new block
+func (l *simulatedL1) mine() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var block []*ethtypes.Transaction
+	for nonce := l.nonce(); ; nonce++ {
+		tx, ok := l.mempool[nonce]
+		if !ok {
+			break
+		}
+		block = append(block, tx)
+		delete(l.mempool, nonce)
+	}
+	l.blocks = append(l.blocks, block)
+}
+
+// mineForeign includes the transaction of the same account not related to the rollup contract
+func (l *simulatedL1) mineForeign(nonce uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.mempool, nonce)
+	l.blocks = append(l.blocks, []*ethtypes.Transaction{
+		ethtypes.NewTx(&ethtypes.DynamicFeeTx{Nonce: nonce}),
+	})
+}
+
+// reorg replaces the last blocks by the empty ones, their transactions are dropped
+func (l *simulatedL1) reorg(depth int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := len(l.blocks) - depth; i < len(l.blocks); i++ {
+		l.blocks[i] = nil
+	}
+}
+
+func (l *simulatedL1) isCommitted(batchIndex string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.externallyCommitted[batchIndex] {
+		return true
+	}
+	for _, block := range l.blocks {
+		for _, tx := range block {
+			if commitBatchIndex(tx) == batchIndex {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (l *simulatedL1) mempoolTxs() []*ethtypes.Transaction {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	txs := make([]*ethtypes.Transaction, 0, len(l.mempool))
+	for _, tx := range l.mempool {
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func (l *simulatedL1) sentCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sent
+}
+
+func (l *simulatedL1) setRejectReplacements(reject bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rejectReplacements = reject
+}
+
+func (l *simulatedL1) setSendFailure(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sendFailure = err
+}
+
+func (l *simulatedL1) setCommitted(batchIndex string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.externallyCommitted[batchIndex] = true
+}
+
+func (l *simulatedL1) setFinalized(batchIndex string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.finalized = batchIndex
+}
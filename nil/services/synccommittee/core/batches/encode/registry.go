@@ -0,0 +1,99 @@
+package encode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type BatchEncoder interface {
+	Encode(batch *types.PrunedBatch, out io.Writer) error
+}
+
+type BatchDecoder interface {
+	// Decode decodes the batch from binary format, so it can be processed by other cluster parts
+	Decode(from io.Reader) (*types.PrunedBatch, error)
+	// DecodeIntermediate decodes the batch into human-readable form
+	DecodeIntermediate(from io.Reader, to io.Writer) error
+}
+
+type codec struct {
+	encoder BatchEncoder
+	decoder BatchDecoder
+}
+
+// Registry keeps encoders and decoders of all known batch format versions,
+// the version of the encoded batch is taken from its header.
+// Decoders of the outdated versions have to stay registered, so the batches committed earlier remain readable.
+type Registry struct {
+	codecs map[uint16]codec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{codecs: make(map[uint16]codec)}
+}
+
+func (r *Registry) Register(version uint16, encoder BatchEncoder, decoder BatchDecoder) error {
+	if _, ok := r.codecs[version]; ok {
+		return fmt.Errorf("batch format version %04X is already registered", version)
+	}
+	r.codecs[version] = codec{encoder: encoder, decoder: decoder}
+	return nil
+}
+
+// Versions returns all registered versions in ascending order
+func (r *Registry) Versions() []uint16 {
+	versions := make([]uint16, 0, len(r.codecs))
+	for version := range r.codecs {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+func (r *Registry) Encoder(version uint16) (BatchEncoder, error) {
+	c, ok := r.codecs[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: no encoder for version %04X", ErrInvalidVersion, version)
+	}
+	return c.encoder, nil
+}
+
+func (r *Registry) Decode(from io.Reader) (*types.PrunedBatch, error) {
+	decoder, data, err := r.decoderFor(from)
+	if err != nil {
+		return nil, err
+	}
+	return decoder.Decode(bytes.NewReader(data))
+}
+
+func (r *Registry) DecodeIntermediate(from io.Reader, to io.Writer) error {
+	decoder, data, err := r.decoderFor(from)
+	if err != nil {
+		return err
+	}
+	return decoder.DecodeIntermediate(bytes.NewReader(data), to)
+}
+
+// decoderFor reads the whole input to peek into the batch header,
+// decoders expect the header to be present in their input
+func (r *Registry) decoderFor(from io.Reader) (BatchDecoder, []byte, error) {
+	data, err := io.ReadAll(from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var header BatchHeader
+	if err := header.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, nil, err
+	}
+
+	c, ok := r.codecs[header.Version]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no decoder for version %04X", ErrInvalidVersion, header.Version)
+	}
+	return c.decoder, data, nil
+}
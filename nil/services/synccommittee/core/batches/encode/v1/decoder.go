@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	protoTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types/proto"
 	"github.com/rs/zerolog"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -29,27 +30,15 @@ func NewDecoder(logger zerolog.Logger) *decoder {
 
 // decodes data data from binary format into human readable
 // intermediate form (transaction in proto format encoded to protojson)
-// in case of need to access decoded data programatically (from sync_committee or other cluster parts)
-// this decoder might be extended with returning something like types.BlockBatch functionality
 func (d *decoder) DecodeIntermediate(from io.Reader, to io.Writer) error {
-	if err := encode.CheckBatchVersion(from, version); err != nil {
-		return err
-	}
-
-	var decompressed bytes.Buffer
-	if err := d.decompressor.Decompress(from, &decompressed); err != nil {
-		return err
-	}
-
-	var protoBatch protoTypes.Batch
-
-	if err := proto.Unmarshal(decompressed.Bytes(), &protoBatch); err != nil {
+	protoBatch, err := d.decodeProto(from)
+	if err != nil {
 		return err
 	}
 
 	humanReadableForm, err := protojson.MarshalOptions{
 		Multiline: true,
-	}.Marshal(&protoBatch)
+	}.Marshal(protoBatch)
 	if err != nil {
 		return err
 	}
@@ -62,3 +51,29 @@ func (d *decoder) DecodeIntermediate(from io.Reader, to io.Writer) error {
 	d.logger.Debug().Int("bytes_written", n).Str("batch_id", protoBatch.BatchId).Msg("serialized batch to protojson")
 	return nil
 }
+
+// Decode decodes the batch from binary format, so it can be processed by other cluster parts
+func (d *decoder) Decode(from io.Reader) (*types.PrunedBatch, error) {
+	protoBatch, err := d.decodeProto(from)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertFromProto(protoBatch)
+}
+
+func (d *decoder) decodeProto(from io.Reader) (*protoTypes.Batch, error) {
+	if err := encode.CheckBatchVersion(from, Version); err != nil {
+		return nil, err
+	}
+
+	var decompressed bytes.Buffer
+	if err := d.decompressor.Decompress(from, &decompressed); err != nil {
+		return nil, err
+	}
+
+	var protoBatch protoTypes.Batch
+	if err := proto.Unmarshal(decompressed.Bytes(), &protoBatch); err != nil {
+		return nil, err
+	}
+	return &protoBatch, nil
+}
@@ -10,7 +10,7 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-const version uint16 = 0x0001
+const Version uint16 = 0x0001
 
 type compressor interface {
 	Compress(from io.Reader, to io.Writer) error
@@ -28,7 +28,7 @@ func NewEncoder(logger zerolog.Logger) *batchEncoder {
 }
 
 func (be *batchEncoder) Encode(batch *types.PrunedBatch, out io.Writer) error {
-	header := encode.NewBatchHeader(version)
+	header := encode.NewBatchHeader(Version)
 	if err := header.EncodeTo(out); err != nil {
 		return err
 	}
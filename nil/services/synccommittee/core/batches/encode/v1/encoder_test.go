@@ -44,7 +44,7 @@ func TestEncoderSimple(t *testing.T) {
 	assert.Equal(t, encode.BatchMagic, temp)
 
 	require.NoError(t, binary.Read(&out, binary.LittleEndian, &temp))
-	assert.Equal(t, version, temp)
+	assert.Equal(t, Version, temp)
 
 	var unwrappedBatch scProto.Batch
 	err = proto.Unmarshal(out.Bytes(), &unwrappedBatch)
@@ -54,5 +54,38 @@ func TestEncoderSimple(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, deserializedBatch.Blocks, len(batch.ChildBlocks)+1)
 	assert.Equal(t, batch.Id, deserializedBatch.BatchId)
-	assert.ElementsMatch(t, prunedBatch.Blocks, deserializedBatch.Blocks)
+	expected := make([]*types.PrunedBlock, 0, len(prunedBatch.Blocks))
+	for _, block := range prunedBatch.Blocks {
+		expected = append(expected, storedBlock(block))
+	}
+	assert.ElementsMatch(t, expected, deserializedBatch.Blocks)
+}
+
+// storedBlock leaves the fields of the block stored by v1,
+// the refund and bounce addresses equal to the sender are omitted
+func storedBlock(block *types.PrunedBlock) *types.PrunedBlock {
+	res := &types.PrunedBlock{
+		ShardId:       block.ShardId,
+		BlockNumber:   block.BlockNumber,
+		Timestamp:     block.Timestamp,
+		PrevBlockHash: block.PrevBlockHash,
+	}
+	for _, tx := range block.Transactions {
+		stored := &types.PrunedTransaction{
+			Flags: tx.Flags,
+			Seqno: tx.Seqno,
+			From:  tx.From,
+			To:    tx.To,
+			Value: tx.Value,
+			Data:  tx.Data,
+		}
+		if !tx.From.Equal(tx.RefundTo) {
+			stored.RefundTo = tx.RefundTo
+		}
+		if !tx.From.Equal(tx.BounceTo) {
+			stored.BounceTo = tx.BounceTo
+		}
+		res.Transactions = append(res.Transactions, stored)
+	}
+	return res
 }
@@ -87,10 +87,10 @@ func ConvertFromProto(batch *proto.Batch) (*types.PrunedBatch, error) {
 			pValue := protoUint256ToUint256(ptx.Value)
 			tx.Value = coreTypes.Value{Uint256: &pValue}
 			if ptx.AddrRefundTo != nil {
-				tx.RefundTo = coreTypes.BytesToAddress(ptx.AddrFrom.AddressBytes)
+				tx.RefundTo = coreTypes.BytesToAddress(ptx.AddrRefundTo.AddressBytes)
 			}
 			if ptx.AddrBounceTo != nil {
-				tx.BounceTo = coreTypes.BytesToAddress(ptx.AddrFrom.AddressBytes)
+				tx.BounceTo = coreTypes.BytesToAddress(ptx.AddrBounceTo.AddressBytes)
 			}
 			b.Transactions = append(b.Transactions, tx)
 		}
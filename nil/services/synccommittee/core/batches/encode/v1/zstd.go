@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"errors"
 	"io"
 
 	"github.com/klauspost/compress/zstd"
@@ -72,6 +73,11 @@ func (zd *zstdDecompressor) Decompress(in io.Reader, out io.Writer) error {
 	defer impl.Close()
 
 	n, err := impl.WriteTo(out)
+	if errors.Is(err, zstd.ErrMagicMismatch) && n > 0 {
+		// the frame is fully decoded and verified, it is followed by the zero padding of the blobs
+		zd.logger.Debug().Int64("decompressed_size", n).Msg("ignored data after zstd frame")
+		err = nil
+	}
 	if err != nil {
 		return err
 	}
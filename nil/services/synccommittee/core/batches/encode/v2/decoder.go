@@ -0,0 +1,79 @@
+package v2
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog"
+)
+
+// limits exceed the capacity of any allowed number of blobs, they bound allocations on corrupted input
+const (
+	maxCompressedSize = 8 << 20
+	maxBodySize       = 64 << 20
+)
+
+type decoder struct {
+	logger zerolog.Logger
+}
+
+func NewDecoder(logger zerolog.Logger) *decoder {
+	return &decoder{
+		logger: logger,
+	}
+}
+
+func (d *decoder) Decode(from io.Reader) (*types.PrunedBatch, error) {
+	if err := encode.CheckBatchVersion(from, Version); err != nil {
+		return nil, err
+	}
+
+	rd := bufio.NewReader(from)
+	size, err := binary.ReadUvarint(rd)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxCompressedSize {
+		return nil, fmt.Errorf("invalid compressed batch size %d", size)
+	}
+	compressed := make([]byte, size)
+	if _, err := io.ReadFull(rd, compressed); err != nil {
+		return nil, err
+	}
+
+	impl, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	defer impl.Close()
+	body, err := impl.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := deserialize(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize batch body: %w", err)
+	}
+	d.logger.Debug().Stringer(logging.FieldBatchId, batch.BatchId).Int("body_size", len(body)).Msg("decoded batch")
+	return batch, nil
+}
+
+// DecodeIntermediate decodes the batch into human-readable JSON
+func (d *decoder) DecodeIntermediate(from io.Reader, to io.Writer) error {
+	batch, err := d.Decode(from)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(to)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(batch)
+}
@@ -0,0 +1,58 @@
+package v2
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog"
+)
+
+// Version 2 stores transactions in a compact binary form with dictionaries shared by all blocks of the batch.
+// The compressed body is prefixed with its length, so the zero padding of blobs is ignored on decoding.
+const Version uint16 = 0x0002
+
+type batchEncoder struct {
+	logger zerolog.Logger
+}
+
+func NewEncoder(logger zerolog.Logger) *batchEncoder {
+	return &batchEncoder{
+		logger: logger,
+	}
+}
+
+func (be *batchEncoder) Encode(batch *types.PrunedBatch, out io.Writer) error {
+	header := encode.NewBatchHeader(Version)
+	if err := header.EncodeTo(out); err != nil {
+		return err
+	}
+
+	body := serialize(batch)
+	be.logger.Info().
+		Stringer(logging.FieldBatchId, batch.BatchId).
+		Int("body_size", len(body)).
+		Msg("packed transactions to batch")
+
+	return be.compress(body, out)
+}
+
+// compress writes the compressed body prefixed with its length
+func (be *batchEncoder) compress(body []byte, out io.Writer) error {
+	impl, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	if err != nil {
+		return err
+	}
+	defer impl.Close()
+	compressed := impl.EncodeAll(body, nil)
+	be.logger.Debug().Int("compressed_size", len(compressed)).Msg("compressed batch body")
+
+	if _, err := out.Write(binary.AppendUvarint(nil, uint64(len(compressed)))); err != nil {
+		return err
+	}
+	_, err = out.Write(compressed)
+	return err
+}
@@ -0,0 +1,175 @@
+package v2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBatch() *types.PrunedBatch {
+	batch := types.NewPrunedBatch(testaide.NewBlockBatch(3))
+
+	// make transactions of the first block cover all optional fields
+	txs := batch.Blocks[0].Transactions
+	txs[0].RefundTo = coreTypes.HexToAddress("0x0002F09EC9F5cCA264eba822BB887f5c900c6e73")
+	txs[0].BounceTo = coreTypes.HexToAddress("0x0002F09EC9F5cCA264eba822BB887f5c900c6e74")
+	txs[0].Seqno = 1 << 40
+	txs[1].Value = coreTypes.NewValueFromBytes(nil)
+	txs[1].Data = nil
+	txs[2].Value = coreTypes.NewValueFromBytes(bytes.Repeat([]byte{0xFF}, 32))
+	txs[2].RefundTo = txs[2].From
+	txs[2].FeeCredit = coreTypes.NewValueFromUint64(5_000_000)
+	txs[2].MaxPriorityFeePerGas = coreTypes.NewValueFromUint64(10)
+	txs[2].MaxFeePerGas = coreTypes.NewValueFromUint64(1_000)
+	txs[2].ChainId = coreTypes.DefaultChainId
+	txs[2].Token = []coreTypes.TokenBalance{
+		{Token: coreTypes.TokenId(txs[0].RefundTo), Balance: coreTypes.NewValueFromUint64(7)},
+	}
+	txs[2].RequestId = 3
+	txs[2].RequestChain = []*coreTypes.AsyncRequestInfo{{Id: 2, Caller: txs[0].BounceTo}}
+	txs[2].Signature = coreTypes.Signature{0x01, 0x02, 0x03}
+	batch.Blocks[0].ForwardTransactions = []*types.PrunedTransaction{txs[2]}
+
+	// the parent hash of the following block of the shard is not stored
+	next := *batch.Blocks[0]
+	next.BlockNumber++
+	next.PrevBlockHash = testaide.RandomHash()
+	next.ForwardTransactions = nil
+	batch.Blocks = append(batch.Blocks, &next)
+	return batch
+}
+
+func TestEncoder_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	batch := newTestBatch()
+
+	var out bytes.Buffer
+	require.NoError(t, NewEncoder(logger).Encode(batch, &out))
+
+	// blobs are padded with zeroes after the batch data
+	out.Write(make([]byte, 1000))
+
+	decoded, err := NewDecoder(logger).Decode(&out)
+	require.NoError(t, err)
+	require.Equal(t, batch.BatchId, decoded.BatchId)
+	require.Len(t, decoded.Blocks, len(batch.Blocks))
+
+	shards := make(map[coreTypes.ShardId]struct{})
+	for i, expected := range batch.Blocks {
+		actual := decoded.Blocks[i]
+		require.Equal(t, expected.ShardId, actual.ShardId)
+		require.Equal(t, expected.BlockNumber, actual.BlockNumber)
+		require.Equal(t, expected.Timestamp, actual.Timestamp)
+		if _, ok := shards[expected.ShardId]; ok {
+			require.Equal(t, common.EmptyHash, actual.PrevBlockHash, "previous block hash is derived on replay")
+		} else {
+			require.Equal(t, expected.PrevBlockHash, actual.PrevBlockHash)
+			shards[expected.ShardId] = struct{}{}
+		}
+		require.Equal(t, expected.MainChainHash, actual.MainChainHash)
+		require.Equal(t, expected.ChildBlocks, actual.ChildBlocks)
+		requireTransactionsEqual(t, expected.Transactions, actual.Transactions)
+		requireTransactionsEqual(t, expected.ForwardTransactions, actual.ForwardTransactions)
+	}
+}
+
+func requireValuesEqual(t *testing.T, expected, actual coreTypes.Value) {
+	t.Helper()
+	require.Zero(t, expected.Cmp(actual), "value %s != %s", expected, actual)
+}
+
+func requireTransactionsEqual(t *testing.T, expected, actual []*types.PrunedTransaction) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+	for j, expectedTx := range expected {
+		actualTx := actual[j]
+		require.Equal(t, expectedTx.Flags, actualTx.Flags)
+		require.Equal(t, expectedTx.Seqno, actualTx.Seqno)
+		require.Equal(t, expectedTx.From, actualTx.From)
+		require.Equal(t, expectedTx.To, actualTx.To)
+		require.Equal(t, expectedTx.RefundTo, actualTx.RefundTo)
+		require.Equal(t, expectedTx.BounceTo, actualTx.BounceTo)
+		requireValuesEqual(t, expectedTx.Value, actualTx.Value)
+		require.Equal(t, []byte(expectedTx.Data), []byte(actualTx.Data))
+		requireValuesEqual(t, expectedTx.FeeCredit, actualTx.FeeCredit)
+		requireValuesEqual(t, expectedTx.MaxPriorityFeePerGas, actualTx.MaxPriorityFeePerGas)
+		requireValuesEqual(t, expectedTx.MaxFeePerGas, actualTx.MaxFeePerGas)
+		require.Equal(t, expectedTx.ChainId, actualTx.ChainId)
+		require.Len(t, actualTx.Token, len(expectedTx.Token))
+		for k, token := range expectedTx.Token {
+			require.Equal(t, token.Token, actualTx.Token[k].Token)
+			requireValuesEqual(t, token.Balance, actualTx.Token[k].Balance)
+		}
+		require.Equal(t, expectedTx.RequestId, actualTx.RequestId)
+		require.Equal(t, expectedTx.RequestChain, actualTx.RequestChain)
+		require.Equal(t, []byte(expectedTx.Signature), []byte(actualTx.Signature))
+	}
+}
+
+func TestEncoder_SmallerThanV1(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	batch := newTestBatch()
+	// only the data stored by both formats is compared
+	for _, block := range batch.Blocks {
+		block.MainChainHash = common.EmptyHash
+		block.ChildBlocks = nil
+		block.ForwardTransactions = nil
+		for _, tx := range block.Transactions {
+			tx.FeeCredit = coreTypes.NewValueFromBytes(nil)
+			tx.MaxPriorityFeePerGas = coreTypes.NewValueFromBytes(nil)
+			tx.MaxFeePerGas = coreTypes.NewValueFromBytes(nil)
+			tx.ChainId = 0
+			tx.Token = nil
+			tx.RequestId = 0
+			tx.RequestChain = nil
+			tx.Signature = nil
+		}
+	}
+
+	var encodedV1, encodedV2 bytes.Buffer
+	require.NoError(t, v1.NewEncoder(logger).Encode(batch, &encodedV1))
+	require.NoError(t, NewEncoder(logger).Encode(batch, &encodedV2))
+	require.Less(t, encodedV2.Len(), encodedV1.Len())
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	decoder := NewDecoder(logger)
+
+	var encodedV1 bytes.Buffer
+	require.NoError(t, v1.NewEncoder(logger).Encode(newTestBatch(), &encodedV1))
+	_, err := decoder.Decode(&encodedV1)
+	require.ErrorIs(t, err, encode.ErrInvalidVersion)
+
+	var encoded bytes.Buffer
+	require.NoError(t, NewEncoder(logger).Encode(newTestBatch(), &encoded))
+	truncated := encoded.Bytes()[:encoded.Len()-10]
+	_, err = decoder.Decode(bytes.NewReader(truncated))
+	require.Error(t, err)
+
+	// valid compression of a corrupted body
+	var corrupted bytes.Buffer
+	header := encode.NewBatchHeader(Version)
+	require.NoError(t, header.EncodeTo(&corrupted))
+	batch := newTestBatch()
+	body := serialize(batch)
+	body[len(body)-1] = 0xFF
+	require.NoError(t, NewEncoder(logger).compress(body, &corrupted))
+	_, err = decoder.Decode(&corrupted)
+	require.Error(t, err)
+}
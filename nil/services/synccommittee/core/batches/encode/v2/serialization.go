@@ -0,0 +1,556 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/google/uuid"
+)
+
+// Batch body layout, all integers are varints:
+//
+//	batchId [16]byte
+//	addresses: count, [20]byte each   -- dictionary of all addresses used in the batch
+//	payloads:  count, (len, bytes)    -- dictionary of distinct call data of the batch
+//	blocks:    count, block each
+//
+//	block: shardId, blockNumber, timestamp (signed delta to the previous block),
+//	       [prevBlockHash [32]byte], [mainChainHash [32]byte], childCount, [32]byte each, txCount, tx each, forwardTxCount, tx each
+//	tx:    flags byte, fields, seqno, from idx, to idx,
+//	       [refundTo idx], [bounceTo idx], [value], [data idx],
+//	       [feeCredit], [maxPriorityFeePerGas], [maxFeePerGas], [chainId],
+//	       [tokens: count, (token idx, value) each], [requestId, requestChain: count, (id, caller idx) each],
+//	       [signature (len, bytes)]
+//	value: len, big-endian bytes
+//
+// The main chain hash is present in child shard blocks only, child blocks are present in main shard blocks only.
+// The previous block hash is present in the first block of each shard in the batch only, the following blocks
+// of the shard are built on top of the preceding one, so their parent hashes are derived during the replay.
+// Out-transactions are not stored as well, they are produced by the execution of in-transactions.
+// Refund and bounce addresses are omitted when they equal to the sender.
+
+const (
+	fieldRefundTo uint64 = 1 << iota
+	fieldBounceTo
+	fieldValue
+	fieldData
+	fieldFeeCredit
+	fieldMaxPriorityFeePerGas
+	fieldMaxFeePerGas
+	fieldChainId
+	fieldTokens
+	fieldRequest
+	fieldSignature
+)
+
+// maxDictionarySize bounds the allocations on decoding of corrupted data
+const maxDictionarySize = 1 << 20
+
+type dictionary[K comparable] struct {
+	index  map[K]uint64
+	values []K
+}
+
+func newDictionary[K comparable]() *dictionary[K] {
+	return &dictionary[K]{index: make(map[K]uint64)}
+}
+
+func (d *dictionary[K]) add(value K) uint64 {
+	if idx, ok := d.index[value]; ok {
+		return idx
+	}
+	idx := uint64(len(d.values))
+	d.index[value] = idx
+	d.values = append(d.values, value)
+	return idx
+}
+
+type bodyWriter struct {
+	buf []byte
+}
+
+func (w *bodyWriter) uvarint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *bodyWriter) varint(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *bodyWriter) bytes(b []byte) {
+	w.uvarint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *bodyWriter) value(v coreTypes.Value) {
+	w.bytes(v.Bytes())
+}
+
+func (w *bodyWriter) transaction(
+	tx *types.PrunedTransaction,
+	addresses *dictionary[coreTypes.Address],
+	payloads *dictionary[string],
+) {
+	var fields uint64
+	if tx.RefundTo != tx.From {
+		fields |= fieldRefundTo
+	}
+	if tx.BounceTo != tx.From {
+		fields |= fieldBounceTo
+	}
+	if !tx.Value.IsZero() {
+		fields |= fieldValue
+	}
+	if len(tx.Data) > 0 {
+		fields |= fieldData
+	}
+	if !tx.FeeCredit.IsZero() {
+		fields |= fieldFeeCredit
+	}
+	if !tx.MaxPriorityFeePerGas.IsZero() {
+		fields |= fieldMaxPriorityFeePerGas
+	}
+	if !tx.MaxFeePerGas.IsZero() {
+		fields |= fieldMaxFeePerGas
+	}
+	if tx.ChainId != 0 {
+		fields |= fieldChainId
+	}
+	if len(tx.Token) > 0 {
+		fields |= fieldTokens
+	}
+	if tx.RequestId != 0 || len(tx.RequestChain) > 0 {
+		fields |= fieldRequest
+	}
+	if len(tx.Signature) > 0 {
+		fields |= fieldSignature
+	}
+
+	w.buf = append(w.buf, tx.Flags.Bits)
+	w.uvarint(fields)
+	w.uvarint(uint64(tx.Seqno))
+	w.uvarint(addresses.add(tx.From))
+	w.uvarint(addresses.add(tx.To))
+	if fields&fieldRefundTo != 0 {
+		w.uvarint(addresses.add(tx.RefundTo))
+	}
+	if fields&fieldBounceTo != 0 {
+		w.uvarint(addresses.add(tx.BounceTo))
+	}
+	if fields&fieldValue != 0 {
+		w.value(tx.Value)
+	}
+	if fields&fieldData != 0 {
+		w.uvarint(payloads.add(string(tx.Data)))
+	}
+	if fields&fieldFeeCredit != 0 {
+		w.value(tx.FeeCredit)
+	}
+	if fields&fieldMaxPriorityFeePerGas != 0 {
+		w.value(tx.MaxPriorityFeePerGas)
+	}
+	if fields&fieldMaxFeePerGas != 0 {
+		w.value(tx.MaxFeePerGas)
+	}
+	if fields&fieldChainId != 0 {
+		w.uvarint(uint64(tx.ChainId))
+	}
+	if fields&fieldTokens != 0 {
+		w.uvarint(uint64(len(tx.Token)))
+		for _, token := range tx.Token {
+			w.uvarint(addresses.add(coreTypes.Address(token.Token)))
+			w.value(token.Balance)
+		}
+	}
+	if fields&fieldRequest != 0 {
+		w.uvarint(tx.RequestId)
+		w.uvarint(uint64(len(tx.RequestChain)))
+		for _, request := range tx.RequestChain {
+			w.uvarint(request.Id)
+			w.uvarint(addresses.add(request.Caller))
+		}
+	}
+	if fields&fieldSignature != 0 {
+		w.bytes(tx.Signature)
+	}
+}
+
+func serialize(batch *types.PrunedBatch) []byte {
+	addresses := newDictionary[coreTypes.Address]()
+	payloads := newDictionary[string]()
+
+	var blocks bodyWriter
+	blocks.uvarint(uint64(len(batch.Blocks)))
+	var prevTimestamp int64
+	shards := make(map[coreTypes.ShardId]struct{})
+	for _, block := range batch.Blocks {
+		blocks.uvarint(uint64(block.ShardId))
+		blocks.uvarint(uint64(block.BlockNumber))
+		blocks.varint(int64(block.Timestamp) - prevTimestamp)
+		prevTimestamp = int64(block.Timestamp)
+
+		if _, ok := shards[block.ShardId]; !ok {
+			shards[block.ShardId] = struct{}{}
+			blocks.buf = append(blocks.buf, block.PrevBlockHash[:]...)
+		}
+		if !block.ShardId.IsMainShard() {
+			blocks.buf = append(blocks.buf, block.MainChainHash[:]...)
+		}
+		blocks.uvarint(uint64(len(block.ChildBlocks)))
+		for _, hash := range block.ChildBlocks {
+			blocks.buf = append(blocks.buf, hash[:]...)
+		}
+
+		blocks.uvarint(uint64(len(block.Transactions)))
+		for _, tx := range block.Transactions {
+			blocks.transaction(tx, addresses, payloads)
+		}
+		blocks.uvarint(uint64(len(block.ForwardTransactions)))
+		for _, tx := range block.ForwardTransactions {
+			blocks.transaction(tx, addresses, payloads)
+		}
+	}
+
+	var body bodyWriter
+	body.buf = append(body.buf, batch.BatchId[:]...)
+	body.uvarint(uint64(len(addresses.values)))
+	for _, addr := range addresses.values {
+		body.buf = append(body.buf, addr[:]...)
+	}
+	body.uvarint(uint64(len(payloads.values)))
+	for _, payload := range payloads.values {
+		body.bytes([]byte(payload))
+	}
+	body.buf = append(body.buf, blocks.buf...)
+	return body.buf
+}
+
+type bodyReader struct {
+	*bytes.Reader
+}
+
+func (r bodyReader) uvarint() (uint64, error) {
+	return binary.ReadUvarint(r)
+}
+
+func (r bodyReader) count() (int, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > maxDictionarySize || n > uint64(r.Len()) {
+		return 0, fmt.Errorf("invalid item count %d, %d bytes left", n, r.Len())
+	}
+	return int(n), nil
+}
+
+func (r bodyReader) bytes() ([]byte, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r bodyReader) value() (coreTypes.Value, error) {
+	value, err := r.bytes()
+	if err != nil {
+		return coreTypes.Value{}, err
+	}
+	if len(value) > 32 {
+		return coreTypes.Value{}, fmt.Errorf("value of %d bytes exceeds 256 bits", len(value))
+	}
+	return coreTypes.NewValueFromBytes(value), nil
+}
+
+func (r bodyReader) hash() (common.Hash, error) {
+	var hash common.Hash
+	_, err := io.ReadFull(r, hash[:])
+	return hash, err
+}
+
+func lookup[T any](r bodyReader, dict []T) (T, error) {
+	var empty T
+	idx, err := r.uvarint()
+	if err != nil {
+		return empty, err
+	}
+	if idx >= uint64(len(dict)) {
+		return empty, fmt.Errorf("dictionary index %d is out of range %d", idx, len(dict))
+	}
+	return dict[idx], nil
+}
+
+func deserialize(body []byte) (*types.PrunedBatch, error) {
+	r := bodyReader{bytes.NewReader(body)}
+
+	var batchId uuid.UUID
+	if _, err := io.ReadFull(r, batchId[:]); err != nil {
+		return nil, err
+	}
+
+	addrCount, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]coreTypes.Address, addrCount)
+	for i := range addresses {
+		if _, err := io.ReadFull(r, addresses[i][:]); err != nil {
+			return nil, err
+		}
+	}
+
+	payloadCount, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	payloads := make([]hexutil.Bytes, payloadCount)
+	for i := range payloads {
+		if payloads[i], err = r.bytes(); err != nil {
+			return nil, err
+		}
+	}
+
+	blockCount, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	batch := &types.PrunedBatch{
+		BatchId: types.BatchId(batchId),
+		Blocks:  make([]*types.PrunedBlock, 0, blockCount),
+	}
+	var timestamp int64
+	shards := make(map[coreTypes.ShardId]struct{})
+	for range blockCount {
+		block, err := readBlock(r, addresses, payloads, &timestamp, shards)
+		if err != nil {
+			return nil, err
+		}
+		batch.Blocks = append(batch.Blocks, block)
+	}
+
+	if r.Len() != 0 {
+		return nil, errors.New("unexpected data after the last block")
+	}
+	return batch, nil
+}
+
+func readBlock(
+	r bodyReader,
+	addresses []coreTypes.Address,
+	payloads []hexutil.Bytes,
+	timestamp *int64,
+	shards map[coreTypes.ShardId]struct{},
+) (*types.PrunedBlock, error) {
+	shardId, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	blockNumber, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	delta, err := binary.ReadVarint(r)
+	if err != nil {
+		return nil, err
+	}
+	*timestamp += delta
+
+	block := &types.PrunedBlock{
+		ShardId:     coreTypes.ShardId(shardId),
+		BlockNumber: coreTypes.BlockNumber(blockNumber),
+		Timestamp:   uint64(*timestamp),
+	}
+
+	if _, ok := shards[block.ShardId]; !ok {
+		shards[block.ShardId] = struct{}{}
+		if block.PrevBlockHash, err = r.hash(); err != nil {
+			return nil, err
+		}
+	}
+	if !block.ShardId.IsMainShard() {
+		if block.MainChainHash, err = r.hash(); err != nil {
+			return nil, err
+		}
+	}
+	childCount, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	for range childCount {
+		hash, err := r.hash()
+		if err != nil {
+			return nil, err
+		}
+		block.ChildBlocks = append(block.ChildBlocks, hash)
+	}
+
+	if block.Transactions, err = readTransactions(r, addresses, payloads); err != nil {
+		return nil, err
+	}
+	if block.ForwardTransactions, err = readTransactions(r, addresses, payloads); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func readTransactions(
+	r bodyReader,
+	addresses []coreTypes.Address,
+	payloads []hexutil.Bytes,
+) ([]*types.PrunedTransaction, error) {
+	txCount, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	var txs []*types.PrunedTransaction
+	for range txCount {
+		tx, err := readTransaction(r, addresses, payloads)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func readTransaction(r bodyReader, addresses []coreTypes.Address, payloads []hexutil.Bytes) (*types.PrunedTransaction, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	seqno, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &types.PrunedTransaction{
+		Flags:                coreTypes.NewTransactionFlagsFromBits(flags),
+		Seqno:                hexutil.Uint64(seqno),
+		Value:                coreTypes.NewValueFromBytes(nil),
+		FeeCredit:            coreTypes.NewValueFromBytes(nil),
+		MaxPriorityFeePerGas: coreTypes.NewValueFromBytes(nil),
+		MaxFeePerGas:         coreTypes.NewValueFromBytes(nil),
+	}
+	if tx.From, err = lookup(r, addresses); err != nil {
+		return nil, err
+	}
+	if tx.To, err = lookup(r, addresses); err != nil {
+		return nil, err
+	}
+	tx.RefundTo, tx.BounceTo = tx.From, tx.From
+	if fields&fieldRefundTo != 0 {
+		if tx.RefundTo, err = lookup(r, addresses); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldBounceTo != 0 {
+		if tx.BounceTo, err = lookup(r, addresses); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldValue != 0 {
+		if tx.Value, err = r.value(); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldData != 0 {
+		if tx.Data, err = lookup(r, payloads); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldFeeCredit != 0 {
+		if tx.FeeCredit, err = r.value(); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldMaxPriorityFeePerGas != 0 {
+		if tx.MaxPriorityFeePerGas, err = r.value(); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldMaxFeePerGas != 0 {
+		if tx.MaxFeePerGas, err = r.value(); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldChainId != 0 {
+		chainId, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		tx.ChainId = coreTypes.ChainId(chainId)
+	}
+	if fields&fieldTokens != 0 {
+		if tx.Token, err = readTokens(r, addresses); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldRequest != 0 {
+		if tx.RequestId, err = r.uvarint(); err != nil {
+			return nil, err
+		}
+		if tx.RequestChain, err = readRequestChain(r, addresses); err != nil {
+			return nil, err
+		}
+	}
+	if fields&fieldSignature != 0 {
+		if tx.Signature, err = r.bytes(); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+func readTokens(r bodyReader, addresses []coreTypes.Address) ([]coreTypes.TokenBalance, error) {
+	count, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]coreTypes.TokenBalance, count)
+	for i := range tokens {
+		token, err := lookup(r, addresses)
+		if err != nil {
+			return nil, err
+		}
+		tokens[i].Token = coreTypes.TokenId(token)
+		if tokens[i].Balance, err = r.value(); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+func readRequestChain(r bodyReader, addresses []coreTypes.Address) ([]*coreTypes.AsyncRequestInfo, error) {
+	count, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	chain := make([]*coreTypes.AsyncRequestInfo, count)
+	for i := range chain {
+		request := &coreTypes.AsyncRequestInfo{}
+		if request.Id, err = r.uvarint(); err != nil {
+			return nil, err
+		}
+		if request.Caller, err = lookup(r, addresses); err != nil {
+			return nil, err
+		}
+		chain[i] = request
+	}
+	return chain, nil
+}
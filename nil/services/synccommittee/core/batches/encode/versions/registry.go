@@ -0,0 +1,24 @@
+package versions
+
+import (
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Default is the version written by the committer unless configured otherwise
+	Default = v1.Version
+	Latest  = v2.Version
+)
+
+// NewRegistry creates a registry of all known batch formats
+func NewRegistry(logger zerolog.Logger) *encode.Registry {
+	registry := encode.NewRegistry()
+	check.PanicIfErr(registry.Register(v1.Version, v1.NewEncoder(logger), v1.NewDecoder(logger)))
+	check.PanicIfErr(registry.Register(v2.Version, v2.NewEncoder(logger), v2.NewDecoder(logger)))
+	// each new implemented format needs to be added here
+	return registry
+}
@@ -0,0 +1,61 @@
+package versions
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_DecodesAllVersions(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(logging.NewLogger("sc_batch_registry_test"))
+	require.Equal(t, []uint16{v1.Version, v2.Version}, registry.Versions())
+
+	for _, version := range registry.Versions() {
+		batch := types.NewPrunedBatch(testaide.NewBlockBatch(2))
+
+		encoder, err := registry.Encoder(version)
+		require.NoError(t, err)
+
+		var encoded bytes.Buffer
+		require.NoError(t, encoder.Encode(batch, &encoded))
+
+		decoded, err := registry.Decode(bytes.NewReader(encoded.Bytes()))
+		require.NoError(t, err, "version %d", version)
+		require.Equal(t, batch.BatchId, decoded.BatchId)
+		require.Len(t, decoded.Blocks, len(batch.Blocks))
+
+		var intermediate bytes.Buffer
+		require.NoError(t, registry.DecodeIntermediate(bytes.NewReader(encoded.Bytes()), &intermediate))
+		require.True(t, json.Valid(intermediate.Bytes()), "version %d", version)
+	}
+}
+
+func TestRegistry_UnknownVersion(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(logging.NewLogger("sc_batch_registry_test"))
+
+	_, err := registry.Encoder(0xFFFF)
+	require.ErrorIs(t, err, encode.ErrInvalidVersion)
+
+	var encoded bytes.Buffer
+	header := encode.NewBatchHeader(0xFFFF)
+	require.NoError(t, header.EncodeTo(&encoded))
+	_, err = registry.Decode(&encoded)
+	require.ErrorIs(t, err, encode.ErrInvalidVersion)
+
+	_, err = registry.Decode(bytes.NewReader([]byte{0x01, 0x02, 0x03, 0x04}))
+	require.ErrorIs(t, err, encode.ErrInvalidMagic)
+
+	require.Error(t, registry.Register(v1.Version, v1.NewEncoder(logging.NewLogger("test")), nil))
+}
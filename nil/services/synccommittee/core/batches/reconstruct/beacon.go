@@ -0,0 +1,59 @@
+package reconstruct
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+type blobSidecar struct {
+	Blob          kzg4844.Blob       `json:"blob"`
+	KZGCommitment kzg4844.Commitment `json:"kzg_commitment"`
+}
+
+// beaconClient fetches blob sidecars from the beacon node API, execution nodes do not keep blobs
+type beaconClient struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+func newBeaconClient(endpoint string, httpClient *http.Client) *beaconClient {
+	return &beaconClient{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: httpClient,
+	}
+}
+
+func (c *beaconClient) blobSidecars(ctx context.Context, blockId string) ([]blobSidecar, error) {
+	url := fmt.Sprintf("%s/eth/v1/beacon/blob_sidecars/%s", c.endpoint, blockId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blob sidecars of beacon block %s: %w", blockId, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("beacon node responded with status %d for block %s: %s",
+			resp.StatusCode, blockId, strings.TrimSpace(string(body)))
+	}
+
+	var response struct {
+		Data []blobSidecar `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode blob sidecars of beacon block %s: %w", blockId, err)
+	}
+	return response.Data, nil
+}
@@ -0,0 +1,225 @@
+package reconstruct
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// L1Client is the subset of the L1 execution node API required to locate committed batches
+type L1Client interface {
+	rollupcontract.EthClient
+	TransactionByHash(ctx context.Context, hash ethcommon.Hash) (tx *ethtypes.Transaction, isPending bool, err error)
+}
+
+// CommittedBatch describes a batch commitment transaction found on L1
+type CommittedBatch struct {
+	BatchId    types.BatchId
+	L1Block    uint64
+	TxHash     ethcommon.Hash
+	BlobHashes []ethcommon.Hash
+}
+
+type L1SourceConfig struct {
+	ContractAddress string
+	BeaconEndpoint  string
+	// FromBlock is the first L1 block to search for commitment events in
+	FromBlock      uint64
+	RequestTimeout time.Duration
+}
+
+// L1Source fetches committed blobs and finalized state roots of the batches from L1
+type L1Source struct {
+	client   L1Client
+	contract *rollupcontract.Rollupcontract
+	abi      *abi.ABI
+	beacon   *beaconClient
+	config   L1SourceConfig
+}
+
+func NewL1Source(client L1Client, config L1SourceConfig) (*L1Source, error) {
+	contract, err := rollupcontract.NewRollupcontract(ethcommon.HexToAddress(config.ContractAddress), client)
+	if err != nil {
+		return nil, fmt.Errorf("can't create rollup contract instance: %w", err)
+	}
+	contractAbi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	return &L1Source{
+		client:   client,
+		contract: contract,
+		abi:      contractAbi,
+		beacon:   newBeaconClient(config.BeaconEndpoint, &http.Client{Timeout: config.RequestTimeout}),
+		config:   config,
+	}, nil
+}
+
+// CommittedBatches lists batches committed in the given range of L1 blocks in the order of their commitment
+func (s *L1Source) CommittedBatches(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]CommittedBatch, error) {
+	iter, err := s.contract.FilterBatchCommitted(&bind.FilterOpts{Start: fromBlock, End: toBlock, Context: ctx}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter commitment events: %w", err)
+	}
+	defer iter.Close()
+
+	var batches []CommittedBatch
+	for iter.Next() {
+		batch, err := s.readCommitment(ctx, iter.Event.Raw)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate commitment events: %w", err)
+	}
+	return batches, nil
+}
+
+// readCommitment restores the batch id from the commitment transaction,
+// the event itself contains only the hash of it
+func (s *L1Source) readCommitment(ctx context.Context, log ethtypes.Log) (*CommittedBatch, error) {
+	tx, _, err := s.client.TransactionByHash(ctx, log.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commitment transaction %s: %w", log.TxHash, err)
+	}
+
+	data := tx.Data()
+	if len(data) < 4 {
+		return nil, fmt.Errorf("commitment transaction %s has no call data", log.TxHash)
+	}
+	method, err := s.abi.MethodById(data[:4])
+	if err != nil || method.Name != "commitBatch" {
+		return nil, fmt.Errorf("transaction %s is not a batch commitment", log.TxHash)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack commitment transaction %s: %w", log.TxHash, err)
+	}
+	batchIndex, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected batch index type %T in transaction %s", args[0], log.TxHash)
+	}
+
+	var batchId types.BatchId
+	if err := batchId.UnmarshalText([]byte(batchIndex)); err != nil {
+		return nil, fmt.Errorf("invalid batch index %q in transaction %s: %w", batchIndex, log.TxHash, err)
+	}
+
+	return &CommittedBatch{
+		BatchId:    batchId,
+		L1Block:    log.BlockNumber,
+		TxHash:     log.TxHash,
+		BlobHashes: tx.BlobHashes(),
+	}, nil
+}
+
+func (s *L1Source) findCommitment(ctx context.Context, batchId types.BatchId) (*ethtypes.Log, error) {
+	iter, err := s.contract.FilterBatchCommitted(
+		&bind.FilterOpts{Start: s.config.FromBlock, Context: ctx}, []string{batchId.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter commitment events: %w", err)
+	}
+	defer iter.Close()
+
+	var found *ethtypes.Log
+	for iter.Next() {
+		// the latest event wins, earlier ones might be reorged out by the time of the last commitment
+		raw := iter.Event.Raw
+		found = &raw
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate commitment events: %w", err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: no commitment event for batchId=%s on L1", ErrBatchNotFound, batchId)
+	}
+	return found, nil
+}
+
+// Blobs fetches the blobs of the batch from the beacon node and checks them against
+// the versioned hashes stored in the rollup contract
+func (s *L1Source) Blobs(ctx context.Context, batchId types.BatchId) ([]kzg4844.Blob, error) {
+	versionedHashes, err := s.contract.GetBlobVersionedHashes(&bind.CallOpts{Context: ctx}, batchId.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versioned hashes of batch %s: %w", batchId, err)
+	}
+	if len(versionedHashes) == 0 {
+		return nil, fmt.Errorf("%w: batchId=%s is not committed to L1", ErrBatchNotFound, batchId)
+	}
+
+	log, err := s.findCommitment(ctx, batchId)
+	if err != nil {
+		return nil, err
+	}
+
+	// sidecars are addressed by the beacon block root, which is exposed in the header of the next execution block
+	header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header of L1 block %d: %w", log.BlockNumber+1, err)
+	}
+	if header.ParentBeaconRoot == nil {
+		return nil, fmt.Errorf("L1 block %d has no parent beacon root", log.BlockNumber+1)
+	}
+
+	sidecars, err := s.beacon.blobSidecars(ctx, header.ParentBeaconRoot.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	byHash := make(map[[32]byte]*blobSidecar, len(sidecars))
+	hasher := sha256.New()
+	for i := range sidecars {
+		hasher.Reset()
+		byHash[kzg4844.CalcBlobHashV1(hasher, &sidecars[i].KZGCommitment)] = &sidecars[i]
+	}
+
+	blobs := make([]kzg4844.Blob, len(versionedHashes))
+	for i, hash := range versionedHashes {
+		sidecar, ok := byHash[hash]
+		if !ok {
+			return nil, fmt.Errorf("%w: blob %d of batch %s (hash %s) is missing in beacon block %s",
+				ErrBatchNotFound, i, batchId, ethcommon.Hash(hash), header.ParentBeaconRoot)
+		}
+		if err := verifyBlob(&sidecar.Blob, sidecar.KZGCommitment); err != nil {
+			return nil, fmt.Errorf("blob %d of batch %s: %w", i, batchId, err)
+		}
+		blobs[i] = sidecar.Blob
+	}
+	return blobs, nil
+}
+
+func verifyBlob(blob *kzg4844.Blob, expected kzg4844.Commitment) error {
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		return fmt.Errorf("failed to compute blob commitment: %w", err)
+	}
+	if commitment != expected {
+		return errors.New("blob does not match its KZG commitment")
+	}
+	return nil
+}
+
+// StateRoot returns the state root the batch was finalized with, it is empty while the batch is not finalized
+func (s *L1Source) StateRoot(ctx context.Context, batchId types.BatchId) (common.Hash, error) {
+	root, err := s.contract.FinalizedStateRoots(&bind.CallOpts{Context: ctx}, batchId.String())
+	if err != nil {
+		return common.EmptyHash, fmt.Errorf("failed to get state root of batch %s: %w", batchId, err)
+	}
+	return common.Hash(root), nil
+}
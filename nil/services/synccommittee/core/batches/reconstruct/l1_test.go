@@ -0,0 +1,245 @@
+package reconstruct
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/versions"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+type l1Commitment struct {
+	batchId  types.BatchId
+	block    uint64
+	tx       *ethtypes.Transaction
+	sidecars []blobSidecar
+}
+
+// fakeL1 serves the rollup contract state and the beacon node API for the committed batches
+type fakeL1 struct {
+	rollupcontract.EthClientMock
+
+	t           *testing.T
+	abi         *abi.ABI
+	commitments []*l1Commitment
+	roots       map[string][32]byte
+	beacon      *httptest.Server
+}
+
+func newFakeL1(t *testing.T) *fakeL1 {
+	t.Helper()
+
+	contractAbi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	require.NoError(t, err)
+
+	l1 := &fakeL1{t: t, abi: contractAbi, roots: make(map[string][32]byte)}
+	l1.EthClientMock = rollupcontract.EthClientMock{
+		CallContractFunc:   l1.callContract,
+		FilterLogsFunc:     l1.filterLogs,
+		HeaderByNumberFunc: l1.headerByNumber,
+	}
+	l1.beacon = httptest.NewServer(http.HandlerFunc(l1.serveSidecars))
+	t.Cleanup(l1.beacon.Close)
+	return l1
+}
+
+func (l *fakeL1) commit(batch *types.PrunedBatch, block uint64) *l1Commitment {
+	l.t.Helper()
+
+	blobs := makeBatchBlobs(l.t, batch, versions.Default)
+	commitment := &l1Commitment{batchId: batch.BatchId, block: block}
+	var hashes []ethcommon.Hash
+	for _, blob := range blobs {
+		kzgCommitment, err := kzg4844.BlobToCommitment(&blob)
+		require.NoError(l.t, err)
+		hashes = append(hashes, kzg4844.CalcBlobHashV1(sha256.New(), &kzgCommitment))
+		commitment.sidecars = append(commitment.sidecars, blobSidecar{Blob: blob, KZGCommitment: kzgCommitment})
+	}
+
+	data, err := l.abi.Pack("commitBatch", batch.BatchId.String(), big.NewInt(int64(len(blobs))))
+	require.NoError(l.t, err)
+	commitment.tx = ethtypes.NewTx(&ethtypes.BlobTx{Nonce: uint64(len(l.commitments)), Data: data, BlobHashes: hashes})
+
+	l.commitments = append(l.commitments, commitment)
+	return commitment
+}
+
+func (l *fakeL1) TransactionByHash(_ context.Context, hash ethcommon.Hash) (*ethtypes.Transaction, bool, error) {
+	for _, c := range l.commitments {
+		if c.tx.Hash() == hash {
+			return c.tx, false, nil
+		}
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (l *fakeL1) find(batchIndex string) *l1Commitment {
+	for _, c := range l.commitments {
+		if c.batchId.String() == batchIndex {
+			return c
+		}
+	}
+	return nil
+}
+
+func (l *fakeL1) callContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := l.abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	batchIndex, _ := args[0].(string)
+
+	switch method.Name {
+	case "getBlobVersionedHashes":
+		hashes := make([][32]byte, 0)
+		if c := l.find(batchIndex); c != nil {
+			for _, hash := range c.tx.BlobHashes() {
+				hashes = append(hashes, hash)
+			}
+		}
+		return method.Outputs.Pack(hashes)
+	case "finalizedStateRoots":
+		return method.Outputs.Pack(l.roots[batchIndex])
+	default:
+		return nil, errors.New("method not simulated")
+	}
+}
+
+func (l *fakeL1) filterLogs(_ context.Context, q ethereum.FilterQuery) ([]ethtypes.Log, error) {
+	event := l.abi.Events["BatchCommitted"]
+	var logs []ethtypes.Log
+	for _, c := range l.commitments {
+		topic := crypto.Keccak256Hash([]byte(c.batchId.String()))
+		if len(q.Topics) > 1 && len(q.Topics[1]) > 0 && q.Topics[1][0] != topic {
+			continue
+		}
+		if c.block < q.FromBlock.Uint64() || (q.ToBlock != nil && c.block > q.ToBlock.Uint64()) {
+			continue
+		}
+		logs = append(logs, ethtypes.Log{
+			Topics:      []ethcommon.Hash{event.ID, topic},
+			BlockNumber: c.block,
+			TxHash:      c.tx.Hash(),
+		})
+	}
+	return logs, nil
+}
+
+func beaconRoot(number uint64) ethcommon.Hash {
+	return crypto.Keccak256Hash(big.NewInt(int64(number)).Bytes())
+}
+
+func (l *fakeL1) headerByNumber(_ context.Context, number *big.Int) (*ethtypes.Header, error) {
+	// the header of the next block references the beacon block with blobs of the previous one
+	root := beaconRoot(number.Uint64() - 1)
+	return &ethtypes.Header{Number: number, ParentBeaconRoot: &root}, nil
+}
+
+func (l *fakeL1) serveSidecars(w http.ResponseWriter, r *http.Request) {
+	blockId := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	sidecars := make([]blobSidecar, 0)
+	for _, c := range l.commitments {
+		if beaconRoot(c.block).Hex() == blockId {
+			sidecars = append(sidecars, c.sidecars...)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": sidecars})
+}
+
+func (l *fakeL1) source(t *testing.T) *L1Source {
+	t.Helper()
+	source, err := NewL1Source(l, L1SourceConfig{
+		ContractAddress: testaide.RandomHash().Hex()[:42],
+		BeaconEndpoint:  l.beacon.URL + "/",
+	})
+	require.NoError(t, err)
+	return source
+}
+
+func TestL1Source_CommittedBatches(t *testing.T) {
+	t.Parallel()
+
+	l1 := newFakeL1(t)
+	first := l1.commit(types.NewPrunedBatch(testaide.NewBlockBatch(1)), 10)
+	second := l1.commit(types.NewPrunedBatch(testaide.NewBlockBatch(2)), 12)
+	l1.commit(types.NewPrunedBatch(testaide.NewBlockBatch(1)), 20)
+
+	toBlock := uint64(15)
+	batches, err := l1.source(t).CommittedBatches(context.Background(), 5, &toBlock)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	for i, expected := range []*l1Commitment{first, second} {
+		require.Equal(t, expected.batchId, batches[i].BatchId)
+		require.Equal(t, expected.block, batches[i].L1Block)
+		require.Equal(t, expected.tx.Hash(), batches[i].TxHash)
+		require.Equal(t, expected.tx.BlobHashes(), batches[i].BlobHashes)
+	}
+}
+
+func TestL1Source_Blobs(t *testing.T) {
+	t.Parallel()
+
+	l1 := newFakeL1(t)
+	// two batches in the same L1 block share the beacon block
+	first := l1.commit(types.NewPrunedBatch(testaide.NewBlockBatch(1)), 10)
+	second := l1.commit(types.NewPrunedBatch(testaide.NewBlockBatch(3)), 10)
+	source := l1.source(t)
+
+	for _, c := range []*l1Commitment{first, second} {
+		blobs, err := source.Blobs(context.Background(), c.batchId)
+		require.NoError(t, err)
+		require.Len(t, blobs, len(c.sidecars))
+		for i := range blobs {
+			require.Equal(t, c.sidecars[i].Blob, blobs[i])
+		}
+	}
+
+	_, err := source.Blobs(context.Background(), types.NewBatchId())
+	require.ErrorIs(t, err, ErrBatchNotFound)
+
+	// beacon node returns a blob not matching the commitment
+	second.sidecars[0].Blob[100] ^= 0x01
+	_, err = source.Blobs(context.Background(), second.batchId)
+	require.ErrorContains(t, err, "does not match its KZG commitment")
+}
+
+func TestL1Source_StateRoot(t *testing.T) {
+	t.Parallel()
+
+	l1 := newFakeL1(t)
+	finalized := l1.commit(types.NewPrunedBatch(testaide.NewBlockBatch(1)), 10)
+	root := testaide.RandomHash()
+	l1.roots[finalized.batchId.String()] = root
+
+	source := l1.source(t)
+	actual, err := source.StateRoot(context.Background(), finalized.batchId)
+	require.NoError(t, err)
+	require.Equal(t, root, actual)
+
+	actual, err = source.StateRoot(context.Background(), types.NewBatchId())
+	require.NoError(t, err)
+	require.True(t, actual.Empty())
+}
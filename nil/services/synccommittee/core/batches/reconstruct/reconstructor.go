@@ -0,0 +1,183 @@
+package reconstruct
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/versions"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/rs/zerolog"
+)
+
+var ErrStateRootMismatch = errors.New("replayed state root does not match the one stored in the rollup contract")
+
+// StateRootSource provides state roots the batches were finalized with on L1
+type StateRootSource interface {
+	StateRoot(ctx context.Context, batchId types.BatchId) (common.Hash, error)
+}
+
+type Status string
+
+const (
+	StatusVerified Status = "verified"
+	StatusMismatch Status = "mismatch"
+	// StatusRootUnavailable means the batch was replayed, but there is no root to compare with:
+	// the batch is not finalized yet or the state root source is not configured
+	StatusRootUnavailable Status = "root_unavailable"
+)
+
+type BatchReport struct {
+	BatchId      types.BatchId `json:"batchId"`
+	BlobCount    int           `json:"blobCount"`
+	BlockCount   int           `json:"blockCount"`
+	TxCount      int           `json:"txCount"`
+	ReplayedRoot common.Hash   `json:"replayedRoot"`
+	ContractRoot common.Hash   `json:"contractRoot"`
+	Status       Status        `json:"status"`
+}
+
+// Reconstructor restores the L2 state from the data committed to L1:
+// it fetches blobs of the batches, decodes them and re-executes the contained transactions
+type Reconstructor struct {
+	blobs    BlobSource
+	roots    StateRootSource
+	replayer Replayer
+	decoder  encode.BatchDecoder
+	logger   zerolog.Logger
+}
+
+// NewReconstructor creates a reconstructor, roots might be nil if the replayed roots are not to be verified
+func NewReconstructor(
+	blobs BlobSource,
+	roots StateRootSource,
+	replayer Replayer,
+	logger zerolog.Logger,
+) *Reconstructor {
+	return &Reconstructor{
+		blobs:    blobs,
+		roots:    roots,
+		replayer: replayer,
+		decoder:  versions.NewRegistry(logger),
+		logger:   logger,
+	}
+}
+
+// Run processes the batches one by one in the given order, which has to match the order of their commitment.
+// It stops on the first batch which fails to be replayed or whose state root differs from the contract one.
+func (r *Reconstructor) Run(ctx context.Context, batchIds []types.BatchId, onReport func(*BatchReport)) error {
+	for _, batchId := range batchIds {
+		report, err := r.Process(ctx, batchId)
+		if err != nil {
+			return err
+		}
+		if onReport != nil {
+			onReport(report)
+		}
+		if report.Status == StatusMismatch {
+			return fmt.Errorf("%w: batchId=%s, replayed=%s, contract=%s",
+				ErrStateRootMismatch, batchId, report.ReplayedRoot, report.ContractRoot)
+		}
+	}
+	return nil
+}
+
+// Process fetches, decodes and replays a single batch
+func (r *Reconstructor) Process(ctx context.Context, batchId types.BatchId) (*BatchReport, error) {
+	blobs, err := r.blobs.Blobs(ctx, batchId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blobs of batch %s: %w", batchId, err)
+	}
+
+	batch, err := r.decode(blobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode batch %s: %w", batchId, err)
+	}
+	if batch.BatchId != batchId {
+		return nil, fmt.Errorf("blobs of batch %s contain batch %s", batchId, batch.BatchId)
+	}
+
+	report := &BatchReport{
+		BatchId:    batchId,
+		BlobCount:  len(blobs),
+		BlockCount: len(batch.Blocks),
+		Status:     StatusRootUnavailable,
+	}
+	for _, block := range batch.Blocks {
+		report.TxCount += len(block.Transactions)
+	}
+
+	report.ReplayedRoot, err = r.replayer.Replay(ctx, batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay batch %s: %w", batchId, err)
+	}
+
+	if r.roots != nil {
+		report.ContractRoot, err = r.roots.StateRoot(ctx, batchId)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case report.ContractRoot.Empty():
+		case report.ContractRoot == report.ReplayedRoot:
+			report.Status = StatusVerified
+		default:
+			report.Status = StatusMismatch
+		}
+	}
+
+	r.logger.Info().
+		Stringer(logging.FieldBatchId, batchId).
+		Int("blockCount", report.BlockCount).
+		Int("txCount", report.TxCount).
+		Stringer("replayedRoot", report.ReplayedRoot).
+		Stringer("contractRoot", report.ContractRoot).
+		Str("status", string(report.Status)).
+		Msg("Batch is reconstructed")
+
+	return report, nil
+}
+
+func (r *Reconstructor) decode(blobs []kzg4844.Blob) (*types.PrunedBatch, error) {
+	data, err := blob.ReadAll(blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var header encode.BatchHeader
+	if err := header.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	batch, err := r.decoder.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if header.Version == v1.Version {
+		restoreV1Fields(batch)
+	}
+	return batch, nil
+}
+
+// restoreV1Fields fills the transaction fields v1 doesn't store: the chain id,
+// and the refund and bounce addresses, which are omitted by the encoder when they equal to the sender
+func restoreV1Fields(batch *types.PrunedBatch) {
+	for _, block := range batch.Blocks {
+		for _, txn := range block.Transactions {
+			txn.ChainId = coreTypes.DefaultChainId
+			if txn.RefundTo.IsEmpty() {
+				txn.RefundTo = txn.From
+			}
+			if txn.BounceTo.IsEmpty() {
+				txn.BounceTo = txn.From
+			}
+		}
+	}
+}
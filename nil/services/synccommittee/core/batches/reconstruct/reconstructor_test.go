@@ -0,0 +1,211 @@
+package reconstruct
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/versions"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReplayer struct {
+	roots    map[types.BatchId]common.Hash
+	replayed []*types.PrunedBatch
+}
+
+func (r *fakeReplayer) Replay(_ context.Context, batch *types.PrunedBatch) (common.Hash, error) {
+	r.replayed = append(r.replayed, batch)
+	return r.roots[batch.BatchId], nil
+}
+
+type fakeRoots map[types.BatchId]common.Hash
+
+func (r fakeRoots) StateRoot(_ context.Context, batchId types.BatchId) (common.Hash, error) {
+	return r[batchId], nil
+}
+
+func makeBatchBlobs(t *testing.T, batch *types.PrunedBatch, version uint16) []kzg4844.Blob {
+	t.Helper()
+
+	encoder, err := versions.NewRegistry(logging.NewLogger("reconstruct_test")).Encoder(version)
+	require.NoError(t, err)
+	var encoded bytes.Buffer
+	require.NoError(t, encoder.Encode(batch, &encoded))
+	blobs, err := blob.NewBuilder().MakeBlobs(&encoded, 6)
+	require.NoError(t, err)
+	return blobs
+}
+
+func writeBlobFile(t *testing.T, dir string, batch *types.PrunedBatch, version uint16) int {
+	t.Helper()
+	blobs := makeBatchBlobs(t, batch, version)
+
+	var data []byte
+	for _, b := range blobs {
+		data = append(data, b[:]...)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, batch.BatchId.String()), data, 0o600))
+	return len(blobs)
+}
+
+func TestReconstructor_VerifiesReplayedRoots(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	batches := []*types.PrunedBatch{
+		types.NewPrunedBatch(testaide.NewBlockBatch(2)),
+		types.NewPrunedBatch(testaide.NewBlockBatch(3)),
+	}
+
+	replayer := &fakeReplayer{roots: make(map[types.BatchId]common.Hash)}
+	roots := make(fakeRoots)
+	var ids []types.BatchId
+	blobCounts := make(map[types.BatchId]int)
+	// batches committed before and after the format upgrade
+	batchVersions := []uint16{v1.Version, v2.Version}
+	for i, batch := range batches {
+		version := batchVersions[i]
+		blobCounts[batch.BatchId] = writeBlobFile(t, dir, batch, version)
+		root := testaide.RandomHash()
+		replayer.roots[batch.BatchId] = root
+		roots[batch.BatchId] = root
+		ids = append(ids, batch.BatchId)
+	}
+
+	reconstructor := NewReconstructor(NewFileBlobSource(dir), roots, replayer, logging.NewLogger("reconstruct_test"))
+
+	var reports []*BatchReport
+	err := reconstructor.Run(context.Background(), ids, func(report *BatchReport) {
+		reports = append(reports, report)
+	})
+	require.NoError(t, err)
+	require.Len(t, reports, len(batches))
+
+	for i, batch := range batches {
+		report := reports[i]
+		require.Equal(t, batch.BatchId, report.BatchId)
+		require.Equal(t, StatusVerified, report.Status)
+		require.Equal(t, blobCounts[batch.BatchId], report.BlobCount)
+		require.Len(t, batch.Blocks, report.BlockCount)
+		require.Equal(t, roots[batch.BatchId], report.ContractRoot)
+
+		replayed := replayer.replayed[i]
+		require.Len(t, replayed.Blocks, len(batch.Blocks))
+		for j, block := range batch.Blocks {
+			require.Equal(t, block.ShardId, replayed.Blocks[j].ShardId)
+			require.Equal(t, block.BlockNumber, replayed.Blocks[j].BlockNumber)
+			// each shard has a single block in the batch, so all the parent hashes are stored
+			require.Equal(t, block.PrevBlockHash, replayed.Blocks[j].PrevBlockHash)
+			require.Len(t, replayed.Blocks[j].Transactions, len(block.Transactions))
+		}
+	}
+}
+
+func TestReconstructor_RestoresV1Fields(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	batch := types.NewPrunedBatch(testaide.NewBlockBatch(2))
+	bounceTo := coreTypes.GenerateRandomAddress(coreTypes.BaseShardId)
+	var txCount int
+	for _, block := range batch.Blocks {
+		for _, txn := range block.Transactions {
+			txn.RefundTo = txn.From
+			txn.BounceTo = bounceTo
+			txCount++
+		}
+	}
+	require.NotZero(t, txCount)
+	writeBlobFile(t, dir, batch, v1.Version)
+
+	replayer := &fakeReplayer{}
+	reconstructor := NewReconstructor(NewFileBlobSource(dir), nil, replayer, logging.NewLogger("reconstruct_test"))
+	_, err := reconstructor.Process(context.Background(), batch.BatchId)
+	require.NoError(t, err)
+
+	require.Len(t, replayer.replayed, 1)
+	for i, block := range replayer.replayed[0].Blocks {
+		for j, txn := range block.Transactions {
+			from := batch.Blocks[i].Transactions[j].From
+			require.Equal(t, from, txn.RefundTo)
+			require.Equal(t, bounceTo, txn.BounceTo)
+			require.Equal(t, coreTypes.DefaultChainId, txn.ChainId)
+		}
+	}
+}
+
+func TestReconstructor_StopsOnMismatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first := types.NewPrunedBatch(testaide.NewBlockBatch(1))
+	second := types.NewPrunedBatch(testaide.NewBlockBatch(1))
+	writeBlobFile(t, dir, first, versions.Default)
+	writeBlobFile(t, dir, second, versions.Default)
+
+	replayer := &fakeReplayer{roots: map[types.BatchId]common.Hash{
+		first.BatchId:  testaide.RandomHash(),
+		second.BatchId: testaide.RandomHash(),
+	}}
+	roots := fakeRoots{first.BatchId: testaide.RandomHash()}
+
+	reconstructor := NewReconstructor(NewFileBlobSource(dir), roots, replayer, logging.NewLogger("reconstruct_test"))
+
+	var reports []*BatchReport
+	err := reconstructor.Run(context.Background(), []types.BatchId{first.BatchId, second.BatchId},
+		func(report *BatchReport) { reports = append(reports, report) })
+	require.ErrorIs(t, err, ErrStateRootMismatch)
+	require.Len(t, reports, 1)
+	require.Equal(t, StatusMismatch, reports[0].Status)
+	require.Len(t, replayer.replayed, 1, "batches after the mismatch should not be replayed")
+}
+
+func TestReconstructor_RootUnavailable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	batch := types.NewPrunedBatch(testaide.NewBlockBatch(1))
+	writeBlobFile(t, dir, batch, versions.Latest)
+	replayer := &fakeReplayer{roots: map[types.BatchId]common.Hash{batch.BatchId: testaide.RandomHash()}}
+	logger := logging.NewLogger("reconstruct_test")
+
+	// batch is not finalized yet
+	report, err := NewReconstructor(NewFileBlobSource(dir), fakeRoots{}, replayer, logger).
+		Process(context.Background(), batch.BatchId)
+	require.NoError(t, err)
+	require.Equal(t, StatusRootUnavailable, report.Status)
+
+	// roots are not verified at all
+	report, err = NewReconstructor(NewFileBlobSource(dir), nil, replayer, logger).
+		Process(context.Background(), batch.BatchId)
+	require.NoError(t, err)
+	require.Equal(t, StatusRootUnavailable, report.Status)
+	require.Equal(t, replayer.roots[batch.BatchId], report.ReplayedRoot)
+}
+
+func TestFileBlobSource_Errors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := NewFileBlobSource(dir)
+
+	_, err := source.Blobs(context.Background(), types.NewBatchId())
+	require.ErrorIs(t, err, ErrBatchNotFound)
+
+	truncated := types.NewBatchId()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, truncated.String()), make([]byte, 100), 0o600))
+	_, err = source.Blobs(context.Background(), truncated)
+	require.ErrorContains(t, err, "invalid size")
+}
@@ -0,0 +1,221 @@
+package reconstruct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// Replayer re-executes transactions of the batch and returns the resulting state root
+type Replayer interface {
+	Replay(ctx context.Context, batch *types.PrunedBatch) (common.Hash, error)
+}
+
+type executionReplayer struct {
+	database db.DB
+	logger   zerolog.Logger
+}
+
+// NewExecutionReplayer creates a replayer building blocks on top of the local database,
+// which has to contain the state the first replayed batch is based on.
+// Replayed blocks are written to the database, so the subsequent batches can be replayed on top of them.
+func NewExecutionReplayer(database db.DB, logger zerolog.Logger) Replayer {
+	return &executionReplayer{
+		database: database,
+		logger:   logger,
+	}
+}
+
+func (r *executionReplayer) Replay(ctx context.Context, batch *types.PrunedBatch) (common.Hash, error) {
+	nShards, err := r.shardCount(ctx)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	var mainBlock *coreTypes.Block
+	// child blocks go first in the batch, so the main block is built on top of their hashes
+	for _, block := range batch.Blocks {
+		res, err := r.replayBlock(ctx, block, nShards)
+		if err != nil {
+			return common.EmptyHash, fmt.Errorf("failed to replay block %d of shard %d: %w",
+				block.BlockNumber, block.ShardId, err)
+		}
+		if block.ShardId.IsMainShard() {
+			mainBlock = res.Block
+		}
+	}
+
+	if mainBlock == nil {
+		return common.EmptyHash, fmt.Errorf("batch %s has no main shard block", batch.BatchId)
+	}
+	return mainBlock.ChildBlocksRootHash, nil
+}
+
+func (r *executionReplayer) shardCount(ctx context.Context) (uint32, error) {
+	tx, err := r.database.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	hashes, err := db.ReadLastBlockHashes(tx)
+	if err != nil {
+		return 0, err
+	}
+	if len(hashes) == 0 {
+		return 0, errors.New("local database contains no blocks, it has to be initialized with the L2 state")
+	}
+	return uint32(len(hashes)), nil
+}
+
+func (r *executionReplayer) replayBlock(
+	ctx context.Context,
+	block *types.PrunedBlock,
+	nShards uint32,
+) (*execution.BlockGenerationResult, error) {
+	r.logger.Debug().
+		Stringer(logging.FieldShardId, block.ShardId).
+		Stringer(logging.FieldBlockNumber, block.BlockNumber).
+		Int("txCount", len(block.Transactions)).
+		Msg("Replaying block")
+
+	proposal, prevBlock, err := r.makeProposal(ctx, block, nShards)
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := execution.NewBlockGenerator(
+		ctx, execution.NewBlockGeneratorParams(block.ShardId, nShards), r.database, prevBlock)
+	if err != nil {
+		return nil, err
+	}
+	defer gen.Rollback()
+
+	gasPrices := gen.CollectGasPrices(proposal.PrevBlockId)
+	res, err := gen.BuildBlock(proposal, gasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build block: %w", err)
+	}
+	if err := gen.Finalize(res, &coreTypes.ConsensusParams{}); err != nil {
+		return nil, fmt.Errorf("failed to finalize block: %w", err)
+	}
+	return res, nil
+}
+
+func (r *executionReplayer) makeProposal(
+	ctx context.Context,
+	block *types.PrunedBlock,
+	nShards uint32,
+) (*execution.Proposal, *coreTypes.Block, error) {
+	tx, err := r.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	prevBlockHash := block.PrevBlockHash
+	if prevBlockHash.Empty() {
+		// formats omitting the hash expect the block to be built on top of the latest block of its shard
+		if prevBlockHash, err = db.ReadLastBlockHash(tx, block.ShardId); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	prevBlock, err := db.ReadBlock(tx, block.ShardId, prevBlockHash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil, fmt.Errorf("parent block %s is not found in the local database", prevBlockHash)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if prevBlock.Id+1 != block.BlockNumber {
+		return nil, nil, fmt.Errorf("block %d can't be built on top of the parent block %d (%s)",
+			block.BlockNumber, prevBlock.Id, prevBlockHash)
+	}
+
+	proposal := &execution.Proposal{
+		PrevBlockId:   prevBlock.Id,
+		PrevBlockHash: prevBlockHash,
+		MainChainHash: block.MainChainHash,
+		ShardHashes:   block.ChildBlocks,
+	}
+	// The collator state is not restored: it is not a part of the block, it only tracks the neighbor transactions
+	// the collator has processed, so the reconstructed database can't be used for collation.
+
+	// formats omitting the links to other shards expect the latest replayed blocks to be referenced
+	if err := r.fillMissingLinks(tx, block.ShardId, nShards, proposal); err != nil {
+		return nil, nil, err
+	}
+
+	txns := make([]*coreTypes.Transaction, 0, len(block.Transactions))
+	for _, txn := range block.Transactions {
+		txns = append(txns, toTransaction(txn))
+	}
+	proposal.InternalTxns, proposal.ExternalTxns = execution.SplitInTransactions(txns)
+	for _, txn := range block.ForwardTransactions {
+		proposal.ForwardTxns = append(proposal.ForwardTxns, toTransaction(txn))
+	}
+
+	return proposal, prevBlock, nil
+}
+
+func (*executionReplayer) fillMissingLinks(
+	tx db.RoTx,
+	shardId coreTypes.ShardId,
+	nShards uint32,
+	proposal *execution.Proposal,
+) error {
+	if !shardId.IsMainShard() {
+		if !proposal.MainChainHash.Empty() {
+			return nil
+		}
+		var err error
+		proposal.MainChainHash, err = db.ReadLastBlockHash(tx, coreTypes.MainShardId)
+		return err
+	}
+
+	if len(proposal.ShardHashes) != 0 {
+		return nil
+	}
+	for shardId := coreTypes.ShardId(1); shardId < coreTypes.ShardId(nShards); shardId++ {
+		hash, err := db.ReadLastBlockHash(tx, shardId)
+		if err != nil {
+			return err
+		}
+		proposal.ShardHashes = append(proposal.ShardHashes, hash)
+	}
+	return nil
+}
+
+// toTransaction restores the transaction from the fields stored in blobs
+func toTransaction(txn *types.PrunedTransaction) *coreTypes.Transaction {
+	res := &coreTypes.Transaction{
+		TransactionDigest: coreTypes.TransactionDigest{
+			Flags:                txn.Flags,
+			FeeCredit:            txn.FeeCredit,
+			MaxPriorityFeePerGas: txn.MaxPriorityFeePerGas,
+			MaxFeePerGas:         txn.MaxFeePerGas,
+			To:                   txn.To,
+			ChainId:              txn.ChainId,
+			Seqno:                coreTypes.Seqno(txn.Seqno),
+			Data:                 coreTypes.Code(txn.Data),
+		},
+		From:         txn.From,
+		RefundTo:     txn.RefundTo,
+		BounceTo:     txn.BounceTo,
+		Value:        txn.Value,
+		Token:        txn.Token,
+		RequestId:    txn.RequestId,
+		RequestChain: txn.RequestChain,
+		Signature:    txn.Signature,
+	}
+	return res
+}
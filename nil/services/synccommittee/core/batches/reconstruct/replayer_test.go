@@ -0,0 +1,159 @@
+package reconstruct
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+const replayerTestShards = 2
+
+// newZeroStateDb creates a database with the zero state of all the shards,
+// the state is generated deterministically, so the databases created by the function are the same
+func newZeroStateDb(t *testing.T) db.DB {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	for shardId := range coreTypes.ShardId(replayerTestShards) {
+		gen, err := execution.NewBlockGenerator(
+			t.Context(), execution.NewBlockGeneratorParams(shardId, replayerTestShards), database, nil)
+		require.NoError(t, err)
+		_, err = gen.GenerateZeroState(&execution.ZeroStateConfig{})
+		gen.Rollback()
+		require.NoError(t, err)
+	}
+	return database
+}
+
+// generateBlock collates the block of the proposal on top of the last block of the shard
+// and returns it the way the sync committee receives it from the RPC
+func generateBlock(
+	t *testing.T,
+	database db.DB,
+	shardId coreTypes.ShardId,
+	proposal *execution.Proposal,
+	childBlocks []common.Hash,
+) *jsonrpc.RPCBlock {
+	t.Helper()
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	prevBlock, prevHash, err := db.ReadLastBlock(tx, shardId)
+	require.NoError(t, err)
+	mainHash, err := db.ReadLastBlockHash(tx, coreTypes.MainShardId)
+	require.NoError(t, err)
+	tx.Rollback()
+
+	proposal.PrevBlockId = prevBlock.Id
+	proposal.PrevBlockHash = prevHash
+	if !shardId.IsMainShard() {
+		proposal.MainChainHash = mainHash
+	}
+	proposal.ShardHashes = childBlocks
+
+	gen, err := execution.NewBlockGenerator(
+		t.Context(), execution.NewBlockGeneratorParams(shardId, replayerTestShards), database, prevBlock)
+	require.NoError(t, err)
+	defer gen.Rollback()
+
+	res, err := gen.GenerateBlock(proposal, &coreTypes.ConsensusParams{})
+	require.NoError(t, err)
+
+	block, err := jsonrpc.NewRPCBlock(shardId, &jsonrpc.BlockWithEntities{
+		Block:          res.Block,
+		Receipts:       res.Receipts,
+		InTransactions: res.InTxns,
+		ChildBlocks:    childBlocks,
+	}, true)
+	require.NoError(t, err)
+	return block
+}
+
+func TestExecutionReplayer_ReproducesCommittedBlocks(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("replayer_test")
+	source := newZeroStateDb(t)
+	target := newZeroStateDb(t)
+
+	childShardId := coreTypes.ShardId(1)
+	txn := &coreTypes.Transaction{
+		TransactionDigest: coreTypes.TransactionDigest{
+			Flags:                coreTypes.NewTransactionFlags(coreTypes.TransactionFlagInternal),
+			FeeCredit:            coreTypes.NewValueFromUint64(5_000_000),
+			MaxPriorityFeePerGas: coreTypes.NewValueFromUint64(10),
+			MaxFeePerGas:         coreTypes.NewValueFromUint64(1_000),
+			To:                   coreTypes.ShardAndHexToAddress(childShardId, "0x1234"),
+			ChainId:              coreTypes.DefaultChainId,
+			Data:                 coreTypes.Code{0x01, 0x02},
+		},
+		From:     coreTypes.ShardAndHexToAddress(coreTypes.MainShardId, "0x5678"),
+		RefundTo: coreTypes.ShardAndHexToAddress(coreTypes.MainShardId, "0x9abc"),
+		Value:    coreTypes.NewValueFromUint64(100),
+		Token: []coreTypes.TokenBalance{
+			{Token: coreTypes.TokenId(coreTypes.ShardAndHexToAddress(coreTypes.MainShardId, "0xdef0")), Balance: coreTypes.NewValueFromUint64(7)},
+		},
+	}
+
+	var batches []*types.PrunedBatch
+	for range 2 {
+		childBlock := generateBlock(t, source, childShardId, &execution.Proposal{
+			InternalTxns: []*coreTypes.Transaction{txn},
+		}, nil)
+		require.Len(t, childBlock.Transactions, 1)
+		mainBlock := generateBlock(t, source, coreTypes.MainShardId, &execution.Proposal{},
+			[]common.Hash{childBlock.Hash})
+
+		batch, err := types.NewBlockBatch(mainBlock, []*jsonrpc.RPCBlock{childBlock})
+		require.NoError(t, err)
+		batches = append(batches, types.NewPrunedBatch(batch))
+		txn.Seqno++
+	}
+
+	// v1 doesn't store the fees, so only the later formats reproduce the blocks
+	dir := t.TempDir()
+	roots := make(fakeRoots)
+	var ids []types.BatchId
+	for _, batch := range batches {
+		writeBlobFile(t, dir, batch, v2.Version)
+		roots[batch.BatchId] = sourceChildBlocksRoot(t, source, batch)
+		ids = append(ids, batch.BatchId)
+	}
+
+	reconstructor := NewReconstructor(
+		NewFileBlobSource(dir), roots, NewExecutionReplayer(target, logger), logger)
+	var reports []*BatchReport
+	require.NoError(t, reconstructor.Run(t.Context(), ids, func(report *BatchReport) {
+		reports = append(reports, report)
+	}))
+	require.Len(t, reports, len(batches))
+	for _, report := range reports {
+		require.Equal(t, StatusVerified, report.Status)
+	}
+}
+
+// sourceChildBlocksRoot reads the root of the child blocks of the main block of the batch from the source database
+func sourceChildBlocksRoot(t *testing.T, database db.DB, batch *types.PrunedBatch) common.Hash {
+	t.Helper()
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	mainBlock := batch.Blocks[len(batch.Blocks)-1]
+	require.True(t, mainBlock.ShardId.IsMainShard())
+	block, err := db.ReadBlockByNumber(tx, coreTypes.MainShardId, mainBlock.BlockNumber)
+	require.NoError(t, err)
+	return block.ChildBlocksRootHash
+}
@@ -0,0 +1,51 @@
+package reconstruct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+var ErrBatchNotFound = errors.New("batch blobs are not found")
+
+// BlobSource provides the blobs the batch was committed to L1 with
+type BlobSource interface {
+	Blobs(ctx context.Context, batchId types.BatchId) ([]kzg4844.Blob, error)
+}
+
+type fileBlobSource struct {
+	dir string
+}
+
+// NewFileBlobSource creates a source reading blobs from local files,
+// each file is named after the batch id and contains concatenated blobs of the batch
+func NewFileBlobSource(dir string) BlobSource {
+	return &fileBlobSource{dir: dir}
+}
+
+func (s *fileBlobSource) Blobs(_ context.Context, batchId types.BatchId) ([]kzg4844.Blob, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, batchId.String()))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: batchId=%s", ErrBatchNotFound, batchId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	const blobSize = len(kzg4844.Blob{})
+	if len(data) == 0 || len(data)%blobSize != 0 {
+		return nil, fmt.Errorf("blob file of batch %s has invalid size %d, expected a multiple of %d",
+			batchId, len(data), blobSize)
+	}
+
+	blobs := make([]kzg4844.Blob, len(data)/blobSize)
+	for i := range blobs {
+		copy(blobs[i][:], data[i*blobSize:])
+	}
+	return blobs, nil
+}
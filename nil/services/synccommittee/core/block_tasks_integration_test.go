@@ -53,7 +53,7 @@ func (s *BlockTasksIntegrationTestSuite) SetupSuite() {
 
 	s.scheduler = scheduler.New(
 		s.taskStorage,
-		newTaskStateChangeHandler(s.blockStorage, &noopStateResetLauncher{}, logger),
+		newTaskStateChangeHandler(s.blockStorage, s.taskStorage, &noopStateResetLauncher{}, s.timer, logger),
 		metricsHandler,
 		logger,
 	)
@@ -85,13 +85,13 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Success_R
 	executorId := testaide.RandomExecutorId()
 
 	// requesting next task for execution
-	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, types.ExecutorCapabilities{}))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.ProofBlock, taskToExecute.TaskType)
 
 	// no new tasks available yet
-	nonAvailableTask, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	nonAvailableTask, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, types.ExecutorCapabilities{}))
 	s.Require().NoError(err)
 	s.Require().Nil(nonAvailableTask)
 
@@ -106,7 +106,7 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Success_R
 	s.Require().Nil(proposalData)
 
 	// requesting next task for execution
-	taskToExecute, err = s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err = s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, types.ExecutorCapabilities{}))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.AggregateProofs, taskToExecute.TaskType)
@@ -137,7 +137,7 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Failure_R
 	executorId := testaide.RandomExecutorId()
 
 	// requesting next task for execution
-	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, types.ExecutorCapabilities{}))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.ProofBlock, taskToExecute.TaskType)
@@ -148,7 +148,7 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Failure_R
 	s.Require().NoError(err)
 
 	// requesting next task for execution
-	taskToExecute, err = s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err = s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, types.ExecutorCapabilities{}))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.AggregateProofs, taskToExecute.TaskType)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/versions"
 )
 
 const (
@@ -15,6 +16,8 @@ type Config struct {
 	TaskListenerRpcEndpoint string
 	PollingDelay            time.Duration
 	ProposerParams          *ProposerParams
+	TaskEventWebhookParams  *TaskEventWebhookParams
+	BatchEncodingVersion    uint16
 	Telemetry               *telemetry.Config
 }
 
@@ -24,6 +27,8 @@ func NewDefaultConfig() *Config {
 		TaskListenerRpcEndpoint: DefaultTaskRpcEndpoint,
 		PollingDelay:            time.Second,
 		ProposerParams:          NewDefaultProposerParams(),
+		TaskEventWebhookParams:  NewDefaultTaskEventWebhookParams(),
+		BatchEncodingVersion:    versions.Default,
 		Telemetry: &telemetry.Config{
 			ServiceName: "sync_committee",
 		},
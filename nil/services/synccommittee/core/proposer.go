@@ -8,11 +8,13 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/concurrent"
+	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/rs/zerolog"
@@ -26,6 +28,13 @@ type ProposerStorage interface {
 	TryGetNextProposalData(ctx context.Context) (*scTypes.ProposalData, error)
 
 	SetBlockAsProposed(ctx context.Context, id scTypes.BlockId) error
+
+	TryGetBatchCommitment(ctx context.Context, batchId scTypes.BatchId) (*scTypes.BatchCommitment, error)
+}
+
+// CommitmentBlobsSource provides the blobs the batches are committed to L1 with
+type CommitmentBlobsSource interface {
+	CommitmentBlobs(commitment *scTypes.BatchCommitment) ([]kzg4844.Blob, error)
 }
 
 type ProposerMetrics interface {
@@ -34,9 +43,12 @@ type ProposerMetrics interface {
 }
 
 type proposer struct {
-	storage     ProposerStorage
-	retryRunner common.RetryRunner
-	ethClient   rollupcontract.EthClient
+	storage        ProposerStorage
+	eventPublisher TaskEventPublisher
+	blobSource     CommitmentBlobsSource
+	retryRunner    common.RetryRunner
+	ethClient      rollupcontract.EthClient
+	timer          common.Timer
 
 	rollupContractWrapper *rollupcontract.Wrapper
 	params                *ProposerParams
@@ -67,7 +79,10 @@ func NewProposer(
 	ctx context.Context,
 	params *ProposerParams,
 	storage ProposerStorage,
+	eventPublisher TaskEventPublisher,
+	blobSource CommitmentBlobsSource,
 	ethClient rollupcontract.EthClient,
+	timer common.Timer,
 	metrics ProposerMetrics,
 	logger zerolog.Logger,
 ) (*proposer, error) {
@@ -80,11 +95,14 @@ func NewProposer(
 	)
 
 	p := &proposer{
-		storage:     storage,
-		ethClient:   ethClient,
-		params:      params,
-		retryRunner: retryRunner,
-		metrics:     metrics,
+		storage:        storage,
+		eventPublisher: eventPublisher,
+		blobSource:     blobSource,
+		ethClient:      ethClient,
+		timer:          timer,
+		params:         params,
+		retryRunner:    retryRunner,
+		metrics:        metrics,
 	}
 
 	p.logger = srv.WorkerLogger(logger, p)
@@ -210,10 +228,13 @@ func (p *proposer) proposeNextBlock(ctx context.Context) error {
 		return nil
 	}
 
-	err = p.sendProof(ctx, data)
+	proposed, err := p.sendProof(ctx, data)
 	if err != nil {
 		return fmt.Errorf("failed to send proof to L1 for block with hash=%s: %w", data.MainShardBlockHash, err)
 	}
+	if !proposed {
+		return nil
+	}
 
 	blockId := scTypes.NewBlockId(types.MainShardId, data.MainShardBlockHash)
 	err = p.storage.SetBlockAsProposed(ctx, blockId)
@@ -247,49 +268,11 @@ func (p *proposer) getLatestProvedStateRoot(ctx context.Context) (common.Hash, e
 	return latestProvedState, err
 }
 
-func (p *proposer) commitBatch(ctx context.Context, blobs []kzg4844.Blob, batchIndexInBlobStorage string) (*ethtypes.Transaction, bool, error) {
-	var tx *ethtypes.Transaction
-	batchTxSkipped := false
-	err := p.retryRunner.Do(ctx, func(context.Context) error {
-		var err error
-		tx, err = p.rollupContractWrapper.CommitBatch(ctx, blobs, batchIndexInBlobStorage)
-		if errors.Is(err, rollupcontract.ErrBatchAlreadyCommitted) {
-			p.logger.Warn().Msg("batch is already committed, skipping blob tx")
-			batchTxSkipped = true
-			return nil
-		}
-		return err
-	})
+func (p *proposer) updateState(ctx context.Context, data *scTypes.ProposalData, blobs []kzg4844.Blob) error {
+	blobTxSidecar, err := rollupcontract.ComputeSidecar(blobs)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to upload blob: %w", err)
-	}
-
-	if !batchTxSkipped {
-		p.logger.Info().
-			Hex("txHash", tx.Hash().Bytes()).
-			Int("gasLimit", int(tx.Gas())).
-			Int("blobGasLimit", int(tx.BlobGas())).
-			Int("cost", int(tx.Cost().Uint64())).
-			Any("blobHashes", tx.BlobHashes()).
-			Msg("blob transaction sent")
-
-		receipt, err := p.rollupContractWrapper.WaitForReceipt(ctx, tx.Hash())
-		if err != nil {
-			return nil, false, err
-		}
-		if receipt == nil {
-			return nil, false, errors.New("CommitBatch tx mining timout exceeded")
-		}
-		if receipt.Status != ethtypes.ReceiptStatusSuccessful {
-			return nil, false, errors.New("CommitBatch tx failed")
-		}
+		return err
 	}
-
-	return tx, batchTxSkipped, nil
-}
-
-func (p *proposer) updateState(ctx context.Context, tx *ethtypes.Transaction, data *scTypes.ProposalData, batchIndexInBlobStorage string) error {
-	blobTxSidecar := tx.BlobTxSidecar()
 	dataProofs, err := rollupcontract.ComputeDataProofs(blobTxSidecar)
 	if err != nil {
 		return err
@@ -303,12 +286,13 @@ func (p *proposer) updateState(ctx context.Context, tx *ethtypes.Transaction, da
 		Int("txCount", len(data.Transactions)).
 		Msg("calling UpdateState L1 method")
 
+	var tx *ethtypes.Transaction
 	updateTxSkipped := false
 	err = p.retryRunner.Do(ctx, func(context.Context) error {
 		var err error
 		tx, err = p.rollupContractWrapper.UpdateState(
 			ctx,
-			batchIndexInBlobStorage,
+			data.BatchId.String(),
 			data.OldProvedStateRoot,
 			data.NewProvedStateRoot,
 			dataProofs,
@@ -338,24 +322,85 @@ func (p *proposer) updateState(ctx context.Context, tx *ethtypes.Transaction, da
 			Msg("UpdateState transaction sent")
 
 		p.metrics.RecordProposerTxSent(ctx, data)
+
+		receipt, err := p.rollupContractWrapper.WaitForReceipt(ctx, tx.Hash())
+		if err != nil {
+			return err
+		}
+		if receipt == nil {
+			return errors.New("UpdateState tx mining timout exceeded")
+		}
+		if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+			return errors.New("UpdateState tx failed")
+		}
+		p.publishStateRootUpdated(ctx, data, tx)
 	}
 
 	return nil
 }
 
-func (p *proposer) sendProof(ctx context.Context, data *scTypes.ProposalData) error {
-	// TODO: populate with actual data
-	blobs := []kzg4844.Blob{{0x01}, {0x02}, {0x03}}
-	batchIndexInBlobStorage := "0x0000000000000000000000000000000000000000000000000000000000000001"
+func (p *proposer) publishStateRootUpdated(ctx context.Context, data *scTypes.ProposalData, tx *ethtypes.Transaction) {
+	event := public.NewStateRootUpdatedEvent(
+		data.MainShardBlockHash,
+		data.OldProvedStateRoot,
+		data.NewProvedStateRoot,
+		common.Hash(tx.Hash()),
+		p.timer.NowTime(),
+	)
+	if err := p.eventPublisher.AddTaskEvents(ctx, event); err != nil {
+		p.logger.Error().Err(err).Stringer("blockHash", data.MainShardBlockHash).Msg("failed to publish state root updated event")
+	}
+}
 
-	tx, _, err := p.commitBatch(ctx, blobs, batchIndexInBlobStorage)
+// sendProof updates the state of L1 with the batch of the proposal once it is committed by the batch committer,
+// the data proofs are built from the blobs the batch is committed with.
+// False is returned if the batch is not committed yet, so the proposal is postponed.
+func (p *proposer) sendProof(ctx context.Context, data *scTypes.ProposalData) (bool, error) {
+	commitment, err := p.storage.TryGetBatchCommitment(ctx, data.BatchId)
 	if err != nil {
-		return err
+		return false, fmt.Errorf("failed to get commitment of batch %s: %w", data.BatchId, err)
+	}
+
+	if commitment == nil {
+		// commitments are removed from the storage after the batches are finalized
+		return p.isBatchFinalized(ctx, data.BatchId)
 	}
 
-	if err := p.updateState(ctx, tx, data, batchIndexInBlobStorage); err != nil {
+	if commitment.Status != scTypes.BatchCommitCommitted {
+		p.logger.Debug().
+			Stringer(logging.FieldBatchId, data.BatchId).
+			Stringer("commitStatus", commitment.Status).
+			Msg("batch is not committed to L1 yet, postponing the proposal")
+		return false, nil
+	}
+
+	blobs, err := p.blobSource.CommitmentBlobs(commitment)
+	if err != nil {
+		return false, fmt.Errorf("failed to build blobs of batch %s: %w", data.BatchId, err)
+	}
+
+	if err := p.updateState(ctx, data, blobs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isBatchFinalized checks if the batch without commitment is finalized on L1, so its proposal is not needed.
+func (p *proposer) isBatchFinalized(ctx context.Context, batchId scTypes.BatchId) (bool, error) {
+	var finalized bool
+	err := p.retryRunner.Do(ctx, func(context.Context) error {
+		var err error
+		finalized, err = p.rollupContractWrapper.IsBatchFinalized(ctx, batchId.String())
 		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check if batch %s is finalized: %w", batchId, err)
 	}
 
-	return nil
+	if finalized {
+		p.logger.Warn().Stringer(logging.FieldBatchId, batchId).Msg("batch is already finalized, skipping UpdateState tx")
+	} else {
+		p.logger.Warn().Stringer(logging.FieldBatchId, batchId).Msg("batch is not queued for commitment, postponing the proposal")
+	}
+	return finalized, nil
 }
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"math/big"
@@ -10,14 +11,19 @@ import (
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	ethereum "github.com/ethereum/go-ethereum"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -31,7 +37,9 @@ type ProposerTestSuite struct {
 	db               db.DB
 	timer            common.Timer
 	storage          *storage.BlockStorage
+	taskStorage      *storage.TaskStorage
 	ethClient        *rollupcontract.EthClientMock
+	batchCommitter   batches.BatchCommitter
 	proposer         *proposer
 	testData         *types.ProposalData
 	callContractMock *callContractMock
@@ -115,6 +123,7 @@ func (s *ProposerTestSuite) SetupSuite() {
 
 	s.timer = testaide.NewTestTimer()
 	s.storage = storage.NewBlockStorage(s.db, s.timer, metricsHandler, logger)
+	s.taskStorage = storage.NewTaskStorage(s.db, s.timer, metricsHandler, logger)
 	s.params = NewDefaultProposerParams()
 	s.testData = testaide.NewProposalData(3, s.timer.NowTime())
 	s.callContractMock = newCallContractMock()
@@ -137,7 +146,20 @@ func (s *ProposerTestSuite) SetupSuite() {
 			return &ethtypes.Receipt{Status: ethtypes.ReceiptStatusSuccessful}, nil
 		},
 	}
-	s.proposer, err = NewProposer(s.ctx, s.params, s.storage, s.ethClient, metricsHandler, logger)
+	s.batchCommitter = batches.NewBatchCommitter(
+		v1.NewEncoder(logger),
+		blob.NewBuilder(),
+		s.storage,
+		s.ethClient,
+		batches.ContractParams{},
+		s.timer,
+		metricsHandler,
+		logger,
+		batches.DefaultCommitOptions(),
+	)
+	s.proposer, err = NewProposer(
+		s.ctx, s.params, s.storage, s.taskStorage, s.batchCommitter, s.ethClient, s.timer, metricsHandler, logger,
+	)
 	s.Require().NoError(err)
 }
 
@@ -152,56 +174,139 @@ func (s *ProposerTestSuite) TearDownSuite() {
 	s.cancellation()
 }
 
-func (s *ProposerTestSuite) TestSendProof() {
-	// Calls inside CommitBatch
-	s.callContractMock.AddExpectedCall("isBatchCommitted", false)
-	// Calls inside UpdateState
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
+// addCommitment stores the commitment of the test batch, its data takes two blobs
+func (s *ProposerTestSuite) addCommitment(status types.BatchCommitStatus) *types.BatchCommitment {
+	s.T().Helper()
+
+	data := make([]byte, 200_000)
+	_, err := rand.Read(data)
+	s.Require().NoError(err)
+
+	commitment := types.NewBatchCommitment(s.testData.BatchId, data, s.timer.NowTime())
+	s.Require().NoError(s.storage.AddBatchCommitment(s.ctx, commitment))
+	commitment.Status = status
+	s.Require().NoError(s.storage.PutBatchCommitment(s.ctx, commitment))
+	return commitment
+}
+
+func (s *ProposerTestSuite) expectUpdateStateCalls(blobCount int) {
+	for range blobCount {
+		s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
+	}
 	s.callContractMock.AddExpectedCall("isBatchFinalized", false)
 	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
 	s.callContractMock.AddExpectedCall("lastFinalizedBatchIndex", "testingFinalizedBatchIndex")
 	s.callContractMock.AddExpectedCall("finalizedStateRoots", s.testData.OldProvedStateRoot)
+}
+
+// requireUpdateStateTx checks that the sent UpdateState transaction refers to the test batch
+// and carries the data proofs of the blobs the batch is committed with
+func (s *ProposerTestSuite) requireUpdateStateTx(tx *ethtypes.Transaction, blobs []kzg4844.Blob) {
+	s.T().Helper()
+
+	contractAbi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	s.Require().NoError(err)
+	method, err := contractAbi.MethodById(tx.Data()[:4])
+	s.Require().NoError(err)
+	s.Require().Equal("updateState", method.Name)
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	s.Require().NoError(err)
 
-	err := s.proposer.sendProof(s.ctx, s.testData)
+	sidecar, err := rollupcontract.ComputeSidecar(blobs)
+	s.Require().NoError(err)
+	expectedProofs, err := rollupcontract.ComputeDataProofs(sidecar)
+	s.Require().NoError(err)
+
+	s.Require().Equal(s.testData.BatchId.String(), args[0])
+	s.Require().Equal(expectedProofs, args[3])
+}
+
+func (s *ProposerTestSuite) TestSendProof() {
+	commitment := s.addCommitment(types.BatchCommitCommitted)
+	blobs, err := s.batchCommitter.CommitmentBlobs(commitment)
+	s.Require().NoError(err)
+	s.Require().Len(blobs, 2)
+
+	s.expectUpdateStateCalls(len(blobs))
+
+	proposed, err := s.proposer.sendProof(s.ctx, s.testData)
 	s.Require().NoError(err, "failed to send proof")
+	s.Require().True(proposed)
 
 	s.Require().NoError(s.callContractMock.EverythingCalled())
-	s.Require().Len(s.ethClient.SendTransactionCalls(), 2, "wrong number of calls to rpc client")
+	sendCalls := s.ethClient.SendTransactionCalls()
+	s.Require().Len(sendCalls, 1, "only UpdateState tx should be sent")
+	s.requireUpdateStateTx(sendCalls[0].Tx, blobs)
+
+	events, err := s.taskStorage.GetTaskEvents(s.ctx, public.NewTaskEventsRequest(0, public.TaskEventsMaxLimit))
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Require().Equal(public.TaskEventStateRootUpdated, events[0].Type)
+	s.Require().Equal(s.testData.OldProvedStateRoot, *events[0].OldStateRoot)
+	s.Require().Equal(s.testData.NewProvedStateRoot, *events[0].NewStateRoot)
 }
 
-// Only UpdateState tx should be created
-func (s *ProposerTestSuite) TestSendProofCommitedBatch() {
-	// Calls inside CommitBatch
-	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
-	// Calls inside UpdateState
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("isBatchFinalized", false)
-	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
-	s.callContractMock.AddExpectedCall("lastFinalizedBatchIndex", "testingFinalizedBatchIndex")
-	s.callContractMock.AddExpectedCall("finalizedStateRoots", s.testData.OldProvedStateRoot)
+// No tx should be created until the batch committer completes the commitment
+func (s *ProposerTestSuite) TestSendProofNotCommittedBatch() {
+	for _, status := range []types.BatchCommitStatus{types.BatchCommitPending, types.BatchCommitSubmitted} {
+		s.Require().NoError(s.db.DropAll())
+		s.addCommitment(status)
 
-	err := s.proposer.sendProof(s.ctx, s.testData)
-	s.Require().NoError(err, "failed to send proof")
+		proposed, err := s.proposer.sendProof(s.ctx, s.testData)
+		s.Require().NoError(err)
+		s.Require().False(proposed, "proposal should be postponed in status %s", status)
+		s.Require().Empty(s.ethClient.SendTransactionCalls(), "no tx should be created")
+	}
+}
 
+func (s *ProposerTestSuite) TestSendProofFailedUpdateState() {
+	receiptFunc := s.ethClient.TransactionReceiptFunc
+	defer func() { s.ethClient.TransactionReceiptFunc = receiptFunc }()
+	s.ethClient.TransactionReceiptFunc = func(ctx context.Context, txHash ethcommon.Hash) (*ethtypes.Receipt, error) {
+		return &ethtypes.Receipt{Status: ethtypes.ReceiptStatusFailed}, nil
+	}
+
+	s.addCommitment(types.BatchCommitCommitted)
+	s.expectUpdateStateCalls(2)
+
+	_, err := s.proposer.sendProof(s.ctx, s.testData)
+	s.Require().Error(err, "UpdateState tx is reverted")
 	s.Require().Len(s.ethClient.SendTransactionCalls(), 1, "wrong number of calls to rpc client")
+
+	events, err := s.taskStorage.GetTaskEvents(s.ctx, public.NewTaskEventsRequest(0, public.TaskEventsMaxLimit))
+	s.Require().NoError(err)
+	s.Require().Empty(events, "state root is not updated")
 }
 
 // No tx should be created
 func (s *ProposerTestSuite) TestSendProofFinalizedBatch() {
-	// Calls inside CommitBatch
-	s.callContractMock.AddExpectedCall("isBatchCommitted", true)
-	// Calls inside UpdateState
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
-	s.callContractMock.AddExpectedCall("verifyDataProof", noValue{})
+	s.addCommitment(types.BatchCommitCommitted)
 	s.callContractMock.AddExpectedCall("isBatchFinalized", true)
 
-	err := s.proposer.sendProof(s.ctx, s.testData)
+	proposed, err := s.proposer.sendProof(s.ctx, s.testData)
 	s.Require().NoError(err, "failed to send proof")
+	s.Require().True(proposed)
+	s.Require().NoError(s.callContractMock.EverythingCalled())
 
 	s.Require().Empty(s.ethClient.SendTransactionCalls(), "no tx should be created")
+
+	events, err := s.taskStorage.GetTaskEvents(s.ctx, public.NewTaskEventsRequest(0, public.TaskEventsMaxLimit))
+	s.Require().NoError(err)
+	s.Require().Empty(events, "state root is not updated")
+}
+
+// The commitment is pruned after the batch is finalized, the proposal is skipped only if L1 confirms it
+func (s *ProposerTestSuite) TestSendProofWithoutCommitment() {
+	s.callContractMock.AddExpectedCall("isBatchFinalized", false)
+	proposed, err := s.proposer.sendProof(s.ctx, s.testData)
+	s.Require().NoError(err)
+	s.Require().False(proposed)
+
+	s.callContractMock.AddExpectedCall("isBatchFinalized", true)
+	proposed, err = s.proposer.sendProof(s.ctx, s.testData)
+	s.Require().NoError(err)
+	s.Require().True(proposed)
+
+	s.Require().NoError(s.callContractMock.EverythingCalled())
+	s.Require().Empty(s.ethClient.SendTransactionCalls(), "no tx should be created")
 }
@@ -9,6 +9,9 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/versions"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reset"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rollupcontract"
@@ -40,14 +43,39 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 	timer := common.NewTimer()
 	blockStorage := storage.NewBlockStorage(database, timer, metricsHandler, logger)
 	taskStorage := storage.NewTaskStorage(database, timer, metricsHandler, logger)
+	if err := taskStorage.IndexWaitingTasks(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to index waiting tasks: %w", err)
+	}
 
 	// todo: add reset logic to TaskStorage (implement StateResetter interface) and pass it here in https://github.com/NilFoundation/nil/pull/419
 	stateResetter := reset.NewStateResetter(logger, blockStorage)
 
+	batchEncoder, err := versions.NewRegistry(logger).Encoder(cfg.BatchEncodingVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch encoder: %w", err)
+	}
+
+	batchCommitter := batches.NewBatchCommitter(
+		batchEncoder,
+		blob.NewBuilder(),
+		blockStorage,
+		ethClient,
+		batches.ContractParams{
+			ContractAddress:  cfg.ProposerParams.ContractAddress,
+			PrivateKey:       cfg.ProposerParams.PrivateKey,
+			EthClientTimeout: cfg.ProposerParams.EthClientTimeout,
+		},
+		timer,
+		metricsHandler,
+		logger,
+		batches.DefaultCommitOptions(),
+	)
+
 	agg := NewAggregator(
 		client,
 		blockStorage,
 		taskStorage,
+		batchCommitter,
 		stateResetter,
 		timer,
 		logger,
@@ -61,7 +89,10 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 		ctx,
 		cfg.ProposerParams,
 		blockStorage,
+		taskStorage,
+		batchCommitter,
 		ethClient,
+		timer,
 		metricsHandler,
 		logger,
 	)
@@ -75,7 +106,7 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 
 	taskScheduler := scheduler.New(
 		taskStorage,
-		newTaskStateChangeHandler(blockStorage, resetLauncher, logger),
+		newTaskStateChangeHandler(blockStorage, taskStorage, resetLauncher, timer, logger),
 		metricsHandler,
 		logger,
 	)
@@ -86,10 +117,13 @@ func New(cfg *Config, database db.DB, ethClient rollupcontract.EthClient) (*Sync
 		logger,
 	)
 
-	syncCommittee.Service = srv.NewService(
-		logger,
-		prop, agg, taskScheduler, taskListener,
-	)
+	workers := []srv.Worker{prop, agg, batchCommitter, taskScheduler, taskListener}
+	if cfg.TaskEventWebhookParams.Endpoint != "" {
+		logger.Info().Msgf("Deliver task events to webhook %v", cfg.TaskEventWebhookParams.Endpoint)
+		workers = append(workers, newTaskEventWebhook(cfg.TaskEventWebhookParams, taskStorage, metricsHandler, logger))
+	}
+
+	syncCommittee.Service = srv.NewService(logger, workers...)
 
 	return syncCommittee, nil
 }
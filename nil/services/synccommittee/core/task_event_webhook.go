@@ -0,0 +1,185 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/rs/zerolog"
+)
+
+// taskEventWebhookCursor is the name of the cursor pointing to the last event delivered to the webhook.
+const taskEventWebhookCursor = "webhook"
+
+type TaskEventWebhookParams struct {
+	// Endpoint is the URL events are posted to, delivery is disabled if it is empty.
+	Endpoint  string
+	BatchSize int
+	// CheckInterval is the delay between the checks for new events after all the pending ones are delivered.
+	CheckInterval  time.Duration
+	RequestTimeout time.Duration
+	MaxRetries     uint32
+	RetryDelay     time.Duration
+	MaxRetryDelay  time.Duration
+}
+
+func NewDefaultTaskEventWebhookParams() *TaskEventWebhookParams {
+	return &TaskEventWebhookParams{
+		BatchSize:      100,
+		CheckInterval:  5 * time.Second,
+		RequestTimeout: 10 * time.Second,
+		MaxRetries:     5,
+		RetryDelay:     100 * time.Millisecond,
+		MaxRetryDelay:  10 * time.Second,
+	}
+}
+
+type TaskEventSource interface {
+	GetTaskEvents(ctx context.Context, request *public.TaskEventsRequest) ([]*public.TaskEvent, error)
+
+	GetTaskEventCursor(ctx context.Context, name string) (uint64, error)
+
+	SetTaskEventCursor(ctx context.Context, name string, seq uint64) error
+}
+
+type TaskEventWebhookMetrics interface {
+	metrics.BasicMetrics
+}
+
+// taskEventWebhook delivers task lifecycle events to the configured HTTP endpoint.
+// Events are posted as a JSON array in the order they were added to the log,
+// the delivery is at-least-once: a batch is resent until the endpoint responds with 2xx status.
+// Events removed from the log by its retention limit before they were delivered
+// are reported as an error, the endpoint can detect them by the gap in the sequence numbers.
+type taskEventWebhook struct {
+	srv.WorkerLoop
+
+	params      *TaskEventWebhookParams
+	source      TaskEventSource
+	httpClient  *http.Client
+	retryRunner common.RetryRunner
+	metrics     TaskEventWebhookMetrics
+	logger      zerolog.Logger
+}
+
+func newTaskEventWebhook(
+	params *TaskEventWebhookParams,
+	source TaskEventSource,
+	metrics TaskEventWebhookMetrics,
+	logger zerolog.Logger,
+) *taskEventWebhook {
+	webhook := &taskEventWebhook{
+		params:     params,
+		source:     source,
+		httpClient: &http.Client{Timeout: params.RequestTimeout},
+		metrics:    metrics,
+	}
+
+	webhook.WorkerLoop = srv.NewWorkerLoop("task_event_webhook", params.CheckInterval, webhook.runIteration)
+	webhook.logger = srv.WorkerLogger(logger, webhook)
+	webhook.retryRunner = common.NewRetryRunner(
+		common.RetryConfig{
+			ShouldRetry: common.LimitRetries(params.MaxRetries),
+			NextDelay:   common.DelayExponential(params.RetryDelay, params.MaxRetryDelay),
+		},
+		webhook.logger,
+	)
+	return webhook
+}
+
+func (w *taskEventWebhook) runIteration(ctx context.Context) {
+	if err := w.deliverPending(ctx); err != nil {
+		w.logger.Error().Err(err).Msg("failed to deliver task events")
+		w.metrics.RecordError(ctx, w.Name())
+	}
+}
+
+// deliverPending posts the undelivered events to the endpoint batch by batch until all of them are delivered.
+func (w *taskEventWebhook) deliverPending(ctx context.Context) error {
+	cursor, err := w.source.GetTaskEventCursor(ctx, taskEventWebhookCursor)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook cursor: %w", err)
+	}
+
+	for ctx.Err() == nil {
+		events, err := w.deliverBatch(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		if len(events) < w.params.BatchSize {
+			return nil
+		}
+		cursor = events[len(events)-1].Seq
+	}
+	return nil
+}
+
+// deliverBatch posts the next batch of events added after the cursor and moves the cursor to the last of them.
+func (w *taskEventWebhook) deliverBatch(ctx context.Context, cursor uint64) ([]*public.TaskEvent, error) {
+	events, err := w.source.GetTaskEvents(ctx, public.NewTaskEventsRequest(cursor, w.params.BatchSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task events: %w", err)
+	}
+	if len(events) == 0 {
+		w.logger.Trace().Uint64("cursor", cursor).Msg("no task events to deliver")
+		return nil, nil
+	}
+
+	if missed := public.MissedTaskEvents(cursor, events); missed > 0 {
+		w.logger.Error().
+			Uint64("cursor", cursor).
+			Uint64("firstSeq", events[0].Seq).
+			Uint64("missedCount", missed).
+			Msg("task events were removed from the log before delivery, the endpoint misses them")
+		w.metrics.RecordError(ctx, w.Name())
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task events: %w", err)
+	}
+
+	err = w.retryRunner.Do(ctx, func(ctx context.Context) error {
+		return w.post(ctx, body)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to post task events: %w", err)
+	}
+
+	lastSeq := events[len(events)-1].Seq
+	w.logger.Debug().
+		Int("eventCount", len(events)).
+		Uint64("lastSeq", lastSeq).
+		Msg("task events delivered")
+
+	if err := w.source.SetTaskEventCursor(ctx, taskEventWebhookCursor, lastSeq); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (w *taskEventWebhook) post(ctx context.Context, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.params.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := w.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskEventWebhookTestSuite struct {
+	suite.Suite
+
+	ctx          context.Context
+	cancellation context.CancelFunc
+
+	db          db.DB
+	taskStorage *storage.TaskStorage
+	params      *TaskEventWebhookParams
+	webhook     *taskEventWebhook
+	endpoint    *httptest.Server
+
+	mu             sync.Mutex
+	failedRequests int
+	requests       int
+	received       []*public.TaskEvent
+}
+
+// prunedEventSource hides the events preceding oldestSeq as if they were removed from the log
+type prunedEventSource struct {
+	*storage.TaskStorage
+	oldestSeq uint64
+}
+
+func (s *prunedEventSource) GetTaskEvents(
+	ctx context.Context,
+	request *public.TaskEventsRequest,
+) ([]*public.TaskEvent, error) {
+	if request.AfterSeq+1 < s.oldestSeq {
+		request = public.NewTaskEventsRequest(s.oldestSeq-1, request.Limit)
+	}
+	return s.TaskStorage.GetTaskEvents(ctx, request)
+}
+
+type errorCountingMetrics struct {
+	errors atomic.Int32
+}
+
+func (m *errorCountingMetrics) RecordError(context.Context, string) {
+	m.errors.Add(1)
+}
+
+func TestTaskEventWebhookTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TaskEventWebhookTestSuite))
+}
+
+func (s *TaskEventWebhookTestSuite) SetupSuite() {
+	s.ctx, s.cancellation = context.WithCancel(context.Background())
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	metricsHandler, err := metrics.NewSyncCommitteeMetrics()
+	s.Require().NoError(err)
+	logger := logging.NewLogger("task_event_webhook_test")
+
+	s.taskStorage = storage.NewTaskStorage(s.db, testaide.NewTestTimer(), metricsHandler, logger)
+	s.endpoint = httptest.NewServer(http.HandlerFunc(s.handleEvents))
+
+	s.params = NewDefaultTaskEventWebhookParams()
+	s.params.Endpoint = s.endpoint.URL
+	s.params.BatchSize = 2
+	s.params.MaxRetries = 3
+	s.params.RetryDelay = time.Millisecond
+	s.params.MaxRetryDelay = time.Millisecond
+	s.webhook = newTaskEventWebhook(s.params, s.taskStorage, metricsHandler, logger)
+}
+
+func (s *TaskEventWebhookTestSuite) TearDownSuite() {
+	s.endpoint.Close()
+	s.cancellation()
+}
+
+func (s *TaskEventWebhookTestSuite) SetupTest() {
+	s.Require().NoError(s.db.DropAll(), "failed to clear database in SetupTest")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failedRequests = 0
+	s.requests = 0
+	s.received = nil
+}
+
+// handleEvents responds with an error to the number of requests set in failedRequests
+func (s *TaskEventWebhookTestSuite) handleEvents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if s.failedRequests > 0 {
+		s.failedRequests--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var events []*public.TaskEvent
+	if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.received = append(s.received, events...)
+}
+
+func (s *TaskEventWebhookTestSuite) addTaskEntries(count int) {
+	s.T().Helper()
+	for range count {
+		entry := testaide.NewTaskEntry(time.Now(), types.WaitingForExecutor, types.UnknownExecutorId)
+		s.Require().NoError(s.taskStorage.AddTaskEntries(s.ctx, entry))
+	}
+}
+
+func (s *TaskEventWebhookTestSuite) requireDelivered(expectedSeq ...uint64) {
+	s.T().Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var actualSeq []uint64
+	for _, event := range s.received {
+		actualSeq = append(actualSeq, event.Seq)
+	}
+	s.Require().Equal(expectedSeq, actualSeq)
+}
+
+func (s *TaskEventWebhookTestSuite) requireRequests(expected int) {
+	s.T().Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Require().Equal(expected, s.requests)
+}
+
+func (s *TaskEventWebhookTestSuite) Test_Delivers_Events_In_Batches() {
+	s.addTaskEntries(5)
+
+	// all the pending events are delivered in a single iteration
+	s.Require().NoError(s.webhook.deliverPending(s.ctx))
+	s.requireDelivered(1, 2, 3, 4, 5)
+	s.requireRequests(3)
+
+	// nothing new to deliver
+	s.Require().NoError(s.webhook.deliverPending(s.ctx))
+	s.requireDelivered(1, 2, 3, 4, 5)
+	s.requireRequests(3)
+
+	s.addTaskEntries(2)
+	s.Require().NoError(s.webhook.deliverPending(s.ctx))
+	s.requireDelivered(1, 2, 3, 4, 5, 6, 7)
+	s.requireRequests(4)
+
+	cursor, err := s.taskStorage.GetTaskEventCursor(s.ctx, taskEventWebhookCursor)
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(7), cursor)
+}
+
+func (s *TaskEventWebhookTestSuite) Test_Reports_Pruned_Events() {
+	s.addTaskEntries(5)
+
+	metricsHandler := &errorCountingMetrics{}
+	source := &prunedEventSource{TaskStorage: s.taskStorage, oldestSeq: 4}
+	webhook := newTaskEventWebhook(s.params, source, metricsHandler, logging.NewLogger("task_event_webhook_test"))
+
+	// the remaining events are delivered, the missed ones are reported
+	s.Require().NoError(webhook.deliverPending(s.ctx))
+	s.requireDelivered(4, 5)
+	s.Require().Equal(int32(1), metricsHandler.errors.Load())
+
+	s.addTaskEntries(1)
+	s.Require().NoError(webhook.deliverPending(s.ctx))
+	s.requireDelivered(4, 5, 6)
+	s.Require().Equal(int32(1), metricsHandler.errors.Load())
+}
+
+func (s *TaskEventWebhookTestSuite) Test_Retries_Failed_Delivery() {
+	s.addTaskEntries(1)
+
+	s.mu.Lock()
+	s.failedRequests = 2
+	s.mu.Unlock()
+
+	s.Require().NoError(s.webhook.deliverPending(s.ctx))
+	s.requireDelivered(1)
+}
+
+func (s *TaskEventWebhookTestSuite) Test_Keeps_Cursor_If_Retries_Exhausted() {
+	s.addTaskEntries(1)
+
+	s.mu.Lock()
+	s.failedRequests = 3
+	s.mu.Unlock()
+
+	s.Require().ErrorContains(s.webhook.deliverPending(s.ctx), "503")
+	s.requireDelivered()
+
+	cursor, err := s.taskStorage.GetTaskEventCursor(s.ctx, taskEventWebhookCursor)
+	s.Require().NoError(err)
+	s.Require().Zero(cursor)
+
+	// the same events are delivered on the next iteration
+	s.Require().NoError(s.webhook.deliverPending(s.ctx))
+	s.requireDelivered(1)
+}
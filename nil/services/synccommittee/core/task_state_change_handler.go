@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/api"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/log"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	"github.com/rs/zerolog"
 )
 
@@ -16,24 +17,35 @@ type ProvedBlockSetter interface {
 	SetBlockAsProved(ctx context.Context, blockId types.BlockId) error
 }
 
+// TaskEventPublisher appends lifecycle events to the task event log.
+type TaskEventPublisher interface {
+	AddTaskEvents(ctx context.Context, events ...*public.TaskEvent) error
+}
+
 type StateResetLauncher interface {
 	LaunchPartialResetWithSuspension(ctx context.Context, failedMainBlockHash common.Hash) error
 }
 
 type taskStateChangeHandler struct {
 	blockSetter        ProvedBlockSetter
+	eventPublisher     TaskEventPublisher
 	stateResetLauncher StateResetLauncher
+	timer              common.Timer
 	logger             zerolog.Logger
 }
 
 func newTaskStateChangeHandler(
 	blockSetter ProvedBlockSetter,
+	eventPublisher TaskEventPublisher,
 	stateResetLauncher StateResetLauncher,
+	timer common.Timer,
 	logger zerolog.Logger,
 ) api.TaskStateChangeHandler {
 	return &taskStateChangeHandler{
 		blockSetter:        blockSetter,
+		eventPublisher:     eventPublisher,
 		stateResetLauncher: stateResetLauncher,
+		timer:              timer,
 		logger:             logger,
 	}
 }
@@ -74,5 +86,11 @@ func (h *taskStateChangeHandler) onTaskSuccess(ctx context.Context, task *types.
 		return fmt.Errorf("failed to set block with id=%s as proved: %w", blockId, err)
 	}
 
+	// event log is informational, failure to publish the event must not affect the proving flow
+	event := public.NewBatchProvedEvent(task, h.timer.NowTime())
+	if err := h.eventPublisher.AddTaskEvents(ctx, event); err != nil {
+		log.NewTaskEvent(h.logger, zerolog.ErrorLevel, task).Err(err).Msg("failed to publish batch proved event")
+	}
+
 	return nil
 }
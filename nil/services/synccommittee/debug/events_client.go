@@ -0,0 +1,11 @@
+package debug
+
+import (
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/rpc"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/rs/zerolog"
+)
+
+func NewEventsClient(endpoint string, logger zerolog.Logger) public.TaskEventsApi {
+	return rpc.NewTaskEventsRpcClient(endpoint, logger)
+}
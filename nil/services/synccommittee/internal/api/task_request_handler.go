@@ -14,10 +14,12 @@ const (
 
 type TaskRequest struct {
 	ExecutorId types.TaskExecutorId `json:"executorId"`
+	// Capabilities define the tasks which can be assigned to the executor
+	Capabilities types.ExecutorCapabilities `json:"capabilities"`
 }
 
-func NewTaskRequest(executorId types.TaskExecutorId) *TaskRequest {
-	return &TaskRequest{ExecutorId: executorId}
+func NewTaskRequest(executorId types.TaskExecutorId, capabilities types.ExecutorCapabilities) *TaskRequest {
+	return &TaskRequest{ExecutorId: executorId, Capabilities: capabilities}
 }
 
 type TaskRequestHandler interface {
@@ -21,6 +21,8 @@ const (
 
 type Config struct {
 	TaskPollingInterval time.Duration
+	// Capabilities are sent to the scheduler to get only the tasks the executor is able to execute
+	Capabilities types.ExecutorCapabilities
 }
 
 func DefaultConfig() *Config {
@@ -86,7 +88,7 @@ func (p *taskExecutorImpl) runIteration(ctx context.Context) {
 }
 
 func (p *taskExecutorImpl) fetchAndHandleTask(ctx context.Context) error {
-	taskRequest := api.NewTaskRequest(p.nonceId)
+	taskRequest := api.NewTaskRequest(p.nonceId, p.config.Capabilities)
 	task, err := p.requestHandler.GetTask(ctx, taskRequest)
 	if err != nil {
 		return err
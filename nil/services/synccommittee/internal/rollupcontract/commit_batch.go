@@ -18,20 +18,23 @@ import (
 	"github.com/holiman/uint256"
 )
 
-// CommitBatch creates blob transaction for `CommitBatch` contract method and sends it on chain. If such `batchIndex` is already
-// submitted, returns `signedTx, ErrBatchAlreadyCommitted`, so `signedTx` could be used later for accessing prepared blobs fields.
-func (r *Wrapper) CommitBatch(
+// TxReplacement defines the transaction to be replaced by a new one. The new transaction uses the same nonce,
+// and its fees are not lower than the given ones.
+type TxReplacement struct {
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	BlobFeeCap *big.Int
+}
+
+// CreateCommitBatchTx creates signed blob transaction for `CommitBatch` contract method without sending it.
+// If `replacement` is not nil, the transaction replaces the one with the same nonce.
+func (r *Wrapper) CreateCommitBatchTx(
 	ctx context.Context,
 	blobs []kzg4844.Blob,
 	batchIndex string,
+	replacement *TxReplacement,
 ) (*ethtypes.Transaction, error) {
-	callOpts, cancel := r.getEthCallOpts(ctx)
-	defer cancel()
-	isCommited, err := r.rollupContract.IsBatchCommitted(callOpts, batchIndex)
-	if err != nil {
-		return nil, err
-	}
-
 	publicKeyECDSA, ok := r.privateKey.Public().(*ecdsa.PublicKey)
 	if !ok {
 		return nil, errors.New("error casting public key to ECDSA")
@@ -42,7 +45,7 @@ func (r *Wrapper) CommitBatch(
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, r.requestTimeout)
 	defer cancel()
 
-	blobTx, err := r.createBlobTx(ctxWithTimeout, blobs, address, batchIndex)
+	blobTx, err := r.createBlobTx(ctxWithTimeout, blobs, address, batchIndex, replacement)
 	if err != nil {
 		return nil, err
 	}
@@ -52,25 +55,11 @@ func (r *Wrapper) CommitBatch(
 		return nil, fmt.Errorf("creating keyed transactor with chain ID: %w", err)
 	}
 
-	signedTx, err := keyedTransactor.Signer(address, blobTx)
-	if err != nil {
-		return nil, err
-	}
-
-	if isCommited {
-		return signedTx, ErrBatchAlreadyCommitted
-	}
-
-	err = r.ethClient.SendTransaction(ctxWithTimeout, signedTx)
-	if err != nil {
-		return nil, err
-	}
-
-	return signedTx, nil
+	return keyedTransactor.Signer(address, blobTx)
 }
 
-// computeSidecar handles all KZG commitment related computations
-func computeSidecar(blobs []kzg4844.Blob) (*ethtypes.BlobTxSidecar, error) {
+// ComputeSidecar handles all KZG commitment related computations
+func ComputeSidecar(blobs []kzg4844.Blob) (*ethtypes.BlobTxSidecar, error) {
 	commitments := make([]kzg4844.Commitment, 0, len(blobs))
 	proofs := make([]kzg4844.Proof, 0, len(blobs))
 
@@ -106,10 +95,21 @@ type txParams struct {
 }
 
 // computeTxParams fetches and computes all necessary transaction parameters
-func (r *Wrapper) computeTxParams(ctx context.Context, from ethcommon.Address, blobCount int) (*txParams, error) {
-	nonce, err := r.ethClient.PendingNonceAt(ctx, from)
-	if err != nil {
-		return nil, fmt.Errorf("getting nonce: %w", err)
+func (r *Wrapper) computeTxParams(
+	ctx context.Context,
+	from ethcommon.Address,
+	blobCount int,
+	replacement *TxReplacement,
+) (*txParams, error) {
+	var nonce uint64
+	if replacement != nil {
+		nonce = replacement.Nonce
+	} else {
+		var err error
+		nonce, err = r.ethClient.PendingNonceAt(ctx, from)
+		if err != nil {
+			return nil, fmt.Errorf("getting nonce: %w", err)
+		}
 	}
 
 	gasTipCap, err := r.ethClient.SuggestGasTipCap(ctx)
@@ -135,6 +135,12 @@ func (r *Wrapper) computeTxParams(ctx context.Context, from ethcommon.Address, b
 	blobFee := eip4844.CalcBlobFee(*head.ExcessBlobGas)
 	gas := ethparams.BlobTxBlobGasPerBlob * uint64(blobCount)
 
+	if replacement != nil {
+		gasTipCap = maxBigInt(gasTipCap, replacement.GasTipCap)
+		gasFeeCap = maxBigInt(gasFeeCap, replacement.GasFeeCap, gasTipCap)
+		blobFee = maxBigInt(blobFee, replacement.BlobFeeCap)
+	}
+
 	return &txParams{
 		Nonce:      nonce,
 		GasTipCap:  gasTipCap,
@@ -145,15 +151,21 @@ func (r *Wrapper) computeTxParams(ctx context.Context, from ethcommon.Address, b
 }
 
 // createBlobTx creates a new blob transaction using the computed blob data and transaction parameters
-func (r *Wrapper) createBlobTx(ctx context.Context, blobs []kzg4844.Blob, from ethcommon.Address, batchIndex string) (*ethtypes.Transaction, error) {
+func (r *Wrapper) createBlobTx(
+	ctx context.Context,
+	blobs []kzg4844.Blob,
+	from ethcommon.Address,
+	batchIndex string,
+	replacement *TxReplacement,
+) (*ethtypes.Transaction, error) {
 	startTime := time.Now()
-	sidecar, err := computeSidecar(blobs)
+	sidecar, err := ComputeSidecar(blobs)
 	if err != nil {
 		return nil, fmt.Errorf("computing blob data: %w", err)
 	}
 	r.logger.Info().Dur("elapsedTime", time.Since(startTime)).Int("blobsLen", len(blobs)).Msg("blob proof computed")
 
-	txParams, err := r.computeTxParams(ctx, from, len(blobs))
+	txParams, err := r.computeTxParams(ctx, from, len(blobs), replacement)
 	if err != nil {
 		return nil, fmt.Errorf("computing tx params: %w", err)
 	}
@@ -189,3 +201,13 @@ func (r *Wrapper) createBlobTx(ctx context.Context, blobs []kzg4844.Blob, from e
 
 	return ethtypes.NewTx(b), nil
 }
+
+func maxBigInt(first *big.Int, rest ...*big.Int) *big.Int {
+	result := first
+	for _, v := range rest {
+		if v != nil && v.Cmp(result) > 0 {
+			result = v
+		}
+	}
+	return result
+}
@@ -4,5 +4,13 @@ import "errors"
 
 var (
 	ErrBatchAlreadyFinalized = errors.New("batch already finalized")
-	ErrBatchAlreadyCommitted = errors.New("batch already committed")
+	ErrReplaceUnderpriced    = errors.New("replacement transaction underpriced")
+	ErrNonceTooLow           = errors.New("nonce too low")
+)
+
+// Messages of the L1 node errors (see core/txpool and core packages of go-ethereum)
+const (
+	txAlreadyKnownMsg     = "already known"
+	replaceUnderpricedMsg = "replacement transaction underpriced"
+	nonceTooLowMsg        = "nonce too low"
 )
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -117,6 +118,64 @@ func (r *Wrapper) FinalizedBatchIndex(ctx context.Context) (string, error) {
 	return r.rollupContract.GetLastFinalizedBatchIndex(callOpts)
 }
 
+func (r *Wrapper) IsBatchCommitted(ctx context.Context, batchIndex string) (bool, error) {
+	callOpts, cancel := r.getEthCallOpts(ctx)
+	defer cancel()
+	return r.rollupContract.IsBatchCommitted(callOpts, batchIndex)
+}
+
+func (r *Wrapper) IsBatchFinalized(ctx context.Context, batchIndex string) (bool, error) {
+	callOpts, cancel := r.getEthCallOpts(ctx)
+	defer cancel()
+	return r.rollupContract.IsBatchFinalized(callOpts, batchIndex)
+}
+
+// SendTransaction sends signed transaction to L1. Errors caused by the transaction nonce and fees
+// are returned as ErrNonceTooLow and ErrReplaceUnderpriced, resending the known transaction is not an error.
+func (r *Wrapper) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, r.requestTimeout)
+	defer cancel()
+
+	// Errors are received over RPC, so only their messages can be checked
+	err := r.ethClient.SendTransaction(ctxWithTimeout, tx)
+	switch {
+	case err == nil:
+		return nil
+	case strings.Contains(err.Error(), txAlreadyKnownMsg):
+		return nil
+	case strings.Contains(err.Error(), replaceUnderpricedMsg):
+		return fmt.Errorf("%w: %w", ErrReplaceUnderpriced, err)
+	case strings.Contains(err.Error(), nonceTooLowMsg):
+		return fmt.Errorf("%w: %w", ErrNonceTooLow, err)
+	default:
+		return err
+	}
+}
+
+// TransactionReceipt returns the receipt of the transaction, or nil if the transaction is not included yet.
+func (r *Wrapper) TransactionReceipt(ctx context.Context, txnHash ethcommon.Hash) (*ethtypes.Receipt, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, r.requestTimeout)
+	defer cancel()
+
+	receipt, err := r.ethClient.TransactionReceipt(ctxWithTimeout, txnHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	return receipt, err
+}
+
+// LatestBlockNumber returns the number of the latest L1 block.
+func (r *Wrapper) LatestBlockNumber(ctx context.Context) (uint64, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, r.requestTimeout)
+	defer cancel()
+
+	header, err := r.ethClient.HeaderByNumber(ctxWithTimeout, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
 // WaitForReceipt repeatedly tries to get tx receipt, retrying on `NotFound` error (tx not mined yet).
 // In case `ReceiptWaitFor` timeout is reached, returns `(nil, nil)`.
 func (r *Wrapper) WaitForReceipt(ctx context.Context, txnHash ethcommon.Hash) (*ethtypes.Receipt, error) {
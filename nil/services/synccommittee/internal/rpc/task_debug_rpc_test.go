@@ -25,8 +25,9 @@ type TaskSchedulerDebugRpcTestSuite struct {
 	context      context.Context
 	cancellation context.CancelFunc
 
-	rpcClient public.TaskDebugApi
-	scheduler scheduler.TaskScheduler
+	rpcClient    public.TaskDebugApi
+	eventsClient public.TaskEventsApi
+	scheduler    scheduler.TaskScheduler
 
 	database db.DB
 	storage  *storage.TaskStorage
@@ -102,6 +103,7 @@ func (s *TaskSchedulerDebugRpcTestSuite) SetupSuite() {
 	s.Require().NoError(err, "task listener did not start in time")
 
 	s.rpcClient = NewTaskDebugRpcClient(listenerEndpoint, logger)
+	s.eventsClient = NewTaskEventsRpcClient(listenerEndpoint, logger)
 }
 
 func (s *TaskSchedulerDebugRpcTestSuite) TearDownTest() {
@@ -351,7 +353,7 @@ func (s *TaskSchedulerDebugRpcTestSuite) requestAndSendResult(
 ) {
 	s.T().Helper()
 
-	taskToExec, err := s.storage.RequestTaskToExecute(s.context, executor)
+	taskToExec, err := s.storage.RequestTaskToExecute(s.context, executor, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExec)
 	s.Require().Equal(expected, taskToExec)
@@ -457,3 +459,26 @@ func (s *TaskSchedulerDebugRpcTestSuite) requireAllPass(assertions []bool, id ty
 		s.FailNowf("", "assertion for task with id=%s failed", id.String())
 	}
 }
+
+func (s *TaskSchedulerDebugRpcTestSuite) Test_Get_Task_Events() {
+	entries := newTaskEntries(s.timer.NowTime())
+	err := s.storage.AddTaskEntries(s.context, entries...)
+	s.Require().NoError(err)
+
+	events, err := s.eventsClient.GetTaskEvents(s.context, public.NewTaskEventsRequest(0, public.TaskEventsMaxLimit))
+	s.Require().NoError(err)
+	s.Require().Len(events, len(entries))
+	for i, event := range events {
+		s.Require().Equal(uint64(i+1), event.Seq)
+		s.Require().Equal(public.TaskEventCreated, event.Type)
+		s.Require().Equal(entries[i].Task.Id, *event.TaskId)
+	}
+
+	events, err = s.eventsClient.GetTaskEvents(s.context, public.NewTaskEventsRequest(uint64(len(entries)-1), 1))
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Require().Equal(uint64(len(entries)), events[0].Seq)
+
+	_, err = s.eventsClient.GetTaskEvents(s.context, public.NewTaskEventsRequest(0, public.TaskEventsMaxLimit+1))
+	s.Require().Error(err)
+}
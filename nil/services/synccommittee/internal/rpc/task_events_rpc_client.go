@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/rs/zerolog"
+)
+
+type taskEventsRpcClient struct {
+	client client.RawClient
+}
+
+func NewTaskEventsRpcClient(apiEndpoint string, logger zerolog.Logger) public.TaskEventsApi {
+	return &taskEventsRpcClient{
+		client: NewRetryClient(apiEndpoint, logger),
+	}
+}
+
+func (c *taskEventsRpcClient) GetTaskEvents(
+	ctx context.Context,
+	request *public.TaskEventsRequest,
+) ([]*public.TaskEvent, error) {
+	return doRPCCall[*public.TaskEventsRequest, []*public.TaskEvent](
+		ctx,
+		c.client,
+		public.EventsGetTaskEvents,
+		request,
+	)
+}
@@ -62,6 +62,12 @@ func (l *TaskListener) Run(context context.Context, started chan<- struct{}) err
 			Service:   public.TaskDebugApi(l.scheduler),
 			Version:   "1.0",
 		},
+		{
+			Namespace: public.EventsNamespace,
+			Public:    true,
+			Service:   public.TaskEventsApi(l.scheduler),
+			Version:   "1.0",
+		},
 	}
 
 	l.logger.Info().Msgf("Open task listener endpoint %v", l.config.HttpEndpoint)
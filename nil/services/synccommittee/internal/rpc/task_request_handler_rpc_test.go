@@ -35,7 +35,7 @@ func (s *TaskRequestHandlerTestSuite) Test_TaskRequestHandler_GetTask() {
 func (s *TaskRequestHandlerTestSuite) testGetTask(executorId types.TaskExecutorId) {
 	s.T().Helper()
 
-	request := api.NewTaskRequest(executorId)
+	request := api.NewTaskRequest(executorId, types.ExecutorCapabilities{})
 	receivedTask, err := s.clientHandler.GetTask(s.context, request)
 	s.Require().NoError(err)
 	getTaskCalls := s.scheduler.GetTaskCalls()
@@ -35,6 +35,7 @@ type TaskScheduler interface {
 	srv.Worker
 	api.TaskRequestHandler
 	public.TaskDebugApi
+	public.TaskEventsApi
 }
 
 type Storage interface {
@@ -44,11 +45,17 @@ type Storage interface {
 
 	GetTaskTreeView(ctx context.Context, taskId types.TaskId) (*public.TaskTreeView, error)
 
-	RequestTaskToExecute(ctx context.Context, executor types.TaskExecutorId) (*types.Task, error)
+	RequestTaskToExecute(
+		ctx context.Context,
+		executor types.TaskExecutorId,
+		capabilities types.ExecutorCapabilities,
+	) (*types.Task, error)
 
 	ProcessTaskResult(ctx context.Context, res *types.TaskResult) error
 
 	RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout time.Duration) error
+
+	GetTaskEvents(ctx context.Context, request *public.TaskEventsRequest) ([]*public.TaskEvent, error)
 }
 
 type Metrics interface {
@@ -94,7 +101,7 @@ func (s *taskSchedulerImpl) runIteration(ctx context.Context) {
 func (s *taskSchedulerImpl) GetTask(ctx context.Context, request *api.TaskRequest) (*types.Task, error) {
 	s.logger.Debug().Stringer(logging.FieldTaskExecutorId, request.ExecutorId).Msg("received new task request")
 
-	task, err := s.storage.RequestTaskToExecute(ctx, request.ExecutorId)
+	task, err := s.storage.RequestTaskToExecute(ctx, request.ExecutorId, request.Capabilities)
 	if err != nil {
 		s.logger.Error().
 			Err(err).
@@ -231,6 +238,22 @@ func (s *taskSchedulerImpl) GetTaskTree(ctx context.Context, taskId types.TaskId
 	return s.storage.GetTaskTreeView(ctx, taskId)
 }
 
+func (s *taskSchedulerImpl) GetTaskEvents(
+	ctx context.Context,
+	request *public.TaskEventsRequest,
+) ([]*public.TaskEvent, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	events, err := s.storage.GetTaskEvents(ctx, request)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to get task events from the storage")
+		return nil, err
+	}
+	return events, nil
+}
+
 func (s *taskSchedulerImpl) onTaskResultError(ctx context.Context, cause error, result *types.TaskResult) error {
 	log.NewTaskResultEvent(s.logger, zerolog.ErrorLevel, result).Err(cause).Msg("Failed to process task result")
 	s.recordError(ctx)
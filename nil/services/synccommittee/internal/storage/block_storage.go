@@ -352,6 +352,7 @@ func (bs *BlockStorage) TryGetNextProposalData(ctx context.Context) (*scTypes.Pr
 
 	return &scTypes.ProposalData{
 		MainShardBlockHash: mainShardEntry.Block.Hash,
+		BatchId:            mainShardEntry.BatchId,
 		Transactions:       transactions,
 		OldProvedStateRoot: *currentProvedStateRoot,
 		NewProvedStateRoot: mainShardEntry.Block.ChildBlocksRootHash,
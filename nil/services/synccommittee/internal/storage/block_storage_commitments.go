@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// batchCommitmentsTable stores the state of batch commitments to L1 until the batches are finalized.
+// Key: scTypes.BatchId, Value: scTypes.BatchCommitment.
+const batchCommitmentsTable db.TableName = "batch_commitments"
+
+// AddBatchCommitment saves the new batch commitment and assigns the next sequence number to it.
+// If the commitment of the batch already exists, it is left unchanged.
+func (bs *BlockStorage) AddBatchCommitment(ctx context.Context, commitment *scTypes.BatchCommitment) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		return bs.addBatchCommitmentImpl(ctx, commitment)
+	})
+}
+
+func (bs *BlockStorage) addBatchCommitmentImpl(ctx context.Context, commitment *scTypes.BatchCommitment) error {
+	tx, err := bs.database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	commitments, err := bs.getBatchCommitmentsTx(tx)
+	if err != nil {
+		return err
+	}
+
+	commitment.Seq = 1
+	for _, c := range commitments {
+		if c.BatchId == commitment.BatchId {
+			bs.logger.Debug().Stringer(logging.FieldBatchId, c.BatchId).Msg("batch commitment already exists")
+			return nil
+		}
+		commitment.Seq = max(commitment.Seq, c.Seq+1)
+	}
+
+	if err := bs.putBatchCommitmentTx(tx, commitment); err != nil {
+		return err
+	}
+	return bs.commit(tx)
+}
+
+// PutBatchCommitment saves the state of the batch commitment.
+func (bs *BlockStorage) PutBatchCommitment(ctx context.Context, commitment *scTypes.BatchCommitment) error {
+	return bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := bs.database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := bs.putBatchCommitmentTx(tx, commitment); err != nil {
+			return err
+		}
+		return bs.commit(tx)
+	})
+}
+
+// TryGetBatchCommitment retrieves the state of the batch commitment. In case if it does not exist, method returns nil.
+func (bs *BlockStorage) TryGetBatchCommitment(
+	ctx context.Context,
+	batchId scTypes.BatchId,
+) (*scTypes.BatchCommitment, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	value, err := tx.Get(batchCommitmentsTable, batchId.Bytes())
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commitment of batch %s: %w", batchId, err)
+	}
+	return unmarshallBatchCommitment(batchId.Bytes(), value)
+}
+
+// GetBatchCommitments retrieves all stored batch commitments ordered by their sequence numbers.
+func (bs *BlockStorage) GetBatchCommitments(ctx context.Context) ([]*scTypes.BatchCommitment, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return bs.getBatchCommitmentsTx(tx)
+}
+
+// PruneFinalizedCommitments removes the commitment of the finalized batch together with all the commitments
+// added before it, since batches are finalized in order. If the batch is unknown, nothing is removed.
+func (bs *BlockStorage) PruneFinalizedCommitments(ctx context.Context, finalizedId scTypes.BatchId) (int, error) {
+	var pruned int
+	err := bs.retryRunner.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pruned, err = bs.pruneFinalizedCommitmentsImpl(ctx, finalizedId)
+		return err
+	})
+	return pruned, err
+}
+
+func (bs *BlockStorage) pruneFinalizedCommitmentsImpl(ctx context.Context, finalizedId scTypes.BatchId) (int, error) {
+	tx, err := bs.database.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	commitments, err := bs.getBatchCommitmentsTx(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	finalizedIdx := slices.IndexFunc(commitments, func(c *scTypes.BatchCommitment) bool {
+		return c.BatchId == finalizedId
+	})
+	if finalizedIdx < 0 {
+		return 0, nil
+	}
+
+	for _, commitment := range commitments[:finalizedIdx+1] {
+		if err := tx.Delete(batchCommitmentsTable, commitment.BatchId.Bytes()); err != nil {
+			return 0, fmt.Errorf("failed to delete commitment of batch %s: %w", commitment.BatchId, err)
+		}
+	}
+
+	if err := bs.commit(tx); err != nil {
+		return 0, err
+	}
+	return finalizedIdx + 1, nil
+}
+
+func (*BlockStorage) putBatchCommitmentTx(tx db.RwTx, commitment *scTypes.BatchCommitment) error {
+	value, err := json.Marshal(commitment)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: failed to encode commitment of batch %s: %w", ErrSerializationFailed, commitment.BatchId, err,
+		)
+	}
+	if err := tx.Put(batchCommitmentsTable, commitment.BatchId.Bytes(), value); err != nil {
+		return fmt.Errorf("failed to put commitment of batch %s: %w", commitment.BatchId, err)
+	}
+	return nil
+}
+
+func (*BlockStorage) getBatchCommitmentsTx(tx db.RoTx) ([]*scTypes.BatchCommitment, error) {
+	iter, err := tx.Range(batchCommitmentsTable, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var commitments []*scTypes.BatchCommitment
+	for iter.HasNext() {
+		key, val, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		commitment, err := unmarshallBatchCommitment(key, val)
+		if err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, commitment)
+	}
+
+	slices.SortFunc(commitments, func(a, b *scTypes.BatchCommitment) int {
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+	return commitments, nil
+}
+
+func unmarshallBatchCommitment(key []byte, value []byte) (*scTypes.BatchCommitment, error) {
+	commitment := &scTypes.BatchCommitment{}
+	if err := json.Unmarshal(value, commitment); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshall batch commitment with id=%s: %w", ErrSerializationFailed, key, err)
+	}
+	return commitment, nil
+}
@@ -492,3 +492,41 @@ func (s *BlockStorageTestSuite) requireBatch(batch *scTypes.BlockBatch, shouldBe
 		}
 	}
 }
+
+func (s *BlockStorageTestSuite) TestBatchCommitments_OrderAndPrune() {
+	const commitmentsCount = 5
+	ids := make([]scTypes.BatchId, 0, commitmentsCount)
+	for range commitmentsCount {
+		commitment := scTypes.NewBatchCommitment(scTypes.NewBatchId(), []byte{1, 2, 3}, time.Now())
+		s.Require().NoError(s.bs.AddBatchCommitment(s.ctx, commitment))
+		ids = append(ids, commitment.BatchId)
+	}
+
+	// adding the existing commitment again does not change it
+	duplicate := scTypes.NewBatchCommitment(ids[0], nil, time.Now())
+	s.Require().NoError(s.bs.AddBatchCommitment(s.ctx, duplicate))
+	stored, err := s.bs.TryGetBatchCommitment(s.ctx, ids[0])
+	s.Require().NoError(err)
+	s.Require().Equal([]byte{1, 2, 3}, stored.Data)
+
+	commitments, err := s.bs.GetBatchCommitments(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(commitments, commitmentsCount)
+	for i, commitment := range commitments {
+		s.Require().Equal(ids[i], commitment.BatchId)
+	}
+
+	pruned, err := s.bs.PruneFinalizedCommitments(s.ctx, scTypes.NewBatchId())
+	s.Require().NoError(err)
+	s.Require().Zero(pruned)
+
+	pruned, err = s.bs.PruneFinalizedCommitments(s.ctx, ids[2])
+	s.Require().NoError(err)
+	s.Require().Equal(3, pruned)
+
+	commitments, err = s.bs.GetBatchCommitments(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(commitments, 2)
+	s.Require().Equal(ids[3], commitments[0].BatchId)
+	s.Require().Equal(ids[4], commitments[1].BatchId)
+}
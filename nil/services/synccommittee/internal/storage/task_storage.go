@@ -3,9 +3,11 @@ package storage
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -14,6 +16,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/log"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
@@ -21,6 +24,11 @@ const (
 	// TaskEntriesTable BadgerDB tables, TaskId is used as a key
 	taskEntriesTable db.TableName = "task_entries"
 
+	// waitingTasksTable is the priority index of the tasks with WaitingForExecutor status.
+	// Keys are made of the task type, creation time and id, so the first key with the given task type
+	// points to the waiting task of this type with the highest priority. Values are empty.
+	waitingTasksTable db.TableName = "waiting_tasks"
+
 	// rescheduledTasksPerTxLimit defines the maximum number of tasks that can be rescheduled
 	// in a single transaction of TaskStorage.RescheduleHangingTasks.
 	rescheduledTasksPerTxLimit = 100
@@ -38,6 +46,9 @@ type TaskStorage struct {
 	commonStorage
 	timer   common.Timer
 	metrics TaskStorageMetrics
+
+	// eventsMutex orders the writes to the task event log, see commitWithEvents
+	eventsMutex sync.Mutex
 }
 
 func NewTaskStorage(
@@ -82,9 +93,79 @@ func (st *TaskStorage) putTaskEntry(tx db.RwTx, entry *types.TaskEntry) error {
 	if err := tx.Put(taskEntriesTable, key, inputBuffer.Bytes()); err != nil {
 		return fmt.Errorf("failed to put task with id %s: %w", entry.Task.Id, err)
 	}
+	return st.updateWaitingTaskIndex(tx, entry)
+}
+
+// Helper to keep the priority index in sync with the task status
+func (st *TaskStorage) updateWaitingTaskIndex(tx db.RwTx, entry *types.TaskEntry) error {
+	key := st.makeWaitingTaskKey(entry)
+	var err error
+	if entry.Status == types.WaitingForExecutor {
+		err = tx.Put(waitingTasksTable, key, nil)
+	} else {
+		err = tx.Delete(waitingTasksTable, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update priority index for task with id %s: %w", entry.Task.Id, err)
+	}
+	return nil
+}
+
+// IndexWaitingTasks adds the tasks with WaitingForExecutor status missing from the priority index to it.
+// The index is maintained on every task update, but the tasks stored before it was introduced are not indexed,
+// so the method must be called on startup before the tasks are requested.
+func (st *TaskStorage) IndexWaitingTasks(ctx context.Context) error {
+	var indexed int
+	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		var err error
+		indexed, err = st.indexWaitingTasksImpl(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if indexed > 0 {
+		st.logger.Info().Int("count", indexed).Msg("Added waiting tasks to the priority index")
+	}
 	return nil
 }
 
+func (st *TaskStorage) indexWaitingTasksImpl(ctx context.Context) (int, error) {
+	tx, err := st.database.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	indexed := 0
+	err = st.iterateOverTaskEntries(tx, func(entry *types.TaskEntry) (bool, error) {
+		if entry.Status != types.WaitingForExecutor {
+			return true, nil
+		}
+		exists, err := tx.Exists(waitingTasksTable, st.makeWaitingTaskKey(entry))
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return true, nil
+		}
+		if err := st.updateWaitingTaskIndex(tx, entry); err != nil {
+			return false, err
+		}
+		indexed++
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := st.commit(tx); err != nil {
+		return 0, err
+	}
+	return indexed, nil
+}
+
 // AddTaskEntries saves set of task entries.
 // If at least one task with a given id already exists, method returns ErrTaskAlreadyExists.
 func (st *TaskStorage) AddTaskEntries(ctx context.Context, tasks ...*types.TaskEntry) error {
@@ -107,28 +188,35 @@ func (st *TaskStorage) addTaskEntriesImpl(ctx context.Context, tasks []*types.Ta
 		return err
 	}
 	defer tx.Rollback()
+	events := make([]*public.TaskEvent, 0, len(tasks))
 	for _, entry := range tasks {
 		if entry == nil {
 			return errNilTaskEntry
 		}
-		if err := st.addSingleTaskEntryTx(tx, entry); err != nil {
+		event, err := st.addSingleTaskEntryTx(tx, entry)
+		if err != nil {
 			return err
 		}
+		events = append(events, event)
 	}
-	return st.commit(tx)
+	return st.commitWithEvents(ctx, tx, events...)
 }
 
-func (st *TaskStorage) addSingleTaskEntryTx(tx db.RwTx, entry *types.TaskEntry) error {
+func (st *TaskStorage) addSingleTaskEntryTx(tx db.RwTx, entry *types.TaskEntry) (*public.TaskEvent, error) {
 	key := st.makeTaskKey(entry)
 	exists, err := tx.Exists(taskEntriesTable, key)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if exists {
-		return fmt.Errorf("%w: taskId=%s", ErrTaskAlreadyExists, entry.Task.Id)
+		return nil, fmt.Errorf("%w: taskId=%s", ErrTaskAlreadyExists, entry.Task.Id)
+	}
+
+	if err := st.putTaskEntry(tx, entry); err != nil {
+		return nil, err
 	}
 
-	return st.putTaskEntry(tx, entry)
+	return public.NewTaskEvent(public.TaskEventCreated, entry, types.UnknownExecutorId, nil, st.timer.NowTime()), nil
 }
 
 // TryGetTaskEntry Retrieve a task entry by its id. In case if task does not exist, method returns nil
@@ -226,31 +314,73 @@ func (st *TaskStorage) GetTaskTreeView(ctx context.Context, rootTaskId types.Tas
 	return getTaskTreeRec(rootTaskId, 0)
 }
 
-// Helper to find available task with higher priority
-func (st *TaskStorage) findTopPriorityTask(tx db.RoTx) (*types.TaskEntry, error) {
+// Helper to find available task with higher priority among the ones accepted by the executor
+func (st *TaskStorage) findTopPriorityTask(
+	tx db.RoTx,
+	capabilities types.ExecutorCapabilities,
+) (*types.TaskEntry, error) {
 	var topPriorityTask *types.TaskEntry = nil
 
-	err := st.iterateOverTaskEntries(tx, func(entry *types.TaskEntry) (bool, error) {
-		if entry.Status != types.WaitingForExecutor {
-			return true, nil
+	// Task types are sorted, so AggregateProofs task comes first and is superseded by a task of any other type
+	for _, taskType := range capabilities.AcceptedTaskTypes() {
+		entry, err := st.findFirstWaitingTask(tx, taskType)
+		if err != nil {
+			return nil, err
 		}
 
-		if entry.HasHigherPriorityThan(topPriorityTask) {
+		if entry != nil && entry.HasHigherPriorityThan(topPriorityTask) {
 			topPriorityTask = entry
 		}
+	}
 
-		return true, nil
-	})
+	return topPriorityTask, nil
+}
+
+// Helper to get the waiting task of the given type with higher priority from the priority index
+func (st *TaskStorage) findFirstWaitingTask(tx db.RoTx, taskType types.TaskType) (*types.TaskEntry, error) {
+	iter, err := tx.Range(waitingTasksTable, []byte{byte(taskType)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	if !iter.HasNext() {
+		return nil, nil
+	}
+	key, _, err := iter.Next()
+	if err != nil {
+		return nil, err
+	}
+	if key[0] != byte(taskType) {
+		return nil, nil
+	}
 
-	return topPriorityTask, err
+	taskId, err := uuid.FromBytes(key[len(key)-16:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid priority index key %x: %w", ErrSerializationFailed, key, err)
+	}
+
+	entry, err := st.extractTaskEntry(tx, types.TaskId(taskId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexed task with id %s: %w", types.TaskId(taskId), err)
+	}
+	if entry.Status != types.WaitingForExecutor {
+		return nil, fmt.Errorf("indexed task with id %s has unexpected status %s", entry.Task.Id, entry.Status)
+	}
+	return entry, nil
 }
 
-// RequestTaskToExecute Find task with no dependencies and higher priority and assign it to the executor
-func (st *TaskStorage) RequestTaskToExecute(ctx context.Context, executor types.TaskExecutorId) (*types.Task, error) {
+// RequestTaskToExecute Find task with no dependencies and higher priority which matches the executor capabilities
+// and assign it to the executor
+func (st *TaskStorage) RequestTaskToExecute(
+	ctx context.Context,
+	executor types.TaskExecutorId,
+	capabilities types.ExecutorCapabilities,
+) (*types.Task, error) {
 	var taskEntry *types.TaskEntry
 	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
 		var err error
-		taskEntry, err = st.requestTaskToExecuteImpl(ctx, executor)
+		taskEntry, err = st.requestTaskToExecuteImpl(ctx, executor, capabilities)
 		return err
 	})
 	if err != nil {
@@ -265,14 +395,18 @@ func (st *TaskStorage) RequestTaskToExecute(ctx context.Context, executor types.
 	return &taskEntry.Task, nil
 }
 
-func (st *TaskStorage) requestTaskToExecuteImpl(ctx context.Context, executor types.TaskExecutorId) (*types.TaskEntry, error) {
+func (st *TaskStorage) requestTaskToExecuteImpl(
+	ctx context.Context,
+	executor types.TaskExecutorId,
+	capabilities types.ExecutorCapabilities,
+) (*types.TaskEntry, error) {
 	tx, err := st.database.CreateRwTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	taskEntry, err := st.findTopPriorityTask(tx)
+	taskEntry, err := st.findTopPriorityTask(tx, capabilities)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +422,8 @@ func (st *TaskStorage) requestTaskToExecuteImpl(ctx context.Context, executor ty
 	if err := st.putTaskEntry(tx, taskEntry); err != nil {
 		return nil, fmt.Errorf("failed to update task entry: %w", err)
 	}
-	if err = st.commit(tx); err != nil {
+	event := public.NewTaskEvent(public.TaskEventStarted, taskEntry, executor, nil, currentTime)
+	if err = st.commitWithEvents(ctx, tx, event); err != nil {
 		return nil, err
 	}
 	return taskEntry, nil
@@ -325,11 +460,12 @@ func (st *TaskStorage) processTaskResultImpl(ctx context.Context, res *types.Tas
 	}
 
 	if res.HasRetryableError() {
-		if err := st.rescheduleTaskTx(tx, entry, res.Error); err != nil {
+		event, err := st.rescheduleTaskTx(tx, entry, res.Error)
+		if err != nil {
 			return err
 		}
 
-		if err := st.commit(tx); err != nil {
+		if err := st.commitWithEvents(ctx, tx, event); err != nil {
 			return err
 		}
 
@@ -337,11 +473,12 @@ func (st *TaskStorage) processTaskResultImpl(ctx context.Context, res *types.Tas
 		return nil
 	}
 
-	if err := st.terminateTaskTx(tx, entry, res); err != nil {
+	event, err := st.terminateTaskTx(tx, entry, res)
+	if err != nil {
 		return err
 	}
 
-	if err := st.commit(tx); err != nil {
+	if err := st.commitWithEvents(ctx, tx, event); err != nil {
 		return err
 	}
 
@@ -349,11 +486,15 @@ func (st *TaskStorage) processTaskResultImpl(ctx context.Context, res *types.Tas
 	return nil
 }
 
-func (st *TaskStorage) terminateTaskTx(tx db.RwTx, entry *types.TaskEntry, res *types.TaskResult) error {
+func (st *TaskStorage) terminateTaskTx(
+	tx db.RwTx,
+	entry *types.TaskEntry,
+	res *types.TaskResult,
+) (*public.TaskEvent, error) {
 	currentTime := st.timer.NowTime()
 
 	if err := entry.Terminate(res, currentTime); err != nil {
-		return err
+		return nil, err
 	}
 
 	if res.IsSuccess() {
@@ -362,17 +503,24 @@ func (st *TaskStorage) terminateTaskTx(tx db.RwTx, entry *types.TaskEntry, res *
 			Msg("Task execution is completed successfully, removing it from the storage")
 
 		if err := tx.Delete(taskEntriesTable, res.TaskId.Bytes()); err != nil {
-			return err
+			return nil, err
+		}
+		if err := tx.Delete(waitingTasksTable, st.makeWaitingTaskKey(entry)); err != nil {
+			return nil, err
 		}
 	} else if err := st.putTaskEntry(tx, entry); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := st.updateDependentsTx(tx, entry, res, currentTime); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	eventType := public.TaskEventCompleted
+	if !res.IsSuccess() {
+		eventType = public.TaskEventFailed
+	}
+	return public.NewTaskEvent(eventType, entry, res.Sender, res.Error, currentTime), nil
 }
 
 func (st *TaskStorage) updateDependentsTx(
@@ -434,6 +582,7 @@ func (st *TaskStorage) rescheduleHangingTasksImpl(
 	defer tx.Rollback()
 
 	currentTime := st.timer.NowTime()
+	var events []*public.TaskEvent
 
 	err = st.iterateOverTaskEntries(tx, func(entry *types.TaskEntry) (bool, error) {
 		if entry.Status != types.Running {
@@ -447,9 +596,11 @@ func (st *TaskStorage) rescheduleHangingTasksImpl(
 
 		previousExecutor := entry.Owner
 		timeoutErr := types.NewTaskErrTimeout(executionTime, taskExecutionTimeout)
-		if err := st.rescheduleTaskTx(tx, entry, timeoutErr); err != nil {
+		event, err := st.rescheduleTaskTx(tx, entry, timeoutErr)
+		if err != nil {
 			return false, err
 		}
+		events = append(events, event)
 
 		rescheduled = append(rescheduled, rescheduledTask{entry.Task.TaskType, previousExecutor})
 		shouldContinue := len(rescheduled) < rescheduledTasksPerTxLimit
@@ -459,7 +610,7 @@ func (st *TaskStorage) rescheduleHangingTasksImpl(
 		return nil, err
 	}
 
-	if err := st.commit(tx); err != nil {
+	if err := st.commitWithEvents(ctx, tx, events...); err != nil {
 		return nil, err
 	}
 
@@ -470,22 +621,24 @@ func (st *TaskStorage) rescheduleTaskTx(
 	tx db.RwTx,
 	entry *types.TaskEntry,
 	cause *types.TaskExecError,
-) error {
+) (*public.TaskEvent, error) {
 	log.NewTaskEvent(st.logger, zerolog.WarnLevel, &entry.Task).
 		Err(cause).
 		Stringer(logging.FieldTaskExecutorId, entry.Owner).
 		Int("retryCount", entry.RetryCount).
 		Msg("Task execution error, rescheduling")
 
+	event := public.NewTaskEvent(public.TaskEventRescheduled, entry, entry.Owner, cause, st.timer.NowTime())
+
 	if err := entry.ResetRunning(); err != nil {
-		return fmt.Errorf("failed to reset task: %w", err)
+		return nil, fmt.Errorf("failed to reset task: %w", err)
 	}
 
 	if err := st.putTaskEntry(tx, entry); err != nil {
-		return fmt.Errorf("failed to put rescheduled task: %w", err)
+		return nil, fmt.Errorf("failed to put rescheduled task: %w", err)
 	}
 
-	return nil
+	return event, nil
 }
 
 func (*TaskStorage) iterateOverTaskEntries(
@@ -522,3 +675,12 @@ func (*TaskStorage) iterateOverTaskEntries(
 func (*TaskStorage) makeTaskKey(entry *types.TaskEntry) []byte {
 	return entry.Task.Id.Bytes()
 }
+
+// makeWaitingTaskKey makes a key of the priority index: task type, creation time and task id.
+// Tasks of the same type created earlier have higher priority.
+func (*TaskStorage) makeWaitingTaskKey(entry *types.TaskEntry) []byte {
+	key := make([]byte, 0, 1+8+16)
+	key = append(key, byte(entry.Task.TaskType))
+	key = binary.BigEndian.AppendUint64(key, uint64(entry.Created.UnixNano()))
+	return append(key, entry.Task.Id[:]...)
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+const (
+	// taskEventsTable is the append-only log of task lifecycle events.
+	// Key: big-endian event sequence number, Value: public.TaskEvent.
+	taskEventsTable db.TableName = "task_events"
+
+	// taskEventCursorsTable stores sequence numbers of the last event of the log (lastTaskEventSeqKey)
+	// and of the last event processed by each of the log consumers.
+	// Key: cursor name, Value: big-endian event sequence number.
+	taskEventCursorsTable db.TableName = "task_event_cursors"
+
+	// taskEventsRetainLimit defines the number of the latest events kept in the log,
+	// older events are removed when the new ones are added.
+	taskEventsRetainLimit = 100_000
+)
+
+// lastTaskEventSeqKey is the cursor of the last event of the log.
+// It is read and updated only by the transactions writing the events, which are run one at a time
+// under TaskStorage.eventsMutex (see commitWithEvents), so the sequence numbers have no gaps
+// and are assigned in the commit order: an event can't appear behind the cursor of a consumer
+// that has already read the following ones.
+// The task transactions don't touch the key, so they don't conflict with each other because of it.
+var lastTaskEventSeqKey = []byte("last_seq")
+
+// AddTaskEvents appends events to the task event log, assigning sequence numbers to them.
+func (st *TaskStorage) AddTaskEvents(ctx context.Context, events ...*public.TaskEvent) error {
+	st.eventsMutex.Lock()
+	defer st.eventsMutex.Unlock()
+
+	return st.addTaskEventsLocked(ctx, events)
+}
+
+// commitWithEvents commits the task transaction and appends the events of its changes to the log
+// in a separate transaction.
+// Both commits are done under eventsMutex, so the events of the changes are ordered the same way as the changes.
+// The changes are kept if the events can't be written: the events are lost and the error is only logged,
+// because the callers must not retry the committed transaction.
+func (st *TaskStorage) commitWithEvents(ctx context.Context, tx db.RwTx, events ...*public.TaskEvent) error {
+	st.eventsMutex.Lock()
+	defer st.eventsMutex.Unlock()
+
+	if err := st.commit(tx); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := st.addTaskEventsLocked(ctx, events); err != nil {
+		st.logger.Error().Err(err).Int("count", len(events)).Msg("Failed to add task events, they are lost")
+	}
+	return nil
+}
+
+func (st *TaskStorage) addTaskEventsLocked(ctx context.Context, events []*public.TaskEvent) error {
+	return st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := st.database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := st.addTaskEventsTx(tx, events...); err != nil {
+			return err
+		}
+		return st.commit(tx)
+	})
+}
+
+// addTaskEventsTx appends the events within the transaction, it must be called under eventsMutex.
+func (st *TaskStorage) addTaskEventsTx(tx db.RwTx, events ...*public.TaskEvent) error {
+	lastSeq, err := st.getTaskEventCursorTx(tx, lastTaskEventSeqKey)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		lastSeq++
+		event.Seq = lastSeq
+
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("%w: failed to marshal task event %d: %w", ErrSerializationFailed, event.Seq, err)
+		}
+		if err := tx.Put(taskEventsTable, makeTaskEventKey(event.Seq), value); err != nil {
+			return fmt.Errorf("failed to put task event %d: %w", event.Seq, err)
+		}
+
+		if event.Seq > taskEventsRetainLimit {
+			err := tx.Delete(taskEventsTable, makeTaskEventKey(event.Seq-taskEventsRetainLimit))
+			if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+				return fmt.Errorf("failed to remove outdated task event: %w", err)
+			}
+		}
+	}
+
+	return st.putTaskEventCursorTx(tx, lastTaskEventSeqKey, lastSeq)
+}
+
+// GetTaskEvents retrieves events with sequence numbers greater than request.AfterSeq, in the order they were added.
+func (st *TaskStorage) GetTaskEvents(ctx context.Context, request *public.TaskEventsRequest) ([]*public.TaskEvent, error) {
+	tx, err := st.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	iter, err := tx.Range(taskEventsTable, makeTaskEventKey(request.AfterSeq+1), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	events := make([]*public.TaskEvent, 0)
+	for iter.HasNext() && len(events) < request.Limit {
+		key, val, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		event := &public.TaskEvent{}
+		if err := json.Unmarshal(val, event); err != nil {
+			return nil, fmt.Errorf(
+				"%w: failed to unmarshal task event %d: %w", ErrSerializationFailed, binary.BigEndian.Uint64(key), err,
+			)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// GetTaskEventCursor returns the sequence number of the last event processed by the named consumer,
+// zero is returned if the consumer has not processed any events yet.
+func (st *TaskStorage) GetTaskEventCursor(ctx context.Context, name string) (uint64, error) {
+	tx, err := st.database.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	return st.getTaskEventCursorTx(tx, []byte(name))
+}
+
+// SetTaskEventCursor saves the sequence number of the last event processed by the named consumer.
+func (st *TaskStorage) SetTaskEventCursor(ctx context.Context, name string, seq uint64) error {
+	return st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := st.database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := st.putTaskEventCursorTx(tx, []byte(name), seq); err != nil {
+			return err
+		}
+		return st.commit(tx)
+	})
+}
+
+func (*TaskStorage) getTaskEventCursorTx(tx db.RoTx, key []byte) (uint64, error) {
+	value, err := tx.Get(taskEventCursorsTable, key)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get task event cursor %s: %w", key, err)
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func (*TaskStorage) putTaskEventCursorTx(tx db.RwTx, key []byte, seq uint64) error {
+	if err := tx.Put(taskEventCursorsTable, key, makeTaskEventKey(seq)); err != nil {
+		return fmt.Errorf("failed to put task event cursor %s: %w", key, err)
+	}
+	return nil
+}
+
+func makeTaskEventKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+func (s *TaskStorageSuite) requireTaskEvents(afterSeq uint64, expected ...public.TaskEventType) []*public.TaskEvent {
+	s.T().Helper()
+
+	events, err := s.ts.GetTaskEvents(s.ctx, public.NewTaskEventsRequest(afterSeq, public.TaskEventsMaxLimit))
+	s.Require().NoError(err)
+
+	actual := make([]public.TaskEventType, 0, len(events))
+	for i, event := range events {
+		s.Require().Equal(afterSeq+uint64(i)+1, event.Seq)
+		actual = append(actual, event.Type)
+	}
+	s.Require().Equal(expected, actual)
+	return events
+}
+
+func (s *TaskStorageSuite) Test_TaskEvents_Lifecycle() {
+	now := s.timer.NowTime()
+	executorId := testaide.RandomExecutorId()
+
+	entry := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+	s.Require().NoError(s.ts.AddTaskEntries(s.ctx, entry))
+
+	task, err := s.ts.RequestTaskToExecute(s.ctx, executorId, types.ExecutorCapabilities{})
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+
+	retryableErr := types.NewTaskExecError(types.TaskErrRpc, "RPC method failed")
+	err = s.ts.ProcessTaskResult(s.ctx, types.NewFailureProverTaskResult(task.Id, executorId, retryableErr))
+	s.Require().NoError(err)
+
+	task, err = s.ts.RequestTaskToExecute(s.ctx, executorId, types.ExecutorCapabilities{})
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+
+	criticalErr := types.NewTaskExecError(types.TaskErrInvalidTask, "invalid task")
+	err = s.ts.ProcessTaskResult(s.ctx, types.NewFailureProverTaskResult(task.Id, executorId, criticalErr))
+	s.Require().NoError(err)
+
+	events := s.requireTaskEvents(0,
+		public.TaskEventCreated,
+		public.TaskEventStarted,
+		public.TaskEventRescheduled,
+		public.TaskEventStarted,
+		public.TaskEventFailed,
+	)
+
+	for _, event := range events {
+		s.Require().Equal(entry.Task.Id, *event.TaskId)
+		s.Require().Equal(entry.Task.BatchId, *event.BatchId)
+		s.Require().Equal(entry.Task.TaskType, event.TaskType)
+	}
+	s.Require().Equal(types.UnknownExecutorId, events[0].ExecutorId)
+	s.Require().Equal(executorId, events[1].ExecutorId)
+	s.Require().Equal(retryableErr, events[2].Error)
+	s.Require().Equal(1, events[3].RetryCount)
+	s.Require().Equal(criticalErr, events[4].Error)
+}
+
+func (s *TaskStorageSuite) Test_TaskEvents_Completed_And_Hanging_Rescheduled() {
+	now := s.timer.NowTime()
+	executionTimeout := time.Minute
+
+	completedEntry := testaide.NewTaskEntry(now, types.Running, testaide.RandomExecutorId())
+	hangingEntry := testaide.NewTaskEntry(now.Add(-executionTimeout*2), types.Running, testaide.RandomExecutorId())
+	s.Require().NoError(s.ts.AddTaskEntries(s.ctx, completedEntry, hangingEntry))
+
+	err := s.ts.ProcessTaskResult(
+		s.ctx,
+		types.NewSuccessProverTaskResult(
+			completedEntry.Task.Id, completedEntry.Owner, types.TaskOutputArtifacts{}, types.TaskResultData{},
+		),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(s.ts.RescheduleHangingTasks(s.ctx, executionTimeout))
+
+	events := s.requireTaskEvents(2, public.TaskEventCompleted, public.TaskEventRescheduled)
+	s.Require().Equal(completedEntry.Owner, events[0].ExecutorId)
+	s.Require().Equal(hangingEntry.Task.Id, *events[1].TaskId)
+	s.Require().Equal(hangingEntry.Owner, events[1].ExecutorId)
+	s.Require().Equal(types.TaskErrTimeout, events[1].Error.ErrType)
+}
+
+func (s *TaskStorageSuite) Test_TaskEvents_Paging_And_Cursors() {
+	for range 5 {
+		s.Require().NoError(s.ts.AddTaskEvents(s.ctx, public.NewBatchProvedEvent(&testaide.NewTaskEntry(
+			s.timer.NowTime(), types.Completed, types.UnknownExecutorId).Task, s.timer.NowTime()),
+		))
+	}
+
+	events, err := s.ts.GetTaskEvents(s.ctx, public.NewTaskEventsRequest(1, 2))
+	s.Require().NoError(err)
+	s.Require().Len(events, 2)
+	s.Require().Equal(uint64(2), events[0].Seq)
+	s.Require().Equal(uint64(3), events[1].Seq)
+
+	events, err = s.ts.GetTaskEvents(s.ctx, public.NewTaskEventsRequest(5, 2))
+	s.Require().NoError(err)
+	s.Require().Empty(events)
+
+	cursor, err := s.ts.GetTaskEventCursor(s.ctx, "consumer")
+	s.Require().NoError(err)
+	s.Require().Zero(cursor)
+
+	s.Require().NoError(s.ts.SetTaskEventCursor(s.ctx, "consumer", 3))
+	cursor, err = s.ts.GetTaskEventCursor(s.ctx, "consumer")
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(3), cursor)
+
+	// cursors of consumers don't affect the sequence of the log
+	s.Require().NoError(s.ts.AddTaskEvents(s.ctx, public.NewStateRootUpdatedEvent(
+		testaide.RandomHash(), testaide.RandomHash(), testaide.RandomHash(), testaide.RandomHash(), s.timer.NowTime(),
+	)))
+	s.requireTaskEvents(5, public.TaskEventStateRootUpdated)
+}
+
+func (s *TaskStorageSuite) Test_TaskEvents_Task_Transactions_Dont_Conflict() {
+	now := s.timer.NowTime()
+	entry := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+	otherEntry := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+
+	tx, err := s.database.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	event, err := s.ts.addSingleTaskEntryTx(tx, entry)
+	s.Require().NoError(err)
+
+	// events of another task are written while the transaction is open
+	s.Require().NoError(s.ts.AddTaskEntries(s.ctx, otherEntry))
+
+	s.Require().NoError(s.ts.commitWithEvents(s.ctx, tx, event))
+	events := s.requireTaskEvents(0, public.TaskEventCreated, public.TaskEventCreated)
+	s.Require().Equal(otherEntry.Task.Id, *events[0].TaskId)
+	s.Require().Equal(entry.Task.Id, *events[1].TaskId)
+}
+
+func (s *TaskStorageSuite) Test_TaskEvents_Concurrently() {
+	entries := make([]*types.TaskEntry, degreeOfParallelism)
+	for i := range entries {
+		entries[i] = testaide.NewTaskEntry(s.timer.NowTime(), types.WaitingForExecutor, types.UnknownExecutorId)
+	}
+
+	waitGroup := sync.WaitGroup{}
+	for _, entry := range entries {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			s.NoError(s.ts.AddTaskEntries(s.ctx, entry))
+		}()
+	}
+	waitGroup.Wait()
+
+	expected := make([]public.TaskEventType, degreeOfParallelism)
+	for i := range expected {
+		expected[i] = public.TaskEventCreated
+	}
+	events := s.requireTaskEvents(0, expected...)
+
+	taskIds := make([]types.TaskId, 0, len(events))
+	for _, event := range events {
+		taskIds = append(taskIds, *event.TaskId)
+	}
+	for _, entry := range entries {
+		s.Require().Contains(taskIds, entry.Task.Id)
+	}
+}
@@ -1,7 +1,11 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
+	"math/rand"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -75,7 +79,7 @@ func (s *TaskStorageSuite) Test_Request_And_Process_Result() {
 	s.Require().NoError(err)
 
 	// No available tasks for executor at this point
-	task, err := s.ts.RequestTaskToExecute(s.ctx, 88)
+	task, err := s.ts.RequestTaskToExecute(s.ctx, 88, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 
@@ -85,7 +89,7 @@ func (s *TaskStorageSuite) Test_Request_And_Process_Result() {
 		types.NewSuccessProverTaskResult(dependency1.Task.Id, dependency1.Owner, types.TaskOutputArtifacts{}, types.TaskResultData{}),
 	)
 	s.Require().NoError(err)
-	task, err = s.ts.RequestTaskToExecute(s.ctx, 88)
+	task, err = s.ts.RequestTaskToExecute(s.ctx, 88, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().NotNil(task)
 	s.Equal(task.Id, lowerPriorityEntry.Task.Id)
@@ -97,7 +101,7 @@ func (s *TaskStorageSuite) Test_Request_And_Process_Result() {
 	)
 	s.Require().NoError(err)
 
-	task, err = s.ts.RequestTaskToExecute(s.ctx, 88)
+	task, err = s.ts.RequestTaskToExecute(s.ctx, 88, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().NotNil(task)
 	s.Equal(task.Id, higherPriorityEntry.Task.Id)
@@ -151,7 +155,7 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_NoEntries() {
 	err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
 	s.Require().NoError(err)
 
-	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(taskToExecute)
 }
@@ -173,7 +177,7 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_NoActiveTasks() {
 
 	// All existing tasks are still available for execution
 	for range entries {
-		taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+		taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 		s.Require().NoError(err)
 		s.Require().NotNil(taskToExecute)
 	}
@@ -192,7 +196,7 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_SingleActiveTask() {
 	s.Require().NoError(err)
 
 	// Active task wasn't rescheduled
-	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(taskToExecute)
 }
@@ -215,13 +219,54 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_MultipleTasks() {
 	s.Require().NoError(err)
 
 	// Outdated task was rescheduled and became available for execution
-	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(outdatedEntry.Task, *taskToExecute)
 
 	// Active and failed tasks weren't rescheduled
-	taskToExecute, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	taskToExecute, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
+	s.Require().NoError(err)
+	s.Require().Nil(taskToExecute)
+}
+
+func (s *TaskStorageSuite) Test_IndexWaitingTasks_NotIndexedEntries() {
+	now := s.timer.NowTime()
+
+	waitingEntries := []*types.TaskEntry{
+		testaide.NewTaskEntry(now.Add(-time.Hour), types.WaitingForExecutor, types.UnknownExecutorId),
+		testaide.NewTaskEntry(now.Add(-time.Minute), types.WaitingForExecutor, types.UnknownExecutorId),
+	}
+	runningEntry := testaide.NewTaskEntry(now.Add(-time.Second), types.Running, testaide.RandomExecutorId())
+
+	// Entries stored before the priority index was introduced
+	tx, err := s.database.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+	for _, entry := range append(slices.Clone(waitingEntries), runningEntry) {
+		var buffer bytes.Buffer
+		s.Require().NoError(gob.NewEncoder(&buffer).Encode(entry))
+		s.Require().NoError(tx.Put(taskEntriesTable, s.ts.makeTaskKey(entry), buffer.Bytes()))
+	}
+	s.Require().NoError(tx.Commit())
+
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
+	s.Require().NoError(err)
+	s.Require().Nil(taskToExecute)
+
+	s.Require().NoError(s.ts.IndexWaitingTasks(s.ctx))
+	// Indexing is idempotent
+	s.Require().NoError(s.ts.IndexWaitingTasks(s.ctx))
+
+	// Waiting tasks are available in the order of priority
+	for _, entry := range waitingEntries {
+		taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
+		s.Require().NoError(err)
+		s.Require().NotNil(taskToExecute)
+		s.Require().Equal(entry.Task, *taskToExecute)
+	}
+
+	taskToExecute, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(taskToExecute)
 }
@@ -276,13 +321,13 @@ func (s *TaskStorageSuite) requireExactTasksCount(tasksCount int) {
 
 	// All added tasks became available
 	for range tasksCount {
-		task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+		task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 		s.Require().NoError(err)
 		s.Require().NotNil(task)
 	}
 
 	// There no more tasks left
-	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 }
@@ -302,7 +347,7 @@ func (s *TaskStorageSuite) Test_RequestTaskToExecute_Concurrently() {
 	for range degreeOfParallelism {
 		go func() {
 			defer waitGroup.Done()
-			task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+			task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
 			s.NoError(err)
 
 			if task != nil {
@@ -341,7 +386,7 @@ func (s *TaskStorageSuite) Test_ProcessTaskResult_Concurrently() {
 	waitGroup.Wait()
 
 	// Task was successfully completed and was removed from the storage
-	task, err := s.ts.RequestTaskToExecute(s.ctx, executorId)
+	task, err := s.ts.RequestTaskToExecute(s.ctx, executorId, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().Nil(task)
 }
@@ -454,3 +499,93 @@ func (s *TaskStorageSuite) Test_AddTaskEntries_Fail_If_Already_Exists() {
 	s.Require().NoError(err)
 	s.Require().Nil(entryFromStorage)
 }
+
+func (s *TaskStorageSuite) Test_RequestTaskToExecute_Priority_ThousandsOfTasks() {
+	const tasksCount = 3000
+	const tasksPerTx = 500
+	now := s.timer.NowTime()
+
+	taskTypes := []types.TaskType{types.AggregateProofs, types.PartialProve, types.MergeProof, types.AggregatedFRI}
+
+	// Every task has a unique creation time, so the expected order is defined uniquely
+	entries := make([]*types.TaskEntry, 0, tasksCount)
+	for i, pos := range rand.Perm(tasksCount) {
+		entry := testaide.NewTaskEntryOfType(taskTypes[i%len(taskTypes)], now, types.WaitingForExecutor, types.UnknownExecutorId)
+		entry.Created = now.Add(-time.Duration(pos) * time.Second)
+		entries = append(entries, entry)
+	}
+	for batch := range slices.Chunk(entries, tasksPerTx) {
+		err := s.ts.AddTaskEntries(s.ctx, batch...)
+		s.Require().NoError(err)
+	}
+
+	// AggregateProofs tasks go last, the rest are ordered by creation time
+	expected := slices.Clone(entries)
+	slices.SortFunc(expected, func(a, b *types.TaskEntry) int {
+		aggregateA, aggregateB := a.Task.TaskType == types.AggregateProofs, b.Task.TaskType == types.AggregateProofs
+		if aggregateA != aggregateB {
+			if aggregateA {
+				return 1
+			}
+			return -1
+		}
+		return a.Created.Compare(b.Created)
+	})
+
+	for i, entry := range expected {
+		task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
+		s.Require().NoError(err)
+		s.Require().NotNil(task)
+		s.Require().Equal(entry.Task.Id, task.Id, "unexpected task at position %d", i)
+	}
+
+	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), types.ExecutorCapabilities{})
+	s.Require().NoError(err)
+	s.Require().Nil(task)
+}
+
+func (s *TaskStorageSuite) Test_RequestTaskToExecute_Capabilities_ThousandsOfTasks() {
+	const tasksPerType = 1000
+	now := s.timer.NowTime()
+
+	var entries []*types.TaskEntry
+	for _, taskType := range []types.TaskType{types.PartialProve, types.AggregateProofs, types.MergeProof} {
+		for i := range tasksPerType {
+			entry := testaide.NewTaskEntryOfType(taskType, now, types.WaitingForExecutor, types.UnknownExecutorId)
+			entry.Created = now.Add(time.Duration(i) * time.Millisecond)
+			entries = append(entries, entry)
+		}
+	}
+	for batch := range slices.Chunk(entries, tasksPerType) {
+		err := s.ts.AddTaskEntries(s.ctx, batch...)
+		s.Require().NoError(err)
+	}
+
+	requestAll := func(capabilities types.ExecutorCapabilities) map[types.TaskType]int {
+		s.T().Helper()
+
+		received := make(map[types.TaskType]int)
+		for {
+			task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId(), capabilities)
+			s.Require().NoError(err)
+			if task == nil {
+				return received
+			}
+			s.Require().True(capabilities.Accepts(task.TaskType), "task of type %s is not accepted", task.TaskType)
+			received[task.TaskType]++
+		}
+	}
+
+	// Small executors can't handle any of the remaining tasks
+	received := requestAll(types.ExecutorCapabilities{
+		TaskTypes:       []types.TaskType{types.AggregateProofs},
+		ResourceClasses: []types.ResourceClass{types.ResourceClassStandard},
+	})
+	s.Require().Empty(received)
+
+	received = requestAll(types.ExecutorCapabilities{TaskTypes: []types.TaskType{types.PartialProve}})
+	s.Require().Equal(map[types.TaskType]int{types.PartialProve: tasksPerType}, received)
+
+	received = requestAll(types.ExecutorCapabilities{ResourceClasses: []types.ResourceClass{types.ResourceClassHighMemory}})
+	s.Require().Equal(map[types.TaskType]int{types.AggregateProofs: tasksPerType, types.MergeProof: tasksPerType}, received)
+}
@@ -137,6 +137,7 @@ func NewProposalData(txCount int, currentTime time.Time) *scTypes.ProposalData {
 
 	return &scTypes.ProposalData{
 		MainShardBlockHash: RandomHash(),
+		BatchId:            scTypes.NewBatchId(),
 		Transactions:       transactions,
 		OldProvedStateRoot: RandomHash(),
 		NewProvedStateRoot: RandomHash(),
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+)
+
+// BatchCommitStatus Status of the batch commitment to L1
+type BatchCommitStatus uint8
+
+const (
+	BatchCommitStatusNone BatchCommitStatus = iota
+	// BatchCommitPending the batch is waiting to be submitted to L1
+	BatchCommitPending
+	// BatchCommitSubmitted the commit transaction is sent and waits for inclusion into L1 block
+	BatchCommitSubmitted
+	// BatchCommitCommitted the commit transaction is included and has enough confirmations
+	BatchCommitCommitted
+)
+
+// CommitTx contains the parameters of the latest transaction sent to commit the batch.
+// If the replacement is rejected as underpriced, its fees are kept, so the next replacement bumps them further.
+type CommitTx struct {
+	Hash       common.Hash `json:"hash"`
+	Nonce      uint64      `json:"nonce"`
+	GasTipCap  *big.Int    `json:"gasTipCap"`
+	GasFeeCap  *big.Int    `json:"gasFeeCap"`
+	BlobFeeCap *big.Int    `json:"blobFeeCap"`
+	SentAt     time.Time   `json:"sentAt"`
+}
+
+// BatchCommitment tracks the commitment of the batch to L1 from the submission of the transaction
+// to the finalization of the batch
+type BatchCommitment struct {
+	BatchId BatchId           `json:"batchId"`
+	Status  BatchCommitStatus `json:"status"`
+
+	// Seq defines the order of the batches, it is assigned when the commitment is added to the storage
+	Seq uint64 `json:"seq"`
+
+	// Data is the encoded batch, the blobs are built from it on every submission
+	Data []byte `json:"data"`
+
+	// Created is the time when the batch was queued for commitment
+	Created time.Time `json:"created"`
+
+	// Tx is the latest transaction sent to commit the batch
+	Tx *CommitTx `json:"tx,omitempty"`
+
+	// SentTxHashes are the hashes of all transactions sent with the current nonce, any of them can be included.
+	SentTxHashes []common.Hash `json:"sentTxHashes,omitempty"`
+
+	// IncludedIn is the number of the L1 block including the commit transaction
+	IncludedIn *uint64 `json:"includedIn,omitempty"`
+}
+
+func NewBatchCommitment(batchId BatchId, data []byte, currentTime time.Time) *BatchCommitment {
+	return &BatchCommitment{
+		BatchId: batchId,
+		Status:  BatchCommitPending,
+		Data:    data,
+		Created: currentTime,
+	}
+}
+
+// OnSent records the transaction sent to commit the batch. Transactions with a different nonce replace
+// all the previously sent ones.
+func (c *BatchCommitment) OnSent(tx *CommitTx) {
+	if c.Tx != nil && c.Tx.Nonce != tx.Nonce {
+		c.SentTxHashes = nil
+	}
+	c.Tx = tx
+	c.SentTxHashes = append(c.SentTxHashes, tx.Hash)
+	c.Status = BatchCommitSubmitted
+	c.IncludedIn = nil
+}
+
+// Reset returns the commitment to the pending state, so the batch is submitted with a new nonce.
+func (c *BatchCommitment) Reset() {
+	c.Status = BatchCommitPending
+	c.Tx = nil
+	c.SentTxHashes = nil
+	c.IncludedIn = nil
+}
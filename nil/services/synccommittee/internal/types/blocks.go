@@ -165,33 +165,55 @@ func (bk BlockId) String() string {
 	return hex.EncodeToString(bk.Bytes())
 }
 
+// PrunedBlock contains the data needed to replay the block: its transactions and its links to the other shards
 type PrunedBlock struct {
 	ShardId       types.ShardId
 	BlockNumber   types.BlockNumber
 	Timestamp     uint64
 	PrevBlockHash common.Hash
-	Transactions  []*PrunedTransaction
+	// MainChainHash is the main shard block the child shard block is built on
+	MainChainHash common.Hash
+	// ChildBlocks are the child shard blocks the main shard block references, ordered by the shard id
+	ChildBlocks  []common.Hash
+	Transactions []*PrunedTransaction
+	// ForwardTransactions are the outbound transactions of other shards forwarded by the block
+	ForwardTransactions []*PrunedTransaction
 }
 
 func NewPrunedBlock(block *jsonrpc.RPCBlock) *PrunedBlock {
+	var forwardTransactions []*PrunedTransaction
+	for _, transaction := range block.ForwardTransactions {
+		forwardTransactions = append(forwardTransactions, NewForwardTransaction(transaction))
+	}
 	return &PrunedBlock{
-		ShardId:       block.ShardId,
-		BlockNumber:   block.Number,
-		Timestamp:     block.DbTimestamp,
-		PrevBlockHash: block.ParentHash,
-		Transactions:  BlockTransactions(block),
+		ShardId:             block.ShardId,
+		BlockNumber:         block.Number,
+		Timestamp:           block.DbTimestamp,
+		PrevBlockHash:       block.ParentHash,
+		MainChainHash:       block.MainChainHash,
+		ChildBlocks:         block.ChildBlocks,
+		Transactions:        BlockTransactions(block),
+		ForwardTransactions: forwardTransactions,
 	}
 }
 
 type PrunedTransaction struct {
-	Flags    types.TransactionFlags
-	Seqno    hexutil.Uint64
-	From     types.Address
-	To       types.Address
-	BounceTo types.Address
-	RefundTo types.Address
-	Value    types.Value
-	Data     hexutil.Bytes
+	Flags                types.TransactionFlags
+	Seqno                hexutil.Uint64
+	From                 types.Address
+	To                   types.Address
+	BounceTo             types.Address
+	RefundTo             types.Address
+	Value                types.Value
+	Data                 hexutil.Bytes
+	FeeCredit            types.Value
+	MaxPriorityFeePerGas types.Value
+	MaxFeePerGas         types.Value
+	ChainId              types.ChainId
+	Token                []types.TokenBalance
+	RequestId            uint64
+	RequestChain         []*types.AsyncRequestInfo
+	Signature            types.Signature
 }
 
 func BlockTransactions(block *jsonrpc.RPCBlock) []*PrunedTransaction {
@@ -204,19 +226,49 @@ func BlockTransactions(block *jsonrpc.RPCBlock) []*PrunedTransaction {
 
 func NewTransaction(transaction *jsonrpc.RPCInTransaction) *PrunedTransaction {
 	return &PrunedTransaction{
-		Flags:    transaction.Flags,
-		Seqno:    transaction.Seqno,
-		From:     transaction.From,
-		To:       transaction.To,
-		BounceTo: transaction.BounceTo,
-		RefundTo: transaction.RefundTo,
-		Value:    transaction.Value,
-		Data:     transaction.Data,
+		Flags:                transaction.Flags,
+		Seqno:                transaction.Seqno,
+		From:                 transaction.From,
+		To:                   transaction.To,
+		BounceTo:             transaction.BounceTo,
+		RefundTo:             transaction.RefundTo,
+		Value:                transaction.Value,
+		Data:                 transaction.Data,
+		FeeCredit:            transaction.FeeCredit,
+		MaxPriorityFeePerGas: transaction.MaxPriorityFeePerGas,
+		MaxFeePerGas:         transaction.MaxFeePerGas,
+		ChainId:              transaction.ChainID,
+		Token:                transaction.Token,
+		RequestId:            transaction.RequestId,
+		RequestChain:         transaction.RequestChain,
+		Signature:            transaction.Signature,
+	}
+}
+
+func NewForwardTransaction(transaction *types.Transaction) *PrunedTransaction {
+	return &PrunedTransaction{
+		Flags:                transaction.Flags,
+		Seqno:                hexutil.Uint64(transaction.Seqno),
+		From:                 transaction.From,
+		To:                   transaction.To,
+		BounceTo:             transaction.BounceTo,
+		RefundTo:             transaction.RefundTo,
+		Value:                transaction.Value,
+		Data:                 hexutil.Bytes(transaction.Data),
+		FeeCredit:            transaction.FeeCredit,
+		MaxPriorityFeePerGas: transaction.MaxPriorityFeePerGas,
+		MaxFeePerGas:         transaction.MaxFeePerGas,
+		ChainId:              transaction.ChainId,
+		Token:                transaction.Token,
+		RequestId:            transaction.RequestId,
+		RequestChain:         transaction.RequestChain,
+		Signature:            transaction.Signature,
 	}
 }
 
 type ProposalData struct {
 	MainShardBlockHash common.Hash
+	BatchId            BatchId
 	Transactions       []*PrunedTransaction
 	OldProvedStateRoot common.Hash
 	NewProvedStateRoot common.Hash
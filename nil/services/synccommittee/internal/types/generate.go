@@ -3,5 +3,7 @@ package types
 //go:generate stringer -type=TaskType -trimprefix=TaskType
 //go:generate stringer -type=ProverResultType -trimprefix=ProverResultType
 //go:generate stringer -type=TaskStatus -trimprefix=TaskStatus
+//go:generate stringer -type=ResourceClass -trimprefix=ResourceClass
+//go:generate stringer -type=BatchCommitStatus -trimprefix=BatchCommit
 //go:generate stringer -type=CircuitType -trimprefix=Circuit
 //go:generate stringer -type=TaskErrType -trimprefix=TaskErr
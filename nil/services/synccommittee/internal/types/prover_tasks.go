@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"slices"
 	"strconv"
 	"time"
 
@@ -95,6 +97,33 @@ func (*TaskExecutorId) Type() string {
 	return "TaskExecutorId"
 }
 
+// ExecutorCapabilities defines the tasks which can be executed by an executor.
+// Empty TaskTypes or ResourceClasses mean that tasks of any type or resource class are accepted.
+type ExecutorCapabilities struct {
+	TaskTypes       []TaskType      `json:"taskTypes,omitempty"`
+	ResourceClasses []ResourceClass `json:"resourceClasses,omitempty"`
+}
+
+// Accepts checks if tasks of the given type can be executed by the executor.
+func (c *ExecutorCapabilities) Accepts(taskType TaskType) bool {
+	if len(c.TaskTypes) > 0 && !slices.Contains(c.TaskTypes, taskType) {
+		return false
+	}
+	return len(c.ResourceClasses) == 0 || slices.Contains(c.ResourceClasses, taskType.ResourceClass())
+}
+
+// AcceptedTaskTypes returns all the task types which can be executed by the executor.
+func (c *ExecutorCapabilities) AcceptedTaskTypes() []TaskType {
+	var accepted []TaskType
+	for taskType := range maps.Values(TaskTypes) {
+		if c.Accepts(taskType) {
+			accepted = append(accepted, taskType)
+		}
+	}
+	slices.Sort(accepted)
+	return accepted
+}
+
 type TaskIdSet map[TaskId]bool
 
 func NewTaskIdSet() TaskIdSet {
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ResourceClass Class of machines required to execute a task
+type ResourceClass uint8
+
+const (
+	ResourceClassNone ResourceClass = iota
+	ResourceClassStandard
+	ResourceClassHighMemory
+)
+
+var ResourceClasses = map[string]ResourceClass{
+	"Standard":   ResourceClassStandard,
+	"HighMemory": ResourceClassHighMemory,
+}
+
+func (c *ResourceClass) Set(str string) error {
+	if v, ok := ResourceClasses[str]; ok {
+		*c = v
+		return nil
+	}
+	return fmt.Errorf("unknown resource class: %s", str)
+}
+
+func (*ResourceClass) Type() string {
+	return "ResourceClass"
+}
+
+func (*ResourceClass) PossibleValues() []string {
+	return slices.Collect(maps.Keys(ResourceClasses))
+}
@@ -47,3 +47,14 @@ func (*TaskType) Type() string {
 func (*TaskType) PossibleValues() []string {
 	return slices.Collect(maps.Keys(TaskTypes))
 }
+
+// ResourceClass returns the class of machines able to execute tasks of the given type.
+// Aggregation of the proofs requires much more memory than proving a part of a block.
+func (t TaskType) ResourceClass() ResourceClass {
+	switch t {
+	case AggregateProofs, MergeProof:
+		return ResourceClassHighMemory
+	default:
+		return ResourceClassStandard
+	}
+}
@@ -57,6 +57,9 @@ func New(config *Config, database db.DB) (*ProofProvider, error) {
 	taskResultSender := scheduler.NewTaskResultSender(taskRpcClient, taskResultStorage, logger)
 
 	taskStorage := storage.NewTaskStorage(database, timer, metricsHandler, logger)
+	if err := taskStorage.IndexWaitingTasks(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to index waiting tasks: %w", err)
+	}
 
 	taskExecutor, err := executor.New(
 		executor.DefaultConfig(),
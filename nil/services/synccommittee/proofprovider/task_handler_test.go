@@ -94,7 +94,7 @@ func (s *TaskHandlerTestSuite) TestHandleAggregateProofsTask() {
 	s.Require().NoError(err)
 
 	otherExecutorId := testaide.RandomExecutorId()
-	requestedTask, err := s.taskStorage.RequestTaskToExecute(s.context, otherExecutorId)
+	requestedTask, err := s.taskStorage.RequestTaskToExecute(s.context, otherExecutorId, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	s.Require().NotNil(requestedTask)
 
@@ -173,7 +173,7 @@ func (s *TaskHandlerTestSuite) TestHandleBlockProofTask() {
 // Ensure that we have available task of certain type, or no tasks available
 func (s *TaskHandlerTestSuite) requestTask(executorId types.TaskExecutorId, available bool, expectedType types.TaskType) *types.Task {
 	s.T().Helper()
-	t, err := s.taskStorage.RequestTaskToExecute(s.context, executorId)
+	t, err := s.taskStorage.RequestTaskToExecute(s.context, executorId, types.ExecutorCapabilities{})
 	s.Require().NoError(err)
 	if !available {
 		s.Require().Nil(t)
@@ -15,6 +15,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/scheduler"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	"github.com/rs/zerolog"
 )
 
@@ -22,6 +23,11 @@ type Config struct {
 	ProofProviderRpcEndpoint string
 	NilRpcEndpoint           string
 	Telemetry                *telemetry.Config
+
+	// TaskTypes and ResourceClasses limit the tasks requested from the proof provider,
+	// tasks of any type or resource class are requested if empty
+	TaskTypes       []string
+	ResourceClasses []string
 }
 
 func NewDefaultConfig() *Config {
@@ -50,6 +56,11 @@ func New(config Config, database db.DB) (*Prover, error) {
 		return nil, fmt.Errorf("error initializing metrics: %w", err)
 	}
 
+	capabilities, err := newExecutorCapabilities(config)
+	if err != nil {
+		return nil, err
+	}
+
 	taskRpcClient := rpc.NewTaskRequestRpcClient(config.ProofProviderRpcEndpoint, logger)
 	taskResultStorage := storage.NewTaskResultStorage(database, logger)
 	taskResultSender := scheduler.NewTaskResultSender(taskRpcClient, taskResultStorage, logger)
@@ -61,8 +72,11 @@ func New(config Config, database db.DB) (*Prover, error) {
 		newTaskHandlerConfig(config.NilRpcEndpoint),
 	)
 
+	executorConfig := executor.DefaultConfig()
+	executorConfig.Capabilities = *capabilities
+
 	taskExecutor, err := executor.New(
-		executor.DefaultConfig(),
+		executorConfig,
 		taskRpcClient,
 		handler,
 		metricsHandler,
@@ -80,6 +94,25 @@ func New(config Config, database db.DB) (*Prover, error) {
 	}, nil
 }
 
+func newExecutorCapabilities(config Config) (*types.ExecutorCapabilities, error) {
+	capabilities := &types.ExecutorCapabilities{}
+	for _, str := range config.TaskTypes {
+		var taskType types.TaskType
+		if err := taskType.Set(str); err != nil {
+			return nil, err
+		}
+		capabilities.TaskTypes = append(capabilities.TaskTypes, taskType)
+	}
+	for _, str := range config.ResourceClasses {
+		var resourceClass types.ResourceClass
+		if err := resourceClass.Set(str); err != nil {
+			return nil, err
+		}
+		capabilities.ResourceClasses = append(capabilities.ResourceClasses, resourceClass)
+	}
+	return capabilities, nil
+}
+
 func NewRPCClient(endpoint string, logger zerolog.Logger) client.Client {
 	return rpc.NewRetryClient(endpoint, logger)
 }
@@ -0,0 +1,120 @@
+package public
+
+import (
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type TaskExecError = types.TaskExecError
+
+type TaskEventType string
+
+const (
+	// TaskEventCreated is published when a new task is added to the storage.
+	TaskEventCreated TaskEventType = "created"
+
+	// TaskEventStarted is published when a task is acquired by an executor.
+	TaskEventStarted TaskEventType = "started"
+
+	// TaskEventRescheduled is published when a task execution failed with a retryable error
+	// or exceeded the execution timeout, and the task is returned to the queue.
+	TaskEventRescheduled TaskEventType = "rescheduled"
+
+	// TaskEventFailed is published when a task execution failed with a critical error.
+	TaskEventFailed TaskEventType = "failed"
+
+	// TaskEventCompleted is published when a task execution is completed successfully.
+	TaskEventCompleted TaskEventType = "completed"
+
+	// TaskEventBatchProved is published when the aggregated proof of a batch is received.
+	TaskEventBatchProved TaskEventType = "batch_proved"
+
+	// TaskEventStateRootUpdated is published when the proved state root is sent to L1.
+	TaskEventStateRootUpdated TaskEventType = "state_root_updated"
+)
+
+var TaskEventTypes = map[string]TaskEventType{
+	string(TaskEventCreated):          TaskEventCreated,
+	string(TaskEventStarted):          TaskEventStarted,
+	string(TaskEventRescheduled):      TaskEventRescheduled,
+	string(TaskEventFailed):           TaskEventFailed,
+	string(TaskEventCompleted):        TaskEventCompleted,
+	string(TaskEventBatchProved):      TaskEventBatchProved,
+	string(TaskEventStateRootUpdated): TaskEventStateRootUpdated,
+}
+
+// TaskEvent is an entry of the append-only log of task lifecycle events.
+// Events are ordered by Seq, which is assigned by the storage when the event is added.
+type TaskEvent struct {
+	Seq  uint64        `json:"seq"`
+	Type TaskEventType `json:"type"`
+	Time time.Time     `json:"time"`
+
+	TaskId     *TaskId        `json:"taskId,omitempty"`
+	TaskType   TaskType       `json:"taskType,omitempty"`
+	BatchId    *BatchId       `json:"batchId,omitempty"`
+	ExecutorId TaskExecutorId `json:"executorId,omitempty"`
+	RetryCount int            `json:"retryCount,omitempty"`
+	Error      *TaskExecError `json:"error,omitempty"`
+
+	BlockHash    *common.Hash `json:"blockHash,omitempty"`
+	OldStateRoot *common.Hash `json:"oldStateRoot,omitempty"`
+	NewStateRoot *common.Hash `json:"newStateRoot,omitempty"`
+	L1TxHash     *common.Hash `json:"l1TxHash,omitempty"`
+}
+
+// NewTaskEvent creates an event describing the state change of the task.
+// The executor is the current owner of the task at the moment of the change.
+func NewTaskEvent(
+	eventType TaskEventType,
+	taskEntry *types.TaskEntry,
+	executor TaskExecutorId,
+	execErr *TaskExecError,
+	currentTime time.Time,
+) *TaskEvent {
+	return &TaskEvent{
+		Type: eventType,
+		Time: currentTime,
+
+		TaskId:     &taskEntry.Task.Id,
+		TaskType:   taskEntry.Task.TaskType,
+		BatchId:    &taskEntry.Task.BatchId,
+		ExecutorId: executor,
+		RetryCount: taskEntry.RetryCount,
+		Error:      execErr,
+
+		BlockHash: &taskEntry.Task.BlockHash,
+	}
+}
+
+func NewBatchProvedEvent(task *types.Task, currentTime time.Time) *TaskEvent {
+	return &TaskEvent{
+		Type: TaskEventBatchProved,
+		Time: currentTime,
+
+		TaskId:    &task.Id,
+		TaskType:  task.TaskType,
+		BatchId:   &task.BatchId,
+		BlockHash: &task.BlockHash,
+	}
+}
+
+func NewStateRootUpdatedEvent(
+	mainBlockHash common.Hash,
+	oldStateRoot common.Hash,
+	newStateRoot common.Hash,
+	l1TxHash common.Hash,
+	currentTime time.Time,
+) *TaskEvent {
+	return &TaskEvent{
+		Type: TaskEventStateRootUpdated,
+		Time: currentTime,
+
+		BlockHash:    &mainBlockHash,
+		OldStateRoot: &oldStateRoot,
+		NewStateRoot: &newStateRoot,
+		L1TxHash:     &l1TxHash,
+	}
+}
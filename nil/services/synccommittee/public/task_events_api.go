@@ -0,0 +1,58 @@
+package public
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	EventsNamespace     = "Events"
+	EventsGetTaskEvents = EventsNamespace + "_getTaskEvents"
+)
+
+const (
+	TaskEventsMinLimit     = 1
+	TaskEventsMaxLimit     = 1000
+	DefaultTaskEventsLimit = 100
+)
+
+type TaskEventsRequest struct {
+	// AfterSeq is the sequence number of the last event received by the subscriber,
+	// only events added after it are returned.
+	AfterSeq uint64 `json:"afterSeq"`
+	Limit    int    `json:"limit"`
+}
+
+func NewTaskEventsRequest(afterSeq uint64, limit int) *TaskEventsRequest {
+	return &TaskEventsRequest{
+		AfterSeq: afterSeq,
+		Limit:    limit,
+	}
+}
+
+func (r *TaskEventsRequest) Validate() error {
+	if r.Limit < TaskEventsMinLimit || r.Limit > TaskEventsMaxLimit {
+		return fmt.Errorf("limit must be between %d and %d, actual is %d", TaskEventsMinLimit, TaskEventsMaxLimit, r.Limit)
+	}
+
+	return nil
+}
+
+// MissedTaskEvents returns the number of events added after afterSeq that precede the first of the events
+// retrieved by GetTaskEvents, i.e. were removed from the log before they were retrieved.
+func MissedTaskEvents(afterSeq uint64, events []*TaskEvent) uint64 {
+	if len(events) == 0 || events[0].Seq <= afterSeq+1 {
+		return 0
+	}
+	return events[0].Seq - afterSeq - 1
+}
+
+// TaskEventsApi provides access to the log of task lifecycle events.
+// The API is poll-only, there are no subscriptions: subscribers tail the log by passing the sequence number
+// of the last received event to the next request. Events are pushed only to the webhook endpoint if it is configured.
+// Sequence numbers have no gaps, only the oldest events are removed from the log when it exceeds its limit,
+// so a subscriber falling behind can detect the events it missed with MissedTaskEvents.
+type TaskEventsApi interface {
+	// GetTaskEvents retrieves events added after request.AfterSeq, in the order they were added.
+	GetTaskEvents(ctx context.Context, request *TaskEventsRequest) ([]*TaskEvent, error)
+}
@@ -1,6 +1,8 @@
 package txnpool
 
 import (
+	"time"
+
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -10,6 +12,11 @@ type metaTxn struct {
 	effectivePriorityFee types.Value
 	bestIndex            int
 	valid                bool
+	addedAt              time.Time
+
+	// indexes in the heaps of receiverTails, -1 if the transaction is not the last one of its receiver
+	ageIndex int
+	tipIndex int
 }
 
 func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
@@ -19,6 +26,8 @@ func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
 		effectivePriorityFee: effectivePriorityFee,
 		valid:                valid,
 		bestIndex:            -1,
+		ageIndex:             -1,
+		tipIndex:             -1,
 	}
 }
 
@@ -0,0 +1,173 @@
+package txnpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/rs/zerolog"
+)
+
+const (
+	relaySeenCacheSize = 100_000
+	// relaySeenTTL is the time during which a transaction with the same hash is not relayed again.
+	relaySeenTTL = 10 * time.Minute
+)
+
+// ErrNoRoute is returned by Relay when none of the nodes known to the node may deliver the transaction.
+var ErrNoRoute = errors.New("no peers to relay the transaction to")
+
+// ShardTopology is the part of collate.ShardTopology that defines the routes of the relayed transactions.
+type ShardTopology interface {
+	GetNeighbors(id types.ShardId, nShards uint32, includeSelf bool) []types.ShardId
+	ShouldPropagateTxn(from types.ShardId, cur types.ShardId, dest types.ShardId) bool
+}
+
+type RelayConfig struct {
+	NShards  uint32
+	Topology ShardTopology
+	// Pools are the pools of the shards served by the node. The transactions to these shards are added to them,
+	// and the node relays the transactions received on the relay topics of these shards.
+	Pools map[types.ShardId]Pool
+}
+
+// Relay accepts the external transactions to any shard and propagates them to the nodes that serve the shard.
+// A transaction is published to the pending transactions topic of its shard if the node has peers there.
+// Otherwise, it is published to the relay topics of the neighbors of the shard (according to the topology),
+// whose nodes forward it further.
+type Relay struct {
+	cfg            RelayConfig
+	networkManager *network.Manager
+	seen           *expirable.LRU[common.Hash, struct{}]
+	logger         zerolog.Logger
+}
+
+func topicRelayTransactions(shardId types.ShardId) string {
+	return fmt.Sprintf("/shard/%s/relay-transactions", shardId)
+}
+
+// NewRelay creates a relay and starts forwarding the transactions received on the relay topics of the local shards.
+func NewRelay(ctx context.Context, cfg RelayConfig, networkManager *network.Manager) (*Relay, error) {
+	r := &Relay{
+		cfg:            cfg,
+		networkManager: networkManager,
+		seen:           expirable.NewLRU[common.Hash, struct{}](relaySeenCacheSize, nil, relaySeenTTL),
+		logger:         logging.NewLogger("txn-relay"),
+	}
+
+	for shardId := range cfg.Pools {
+		sub, err := networkManager.PubSub().Subscribe(topicRelayTransactions(shardId))
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			r.listen(ctx, shardId, sub)
+		}()
+	}
+	return r, nil
+}
+
+func (r *Relay) listen(ctx context.Context, cur types.ShardId, sub *network.Subscription) {
+	defer sub.Close()
+
+	for m := range sub.StartMessages(ctx, true) {
+		txn := &types.Transaction{}
+		if err := txn.UnmarshalSSZ(m.Data); err != nil {
+			r.logger.Error().Err(err).Msg("Failed to unmarshal relayed transaction")
+			r.networkManager.ReportPeer(m.From, network.MisbehaviorMalformedMessage)
+			continue
+		}
+
+		// The relays check the transactions, so the invalid ones are the fault of the author.
+		reason, err := r.relay(ctx, &cur, txn)
+		if reason.IsInvalid() {
+			r.networkManager.ReportPeer(m.From, network.MisbehaviorInvalidTransaction)
+		}
+		if err != nil {
+			r.logger.Debug().Err(err).
+				Stringer(logging.FieldShardId, cur).
+				Msg("Failed to relay transaction")
+		}
+	}
+}
+
+// Relay sends the transaction to the nodes of its shard.
+// It returns DuplicateHash for the transactions relayed recently.
+func (r *Relay) Relay(ctx context.Context, txn *types.Transaction) (DiscardReason, error) {
+	return r.relay(ctx, nil, txn)
+}
+
+// relay sends the transaction from the current shard (nil for the transactions received from the clients).
+func (r *Relay) relay(ctx context.Context, cur *types.ShardId, txn *types.Transaction) (DiscardReason, error) {
+	// Only the stateless checks are possible here, the rest is checked by the pool of the shard.
+	if txn.ChainId != types.DefaultChainId {
+		return InvalidChainId, nil
+	}
+
+	dest := txn.To.ShardId()
+	if uint32(dest) >= r.cfg.NShards {
+		return NotSet, fmt.Errorf("transaction to unknown shard %d", dest)
+	}
+
+	hash := types.NewTxnWithHash(txn).Hash()
+	if r.seen.Contains(hash) {
+		return DuplicateHash, nil
+	}
+	r.seen.Add(hash, struct{}{})
+
+	logger := r.logger.With().
+		Stringer(logging.FieldTransactionHash, hash).
+		Stringer(logging.FieldShardId, dest).
+		Logger()
+
+	if pool, ok := r.cfg.Pools[dest]; ok {
+		// The pool publishes the accepted transactions itself.
+		reasons, err := pool.Add(ctx, txn)
+		if err != nil {
+			return NotSet, err
+		}
+		logger.Trace().Msg("Added relayed transaction to the local pool")
+		return reasons[0], nil
+	}
+
+	data, err := txn.MarshalSSZ()
+	if err != nil {
+		return NotSet, fmt.Errorf("failed to marshal txn: %w", err)
+	}
+
+	ps := r.networkManager.PubSub()
+	if topic := topicPendingTransactions(dest); len(ps.ListPeers(topic)) > 0 {
+		logger.Trace().Msg("Relaying transaction to the shard")
+		return NotSet, ps.Publish(ctx, topic, data)
+	}
+
+	for _, hop := range r.nextHops(cur, dest) {
+		if topic := topicRelayTransactions(hop); len(ps.ListPeers(topic)) > 0 {
+			logger.Trace().Stringer("hop", hop).Msg("Relaying transaction via the neighbor shard")
+			return NotSet, ps.Publish(ctx, topic, data)
+		}
+	}
+
+	// Let the transaction be relayed again once the route appears.
+	r.seen.Remove(hash)
+	return NotSet, ErrNoRoute
+}
+
+// nextHops returns the shards whose nodes may deliver the transaction to the destination shard.
+// The transactions from the clients are sent to the neighbors of the destination,
+// and the relaying nodes send them further only in the direction defined by the topology.
+func (r *Relay) nextHops(cur *types.ShardId, dest types.ShardId) []types.ShardId {
+	if cur == nil {
+		return r.cfg.Topology.GetNeighbors(dest, r.cfg.NShards, false)
+	}
+	return slices.DeleteFunc(r.cfg.Topology.GetNeighbors(*cur, r.cfg.NShards, false), func(hop types.ShardId) bool {
+		return hop == dest || !r.cfg.Topology.ShouldPropagateTxn(*cur, hop, dest)
+	})
+}
@@ -0,0 +1,127 @@
+package txnpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lineTopology connects every shard to the adjacent ones and relays the transactions in one direction.
+type lineTopology struct{}
+
+func (lineTopology) GetNeighbors(id types.ShardId, nShards uint32, includeSelf bool) []types.ShardId {
+	var res []types.ShardId
+	if id > 0 {
+		res = append(res, id-1)
+	}
+	if uint32(id)+1 < nShards {
+		res = append(res, id+1)
+	}
+	if includeSelf {
+		res = append(res, id)
+	}
+	return res
+}
+
+func (lineTopology) ShouldPropagateTxn(from types.ShardId, cur types.ShardId, dest types.ShardId) bool {
+	return (from < cur) == (cur < dest)
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	const nShards = 3
+	nms := network.NewTestManagers(t, ctx, 9110, 3)
+	defer func() {
+		// Stop the subscriptions before closing the managers.
+		cancel()
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	newPool := func(shardId types.ShardId, nm *network.Manager) *TxnPool {
+		t.Helper()
+
+		pool, err := New(ctx, NewConfig(shardId), nm)
+		require.NoError(t, err)
+		return pool
+	}
+	newRelay := func(nm *network.Manager, pools map[types.ShardId]Pool) *Relay {
+		t.Helper()
+
+		relay, err := NewRelay(ctx, RelayConfig{
+			NShards:  nShards,
+			Topology: lineTopology{},
+			Pools:    pools,
+		}, nm)
+		require.NoError(t, err)
+		return relay
+	}
+
+	// The client node serves no shards, it is connected only to the node of shard 1,
+	// which is connected to the node of shard 2.
+	clientRelay := newRelay(nms[0], nil)
+	hopPool := newPool(1, nms[1])
+	hopRelay := newRelay(nms[1], map[types.ShardId]Pool{1: hopPool})
+	destPool := newPool(2, nms[2])
+	newRelay(nms[2], map[types.ShardId]Pool{2: destPool})
+
+	address := types.ShardAndHexToAddress(2, "11")
+
+	t.Run("NoRoute", func(t *testing.T) {
+		_, err := clientRelay.Relay(ctx, newTransaction(address, 0, 1))
+		require.ErrorIs(t, err, ErrNoRoute)
+	})
+
+	network.ConnectManagers(t, nms[0], nms[1])
+	network.ConnectManagers(t, nms[1], nms[2])
+	require.Eventually(t, func() bool {
+		return len(nms[0].PubSub().ListPeers(topicRelayTransactions(1))) > 0 &&
+			len(nms[1].PubSub().ListPeers(topicPendingTransactions(2))) > 0
+	}, 10*time.Second, 50*time.Millisecond)
+
+	txn := newTransaction(address, 0, 1)
+	reason, err := clientRelay.Relay(ctx, txn)
+	require.NoError(t, err)
+	assert.Equal(t, NotSet, reason)
+
+	require.Eventually(t, func() bool {
+		has, err := destPool.IdHashKnown(txn.Hash())
+		require.NoError(t, err)
+		return has
+	}, 20*time.Second, 100*time.Millisecond)
+
+	t.Run("Duplicate", func(t *testing.T) {
+		reason, err := clientRelay.Relay(ctx, txn)
+		require.NoError(t, err)
+		assert.Equal(t, DuplicateHash, reason)
+	})
+
+	t.Run("InvalidChainId", func(t *testing.T) {
+		invalid := newTransaction(address, 1, 1)
+		invalid.ChainId = types.DefaultChainId + 1
+		reason, err := clientRelay.Relay(ctx, invalid)
+		require.NoError(t, err)
+		assert.Equal(t, InvalidChainId, reason)
+	})
+
+	t.Run("LocalPool", func(t *testing.T) {
+		local := newTransaction(types.ShardAndHexToAddress(1, "11"), 0, 1)
+		reason, err := hopRelay.Relay(ctx, local)
+		require.NoError(t, err)
+		assert.Equal(t, NotSet, reason)
+
+		has, err := hopPool.IdHashKnown(local.Hash())
+		require.NoError(t, err)
+		assert.True(t, has)
+	})
+}
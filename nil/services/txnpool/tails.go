@@ -0,0 +1,125 @@
+package txnpool
+
+import (
+	"container/heap"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// receiverTails tracks the last (highest seqno) transaction of each receiver.
+// Only such transactions can be evicted without leaving seqno gaps,
+// so they are indexed by the time they were added to the pool and by their tips.
+type receiverTails struct {
+	tails map[types.Address]*metaTxn
+	byAge *tailHeap
+	byTip *tailHeap
+}
+
+func newReceiverTails() *receiverTails {
+	return &receiverTails{
+		tails: make(map[types.Address]*metaTxn),
+		byAge: &tailHeap{
+			less:  func(a, b *metaTxn) bool { return a.addedAt.Before(b.addedAt) },
+			index: func(txn *metaTxn) *int { return &txn.ageIndex },
+		},
+		byTip: &tailHeap{
+			less:  hasLowerTip,
+			index: func(txn *metaTxn) *int { return &txn.tipIndex },
+		},
+	}
+}
+
+// set makes txn the last transaction of the receiver, nil means the receiver has no transactions.
+func (t *receiverTails) set(to types.Address, txn *metaTxn) {
+	old := t.tails[to]
+	if old == txn {
+		return
+	}
+	if old != nil {
+		t.byAge.remove(old)
+		t.byTip.remove(old)
+	}
+	if txn == nil {
+		delete(t.tails, to)
+		return
+	}
+	t.tails[to] = txn
+	heap.Push(t.byAge, txn)
+	heap.Push(t.byTip, txn)
+}
+
+// oldest returns the last transaction of the receivers that was added to the pool earliest.
+func (t *receiverTails) oldest() *metaTxn {
+	if t.byAge.Len() == 0 {
+		return nil
+	}
+	return t.byAge.txns[0]
+}
+
+// lowestTip returns the last transaction of the receivers other than the given one with the lowest tip.
+func (t *receiverTails) lowestTip(except types.Address) *metaTxn {
+	h := t.byTip
+	if h.Len() == 0 {
+		return nil
+	}
+	if root := h.txns[0]; root.To != except {
+		return root
+	}
+	// The root belongs to the excluded receiver, the next lowest is one of its children.
+	var res *metaTxn
+	for i := 1; i <= 2 && i < h.Len(); i++ {
+		if res == nil || hasLowerTip(h.txns[i], res) {
+			res = h.txns[i]
+		}
+	}
+	return res
+}
+
+// updateTips restores the order of the tips after they were recalculated.
+func (t *receiverTails) updateTips() {
+	heap.Init(t.byTip)
+}
+
+type tailHeap struct {
+	txns  []*metaTxn
+	less  func(a, b *metaTxn) bool
+	index func(*metaTxn) *int
+}
+
+func (h *tailHeap) Len() int {
+	return len(h.txns)
+}
+
+func (h *tailHeap) Less(i, j int) bool {
+	return h.less(h.txns[i], h.txns[j])
+}
+
+func (h *tailHeap) Swap(i, j int) {
+	h.txns[i], h.txns[j] = h.txns[j], h.txns[i]
+	*h.index(h.txns[i]) = i
+	*h.index(h.txns[j]) = j
+}
+
+func (h *tailHeap) Push(x any) {
+	txn, ok := x.(*metaTxn)
+	check.PanicIfNot(ok)
+	*h.index(txn) = len(h.txns)
+	h.txns = append(h.txns, txn)
+}
+
+func (h *tailHeap) Pop() any {
+	old := h.txns
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil // avoid memory leak
+	h.txns = old[0 : n-1]
+	*h.index(item) = -1
+	return item
+}
+
+func (h *tailHeap) remove(txn *metaTxn) {
+	if i := *h.index(txn); i >= 0 {
+		heap.Remove(h, i)
+	}
+}
@@ -57,6 +57,22 @@ func (b *ByReceiverAndSeqno) seqno(to types.Address) (seqno types.Seqno, ok bool
 	return seqno, ok
 }
 
+// last returns the transaction of the receiver with the highest seqno.
+func (b *ByReceiverAndSeqno) last(to types.Address) *metaTxn {
+	s := b.search
+	s.To = to
+	s.Seqno = math.MaxUint64
+
+	var res *metaTxn
+	b.tree.DescendLessOrEqual(s, func(txn *metaTxn) bool {
+		if txn.To.Equal(to) {
+			res = txn
+		}
+		return false
+	})
+	return res
+}
+
 func (b *ByReceiverAndSeqno) ascendAll(f func(*metaTxn) bool) {
 	b.tree.Ascend(func(mm *metaTxn) bool {
 		return f(mm)
@@ -75,7 +91,7 @@ func (b *ByReceiverAndSeqno) ascend(to types.Address, f func(*metaTxn) bool) {
 	})
 }
 
-func (b *ByReceiverAndSeqno) count(to types.Address) int { //nolint:unused
+func (b *ByReceiverAndSeqno) count(to types.Address) int {
 	return b.toTxnCount[to]
 }
 
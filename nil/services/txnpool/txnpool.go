@@ -27,12 +27,33 @@ type Pool interface {
 	Peek(n int) ([]*types.TxnWithHash, error)
 	SeqnoToAddress(addr types.Address) (seqno types.Seqno, inPool bool)
 	Get(hash common.Hash) (*types.Transaction, error)
+	// Content returns a snapshot of all transactions in the pool.
+	Content() *Content
+
+	// AddPendingListener returns a channel that receives transactions accepted by the pool
+	// and a function that removes the listener and closes the channel.
+	// The channel is closed by the pool if the listener doesn't keep up with the accepted transactions.
+	AddPendingListener() (<-chan *types.TxnWithHash, func())
+}
+
+// Content is a snapshot of the pool. Transactions are ordered by receiver and seqno.
+type Content struct {
+	BaseFee types.Value
+	// Pending transactions can be included into a block at the current base fee.
+	Pending []*types.TxnWithHash
+	// Queued transactions wait for the base fee to drop below their MaxFeePerGas.
+	Queued []*types.TxnWithHash
 }
 
+// pendingListenerBuffer is the number of transactions a pending listener may lag behind the pool
+// before it's dropped.
+const pendingListenerBuffer = 100
+
 type TxnPool struct {
 	started bool
 	cfg     Config
 	baseFee types.Value
+	timer   common.Timer
 
 	networkManager *network.Manager
 
@@ -40,8 +61,13 @@ type TxnPool struct {
 
 	byHash map[string]*metaTxn // hash => txn : only those records not committed to db yet
 	all    *ByReceiverAndSeqno // from => (sorted map of txn seqno => *txn)
+	tails  *receiverTails      // the last transactions of the receivers, candidates for eviction
 	queue  *TxnQueue
 	logger zerolog.Logger
+
+	listenersLock sync.Mutex
+	listeners     map[uint64]chan *types.TxnWithHash
+	lastListener  uint64
 }
 
 func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*TxnPool, error) {
@@ -52,13 +78,17 @@ func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*Txn
 	res := &TxnPool{
 		started: true,
 		cfg:     cfg,
+		timer:   common.NewTimer(),
 
 		networkManager: networkManager,
 
 		byHash: map[string]*metaTxn{},
 		all:    NewBySenderAndSeqno(logger),
+		tails:  newReceiverTails(),
 		queue:  &TxnQueue{},
 		logger: logger,
+
+		listeners: make(map[uint64]chan *types.TxnWithHash),
 	}
 
 	if networkManager == nil {
@@ -81,11 +111,12 @@ func New(ctx context.Context, cfg Config, networkManager *network.Manager) (*Txn
 func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 	defer sub.Close()
 
-	for m := range sub.Start(ctx, true) {
+	for m := range sub.StartMessages(ctx, true) {
 		txn := &types.Transaction{}
-		if err := txn.UnmarshalSSZ(m); err != nil {
+		if err := txn.UnmarshalSSZ(m.Data); err != nil {
 			p.logger.Error().Err(err).
 				Msg("Failed to unmarshal transaction from network")
+			p.networkManager.ReportPeer(m.From, network.MisbehaviorMalformedMessage)
 			continue
 		}
 
@@ -95,6 +126,7 @@ func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 			p.logger.Error().Err(err).
 				Stringer(logging.FieldTransactionHash, mm.Hash()).
 				Msg("Failed to add transaction from network")
+			p.networkManager.ReportPeer(m.From, network.MisbehaviorInvalidTransaction)
 			continue
 		}
 
@@ -102,6 +134,9 @@ func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 			p.logger.Debug().
 				Stringer(logging.FieldTransactionHash, mm.Hash()).
 				Msgf("Discarded transaction from network with reason %s", reasons[0])
+			if reasons[0].IsInvalid() {
+				p.networkManager.ReportPeer(m.From, network.MisbehaviorInvalidTransaction)
+			}
 		}
 	}
 }
@@ -153,11 +188,13 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 			continue
 		}
 
+		txn.addedAt = p.timer.NowTime()
 		if reason := p.addLocked(txn); reason != NotSet {
 			discardReasons[i] = reason
 			continue
 		}
 		discardReasons[i] = NotSet // unnecessary
+		p.notifyListeners(txn.TxnWithHash)
 		p.logger.Debug().
 			Uint64(logging.FieldShardId, uint64(txn.To.ShardId())).
 			Stringer(logging.FieldTransactionHash, txn.Hash()).
@@ -168,6 +205,44 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 	return discardReasons, nil
 }
 
+func (p *TxnPool) AddPendingListener() (<-chan *types.TxnWithHash, func()) {
+	p.listenersLock.Lock()
+	defer p.listenersLock.Unlock()
+
+	p.lastListener++
+	id := p.lastListener
+	ch := make(chan *types.TxnWithHash, pendingListenerBuffer)
+	p.listeners[id] = ch
+
+	return ch, func() {
+		p.listenersLock.Lock()
+		defer p.listenersLock.Unlock()
+		if ch, ok := p.listeners[id]; ok {
+			close(ch)
+			delete(p.listeners, id)
+		}
+	}
+}
+
+func (p *TxnPool) notifyListeners(txn *types.TxnWithHash) {
+	p.listenersLock.Lock()
+	defer p.listenersLock.Unlock()
+	for id, ch := range p.listeners {
+		select {
+		case ch <- txn:
+		default:
+			// A slow listener shouldn't block the pool, and it shouldn't silently miss transactions either,
+			// so it's dropped and the subscriber learns about it from the closed channel.
+			p.logger.Warn().
+				Uint64("listener", id).
+				Stringer(logging.FieldTransactionHash, txn.Hash()).
+				Msg("Pending transactions listener is full, dropping it.")
+			close(ch)
+			delete(p.listeners, id)
+		}
+	}
+}
+
 func (p *TxnPool) validateTxn(txn *metaTxn) (DiscardReason, bool) {
 	seqno, has := p.all.seqno(txn.To)
 	if has && seqno > txn.Seqno {
@@ -254,10 +329,14 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 
 		p.queue.Remove(found)
 		p.discardLocked(found, ReplacedByHigherTip)
+	} else if p.cfg.AccountSlots != 0 && uint64(p.all.count(txn.To)) >= p.cfg.AccountSlots {
+		return AccountSlotsFull
 	}
 
-	if uint64(p.queue.Len()) >= p.cfg.Size {
-		return PoolOverflow
+	if uint64(len(p.byHash)) >= p.cfg.Size {
+		if !p.cfg.EvictLowestTip || !p.evictLowestTipLocked(txn) {
+			return PoolOverflow
+		}
 	}
 
 	hashStr := string(txn.Hash().Bytes())
@@ -265,6 +344,7 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 
 	replaced := p.all.replaceOrInsert(txn)
 	check.PanicIfNot(replaced == nil)
+	p.updateTailLocked(txn.To)
 
 	if needToAdd := txn.valid; needToAdd {
 		for _, t := range p.queue.txns {
@@ -285,12 +365,59 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 	return NotSet
 }
 
+// hasLowerTip reports whether a is less attractive for inclusion into a block than b.
+func hasLowerTip(a, b *metaTxn) bool {
+	if a.valid != b.valid {
+		return !a.valid
+	}
+	return a.effectivePriorityFee.Cmp(b.effectivePriorityFee) < 0
+}
+
+// evictLowestTipLocked makes room for the given transaction by discarding the transaction with the lowest tip
+// if the latter is lower than the tip of the given one. Only the last transactions of other receivers
+// are considered, so no seqno gaps appear.
+func (p *TxnPool) evictLowestTipLocked(txn *metaTxn) bool {
+	victim := p.tails.lowestTip(txn.To)
+	if victim == nil || !hasLowerTip(victim, txn) {
+		return false
+	}
+	p.discardLocked(victim, EvictedByHigherTip)
+	return true
+}
+
+// evictExpiredLocked discards the transactions that stay in the pool longer than the configured lifetime.
+// The transactions of a receiver are discarded starting from the highest seqno, so no seqno gaps appear:
+// an expired transaction stays in the pool while it is followed by a transaction that has not expired yet.
+func (p *TxnPool) evictExpiredLocked() {
+	if p.cfg.Lifetime == 0 {
+		return
+	}
+
+	deadline := p.timer.NowTime().Add(-p.cfg.Lifetime)
+	discarded := 0
+	for txn := p.tails.oldest(); txn != nil && txn.addedAt.Before(deadline); txn = p.tails.oldest() {
+		p.discardLocked(txn, Expired)
+		discarded++
+	}
+
+	if discarded > 0 {
+		p.logger.Debug().
+			Int("count", discarded).
+			Msg("Discarded expired transactions")
+	}
+}
+
+func (p *TxnPool) updateTailLocked(to types.Address) {
+	p.tails.set(to, p.all.last(to))
+}
+
 // dropping transaction from all sub-structures and from db
 // Important: don't call it while iterating by "all"
 func (p *TxnPool) discardLocked(txn *metaTxn, reason DiscardReason) {
 	hashStr := string(txn.Hash().Bytes())
 	delete(p.byHash, hashStr)
 	p.all.delete(txn, reason)
+	p.updateTailLocked(txn.To)
 	if txn.IsInQueue() {
 		p.queue.Remove(txn)
 		if t := p.nextSenderTxnLocked(txn.To, txn.Seqno); t != nil {
@@ -335,6 +462,7 @@ func (p *TxnPool) OnCommitted(_ context.Context, baseFee types.Value, committed
 	if err := p.removeCommitted(p.all, committed); err != nil {
 		return fmt.Errorf("failed to remove committed transactions: %w", err)
 	}
+	p.evictExpiredLocked()
 	if p.baseFee != baseFee {
 		p.baseFee = baseFee
 		p.UpdateTransactions()
@@ -348,6 +476,7 @@ func (p *TxnPool) UpdateTransactions() {
 		txn.effectivePriorityFee, txn.valid = execution.GetEffectivePriorityFee(p.baseFee, txn.Transaction)
 		return true
 	})
+	p.tails.updateTips()
 	p.all.ascendAll(func(txn *metaTxn) bool {
 		if !txn.valid && txn.bestIndex >= 0 {
 			p.queue.Remove(txn)
@@ -419,6 +548,22 @@ func (p *TxnPool) removeCommitted(bySeqno *ByReceiverAndSeqno, txns []*types.Tra
 	return nil
 }
 
+func (p *TxnPool) Content() *Content {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	res := &Content{BaseFee: p.baseFee}
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		if txn.valid {
+			res.Pending = append(res.Pending, txn.TxnWithHash)
+		} else {
+			res.Queued = append(res.Queued, txn.TxnWithHash)
+		}
+		return true
+	})
+	return res
+}
+
 func (p *TxnPool) Peek(n int) ([]*types.TxnWithHash, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -431,7 +576,9 @@ func (p *TxnPool) Peek(n int) ([]*types.TxnWithHash, error) {
 		check.PanicIfNot(ok)
 		res = append(res, txn.TxnWithHash)
 		if txn = p.nextSenderTxnLocked(txn.To, txn.Seqno); txn != nil {
-			heap.Push(q, txn)
+			// Push a copy, the heap of the clone must not change the index of the transaction in the pool's queue.
+			next := *txn
+			heap.Push(q, &next)
 		}
 	}
 
@@ -167,6 +167,143 @@ func (s *SuiteTxnPool) TestAddOverflow() {
 		newTransaction(defaultAddress, 1, 123), PoolOverflow)
 }
 
+func (s *SuiteTxnPool) TestAddOverflowEvictsLowestTip() {
+	s.pool.cfg.Size = 3
+	s.pool.cfg.EvictLowestTip = true
+
+	address2 := types.ShardAndHexToAddress(0, "22")
+	address3 := types.ShardAndHexToAddress(0, "33")
+
+	txn11 := newTransaction2(defaultAddress, 0, 10, defaultMaxFee, 11)
+	txn12 := newTransaction2(defaultAddress, 1, 30, defaultMaxFee, 12)
+	txn21 := newTransaction2(address2, 0, 20, defaultMaxFee, 21)
+	s.addTransactionsSuccessfully(txn11, txn12, txn21)
+
+	// The tip is not higher than the lowest tip among the last transactions of the receivers.
+	s.addTransactionWithDiscardReason(newTransaction2(address3, 0, 20, defaultMaxFee, 31), PoolOverflow)
+
+	// txn21 is evicted: txn11 has a lower tip, but it is followed by txn12.
+	reasons, err := s.pool.Add(s.ctx, newTransaction2(address3, 0, 25, defaultMaxFee, 31))
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{NotSet}, reasons)
+	s.checkTransactionsOrder(31, 11, 12)
+
+	has, err := s.pool.IdHashKnown(txn21.Hash())
+	s.Require().NoError(err)
+	s.False(has)
+
+	// txn31 is the only transaction of other receivers.
+	reasons, err = s.pool.Add(s.ctx, newTransaction2(defaultAddress, 2, 100, defaultMaxFee, 13))
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{NotSet}, reasons)
+	s.checkTransactionsOrder(11, 12, 13)
+
+	// Transactions of the same receiver are never evicted to avoid seqno gaps.
+	s.addTransactionWithDiscardReason(newTransaction2(defaultAddress, 3, 200, defaultMaxFee, 14), PoolOverflow)
+
+	s.pool.cfg.EvictLowestTip = false
+	s.addTransactionWithDiscardReason(newTransaction2(address2, 0, 100, defaultMaxFee, 21), PoolOverflow)
+}
+
+func (s *SuiteTxnPool) TestAccountSlots() {
+	s.pool.cfg.AccountSlots = 2
+
+	txn := newTransaction(defaultAddress, 1, 123)
+	s.addTransactionsSuccessfully(newTransaction(defaultAddress, 0, 123), txn)
+	s.addTransactionWithDiscardReason(newTransaction(defaultAddress, 2, 123), AccountSlotsFull)
+
+	// Replacement doesn't take a new slot.
+	replacement := common.CopyPtr(txn)
+	replacement.FeeCredit = replacement.FeeCredit.Add64(1)
+	reasons, err := s.pool.Add(s.ctx, replacement)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{NotSet}, reasons)
+
+	// Other receivers are not affected.
+	s.addTransactionsSuccessfully(newTransaction(types.ShardAndHexToAddress(0, "22"), 0, 123))
+}
+
+func (s *SuiteTxnPool) TestExpired() {
+	timer := common.NewTestTimerFromTime(time.Now())
+	s.pool.timer = timer
+	s.pool.cfg.Lifetime = time.Minute
+
+	txn1 := newTransaction2(defaultAddress, 0, 10, defaultMaxFee, 1)
+	s.addTransactionsSuccessfully(txn1)
+
+	timer.Add(30 * time.Second)
+	txn2 := newTransaction2(defaultAddress, 1, 10, defaultMaxFee, 2)
+	s.addTransactionsSuccessfully(txn2)
+
+	// txn1 has expired, but it is followed by txn2, discarding it would leave a seqno gap.
+	timer.Add(40 * time.Second)
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, nil))
+	s.checkTransactionsOrder(1, 2)
+
+	timer.Add(time.Minute)
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, nil))
+	s.checkTransactionsOrder()
+
+	// Receivers don't affect each other.
+	txn3 := newTransaction2(defaultAddress, 2, 10, defaultMaxFee, 3)
+	s.addTransactionsSuccessfully(txn3)
+	timer.Add(30 * time.Second)
+	txn21 := newTransaction2(types.ShardAndHexToAddress(0, "22"), 0, 10, defaultMaxFee, 21)
+	s.addTransactionsSuccessfully(txn21)
+
+	timer.Add(40 * time.Second)
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, nil))
+	s.checkTransactionsOrder(21)
+}
+
+func (s *SuiteTxnPool) TestEvictionDisabledByDefault() {
+	cfg := NewConfig(0)
+	s.Zero(cfg.AccountSlots)
+	s.Zero(cfg.Lifetime)
+	s.False(cfg.EvictLowestTip)
+
+	s.pool.cfg.Size = 1
+	s.addTransactionsSuccessfully(newTransaction2(defaultAddress, 0, 10, defaultMaxFee, 1))
+	s.addTransactionWithDiscardReason(
+		newTransaction2(types.ShardAndHexToAddress(0, "22"), 0, 100, defaultMaxFee, 2), PoolOverflow)
+}
+
+func (s *SuiteTxnPool) TestContent() {
+	address2 := types.ShardAndHexToAddress(0, "22")
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, nil))
+
+	txn21 := newTransaction2(address2, 0, 10, defaultMaxFee, 21)
+	txn11 := newTransaction2(defaultAddress, 0, 10, defaultMaxFee, 11)
+	txn12 := newTransaction2(defaultAddress, 1, 10, 90, 12) // MaxFeePerGas is below the base fee
+	s.addTransactions(txn21, txn11, txn12)
+
+	content := s.pool.Content()
+	s.Equal(defaultBaseFee, content.BaseFee)
+	s.Equal([]*types.TxnWithHash{types.NewTxnWithHash(txn11), types.NewTxnWithHash(txn21)}, content.Pending)
+	s.Equal([]*types.TxnWithHash{types.NewTxnWithHash(txn12)}, content.Queued)
+}
+
+func (s *SuiteTxnPool) TestPendingListenerOverflow() {
+	txns, remove := s.pool.AddPendingListener()
+	defer remove()
+
+	for seqno := range types.Seqno(pendingListenerBuffer + 1) {
+		s.addTransactionsSuccessfully(newTransaction(defaultAddress, seqno, 123))
+	}
+
+	// the listener receives the buffered transactions, then the channel is closed
+	for seqno := range types.Seqno(pendingListenerBuffer) {
+		txn, ok := <-txns
+		s.Require().True(ok)
+		s.Equal(seqno, txn.Seqno)
+	}
+	_, ok := <-txns
+	s.False(ok)
+
+	// the dropped listener doesn't affect the following transactions
+	s.addTransactionsSuccessfully(newTransaction(defaultAddress, pendingListenerBuffer+1, 123))
+}
+
 func (s *SuiteTxnPool) TestStarted() {
 	s.True(s.pool.Started())
 }
@@ -2,6 +2,7 @@ package txnpool
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -9,10 +10,20 @@ import (
 const defaultPoolSize = 10000
 
 type Config struct {
-	ShardId types.ShardId
-	Size    uint64
+	ShardId types.ShardId `yaml:"-"`
+	// Size is the maximum number of transactions in the pool.
+	Size uint64 `yaml:"size"`
+	// AccountSlots is the maximum number of transactions to a single receiver. Zero means no limit.
+	AccountSlots uint64 `yaml:"accountSlots"`
+	// Lifetime is the maximum time a transaction can stay in the pool. Zero means forever.
+	Lifetime time.Duration `yaml:"lifetime"`
+	// EvictLowestTip makes the full pool evict the transaction with the lowest tip
+	// in favor of a new one with a higher tip instead of rejecting the latter.
+	EvictLowestTip bool `yaml:"evictLowestTip"`
 }
 
+// NewConfig returns the configuration of the pool with the eviction policies disabled,
+// so the full pool rejects new transactions and the accepted ones stay until they are committed.
 func NewConfig(shardId types.ShardId) Config {
 	return Config{
 		ShardId: shardId,
@@ -20,6 +31,12 @@ func NewConfig(shardId types.ShardId) Config {
 	}
 }
 
+// NewDefaultConfig returns the default configuration to be used as a template for the pools of all shards.
+func NewDefaultConfig() *Config {
+	cfg := NewConfig(types.MainShardId)
+	return &cfg
+}
+
 type DiscardReason uint8
 
 const (
@@ -36,8 +53,22 @@ const (
 	DuplicateHash       DiscardReason = 21 // There was an existing transaction with the same hash
 	Unverified          DiscardReason = 22 // Transaction verification failed
 	TooSmallMaxFee      DiscardReason = 23 // Transaction max fee is too small
+	AccountSlotsFull    DiscardReason = 24 // The receiver already has the maximum number of transactions in the pool
+	Expired             DiscardReason = 25 // Transaction stayed in the pool longer than the configured lifetime
+	EvictedByHigherTip  DiscardReason = 26 // The pool was full, and the transaction had the lowest tip
 )
 
+// IsInvalid reports whether the transaction can never be accepted, unlike the ones discarded because of the state
+// of the pool or of the account, which may be caused by the order of the gossip.
+func (r DiscardReason) IsInvalid() bool {
+	switch r {
+	case InvalidChainId, NegativeValue, Unverified:
+		return true
+	default:
+		return false
+	}
+}
+
 func (r DiscardReason) String() string {
 	switch r {
 	case NotSet:
@@ -66,6 +97,12 @@ func (r DiscardReason) String() string {
 		return "verification failed"
 	case TooSmallMaxFee:
 		return "max fee too small"
+	case AccountSlotsFull:
+		return "account slots full"
+	case Expired:
+		return "expired"
+	case EvictedByHigherTip:
+		return "evicted by higher tip"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -594,6 +594,23 @@ func (s *SuiteCliExec) TestCliCometa() {
 		s.Require().Equal(
 			"Contract metadata for address 0x0001111111111111111111111111111111111111 has been registered", out)
 	})
+
+	s.Run("Verify main smart account", func() {
+		out := s.RunCliCfg("cometa", "verify",
+			"--address", types.MainSmartAccountAddress.Hex(),
+			"--compile-input", "../../contracts/solidity/compile-smart-account.json")
+		s.Contains(out, "Contract at address 0x0001111111111111111111111111111111111111 has been verified, match: ")
+
+		out = s.RunCliCfg("cometa", "info", "--address", types.MainSmartAccountAddress.Hex())
+		s.Regexp("Verification: (full|partial)", out)
+	})
+
+	s.Run("Verify mismatching contract", func() {
+		_, err := s.RunCliNoCheck("-c", s.TmpDir+"/config.ini", "cometa", "verify",
+			"--address", types.MainSmartAccountAddress.Hex(),
+			"--compile-input", "../contracts/counter-compile.json")
+		s.Require().Error(err)
+	})
 }
 
 func parseCometaOutput(out string) []map[string]string {
@@ -19,7 +19,7 @@ func StartFaucetService(t *testing.T, ctx context.Context, wg *sync.WaitGroup, c
 
 	endpoint := rpc.GetSockPathService(t, "faucet")
 
-	serviceFaucet, err := faucet.NewService(client)
+	serviceFaucet, err := faucet.NewService(ctx, nil, client)
 	require.NoError(t, err)
 
 	wg.Add(1)
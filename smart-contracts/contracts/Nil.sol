@@ -394,6 +394,7 @@ library Nil {
     struct ValidatorInfo {
         uint8[33] PublicKey;
         address WithdrawalAddress;
+        uint64 Weight;
     }
 
     struct ListValidators{
@@ -416,6 +417,18 @@ library Nil {
         bytes32 hash;
     }
 
+    // Shares of the block gas limit and changes of the base fee are in basis points (1/10000).
+    struct FeeParams {
+        uint64 targetUtilization;
+        uint64 maxChange;
+        uint64 smoothing;
+        uint256 minBaseFee;
+    }
+
+    struct ParamFees {
+        FeeParams[] shards;
+    }
+
     /**
      * @dev Returns the current validators.
      * @return Struct containing the list of validators.
@@ -434,6 +447,15 @@ library Nil {
         return abi.decode(data, (ParamGasPrice));
     }
 
+    /**
+     * @dev Returns the base fee curve parameters of the shards.
+     * @return Struct containing the fee parameters indexed by the shard id.
+     */
+    function getParamFees() internal returns(ParamFees memory) {
+        bytes memory data = getConfigParam("fees");
+        return abi.decode(data, (ParamFees));
+    }
+
     /**
      * @dev Logs a transaction with data.
      * @param transaction Transaction to log.
@@ -514,6 +536,7 @@ contract NilConfigAbi {
     function curr_validators(Nil.ParamValidators memory) public {}
     function gas_price(Nil.ParamGasPrice memory) public {}
     function l1block(Nil.ParamL1BlockInfo memory) public {}
+    function fees(Nil.ParamFees memory) public {}
 }
 
 function tokenIdEqual(TokenId a, TokenId b) pure returns (bool) {